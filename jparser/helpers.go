@@ -232,9 +232,11 @@ func SkipArray(d *json.Decoder, desc string) error {
 
 // Pair represents unmarshalled part of JSON byte stream.
 // Value is an array of Pairs or primitive value or []interface{}.
+// Offset is input offset of the pair's key end (see LineCounter).
 type Pair struct {
-	K string
-	V interface{}
+	K      string
+	V      interface{}
+	Offset int64
 }
 
 // GetUndefined unmarshals whole part of JSON byte stream.
@@ -290,12 +292,13 @@ func GetObject(d *json.Decoder, desc string) ([]Pair, error) {
 			return nil, newObjectTokenError(t, DelimObjectEnd, desc)
 
 		case string:
+			off := d.InputOffset()
 			v, err := GetUndefined(d, desc)
 			if err != nil {
 				return nil, bindError(err, t)
 			}
 
-			obj = append(obj, Pair{K: t, V: v})
+			obj = append(obj, Pair{K: t, V: v, Offset: off})
 
 		case json.Delim:
 			if t.String() != DelimObjectEnd {
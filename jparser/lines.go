@@ -0,0 +1,55 @@
+package jparser
+
+import (
+	"bytes"
+	"io"
+	"sort"
+)
+
+// LineCounter wraps reader of JSON byte stream and remembers where lines
+// of the stream start. It converts input offsets reported by json.Decoder
+// (see its InputOffset method) to line and column numbers.
+type LineCounter struct {
+	r     io.Reader
+	n     int64
+	lines []int64
+}
+
+// NewLineCounter creates line counter for given reader. Decoder should read
+// the stream via the counter.
+func NewLineCounter(r io.Reader) *LineCounter {
+	return &LineCounter{r: r}
+}
+
+// Read implements io.Reader interface.
+func (c *LineCounter) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+
+	for i := 0; i < n; {
+		j := bytes.IndexByte(b[i:n], '\n')
+		if j < 0 {
+			break
+		}
+
+		i += j + 1
+		c.lines = append(c.lines, c.n+int64(i))
+	}
+	c.n += int64(n)
+
+	return n, err
+}
+
+// Position returns line and column of given input offset. Both line and
+// column start from 1. Column counts bytes from the start of the line.
+func (c *LineCounter) Position(offset int64) (int, int) {
+	i := sort.Search(len(c.lines), func(i int) bool {
+		return c.lines[i] > offset
+	})
+
+	start := int64(0)
+	if i > 0 {
+		start = c.lines[i-1]
+	}
+
+	return i + 1, int(offset-start) + 1
+}
@@ -36,9 +36,9 @@ func init() {
 	flag.Var(&conf.addresses, "s", "server(s) to upload policy to")
 	flag.DurationVar(&conf.timeout, "t", 5*time.Second, "connection timeout")
 	flag.IntVar(&conf.chunkSize, "c", 64*1024, "size of chunk for splitting uploads")
-	flag.StringVar(&conf.contentID, "id", "", "id of content to upload")
-	flag.StringVar(&conf.fromTag, "vf", "", "tag to update from (if not specified data to upload is full snapshot)")
-	flag.StringVar(&conf.toTag, "vt", "", "new tag to set (if not specified data to upload is not updateable)")
+	flag.StringVar(&conf.contentID, "id", "", "id of content to upload, list versions of, roll back or delete")
+	flag.StringVar(&conf.fromTag, "vf", "", "tag to update from (if not specified data to upload is full snapshot) or tag content to delete must have")
+	flag.StringVar(&conf.toTag, "vt", "", "new tag to set (if not specified data to upload is not updateable) or tag to roll back to")
 
 	flag.Parse()
 }
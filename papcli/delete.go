@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/infobloxopen/themis/pdpctrl-client"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func deleteContent(hosts []*pdpcc.Client) {
+	if len(conf.contentID) <= 0 {
+		panic(fmt.Errorf("no content to delete. Please specify it with -id"))
+	}
+
+	errors := 0
+	for i, h := range hosts {
+		if err := h.DeleteContent(conf.contentID, conf.fromTag); err != nil {
+			log.Errorf("Failed to delete content from %s: %v", conf.addresses[i], err)
+			errors++
+		}
+	}
+
+	if errors >= len(hosts) {
+		panic(fmt.Errorf("no hosts deleted content"))
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
@@ -12,8 +13,13 @@ import (
 func main() {
 	log.SetLevel(log.InfoLevel)
 
-	f, policy := openFile()
-	defer f.Close()
+	cmd := flag.Arg(0)
+	switch cmd {
+	case "", "upload":
+	case "versions", "rollback", "status", "delete":
+	default:
+		panic(fmt.Errorf("unknown command %q. Expected upload, versions, rollback, status or delete", cmd))
+	}
 
 	hosts := []*pdpcc.Client{}
 
@@ -27,6 +33,28 @@ func main() {
 		defer h.Close()
 	}
 
+	switch cmd {
+	case "versions":
+		versions(hosts)
+
+	case "rollback":
+		rollback(hosts)
+
+	case "status":
+		status(hosts)
+
+	case "delete":
+		deleteContent(hosts)
+
+	default:
+		upload(hosts)
+	}
+}
+
+func upload(hosts []*pdpcc.Client) {
+	f, policy := openFile()
+	defer f.Close()
+
 	log.Infof("Requesting data upload to PDP servers...")
 
 	uids := make([]int32, len(hosts))
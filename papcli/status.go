@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/infobloxopen/themis/pdpctrl-client"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func status(hosts []*pdpcc.Client) {
+	for i, h := range hosts {
+		st, err := h.Status()
+		if err != nil {
+			log.Errorf("Failed to get status of %s: %v", conf.addresses[i], err)
+			continue
+		}
+
+		fmt.Printf("%s:\n", conf.addresses[i])
+		fmt.Printf("  ready: %v\n", st.Ready)
+		fmt.Printf("  policies: %s\n", describeTag(st.PolicyTag))
+		fmt.Printf("  symbols: %d types, %d attributes, %d variables\n",
+			st.Symbols.Types, st.Symbols.Attributes, st.Symbols.Variables)
+
+		fmt.Println("  content:")
+		for _, c := range st.Contents {
+			fmt.Printf("  - %s: %s (%d items)\n", c.ID, describeTag(c.Tag), c.Items)
+		}
+
+		fmt.Println("  queue:")
+		for _, e := range st.Queue {
+			what := "policies"
+			if len(e.ContentID) > 0 {
+				what = fmt.Sprintf("content %s", e.ContentID)
+			}
+
+			state := "requested"
+			if e.Uploaded {
+				state = "uploaded"
+			}
+
+			fmt.Printf("  - %d: %s %s -> %s (%s)\n", e.ID, what, describeTag(e.FromTag), describeTag(e.ToTag), state)
+		}
+	}
+}
+
+func describeTag(tag string) string {
+	if len(tag) > 0 {
+		return tag
+	}
+
+	return "no tag"
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/infobloxopen/themis/pdpctrl-client"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func versions(hosts []*pdpcc.Client) {
+	for i, h := range hosts {
+		var (
+			vs  []pdpcc.Version
+			err error
+		)
+		if len(conf.contentID) > 0 {
+			vs, err = h.ListContentVersions(conf.contentID)
+		} else {
+			vs, err = h.ListPoliciesVersions()
+		}
+
+		if err != nil {
+			log.Errorf("Failed to get versions from %s: %v", conf.addresses[i], err)
+			continue
+		}
+
+		fmt.Printf("%s:\n", conf.addresses[i])
+		for _, v := range vs {
+			mark := " "
+			if v.Current {
+				mark = "*"
+			}
+
+			fmt.Printf("%s %s %s\n", mark, v.Tag, v.Timestamp.Format(time.RFC3339))
+		}
+	}
+}
+
+func rollback(hosts []*pdpcc.Client) {
+	if len(conf.toTag) <= 0 {
+		panic(fmt.Errorf("no tag to roll back to. Please specify it with -vt"))
+	}
+
+	errors := 0
+	for i, h := range hosts {
+		var err error
+		if len(conf.contentID) > 0 {
+			err = h.RollbackContent(conf.contentID, conf.toTag)
+		} else {
+			err = h.RollbackPolicies(conf.toTag)
+		}
+
+		if err != nil {
+			log.Errorf("Failed to roll back %s: %v", conf.addresses[i], err)
+			errors++
+		}
+	}
+
+	if errors >= len(hosts) {
+		panic(fmt.Errorf("no hosts rolled back"))
+	}
+}
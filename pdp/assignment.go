@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/infobloxopen/go-trees/domain"
 	"github.com/infobloxopen/go-trees/domaintree"
@@ -138,6 +139,22 @@ func MakeListOfStringsAssignment(id string, v []string) AttributeAssignment {
 	)
 }
 
+// MakeDateTimeAssignment creates attribute assignment for date and time value.
+func MakeDateTimeAssignment(id string, v time.Time) AttributeAssignment {
+	return MakeAttributeAssignment(
+		MakeAttribute(id, TypeDateTime),
+		MakeDateTimeValue(v),
+	)
+}
+
+// MakeDurationAssignment creates attribute assignment for duration value.
+func MakeDurationAssignment(id string, v time.Duration) AttributeAssignment {
+	return MakeAttributeAssignment(
+		MakeAttribute(id, TypeDuration),
+		MakeDurationValue(v),
+	)
+}
+
 // MakeFlags8Assignment creates attribute assignment for flags value which fits
 // 8 bits integer.
 func MakeFlags8Assignment(id string, t Type, v uint8) AttributeAssignment {
@@ -0,0 +1,54 @@
+package ast
+
+/* AUTOMATICALLY GENERATED FROM errors.yaml - DO NOT EDIT */
+
+const (
+	externalErrorID     = 0
+	includeCycleErrorID = 1
+	policyPathErrorID   = 2
+)
+
+type externalError struct {
+	errorLink
+	err error
+}
+
+func newExternalError(err error) *externalError {
+	return &externalError{
+		errorLink: errorLink{id: externalErrorID},
+		err:       err}
+}
+
+func (e *externalError) Error() string {
+	return e.errorf("%s", e.err)
+}
+
+type includeCycleError struct {
+	errorLink
+	chain string
+}
+
+func newIncludeCycleError(chain string) *includeCycleError {
+	return &includeCycleError{
+		errorLink: errorLink{id: includeCycleErrorID},
+		chain:     chain}
+}
+
+func (e *includeCycleError) Error() string {
+	return e.errorf("Policy documents include each other: %s", e.chain)
+}
+
+type policyPathError struct {
+	errorLink
+	path string
+}
+
+func newPolicyPathError(path string) *policyPathError {
+	return &policyPathError{
+		errorLink: errorLink{id: policyPathErrorID},
+		path:      path}
+}
+
+func (e *policyPathError) Error() string {
+	return e.errorf("%q is neither regular file nor directory", e.path)
+}
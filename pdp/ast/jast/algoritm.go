@@ -33,6 +33,31 @@ type caParams struct {
 	arg    pdp.Expression
 	order  int
 	subAlg interface{}
+	raw    map[string]interface{}
+}
+
+func isCustomCombiningAlg(ID string) bool {
+	id := strings.ToLower(ID)
+	if _, ok := pdp.RuleCombiningAlgParamsParsers[id]; ok {
+		return true
+	}
+
+	_, ok := pdp.PolicyCombiningAlgParamsParsers[id]
+	return ok
+}
+
+func (ctx context) buildCustomCombiningAlgParams(alg *caParams, parser pdp.CombiningAlgParamsParser) (interface{}, boundError) {
+	raw := alg.raw
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+
+	params, err := parser(raw)
+	if err != nil {
+		return nil, bindError(err, "parameters")
+	}
+
+	return params, nil
 }
 
 func checkPolicyID(ID string, policies []pdp.Evaluable) bool {
@@ -144,12 +169,19 @@ func (ctx context) buildRuleCombiningAlg(alg interface{}, rules []*pdp.Rule) (pd
 			return nil, nil, newUnknownRCAError(alg.id)
 		}
 
-		paramBuilder, ok := ruleCombiningAlgParamBuilders[id]
-		if !ok {
+		var (
+			params interface{}
+			err    boundError
+		)
+
+		if paramBuilder, ok := ruleCombiningAlgParamBuilders[id]; ok {
+			params, err = paramBuilder(ctx, alg, rules)
+		} else if parser, ok := pdp.RuleCombiningAlgParamsParsers[id]; ok {
+			params, err = ctx.buildCustomCombiningAlgParams(alg, parser)
+		} else {
 			return nil, nil, newNotImplementedRCAError(alg.id)
 		}
 
-		params, err := paramBuilder(ctx, alg, rules)
 		if err != nil {
 			return nil, nil, bindError(err, alg.id)
 		}
@@ -176,12 +208,19 @@ func (ctx context) buildPolicyCombiningAlg(alg interface{}, policies []pdp.Evalu
 			return nil, nil, newUnknownPCAError(alg.id)
 		}
 
-		paramBuilder, ok := policyCombiningAlgParamBuilders[id]
-		if !ok {
+		var (
+			params interface{}
+			err    boundError
+		)
+
+		if paramBuilder, ok := policyCombiningAlgParamBuilders[id]; ok {
+			params, err = paramBuilder(ctx, alg, policies)
+		} else if parser, ok := pdp.PolicyCombiningAlgParamsParsers[id]; ok {
+			params, err = ctx.buildCustomCombiningAlgParams(alg, parser)
+		} else {
 			return nil, nil, newNotImplementedPCAError(alg.id)
 		}
 
-		params, err := paramBuilder(ctx, alg, policies)
 		if err != nil {
 			return nil, nil, bindError(err, alg.id)
 		}
@@ -319,7 +358,21 @@ func (ctx context) unmarshalCombiningAlgObj(d *json.Decoder) (*caParams, error)
 			return nil
 		}
 
-		return newUnknownFieldError(k)
+		if idOk && !isCustomCombiningAlg(params.id) {
+			return newUnknownFieldError(k)
+		}
+
+		var v interface{}
+		if err := d.Decode(&v); err != nil {
+			return err
+		}
+
+		if params.raw == nil {
+			params.raw = make(map[string]interface{})
+		}
+
+		params.raw[k] = v
+		return nil
 	}, "algorithm"); err != nil {
 		return nil, err
 	}
@@ -328,7 +381,14 @@ func (ctx context) unmarshalCombiningAlgObj(d *json.Decoder) (*caParams, error)
 		return nil, newMissingAttributeError(yastTagID, "algorithm")
 	}
 
-	if !mapOk {
+	custom := isCustomCombiningAlg(params.id)
+	if !custom {
+		for k := range params.raw {
+			return nil, newUnknownFieldError(k)
+		}
+	}
+
+	if !mapOk && !custom {
 		if idOk {
 			return nil, bindError(newMissingAttributeError(yastTagMap, fmt.Sprintf("%q", params.id)), "algorithm")
 		}
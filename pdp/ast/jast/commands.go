@@ -27,40 +27,64 @@ func (ctx *context) unmarshalEntity(d *json.Decoder) (interface{}, error) {
 		cond     pdp.Expression
 		obligs   []pdp.AttributeAssignment
 		alg      interface{}
+
+		pctx *context
 	)
 
+	enter := func() {
+		if pctx == nil {
+			pctx = ctx.enterPolicy(id, hidden)
+		}
+	}
+
 	if err := jparser.UnmarshalObject(d, func(k string, d *json.Decoder) error {
 		var err error
 
 		switch strings.ToLower(k) {
+		case yastTagRef:
+			return newNotLinkedDocumentError(k)
+
 		case yastTagID:
 			hidden = false
 			id, err = jparser.GetString(d, "policy or set or rule id")
 			return err
 
+		case yastTagVariables:
+			if pctx != nil {
+				return newMisplacedVariablesError()
+			}
+
+			enter()
+			return pctx.unmarshalVariables(d)
+
 		case yastTagAlg:
-			alg, err = ctx.unmarshalCombiningAlg(d)
+			enter()
+			alg, err = pctx.unmarshalCombiningAlg(d)
 			return err
 
 		case yastTagTarget:
-			target, err = ctx.unmarshalTarget(d)
+			enter()
+			target, err = pctx.unmarshalTarget(d)
 			return err
 
 		case yastTagObligation:
-			obligs, err = ctx.unmarshalObligations(d)
+			enter()
+			obligs, err = pctx.unmarshalObligations(d)
 			return err
 
 		case yastTagPolicies:
+			enter()
 			isPolicySet = true
-			policies, err = ctx.unmarshalPolicies(d)
+			policies, err = pctx.unmarshalPolicies(d)
 			if err != nil {
 				return bindError(err, makeSource("policy set", id, hidden))
 			}
 			return nil
 
 		case yastTagRules:
+			enter()
 			isPolicy = true
-			rules, err = ctx.unmarshalRules(d)
+			rules, err = pctx.unmarshalRules(d)
 			if err != nil {
 				return bindError(err, makeSource("policy", id, hidden))
 			}
@@ -83,7 +107,8 @@ func (ctx *context) unmarshalEntity(d *json.Decoder) (interface{}, error) {
 			return nil
 
 		case yastTagCondition:
-			cond, err = ctx.unmarshalCondition(d)
+			enter()
+			cond, err = pctx.unmarshalCondition(d)
 			return err
 		}
 
@@ -155,6 +180,7 @@ func (ctx *context) unmarshalCommand(d *json.Decoder, u *pdp.PolicyUpdate) error
 		op     int
 		path   []string
 		entity interface{}
+		vars   pdp.Symbols
 	)
 
 	if err := jparser.UnmarshalObject(d, func(k string, d *json.Decoder) error {
@@ -187,7 +213,17 @@ func (ctx *context) unmarshalCommand(d *json.Decoder, u *pdp.PolicyUpdate) error
 
 		case yastTagEntity:
 			if op == pdp.UOAdd {
-				entity, err = ctx.unmarshalEntity(d)
+				// Entity can refer variables visible at the path so
+				// the path should go first. Its own variables are
+				// collected separately and get to symbol tables only
+				// when the update is applied.
+				vars = pdp.MakeDocumentSymbols(ctx.symbols)
+				c := *ctx
+				c.path = path
+				c.putVars = true
+				c.cmdVars = &vars
+
+				entity, err = c.unmarshalEntity(d)
 			}
 
 			return err
@@ -198,7 +234,7 @@ func (ctx *context) unmarshalCommand(d *json.Decoder, u *pdp.PolicyUpdate) error
 		return err
 	}
 
-	u.Append(op, path, entity)
+	u.AppendWithVariables(op, path, entity, vars)
 
 	return nil
 }
@@ -11,6 +11,17 @@ import (
 type context struct {
 	symbols    pdp.Symbols
 	rootPolicy pdp.Evaluable
+	its        []pdp.IterationVariable
+
+	vars    []map[string]*pdp.Variable
+	path    []string
+	hidden  bool
+	putVars bool
+	linked  bool
+
+	// cmdVars collects variables of entity of policy update command.
+	// Variables of policy document go to symbols.
+	cmdVars *pdp.Symbols
 }
 
 func newContext() *context {
@@ -43,8 +54,18 @@ func (ctx *context) unmarshal(d *json.Decoder) error {
 		case yastTagAttributes:
 			return ctx.unmarshalAttributeDeclarations(d)
 
+		case yastTagVariables:
+			return ctx.unmarshalVariables(d)
+
 		case yastTagPolicies:
 			return ctx.unmarshalRootPolicy(d)
+
+		case yastTagInclude:
+			if !ctx.linked {
+				return newNotLinkedDocumentError(k)
+			}
+
+			return jparser.SkipValue(d, "list of included documents")
 		}
 
 		return newUnknownFieldError(k)
@@ -3,8 +3,12 @@ package jast
 //go:generate bash -c "(egen -i $GOPATH/src/github.com/infobloxopen/themis/pdp/ast/jast/errors.yaml > $GOPATH/src/github.com/infobloxopen/themis/pdp/ast/jast/errors.go) && gofmt -l -s -w $GOPATH/src/github.com/infobloxopen/themis/pdp/ast/jast/errors.go"
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
+
+	"github.com/infobloxopen/themis/jparser"
+	"github.com/infobloxopen/themis/pdp"
 )
 
 const errorSourcePathSeparator = ">"
@@ -12,6 +16,8 @@ const errorSourcePathSeparator = ">"
 type boundError interface {
 	error
 	bind(src string)
+	locate(offset int64)
+	resolve(lines *jparser.LineCounter)
 }
 
 func bindError(err error, src string) boundError {
@@ -28,21 +34,87 @@ func bindErrorf(err error, format string, args ...interface{}) boundError {
 	return bindError(err, fmt.Sprintf(format, args...))
 }
 
+// locateError sets input offset to the error. Error which isn't bound
+// error becomes external error.
+func locateError(err error, offset int64) boundError {
+	b, ok := err.(boundError)
+	if !ok {
+		b = newExternalError(err)
+	}
+
+	b.locate(offset)
+	return b
+}
+
+// resolveError sets current input offset of given decoder to the error
+// (if the error doesn't have offset yet) and converts the offset to line
+// and column.
+func resolveError(err error, d *json.Decoder, lines *jparser.LineCounter) error {
+	b := locateError(err, d.InputOffset())
+	b.resolve(lines)
+	return b
+}
+
 type errorLink struct {
-	id   int
-	path []string
+	id     int
+	path   []string
+	offset *int64
+	pos    *pdp.SourcePosition
 }
 
 func (e *errorLink) errorf(format string, args ...interface{}) string {
 	msg := fmt.Sprintf(format, args...)
 
 	if len(e.path) > 0 {
-		return fmt.Sprintf("#%02x (%s): %s", e.id, strings.Join(e.path, errorSourcePathSeparator), msg)
+		msg = fmt.Sprintf("#%02x (%s): %s", e.id, strings.Join(e.path, errorSourcePathSeparator), msg)
+	} else {
+		msg = fmt.Sprintf("#%02x: %s", e.id, msg)
+	}
+
+	if e.pos != nil {
+		return fmt.Sprintf("%s: %s", e.pos, msg)
 	}
 
-	return fmt.Sprintf("#%02x: %s", e.id, msg)
+	return msg
 }
 
 func (e *errorLink) bind(src string) {
 	e.path = append([]string{src}, e.path...)
 }
+
+// locate sets input offset of the error. The offset is set only once so
+// the error keeps offset of the innermost JSON value it's been found in.
+func (e *errorLink) locate(offset int64) {
+	if e.offset == nil {
+		e.offset = &offset
+	}
+}
+
+// resolve converts input offset of the error to its position.
+func (e *errorLink) resolve(lines *jparser.LineCounter) {
+	if e.offset != nil {
+		line, column := lines.Position(*e.offset)
+		e.pos = &pdp.SourcePosition{
+			Line:   line,
+			Column: column,
+		}
+	}
+}
+
+// Position implements pdp.PositionedError interface and returns position
+// in JSON document where the error has been found.
+func (e *errorLink) Position() (pdp.SourcePosition, bool) {
+	if e.pos == nil {
+		return pdp.SourcePosition{}, false
+	}
+
+	return *e.pos, true
+}
+
+// SetSourceFile implements pdp.PositionedError interface and sets name of
+// JSON document to the error's position.
+func (e *errorLink) SetSourceFile(name string) {
+	if e.pos != nil {
+		e.pos.File = name
+	}
+}
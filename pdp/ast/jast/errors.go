@@ -61,6 +61,15 @@ const (
 	unknownFlagNameErrorID              = 47
 	unknownAggregationTypeErrorID       = 48
 	invalidAggregationTypeErrorID       = 49
+	invalidDateTimeErrorID              = 50
+	invalidDurationErrorID              = 51
+	unknownVariableErrorID              = 52
+	missingQuantifierVariableErrorID    = 53
+	quantifierCollectionTypeErrorID     = 54
+	duplicateVariableErrorID            = 55
+	misplacedVariablesErrorID           = 56
+	notLinkedDocumentErrorID            = 57
+	policyReferenceFieldsErrorID        = 58
 )
 
 type externalError struct {
@@ -821,3 +830,136 @@ func newInvalidAggregationTypeError(a string, t pdp.Type) *invalidAggregationTyp
 func (e *invalidAggregationTypeError) Error() string {
 	return e.errorf("Inappropriate aggregation type %q for selector type %q", e.a, e.t)
 }
+
+type invalidDateTimeError struct {
+	errorLink
+	s   string
+	err error
+}
+
+func newInvalidDateTimeError(s string, err error) *invalidDateTimeError {
+	return &invalidDateTimeError{
+		errorLink: errorLink{id: invalidDateTimeErrorID},
+		s:         s,
+		err:       err}
+}
+
+func (e *invalidDateTimeError) Error() string {
+	return e.errorf("Expected value of datetime type but got %q (%v)", e.s, e.err)
+}
+
+type invalidDurationError struct {
+	errorLink
+	s   string
+	err error
+}
+
+func newInvalidDurationError(s string, err error) *invalidDurationError {
+	return &invalidDurationError{
+		errorLink: errorLink{id: invalidDurationErrorID},
+		s:         s,
+		err:       err}
+}
+
+func (e *invalidDurationError) Error() string {
+	return e.errorf("Expected value of duration type but got %q (%v)", e.s, e.err)
+}
+
+type unknownVariableError struct {
+	errorLink
+	ID string
+}
+
+func newUnknownVariableError(ID string) *unknownVariableError {
+	return &unknownVariableError{
+		errorLink: errorLink{id: unknownVariableErrorID},
+		ID:        ID}
+}
+
+func (e *unknownVariableError) Error() string {
+	return e.errorf("Unknown variable %q", e.ID)
+}
+
+type missingQuantifierVariableError struct {
+	errorLink
+}
+
+func newMissingQuantifierVariableError() *missingQuantifierVariableError {
+	return &missingQuantifierVariableError{
+		errorLink: errorLink{id: missingQuantifierVariableErrorID}}
+}
+
+func (e *missingQuantifierVariableError) Error() string {
+	return e.errorf("Quantifier 'var' and 'over' attributes are missing or placed after 'expr' attribute")
+}
+
+type quantifierCollectionTypeError struct {
+	errorLink
+	t pdp.Type
+}
+
+func newQuantifierCollectionTypeError(t pdp.Type) *quantifierCollectionTypeError {
+	return &quantifierCollectionTypeError{
+		errorLink: errorLink{id: quantifierCollectionTypeErrorID},
+		t:         t}
+}
+
+func (e *quantifierCollectionTypeError) Error() string {
+	return e.errorf("Expected list of strings, set of strings, set of networks or set of domains to iterate over but got %q", e.t)
+}
+
+type duplicateVariableError struct {
+	errorLink
+	ID string
+}
+
+func newDuplicateVariableError(ID string) *duplicateVariableError {
+	return &duplicateVariableError{
+		errorLink: errorLink{id: duplicateVariableErrorID},
+		ID:        ID}
+}
+
+func (e *duplicateVariableError) Error() string {
+	return e.errorf("Duplicate variable %q", e.ID)
+}
+
+type misplacedVariablesError struct {
+	errorLink
+}
+
+func newMisplacedVariablesError() *misplacedVariablesError {
+	return &misplacedVariablesError{
+		errorLink: errorLink{id: misplacedVariablesErrorID}}
+}
+
+func (e *misplacedVariablesError) Error() string {
+	return e.errorf("Policy 'variables' attribute is placed after 'target', 'alg', 'obligations', 'policies' or 'rules' attribute")
+}
+
+type notLinkedDocumentError struct {
+	errorLink
+	tag string
+}
+
+func newNotLinkedDocumentError(tag string) *notLinkedDocumentError {
+	return &notLinkedDocumentError{
+		errorLink: errorLink{id: notLinkedDocumentErrorID},
+		tag:       tag}
+}
+
+func (e *notLinkedDocumentError) Error() string {
+	return e.errorf("%q is allowed only in policy documents loaded together with the documents they refer", e.tag)
+}
+
+type policyReferenceFieldsError struct {
+	errorLink
+}
+
+func newPolicyReferenceFieldsError() *policyReferenceFieldsError {
+	return &policyReferenceFieldsError{
+		errorLink: errorLink{id: policyReferenceFieldsErrorID}}
+}
+
+func (e *policyReferenceFieldsError) Error() string {
+	return e.errorf("Policy reference can't have any fields other than \"ref\"")
+}
@@ -64,7 +64,20 @@ func (ctx context) unmarshalExpression(d *json.Decoder) (pdp.Expression, error)
 			expr, err = ctx.unmarshalSelector(d)
 			return err
 
+		case yastTagVariable:
+			expr, err = ctx.unmarshalVariable(d)
+			return err
+
 		default:
+			if q, ok := pdp.QuantifierIDs[k]; ok {
+				expr, err = ctx.unmarshalQuantifier(q, d)
+				if err != nil {
+					return bindError(err, k)
+				}
+
+				return nil
+			}
+
 			validators, ok := pdp.FunctionArgumentValidators[k]
 			if !ok {
 				return newUnknownFunctionError(k)
@@ -78,6 +91,10 @@ func (ctx context) unmarshalExpression(d *json.Decoder) (pdp.Expression, error)
 			for _, validator := range validators {
 				if maker := validator(args); maker != nil {
 					expr = maker(args)
+					if err := pdp.ValidateExpression(expr); err != nil {
+						return bindError(err, k)
+					}
+
 					return nil
 				}
 			}
@@ -90,3 +107,93 @@ func (ctx context) unmarshalExpression(d *json.Decoder) (pdp.Expression, error)
 
 	return expr, nil
 }
+
+func (ctx context) unmarshalVariable(d *json.Decoder) (pdp.Expression, error) {
+	ID, err := jparser.GetString(d, "variable name")
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(ctx.its) - 1; i >= 0; i-- {
+		if ctx.its[i].GetID() == ID {
+			return ctx.its[i], nil
+		}
+	}
+
+	return ctx.lookupVariable(ID)
+}
+
+func (ctx context) unmarshalQuantifier(q int, d *json.Decoder) (pdp.Expression, error) {
+	var (
+		ID   string
+		over pdp.Expression
+		e    pdp.Expression
+	)
+
+	if err := jparser.CheckObjectStart(d, "quantifier"); err != nil {
+		return nil, err
+	}
+
+	if err := jparser.UnmarshalObject(d, func(k string, d *json.Decoder) error {
+		var err error
+
+		switch strings.ToLower(k) {
+		case yastTagVariable:
+			ID, err = jparser.GetString(d, "iteration variable name")
+			return err
+
+		case yastTagOver:
+			if err := jparser.CheckObjectStart(d, "collection expression"); err != nil {
+				return bindError(err, yastTagOver)
+			}
+
+			over, err = ctx.unmarshalExpression(d)
+			if err != nil {
+				return bindError(err, yastTagOver)
+			}
+
+			return nil
+
+		case yastTagExpression:
+			if len(ID) <= 0 || over == nil {
+				return newMissingQuantifierVariableError()
+			}
+
+			t, ok := pdp.GetIterationItemType(over.GetResultType())
+			if !ok {
+				return newQuantifierCollectionTypeError(over.GetResultType())
+			}
+
+			its := make([]pdp.IterationVariable, len(ctx.its)+1)
+			copy(its, ctx.its)
+			its[len(ctx.its)] = pdp.MakeIterationVariable(ID, t)
+			ctx.its = its
+
+			if err := jparser.CheckObjectStart(d, "nested expression"); err != nil {
+				return bindError(err, yastTagExpression)
+			}
+
+			e, err = ctx.unmarshalExpression(d)
+			if err != nil {
+				return bindError(err, yastTagExpression)
+			}
+
+			return nil
+		}
+
+		return newUnknownFieldError(k)
+	}, "quantifier"); err != nil {
+		return nil, err
+	}
+
+	if e == nil {
+		return nil, newMissingAttributeError(yastTagExpression, "quantifier")
+	}
+
+	r, err := pdp.MakeQuantifiedExpression(q, ID, over, e)
+	if err != nil {
+		return nil, bindError(err, yastTagExpression)
+	}
+
+	return r, nil
+}
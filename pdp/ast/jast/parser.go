@@ -4,7 +4,9 @@ package jast
 import (
 	"encoding/json"
 	"io"
+	"strings"
 
+	"github.com/infobloxopen/themis/jparser"
 	"github.com/infobloxopen/themis/pdp"
 
 	"github.com/google/uuid"
@@ -33,6 +35,12 @@ const (
 	yastTagAttribute   = "attr"
 	yastTagValue       = "val"
 	yastTagSelector    = "selector"
+	yastTagVariable    = "var"
+	yastTagOver        = "over"
+	yastTagExpression  = "expr"
+	yastTagVariables   = "variables"
+	yastTagRef         = "ref"
+	yastTagInclude     = "include"
 	yastTagType        = "type"
 	yastTagContent     = "content"
 	yastTagURI         = "uri"
@@ -47,19 +55,78 @@ type Parser struct{}
 // Unmarshal parses policies JSON representation to PDP's internal representation.
 func (p Parser) Unmarshal(in io.Reader, tag *uuid.UUID) (*pdp.PolicyStorage, error) {
 	ctx := newContext()
-	if err := ctx.unmarshal(json.NewDecoder(in)); err != nil {
-		return nil, err
+	ctx.putVars = true
+
+	lines := jparser.NewLineCounter(in)
+	d := json.NewDecoder(lines)
+	if err := ctx.unmarshal(d); err != nil {
+		return nil, resolveError(err, d, lines)
 	}
 
 	return pdp.NewPolicyStorage(ctx.rootPolicy, ctx.symbols, tag), nil
 }
 
+// UnmarshalDocument parses policy document which can refer root policies
+// of other documents. It puts types and attributes to given symbol tables.
+// Resulting storage has no root policy if the document contains only
+// declarations.
+func (p Parser) UnmarshalDocument(in io.Reader, s pdp.Symbols) (*pdp.PolicyStorage, error) {
+	ctx := newContextWithSymbols(s)
+	ctx.putVars = true
+	ctx.linked = true
+
+	lines := jparser.NewLineCounter(in)
+	d := json.NewDecoder(lines)
+	if err := ctx.unmarshal(d); err != nil {
+		return nil, resolveError(err, d, lines)
+	}
+
+	return pdp.NewPolicyStorage(ctx.rootPolicy, ctx.symbols, nil), nil
+}
+
+// GetIncludes returns list of documents included by given document.
+func (p Parser) GetIncludes(in io.Reader) ([]string, error) {
+	lines := jparser.NewLineCounter(in)
+	d := json.NewDecoder(lines)
+	ok, err := jparser.CheckRootObjectStart(d)
+	if err != nil {
+		return nil, resolveError(err, d, lines)
+	}
+
+	if !ok {
+		return nil, nil
+	}
+
+	var paths []string
+	if err := jparser.UnmarshalObject(d, func(k string, d *json.Decoder) error {
+		if strings.ToLower(k) != yastTagInclude {
+			return jparser.SkipValue(d, k)
+		}
+
+		if err := jparser.CheckArrayStart(d, "list of included documents"); err != nil {
+			return err
+		}
+
+		return jparser.GetStringSequenceFromArray(d, func(idx int, s string) error {
+			paths = append(paths, s)
+			return nil
+		}, "list of included documents")
+	}, "root"); err != nil {
+		return nil, resolveError(err, d, lines)
+	}
+
+	return paths, nil
+}
+
 // UnmarshalUpdate parses policies update JSON representation to PDP's internal representation.
 func (p Parser) UnmarshalUpdate(in io.Reader, s pdp.Symbols, oldTag, newTag uuid.UUID) (*pdp.PolicyUpdate, error) {
 	ctx := newContextWithSymbols(s)
 	u := pdp.NewPolicyUpdate(oldTag, newTag)
-	if err := ctx.unmarshalCommands(json.NewDecoder(in), u); err != nil {
-		return nil, err
+
+	lines := jparser.NewLineCounter(in)
+	d := json.NewDecoder(lines)
+	if err := ctx.unmarshalCommands(d, u); err != nil {
+		return nil, resolveError(err, d, lines)
 	}
 
 	return u, nil
@@ -1,11 +1,14 @@
 package jast
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/infobloxopen/go-trees/domain"
 
 	"github.com/infobloxopen/themis/pdp"
 	_ "github.com/infobloxopen/themis/pdp/selector"
@@ -2337,142 +2340,1189 @@ const (
     ]
   }
 }
+`
+	customFunctionPolicy = `{
+  "attributes": {
+    "s": "string"
+  },
+  "policies": {
+    "alg": "FirstApplicableEffect",
+    "rules": [
+      {
+        "target": [
+          {
+            "test has prefix": [
+              {"attr": "s"},
+              {"val": {"type": "string", "content": "test"}}
+            ]
+          }
+        ],
+        "condition": {
+          "test has prefix": [
+            {"attr": "s"},
+            {"val": {"type": "string", "content": "test-"}}
+          ]
+        },
+        "effect": "Permit"
+      }
+    ]
+  }
+}
+`
+
+	customAlgPolicy = `{
+  "attributes": {
+    "s": "string"
+  },
+  "policies": {
+    "alg": {
+      "weights": {
+        "Europe": 3,
+        "Asia": 1,
+        "America": 1
+      },
+      "id": "test weighted majority"
+    },
+    "policies": [
+      {
+        "id": "Europe",
+        "alg": "FirstApplicableEffect",
+        "rules": [
+          {
+            "target": [
+              {
+                "equal": [
+                  {"attr": "s"},
+                  {"val": {"type": "string", "content": "deny"}}
+                ]
+              }
+            ],
+            "effect": "Deny"
+          },
+          {
+            "effect": "Permit"
+          }
+        ]
+      },
+      {
+        "id": "Asia",
+        "alg": "FirstApplicableEffect",
+        "rules": [
+          {
+            "effect": "Deny"
+          }
+        ]
+      },
+      {
+        "id": "America",
+        "alg": "FirstApplicableEffect",
+        "rules": [
+          {
+            "effect": "Deny"
+          }
+        ]
+      }
+    ]
+  }
+}
+`
+
+	patternPolicy = `{
+  "attributes": {
+    "s": "string",
+    "d": "domain"
+  },
+  "policies": {
+    "alg": "FirstApplicableEffect",
+    "rules": [
+      {
+        "target": [
+          {
+            "match": [
+              {"attr": "s"},
+              {"val": {"type": "string", "content": "^test-[0-9]+$"}}
+            ]
+          }
+        ],
+        "condition": {
+          "glob": [
+            {"attr": "d"},
+            {"val": {"type": "string", "content": "*.corp.example.com"}}
+          ]
+        },
+        "effect": "Permit"
+      }
+    ]
+  }
+}
+`
+
+	stringFunctionsPolicy = `{
+  "attributes": {
+    "s": "string",
+    "r": "string"
+  },
+  "policies": {
+    "alg": "FirstApplicableEffect",
+    "rules": [
+      {
+        "target": [
+          {
+            "has suffix": [
+              {"attr": "s"},
+              {"val": {"type": "string", "content": "example.com"}}
+            ]
+          }
+        ],
+        "condition": {
+          "and": [
+            {
+              "has prefix": [
+                {"lower": [{"trim": [{"attr": "s"}]}]},
+                {"val": {"type": "string", "content": "user@"}}
+              ]
+            },
+            {
+              "equal": [
+                {
+                  "string length": [
+                    {
+                      "substring": [
+                        {"upper": [{"attr": "s"}]},
+                        {"val": {"type": "integer", "content": 0}},
+                        {"val": {"type": "integer", "content": 4}}
+                      ]
+                    }
+                  ]
+                },
+                {"val": {"type": "integer", "content": 4}}
+              ]
+            }
+          ]
+        },
+        "effect": "Permit",
+        "obligations": [
+          {
+            "r": {
+              "join": [
+                {
+                  "split": [
+                    {"attr": "s"},
+                    {"val": {"type": "string", "content": "@"}}
+                  ]
+                },
+                {"val": {"type": "string", "content": " at "}}
+              ]
+            }
+          }
+        ]
+      }
+    ]
+  }
+}
+`
+
+	quantifiedExpressionsPolicy = `{
+  "attributes": {
+    "groups": "list of strings",
+    "r": "list of strings"
+  },
+  "policies": {
+    "alg": "FirstApplicableEffect",
+    "rules": [
+      {
+        "condition": {
+          "any": {
+            "var": "g",
+            "over": {"attr": "groups"},
+            "expr": {
+              "equal": [
+                {"var": "g"},
+                {"val": {"type": "string", "content": "admin"}}
+              ]
+            }
+          }
+        },
+        "effect": "Permit",
+        "obligations": [
+          {
+            "r": {
+              "map": {
+                "var": "g",
+                "over": {
+                  "filter": {
+                    "var": "g",
+                    "over": {"attr": "groups"},
+                    "expr": {
+                      "not": [
+                        {
+                          "equal": [
+                            {"var": "g"},
+                            {"val": {"type": "string", "content": "admin"}}
+                          ]
+                        }
+                      ]
+                    }
+                  }
+                },
+                "expr": {"upper": [{"var": "g"}]}
+              }
+            }
+          }
+        ]
+      }
+    ]
+  }
+}
+`
+
+	variablesPolicy = `{
+  "attributes": {
+    "role": "string",
+    "level": "integer",
+    "r": "string"
+  },
+  "variables": {
+    "isAdmin": {
+      "equal": [
+        {"attr": "role"},
+        {"val": {"type": "string", "content": "admin"}}
+      ]
+    }
+  },
+  "policies": {
+    "id": "Root",
+    "alg": "FirstApplicableEffect",
+    "policies": [
+      {
+        "id": "Levels",
+        "variables": {
+          "high": {
+            "and": [
+              {"var": "isAdmin"},
+              {
+                "greater": [
+                  {"attr": "level"},
+                  {"val": {"type": "integer", "content": 5}}
+                ]
+              }
+            ]
+          },
+          "label": {
+            "if": [
+              {"var": "high"},
+              {"val": {"type": "string", "content": "high"}},
+              {"val": {"type": "string", "content": "low"}}
+            ]
+          }
+        },
+        "alg": "FirstApplicableEffect",
+        "rules": [
+          {
+            "id": "High",
+            "condition": {"var": "high"},
+            "effect": "Permit",
+            "obligations": [{"r": {"var": "label"}}]
+          }
+        ]
+      }
+    ]
+  }
+}
+`
+
+	variablesUpdate = `[
+  {
+    "op": "add",
+    "path": ["Root", "Levels"],
+    "entity": {
+      "id": "Low",
+      "condition": {"not": [{"var": "high"}]},
+      "effect": "Deny",
+      "obligations": [{"r": {"var": "label"}}]
+    }
+  }
+]
+`
+
+	variablesPolicyUpdate = `[
+  {
+    "op": "add",
+    "path": ["Root"],
+    "entity": {
+      "id": "Extra",
+      "variables": {
+        "low": {"not": [{"var": "isAdmin"}]}
+      },
+      "alg": "FirstApplicableEffect",
+      "rules": [
+        {
+          "id": "Admin",
+          "condition": {"var": "isAdmin"},
+          "effect": "Permit"
+        }
+      ]
+    }
+  }
+]
+`
+
+	variablesRuleUpdate = `[
+  {
+    "op": "add",
+    "path": ["Root", "Extra"],
+    "entity": {
+      "id": "User",
+      "condition": {"var": "low"},
+      "effect": "Deny"
+    }
+  }
+]
+`
+
+	variablesReplaceUpdate = `[
+  {
+    "op": "add",
+    "path": ["Root"],
+    "entity": {
+      "id": "Extra",
+      "alg": "FirstApplicableEffect",
+      "rules": [
+        {
+          "id": "Admin",
+          "condition": {"var": "isAdmin"},
+          "effect": "Permit"
+        }
+      ]
+    }
+  }
+]
+`
+
+	misplacedVariablesPolicy = `{
+  "attributes": {
+    "r": "string"
+  },
+  "policies": {
+    "alg": "FirstApplicableEffect",
+    "rules": [
+      {
+        "effect": "Permit",
+        "obligations": [{"r": {"val": {"type": "string", "content": "test"}}}]
+      }
+    ],
+    "variables": {
+      "label": {"val": {"type": "string", "content": "test"}}
+    }
+  }
+}
+`
+
+	misplacedQuantifierVariablePolicy = `{
+  "attributes": {
+    "groups": "list of strings"
+  },
+  "policies": {
+    "alg": "FirstApplicableEffect",
+    "rules": [
+      {
+        "condition": {
+          "all": {
+            "expr": {
+              "equal": [
+                {"var": "g"},
+                {"val": {"type": "string", "content": "admin"}}
+              ]
+            },
+            "var": "g",
+            "over": {"attr": "groups"}
+          }
+        },
+        "effect": "Permit"
+      }
+    ]
+  }
+}
+`
+
+	dateTimePolicy = `{
+  "attributes": {
+    "issued": "datetime",
+    "day": "string"
+  },
+  "policies": {
+    "alg": "FirstApplicableEffect",
+    "rules": [
+      {
+        "target": [
+          {
+            "after": [
+              {"attr": "issued"},
+              {"val": {"type": "datetime", "content": "2019-01-01T00:00:00Z"}}
+            ]
+          }
+        ],
+        "condition": {
+          "and": [
+            {
+              "before": [
+                {"now": []},
+                {
+                  "add": [
+                    {"attr": "issued"},
+                    {"val": {"type": "duration", "content": "15m"}}
+                  ]
+                }
+              ]
+            },
+            {
+              "time of day in range": [
+                {
+                  "in time zone": [
+                    {"now": []},
+                    {"val": {"type": "string", "content": "America/New_York"}}
+                  ]
+                },
+                {"val": {"type": "string", "content": "09:00"}},
+                {"val": {"type": "string", "content": "18:00"}}
+              ]
+            }
+          ]
+        },
+        "effect": "Permit",
+        "obligations": [
+          {
+            "day": {
+              "day of week": [
+                {
+                  "in time zone": [
+                    {"now": []},
+                    {"val": {"type": "string", "content": "America/New_York"}}
+                  ]
+                }
+              ]
+            }
+          }
+        ]
+      }
+    ]
+  }
+}
+`
+
+	invalidTimeZonePolicy = `{
+  "attributes": {
+    "issued": "datetime"
+  },
+  "policies": {
+    "alg": "FirstApplicableEffect",
+    "rules": [
+      {
+        "condition": {
+          "before": [
+            {"attr": "issued"},
+            {
+              "in time zone": [
+                {"now": []},
+                {"val": {"type": "string", "content": "Mars/Olympus_Mons"}}
+              ]
+            }
+          ]
+        },
+        "effect": "Permit"
+      }
+    ]
+  }
+}
+`
+
+	xacmlAlgsPolicy = `{
+  "attributes": {
+    "l": "list of strings"
+  },
+  "policies": {
+    "id": "Root",
+    "alg": "PermitOverrides",
+    "policies": [
+      {
+        "id": "DenyUnlessPermit",
+        "alg": "DenyUnlessPermit",
+        "rules": [
+          {
+            "effect": "Deny"
+          }
+        ]
+      },
+      {
+        "id": "PermitUnlessDeny",
+        "alg": "PermitUnlessDeny",
+        "rules": [
+          {
+            "effect": "Deny"
+          }
+        ]
+      },
+      {
+        "id": "OnlyOneApplicable",
+        "alg": "OnlyOneApplicable",
+        "policies": [
+          {
+            "id": "Mapper",
+            "alg": {
+              "id": "mapper",
+              "map": {
+                "attr": "l"
+              },
+              "alg": "PermitOverrides"
+            },
+            "rules": [
+              {
+                "id": "Deny",
+                "effect": "Deny"
+              },
+              {
+                "id": "Permit",
+                "effect": "Permit"
+              }
+            ]
+          }
+        ]
+      }
+    ]
+  }
+}
 `
 )
 
-func TestUnmarshal(t *testing.T) {
+func TestUnmarshal(t *testing.T) {
+	p := Parser{}
+	_, err := p.Unmarshal(strings.NewReader(invalidJSON), nil)
+	if err == nil {
+		t.Errorf("Expected error for invalid JSON but got nothing")
+	}
+
+	_, err = p.Unmarshal(strings.NewReader(invalidRootKeysPolicy), nil)
+	if err == nil {
+		t.Errorf("Expected error for policy with invalid keys but got nothing")
+	} else {
+		_, ok := err.(*unknownFieldError)
+		if !ok {
+			t.Errorf("Expected *unknownFieldError for policy with invalid keys but got %T (%s)", err, err)
+		}
+	}
+
+	s, err := p.Unmarshal(strings.NewReader(simpleAllPermitPolicy), nil)
+	if err != nil {
+		t.Errorf("Expected no error but got %T (%s)", err, err)
+	} else {
+		p, ok := s.Root().(*pdp.Policy)
+		if !ok {
+			t.Errorf("Expected policy as root item in Simple All Permit Policy but got %T", p)
+		} else {
+			PID, ok := p.GetID()
+			if !ok {
+				t.Errorf("Expected %q as Simple All Permit Policy ID but got hidden policy", "Default")
+			} else if PID != "Default" {
+				t.Errorf("Expected %q as Simple All Permit Policy ID but got %q", "Default", PID)
+			}
+		}
+
+		r := s.Root().Calculate(&pdp.Context{})
+		if r.Effect != pdp.EffectPermit {
+			t.Errorf("Expected permit as a response for Simple All Permit Policy but got %d", r.Effect)
+		}
+	}
+
+	s, err = p.Unmarshal(strings.NewReader(allFeaturePolicies), nil)
+	if err != nil {
+		t.Errorf("Expected no error but got %T (%s)", err, err)
+	} else {
+		ctx, err := pdp.NewContext(nil, 5, func(i int) (string, pdp.AttributeValue, error) {
+			switch i {
+			case 0:
+				v, err := pdp.MakeValueFromString(pdp.TypeBoolean, "true")
+				if err != nil {
+					return "", pdp.UndefinedValue, err
+				}
+
+				return "boolAttr", v, nil
+
+			case 1:
+				v, err := pdp.MakeValueFromString(pdp.TypeString, "string")
+				if err != nil {
+					return "", pdp.UndefinedValue, err
+				}
+
+				return "strAttr", v, nil
+
+			case 2:
+				v, err := pdp.MakeValueFromString(pdp.TypeAddress, "192.0.2.1")
+				if err != nil {
+					return "", pdp.UndefinedValue, err
+				}
+
+				return "addrAttr", v, nil
+
+			case 3:
+				v, err := pdp.MakeValueFromString(pdp.TypeNetwork, "192.0.2.0/24")
+				if err != nil {
+					return "", pdp.UndefinedValue, err
+				}
+
+				return "netAttr", v, nil
+
+			case 4:
+				v, err := pdp.MakeValueFromString(pdp.TypeString, "example.com")
+				if err != nil {
+					return "", pdp.UndefinedValue, err
+				}
+
+				return "domAttr", v, nil
+			}
+
+			return "", pdp.UndefinedValue, fmt.Errorf("no attribute for index %d", i)
+		})
+		if err != nil {
+			t.Errorf("Expected no error but got %T (%s)", err, err)
+		} else {
+			r := s.Root().Calculate(ctx)
+			effect, o, err := r.Effect, r.Obligations, r.Status
+			if effect != pdp.EffectDeny {
+				if err != nil {
+					t.Errorf("Expected deny as a response for Simple All Permit Policy but got %d (%s)", effect, err)
+				} else {
+					t.Errorf("Expected deny as a response for Simple All Permit Policy but got %d", effect)
+				}
+			}
+
+			if len(o) < 1 {
+				t.Error("Expected at least one obligation")
+			} else {
+				_, _, v, err := o[0].Serialize(ctx)
+				if err != nil {
+					t.Errorf("Expected no error but got %T (%s)", err, err)
+				} else {
+					e := "Nested Mappers Policy Set Deny"
+					if v != e {
+						t.Errorf("Expected %q but got %q", e, v)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMissingSubAlg(t *testing.T) {
+	p := Parser{}
+	_, err := p.Unmarshal(strings.NewReader(missingSubAlgPCAPolicy), nil)
+	if err == nil {
+		t.Errorf("expected *missingPCAError but got no error")
+	} else if _, ok := err.(*missingPCAError); !ok {
+		t.Errorf("expected *missingPCAError but got %T: %s", err, err)
+	}
+
+	p = Parser{}
+	_, err = p.Unmarshal(strings.NewReader(missingSubAlgRCAPolicy), nil)
+	if err == nil {
+		t.Errorf("expected *missingRCAError but got no error")
+	} else if _, ok := err.(*missingRCAError); !ok {
+		t.Errorf("expected *missingRCAError but got %T: %s", err, err)
+	}
+}
+
+func TestXACMLAlgorithms(t *testing.T) {
+	p := Parser{}
+	s, err := p.Unmarshal(strings.NewReader(xacmlAlgsPolicy), nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	ctx, err := pdp.NewContext(nil, 1, func(i int) (string, pdp.AttributeValue, error) {
+		return "l", pdp.MakeListOfStringsValue([]string{"Deny", "Permit"}), nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	r := s.Root().Calculate(ctx)
+	if r.Effect != pdp.EffectPermit {
+		t.Errorf("Expected permit as a response for XACML algorithms policy but got %d (%s)", r.Effect, r.Status)
+	}
+
+	m, err := s.GetAtPath([]string{"Root"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	b := new(bytes.Buffer)
+	if err := m.MarshalWithDepth(b, 2); err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	for _, e := range []string{
+		"permitOverridesPCA",
+		"denyUnlessPermitRCA",
+		"permitUnlessDenyRCA",
+		"onlyOneApplicablePCA",
+		"permitOverridesRCA",
+	} {
+		if !strings.Contains(b.String(), e) {
+			t.Errorf("Expected %q in marshaled policies but got %s", e, b)
+		}
+	}
+}
+
+func TestCustomFunction(t *testing.T) {
+	name := "test has prefix"
+	err := pdp.RegisterFunction(name, pdp.FunctionSignature{
+		Args:   pdp.MakeSignature(pdp.TypeString, pdp.TypeString),
+		Result: pdp.TypeBoolean,
+	}, func(ctx *pdp.Context, args []pdp.AttributeValue) (pdp.AttributeValue, error) {
+		s, err := args[0].GetString()
+		if err != nil {
+			return pdp.UndefinedValue, err
+		}
+
+		prefix, err := args[1].GetString()
+		if err != nil {
+			return pdp.UndefinedValue, err
+		}
+
+		return pdp.MakeBooleanValue(strings.HasPrefix(s, prefix)), nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+	defer delete(pdp.FunctionArgumentValidators, name)
+	defer delete(pdp.TargetCompatibleExpressions, name)
+
+	p := Parser{}
+	s, err := p.Unmarshal(strings.NewReader(customFunctionPolicy), nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	for v, e := range map[string]int{
+		"test-string": pdp.EffectPermit,
+		"testString":  pdp.EffectNotApplicable,
+		"example":     pdp.EffectNotApplicable,
+	} {
+		ctx, err := pdp.NewContext(nil, 1, func(i int) (string, pdp.AttributeValue, error) {
+			return "s", pdp.MakeStringValue(v), nil
+		})
+		if err != nil {
+			t.Fatalf("Expected no error but got %T (%s)", err, err)
+		}
+
+		r := s.Root().Calculate(ctx)
+		if r.Effect != e {
+			t.Errorf("Expected %s for %q but got %s (%s)",
+				pdp.EffectNameFromEnum(e), v, pdp.EffectNameFromEnum(r.Effect), r.Status)
+		}
+	}
+}
+
+func TestCustomCombiningAlg(t *testing.T) {
+	ID := "test weighted majority"
+	err := pdp.RegisterPolicyCombiningParamAlg(ID, makeTestWeightedMajorityPCA, parseTestWeightedMajorityParams)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+	defer delete(pdp.PolicyCombiningParamAlgs, ID)
+	defer delete(pdp.PolicyCombiningAlgParamsParsers, ID)
+
+	p := Parser{}
+	s, err := p.Unmarshal(strings.NewReader(customAlgPolicy), nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	for v, e := range map[string]int{
+		"permit": pdp.EffectPermit,
+		"deny":   pdp.EffectDeny,
+	} {
+		ctx, err := pdp.NewContext(nil, 1, func(i int) (string, pdp.AttributeValue, error) {
+			return "s", pdp.MakeStringValue(v), nil
+		})
+		if err != nil {
+			t.Fatalf("Expected no error but got %T (%s)", err, err)
+		}
+
+		r := s.Root().Calculate(ctx)
+		if r.Effect != e {
+			t.Errorf("Expected %s for %q but got %s (%s)",
+				pdp.EffectNameFromEnum(e), v, pdp.EffectNameFromEnum(r.Effect), r.Status)
+		}
+	}
+
+	_, err = p.Unmarshal(strings.NewReader(strings.Replace(customAlgPolicy, `"Europe": 3`, `"Europe": "three"`, 1)), nil)
+	if err == nil {
+		t.Errorf("Expected error for invalid weight but got nothing")
+	}
+
+	_, err = p.Unmarshal(strings.NewReader(strings.Replace(customAlgPolicy, ID, "Mapper", 1)), nil)
+	if err == nil {
+		t.Errorf("Expected error for unknown mapper field but got nothing")
+	}
+}
+
+type testWeightedMajorityPCA struct {
+	weights map[string]float64
+}
+
+func makeTestWeightedMajorityPCA(policies []pdp.Evaluable, params interface{}) pdp.CustomPolicyCombiningAlg {
+	weights, _ := params.(map[string]float64)
+	return testWeightedMajorityPCA{weights: weights}
+}
+
+func parseTestWeightedMajorityParams(params map[string]interface{}) (interface{}, error) {
+	m, ok := params["weights"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected weights map but got %T", params["weights"])
+	}
+
+	weights := make(map[string]float64, len(m))
+	for k, v := range m {
+		w, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("expected number as weight of %q but got %T", k, v)
+		}
+
+		weights[k] = w
+	}
+
+	return weights, nil
+}
+
+func (a testWeightedMajorityPCA) Execute(policies []pdp.Evaluable, ctx *pdp.Context) pdp.Response {
+	var permit, deny float64
+	for _, p := range policies {
+		id, _ := p.GetID()
+		switch p.Calculate(ctx).Effect {
+		case pdp.EffectPermit:
+			permit += a.weights[id]
+
+		case pdp.EffectDeny:
+			deny += a.weights[id]
+		}
+	}
+
+	if permit > deny {
+		return pdp.Response{Effect: pdp.EffectPermit}
+	}
+
+	return pdp.Response{Effect: pdp.EffectDeny}
+}
+
+func (a testWeightedMajorityPCA) MarshalJSON() ([]byte, error) {
+	return []byte(`{"type":"testWeightedMajorityPCA"}`), nil
+}
+
+func TestPatternMatch(t *testing.T) {
+	p := Parser{}
+	s, err := p.Unmarshal(strings.NewReader(patternPolicy), nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	for _, c := range []struct {
+		s string
+		d string
+		e int
+	}{
+		{s: "test-1", d: "www.corp.example.com", e: pdp.EffectPermit},
+		{s: "test-x", d: "www.corp.example.com", e: pdp.EffectNotApplicable},
+		{s: "test-1", d: "www.example.com", e: pdp.EffectNotApplicable},
+	} {
+		d, err := domain.MakeNameFromString(c.d)
+		if err != nil {
+			t.Fatalf("Expected no error but got %T (%s)", err, err)
+		}
+
+		attrs := []pdp.AttributeValue{pdp.MakeStringValue(c.s), pdp.MakeDomainValue(d)}
+		ctx, err := pdp.NewContext(nil, len(attrs), func(i int) (string, pdp.AttributeValue, error) {
+			return []string{"s", "d"}[i], attrs[i], nil
+		})
+		if err != nil {
+			t.Fatalf("Expected no error but got %T (%s)", err, err)
+		}
+
+		r := s.Root().Calculate(ctx)
+		if r.Effect != c.e {
+			t.Errorf("Expected %s for %q and %q but got %s (%s)",
+				pdp.EffectNameFromEnum(c.e), c.s, c.d, pdp.EffectNameFromEnum(r.Effect), r.Status)
+		}
+	}
+
+	_, err = p.Unmarshal(strings.NewReader(strings.Replace(patternPolicy, "^test-[0-9]+$", "^test-[0-9+$", 1)), nil)
+	if err == nil || !strings.Contains(err.Error(), "regular expression") {
+		t.Errorf("Expected error for invalid regular expression in target but got %v", err)
+	}
+
+	_, err = p.Unmarshal(strings.NewReader(strings.Replace(patternPolicy, "*.corp.example.com", "*.corp[", 1)), nil)
+	if err == nil || !strings.Contains(err.Error(), "glob pattern") {
+		t.Errorf("Expected error for invalid glob in condition but got %v", err)
+	}
+}
+
+func TestStringFunctions(t *testing.T) {
+	p := Parser{}
+	s, err := p.Unmarshal(strings.NewReader(stringFunctionsPolicy), nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	for v, e := range map[string]int{
+		" User@example.com":  pdp.EffectPermit,
+		"admin@example.com":  pdp.EffectNotApplicable,
+		" User@example.com ": pdp.EffectNotApplicable,
+	} {
+		ctx, err := pdp.NewContext(nil, 1, func(i int) (string, pdp.AttributeValue, error) {
+			return "s", pdp.MakeStringValue(v), nil
+		})
+		if err != nil {
+			t.Fatalf("Expected no error but got %T (%s)", err, err)
+		}
+
+		r := s.Root().Calculate(ctx)
+		if r.Effect != e {
+			t.Errorf("Expected %s for %q but got %s (%s)",
+				pdp.EffectNameFromEnum(e), v, pdp.EffectNameFromEnum(r.Effect), r.Status)
+		}
+
+		if r.Effect == pdp.EffectPermit {
+			if len(r.Obligations) != 1 {
+				t.Errorf("Expected single obligation for %q but got %d", v, len(r.Obligations))
+				continue
+			}
+
+			_, _, o, err := r.Obligations[0].Serialize(ctx)
+			if err != nil {
+				t.Errorf("Expected no error but got %T (%s)", err, err)
+			} else if o != " User at example.com" {
+				t.Errorf("Expected %q for %q but got %q", " User at example.com", v, o)
+			}
+		}
+	}
+}
+
+func TestQuantifiedExpressions(t *testing.T) {
 	p := Parser{}
-	_, err := p.Unmarshal(strings.NewReader(invalidJSON), nil)
-	if err == nil {
-		t.Errorf("Expected error for invalid JSON but got nothing")
+	s, err := p.Unmarshal(strings.NewReader(quantifiedExpressionsPolicy), nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
 	}
 
-	_, err = p.Unmarshal(strings.NewReader(invalidRootKeysPolicy), nil)
-	if err == nil {
-		t.Errorf("Expected error for policy with invalid keys but got nothing")
-	} else {
-		_, ok := err.(*unknownFieldError)
-		if !ok {
-			t.Errorf("Expected *unknownFieldError for policy with invalid keys but got %T (%s)", err, err)
-		}
+	ctx, err := pdp.NewContext(nil, 1, func(i int) (string, pdp.AttributeValue, error) {
+		return "groups", pdp.MakeListOfStringsValue([]string{"users", "admin", "ops"}), nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
 	}
 
-	s, err := p.Unmarshal(strings.NewReader(simpleAllPermitPolicy), nil)
+	r := s.Root().Calculate(ctx)
+	if r.Effect != pdp.EffectPermit {
+		t.Fatalf("Expected %s but got %s (%s)", pdp.EffectNameFromEnum(pdp.EffectPermit),
+			pdp.EffectNameFromEnum(r.Effect), r.Status)
+	}
+
+	if len(r.Obligations) != 1 {
+		t.Fatalf("Expected single obligation but got %d", len(r.Obligations))
+	}
+
+	_, _, o, err := r.Obligations[0].Serialize(ctx)
 	if err != nil {
 		t.Errorf("Expected no error but got %T (%s)", err, err)
-	} else {
-		p, ok := s.Root().(*pdp.Policy)
-		if !ok {
-			t.Errorf("Expected policy as root item in Simple All Permit Policy but got %T", p)
-		} else {
-			PID, ok := p.GetID()
-			if !ok {
-				t.Errorf("Expected %q as Simple All Permit Policy ID but got hidden policy", "Default")
-			} else if PID != "Default" {
-				t.Errorf("Expected %q as Simple All Permit Policy ID but got %q", "Default", PID)
-			}
-		}
+	} else if e := "\"USERS\",\"OPS\""; o != e {
+		t.Errorf("Expected %q but got %q", e, o)
+	}
 
-		r := s.Root().Calculate(&pdp.Context{})
-		if r.Effect != pdp.EffectPermit {
-			t.Errorf("Expected permit as a response for Simple All Permit Policy but got %d", r.Effect)
-		}
+	_, err = p.Unmarshal(strings.NewReader(misplacedQuantifierVariablePolicy), nil)
+	if err == nil {
+		t.Errorf("Expected error for misplaced iteration variable but got nothing")
+	} else if !strings.Contains(err.Error(), "placed after 'expr'") {
+		t.Errorf("Expected misplaced iteration variable error but got %T (%s)", err, err)
 	}
+}
 
-	s, err = p.Unmarshal(strings.NewReader(allFeaturePolicies), nil)
+func TestVariables(t *testing.T) {
+	p := Parser{}
+	tag := uuid.New()
+	s, err := p.Unmarshal(strings.NewReader(variablesPolicy), &tag)
 	if err != nil {
-		t.Errorf("Expected no error but got %T (%s)", err, err)
-	} else {
-		ctx, err := pdp.NewContext(nil, 5, func(i int) (string, pdp.AttributeValue, error) {
-			switch i {
-			case 0:
-				v, err := pdp.MakeValueFromString(pdp.TypeBoolean, "true")
-				if err != nil {
-					return "", pdp.UndefinedValue, err
-				}
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
 
-				return "boolAttr", v, nil
+	assertVariablesPolicy(t, s, "admin", 7, pdp.EffectPermit, "high")
+	assertVariablesPolicy(t, s, "user", 7, pdp.EffectNotApplicable, "")
 
-			case 1:
-				v, err := pdp.MakeValueFromString(pdp.TypeString, "string")
-				if err != nil {
-					return "", pdp.UndefinedValue, err
-				}
+	tr, err := s.NewTransaction(&tag)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
 
-				return "strAttr", v, nil
+	u, err := p.UnmarshalUpdate(strings.NewReader(variablesUpdate), tr.Symbols(), tag, uuid.New())
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
 
-			case 2:
-				v, err := pdp.MakeValueFromString(pdp.TypeAddress, "192.0.2.1")
-				if err != nil {
-					return "", pdp.UndefinedValue, err
-				}
+	if err := tr.Apply(u); err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
 
-				return "addrAttr", v, nil
+	s, err = tr.Commit()
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
 
-			case 3:
-				v, err := pdp.MakeValueFromString(pdp.TypeNetwork, "192.0.2.0/24")
-				if err != nil {
-					return "", pdp.UndefinedValue, err
-				}
+	assertVariablesPolicy(t, s, "admin", 7, pdp.EffectPermit, "high")
+	assertVariablesPolicy(t, s, "user", 7, pdp.EffectDeny, "low")
 
-				return "netAttr", v, nil
+	_, err = p.Unmarshal(strings.NewReader(misplacedVariablesPolicy), nil)
+	if err == nil {
+		t.Errorf("Expected error for misplaced variables but got nothing")
+	} else if !strings.Contains(err.Error(), "'variables' attribute is placed after") {
+		t.Errorf("Expected misplaced variables error but got %T (%s)", err, err)
+	}
+}
 
-			case 4:
-				v, err := pdp.MakeValueFromString(pdp.TypeString, "example.com")
-				if err != nil {
-					return "", pdp.UndefinedValue, err
-				}
+func TestVariablesUpdates(t *testing.T) {
+	p := Parser{}
+	tag := uuid.New()
+	s, err := p.Unmarshal(strings.NewReader(variablesPolicy), &tag)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
 
-				return "domAttr", v, nil
-			}
+	s, tag = applyVariablesUpdate(t, s, tag, variablesPolicyUpdate)
+	s, tag = applyVariablesUpdate(t, s, tag, variablesRuleUpdate)
+	assertVariablesPolicy(t, s, "user", 2, pdp.EffectDeny, "")
 
-			return "", pdp.UndefinedValue, fmt.Errorf("no attribute for index %d", i)
-		})
+	s, tag = applyVariablesUpdate(t, s, tag, variablesReplaceUpdate)
+	if _, ok := s.GetSymbols().GetVariable([]string{"Root", "Extra"}, "low"); ok {
+		t.Errorf("Expected no variable of replaced policy")
+	}
+
+	_, err = p.UnmarshalUpdate(strings.NewReader(variablesRuleUpdate), s.GetSymbols(), tag, uuid.New())
+	if err == nil {
+		t.Errorf("Expected error for variable of replaced policy but got nothing")
+	} else if !strings.Contains(err.Error(), "low") {
+		t.Errorf("Expected unknown variable error but got %T (%s)", err, err)
+	}
+}
+
+func applyVariablesUpdate(t *testing.T, s *pdp.PolicyStorage, tag uuid.UUID, in string) (*pdp.PolicyStorage, uuid.UUID) {
+	tr, err := s.NewTransaction(&tag)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	newTag := uuid.New()
+	u, err := Parser{}.UnmarshalUpdate(strings.NewReader(in), tr.Symbols(), tag, newTag)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	if err := tr.Apply(u); err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	s, err = tr.Commit()
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	return s, newTag
+}
+
+func assertVariablesPolicy(t *testing.T, s *pdp.PolicyStorage, role string, level int64, effect int, o string) {
+	ctx, err := pdp.NewContext(nil, 2, func(i int) (string, pdp.AttributeValue, error) {
+		if i > 0 {
+			return "level", pdp.MakeIntegerValue(level), nil
+		}
+
+		return "role", pdp.MakeStringValue(role), nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	r := s.Root().Calculate(ctx)
+	if r.Effect != effect {
+		t.Errorf("Expected %s for %q but got %s (%s)", pdp.EffectNameFromEnum(effect),
+			role, pdp.EffectNameFromEnum(r.Effect), r.Status)
+		return
+	}
+
+	if len(o) > 0 {
+		if len(r.Obligations) != 1 {
+			t.Errorf("Expected single obligation for %q but got %d", role, len(r.Obligations))
+			return
+		}
+
+		_, _, v, err := r.Obligations[0].Serialize(ctx)
 		if err != nil {
 			t.Errorf("Expected no error but got %T (%s)", err, err)
-		} else {
-			r := s.Root().Calculate(ctx)
-			effect, o, err := r.Effect, r.Obligations, r.Status
-			if effect != pdp.EffectDeny {
-				if err != nil {
-					t.Errorf("Expected deny as a response for Simple All Permit Policy but got %d (%s)", effect, err)
-				} else {
-					t.Errorf("Expected deny as a response for Simple All Permit Policy but got %d", effect)
-				}
-			}
-
-			if len(o) < 1 {
-				t.Error("Expected at least one obligation")
-			} else {
-				_, _, v, err := o[0].Serialize(ctx)
-				if err != nil {
-					t.Errorf("Expected no error but got %T (%s)", err, err)
-				} else {
-					e := "Nested Mappers Policy Set Deny"
-					if v != e {
-						t.Errorf("Expected %q but got %q", e, v)
-					}
-				}
-			}
+		} else if v != o {
+			t.Errorf("Expected %q for %q but got %q", o, role, v)
 		}
 	}
 }
 
-func TestMissingSubAlg(t *testing.T) {
+func TestDateTimeFunctions(t *testing.T) {
 	p := Parser{}
-	_, err := p.Unmarshal(strings.NewReader(missingSubAlgPCAPolicy), nil)
-	if err == nil {
-		t.Errorf("expected *missingPCAError but got no error")
-	} else if _, ok := err.(*missingPCAError); !ok {
-		t.Errorf("expected *missingPCAError but got %T: %s", err, err)
+	s, err := p.Unmarshal(strings.NewReader(dateTimePolicy), nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
 	}
 
-	p = Parser{}
-	_, err = p.Unmarshal(strings.NewReader(missingSubAlgRCAPolicy), nil)
+	now := time.Date(2019, 1, 4, 17, 40, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		issued time.Time
+		now    time.Time
+		effect int
+	}{
+		{now.Add(-10 * time.Minute), now, pdp.EffectPermit},
+		{now.Add(-20 * time.Minute), now, pdp.EffectNotApplicable},
+		{now.Add(-10 * time.Minute), now.Add(-10 * time.Hour), pdp.EffectNotApplicable},
+		{time.Date(2018, 12, 31, 23, 59, 0, 0, time.UTC), now, pdp.EffectNotApplicable},
+	} {
+		ctx, err := pdp.NewContext(nil, 1, func(i int) (string, pdp.AttributeValue, error) {
+			return "issued", pdp.MakeDateTimeValue(tc.issued), nil
+		})
+		if err != nil {
+			t.Fatalf("Expected no error but got %T (%s)", err, err)
+		}
+
+		n := tc.now
+		ctx.SetClock(func() time.Time { return n })
+
+		r := s.Root().Calculate(ctx)
+		if r.Effect != tc.effect {
+			t.Errorf("Expected %s for %s issued at %s but got %s (%s)", pdp.EffectNameFromEnum(tc.effect),
+				tc.now, tc.issued, pdp.EffectNameFromEnum(r.Effect), r.Status)
+		}
+
+		if r.Effect == pdp.EffectPermit {
+			if len(r.Obligations) != 1 {
+				t.Errorf("Expected single obligation but got %d", len(r.Obligations))
+				continue
+			}
+
+			_, _, o, err := r.Obligations[0].Serialize(ctx)
+			if err != nil {
+				t.Errorf("Expected no error but got %T (%s)", err, err)
+			} else if o != "Friday" {
+				t.Errorf("Expected %q but got %q", "Friday", o)
+			}
+		}
+	}
+
+	_, err = p.Unmarshal(strings.NewReader(invalidTimeZonePolicy), nil)
 	if err == nil {
-		t.Errorf("expected *missingRCAError but got no error")
-	} else if _, ok := err.(*missingRCAError); !ok {
-		t.Errorf("expected *missingRCAError but got %T: %s", err, err)
+		t.Errorf("Expected error for invalid time zone but got nothing")
+	} else if !strings.Contains(err.Error(), "time zone") {
+		t.Errorf("Expected time zone error but got %T (%s)", err, err)
 	}
 }
 
@@ -2704,3 +3754,140 @@ func newStringContext(m map[string]string) (*pdp.Context, error) {
 		return n, pdp.MakeStringValue(v), nil
 	})
 }
+
+const (
+	referencesPolicy = `{
+  "include": ["users.json"],
+  "policies": {
+    "id": "Root",
+    "alg": "FirstApplicableEffect",
+    "policies": [
+      {"ref": "Users"}
+    ]
+  }
+}`
+
+	referenceWithFieldsPolicy = `{
+  "policies": {
+    "id": "Root",
+    "alg": "FirstApplicableEffect",
+    "policies": [
+      {"ref": "Users", "alg": "FirstApplicableEffect"}
+    ]
+  }
+}`
+
+	referenceUpdate = `[
+  {
+    "op": "add",
+    "path": ["Root"],
+    "entity": {"ref": "Users"}
+  }
+]`
+)
+
+func TestPolicyReferences(t *testing.T) {
+	p := Parser{}
+
+	includes, err := p.GetIncludes(strings.NewReader(referencesPolicy))
+	if err != nil {
+		t.Errorf("Expected no error but got %T (%s)", err, err)
+	} else if len(includes) != 1 || includes[0] != "users.json" {
+		t.Errorf("Expected %q as includes but got %q", []string{"users.json"}, includes)
+	}
+
+	s, err := p.UnmarshalDocument(strings.NewReader(referencesPolicy), pdp.MakeSymbols())
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	assertVariablesPolicy(t, s, "user", 0, pdp.EffectIndeterminate, "")
+
+	_, err = p.Unmarshal(strings.NewReader(referencesPolicy), nil)
+	if err == nil {
+		t.Errorf("Expected error for include in single document but got nothing")
+	} else if _, ok := err.(*notLinkedDocumentError); !ok {
+		t.Errorf("Expected *notLinkedDocumentError but got %T (%s)", err, err)
+	}
+
+	_, err = p.UnmarshalDocument(strings.NewReader(referenceWithFieldsPolicy), pdp.MakeSymbols())
+	if err == nil {
+		t.Errorf("Expected error for reference with extra fields but got nothing")
+	} else if !strings.Contains(err.Error(), "other than") {
+		t.Errorf("Expected policy reference fields error but got %T (%s)", err, err)
+	}
+
+	_, err = p.UnmarshalUpdate(strings.NewReader(referenceUpdate), pdp.MakeSymbols(), uuid.New(), uuid.New())
+	if err == nil {
+		t.Errorf("Expected error for reference in update but got nothing")
+	} else if !strings.Contains(err.Error(), "allowed only") {
+		t.Errorf("Expected not linked document error but got %T (%s)", err, err)
+	}
+}
+
+const (
+	positionsPolicy = `{
+  "attributes": {
+    "x": "string"
+  },
+  "policies": {
+    "id": "Root",
+    "alg": "FirstApplicableEffect",
+    "rules": [
+      {
+        "effect": "Permit",
+        "obligations": [
+          {"x": {"val": {"type": "string", "content": 1}}}
+        ]
+      }
+    ]
+  }
+}`
+
+	positionsUpdate = `[
+  {
+    "op": "some",
+    "path": ["Root"]
+  }
+]`
+)
+
+func TestErrorPositions(t *testing.T) {
+	p := Parser{}
+
+	_, err := p.Unmarshal(strings.NewReader(positionsPolicy), nil)
+	assertErrorPosition(t, "value", err, pdp.SourcePosition{Line: 12, Column: 56})
+
+	_, err = p.UnmarshalUpdate(strings.NewReader(positionsUpdate), pdp.MakeSymbols(), uuid.New(), uuid.New())
+	assertErrorPosition(t, "update", err, pdp.SourcePosition{Line: 3, Column: 17})
+
+	_, err = p.Unmarshal(strings.NewReader(invalidJSON), nil)
+	if _, ok := pdp.GetErrorPosition(err); err == nil || !ok {
+		t.Errorf("Expected JSON syntax error with position but got %v", err)
+	}
+}
+
+func assertErrorPosition(t *testing.T, desc string, err error, e pdp.SourcePosition) {
+	if err == nil {
+		t.Errorf("Expected %s error but got nothing", desc)
+		return
+	}
+
+	pos, ok := pdp.GetErrorPosition(err)
+	if !ok {
+		t.Errorf("Expected position of %s error but got nothing (%s)", desc, err)
+		return
+	}
+
+	if pos != e {
+		t.Errorf("Expected %s error at %s but got %s (%s)", desc, e, pos, err)
+	}
+
+	if !pdp.SetErrorSourceFile(err, "test.json") {
+		t.Errorf("Expected file name set to %s error", desc)
+	}
+
+	if s := err.Error(); !strings.HasPrefix(s, "test.json:"+e.String()+": ") {
+		t.Errorf("Expected %s error starting with %q but got %q", desc, "test.json:"+e.String(), s)
+	}
+}
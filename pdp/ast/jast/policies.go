@@ -43,6 +43,8 @@ func (ctx *context) unmarshalEvaluable(d *json.Decoder) (pdp.Evaluable, error) {
 		hidden      = true
 		isPolicy    bool
 		isPolicySet bool
+		isRef       bool
+		fields      int
 
 		pid      string
 		policies []pdp.Evaluable
@@ -50,49 +52,86 @@ func (ctx *context) unmarshalEvaluable(d *json.Decoder) (pdp.Evaluable, error) {
 		target   pdp.Target
 		obligs   []pdp.AttributeAssignment
 		alg      interface{}
+
+		// Policy content is parsed with own context which gets policy ID
+		// and variables. ID placed after content hides the policy's
+		// variables from updates.
+		pctx *context
 	)
 
+	enter := func() {
+		if pctx == nil {
+			pctx = ctx.enterPolicy(pid, hidden)
+		}
+	}
+
 	if err := jparser.UnmarshalObject(d, func(k string, d *json.Decoder) error {
 		var err error
 
+		fields++
 		switch strings.ToLower(k) {
+		case yastTagRef:
+			if !ctx.linked {
+				return newNotLinkedDocumentError(k)
+			}
+
+			isRef = true
+			pid, err = jparser.GetString(d, "policy reference")
+			return err
+
 		case yastTagID:
 			hidden = false
 			pid, err = jparser.GetString(d, "policy or policy set id")
 			return err
 
+		case yastTagVariables:
+			if pctx != nil {
+				return bindError(newMisplacedVariablesError(), makeSource("policy or policy set", pid, hidden))
+			}
+
+			enter()
+			if err := pctx.unmarshalVariables(d); err != nil {
+				return bindError(err, makeSource("policy or policy set", pid, hidden))
+			}
+			return nil
+
 		case yastTagAlg:
-			alg, err = ctx.unmarshalCombiningAlg(d)
+			enter()
+			alg, err = pctx.unmarshalCombiningAlg(d)
 			if err != nil {
 				return bindError(err, makeSource("policy or policy set", pid, hidden))
 			}
 			return err
 
 		case yastTagTarget:
-			target, err = ctx.unmarshalTarget(d)
+			enter()
+			target, err = pctx.unmarshalTarget(d)
 			if err != nil {
 				return bindError(err, makeSource("policy or policy set", pid, hidden))
 			}
 			return nil
 
 		case yastTagObligation:
-			obligs, err = ctx.unmarshalObligations(d)
+			enter()
+			obligs, err = pctx.unmarshalObligations(d)
 			if err != nil {
 				return bindError(err, makeSource("policy or policy set", pid, hidden))
 			}
 			return nil
 
 		case yastTagPolicies:
+			enter()
 			isPolicySet = true
-			policies, err = ctx.unmarshalPolicies(d)
+			policies, err = pctx.unmarshalPolicies(d)
 			if err != nil {
 				return bindError(err, makeSource("policy set", pid, hidden))
 			}
 			return nil
 
 		case yastTagRules:
+			enter()
 			isPolicy = true
-			rules, err = ctx.unmarshalRules(d)
+			rules, err = pctx.unmarshalRules(d)
 			if err != nil {
 				return bindError(err, makeSource("policy", pid, hidden))
 			}
@@ -104,6 +143,14 @@ func (ctx *context) unmarshalEvaluable(d *json.Decoder) (pdp.Evaluable, error) {
 		return nil, err
 	}
 
+	if isRef {
+		if fields > 1 {
+			return nil, newPolicyReferenceFieldsError()
+		}
+
+		return pdp.NewPolicyReference(pid), nil
+	}
+
 	if isPolicy && isPolicySet {
 		return nil, newPolicyAmbiguityError()
 	}
@@ -88,7 +88,12 @@ func (ctx context) unmarshalTargetMatchExpression(id string, d *json.Decoder) (p
 		return nil, newMatchFunctionCastError(id, firstType, secondType)
 	}
 
-	return maker(first, second), nil
+	e := maker(first, second)
+	if err := pdp.ValidateExpression(e); err != nil {
+		return nil, bindError(err, id)
+	}
+
+	return e, nil
 }
 
 func (ctx context) unmarshalTargetAllOfItem(d *json.Decoder) (pdp.Match, error) {
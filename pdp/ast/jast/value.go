@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/infobloxopen/themis/jparser"
 	"github.com/infobloxopen/themis/pdp"
@@ -87,6 +88,34 @@ func (ctx context) unmarshalDomainValue(d *json.Decoder) (pdp.AttributeValue, er
 	return pdp.MakeDomainValue(dom), nil
 }
 
+func (ctx context) unmarshalDateTimeValue(d *json.Decoder) (pdp.AttributeValue, error) {
+	s, err := jparser.GetString(d, "value of datetime type")
+	if err != nil {
+		return pdp.UndefinedValue, err
+	}
+
+	t, ierr := time.Parse(time.RFC3339Nano, s)
+	if ierr != nil {
+		return pdp.UndefinedValue, newInvalidDateTimeError(s, ierr)
+	}
+
+	return pdp.MakeDateTimeValue(t), nil
+}
+
+func (ctx context) unmarshalDurationValue(d *json.Decoder) (pdp.AttributeValue, error) {
+	s, err := jparser.GetString(d, "value of duration type")
+	if err != nil {
+		return pdp.UndefinedValue, err
+	}
+
+	x, ierr := time.ParseDuration(s)
+	if ierr != nil {
+		return pdp.UndefinedValue, newInvalidDurationError(s, ierr)
+	}
+
+	return pdp.MakeDurationValue(x), nil
+}
+
 func (ctx context) unmarshalSetOfStringsValue(d *json.Decoder) (pdp.AttributeValue, error) {
 	set := strtree.NewTree()
 	if err := jparser.GetStringSequence(d, func(idx int, s string) error {
@@ -286,6 +315,12 @@ func (ctx context) unmarshalValueByType(t pdp.Type, d *json.Decoder) (pdp.Attrib
 
 	case pdp.TypeListOfStrings:
 		return ctx.unmarshalListOfStringsValue(d)
+
+	case pdp.TypeDateTime:
+		return ctx.unmarshalDateTimeValue(d)
+
+	case pdp.TypeDuration:
+		return ctx.unmarshalDurationValue(d)
 	}
 
 	return pdp.UndefinedValue, newNotImplementedValueTypeError(t)
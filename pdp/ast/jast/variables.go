@@ -0,0 +1,89 @@
+package jast
+
+import (
+	"encoding/json"
+
+	"github.com/infobloxopen/themis/jparser"
+	"github.com/infobloxopen/themis/pdp"
+)
+
+// enterPolicy returns context for content of policy set or policy with
+// given ID.
+func (ctx *context) enterPolicy(ID string, hidden bool) *context {
+	c := *ctx
+	if hidden {
+		c.hidden = true
+		return &c
+	}
+
+	c.path = make([]string, len(ctx.path)+1)
+	copy(c.path, ctx.path)
+	c.path[len(ctx.path)] = ID
+
+	return &c
+}
+
+// unmarshalVariables parses variables section and makes the variables
+// visible in the context. Variable can refer only variables defined before
+// it.
+func (ctx *context) unmarshalVariables(d *json.Decoder) error {
+	if err := jparser.CheckObjectStart(d, "variables"); err != nil {
+		return err
+	}
+
+	s := make(map[string]*pdp.Variable)
+
+	vars := make([]map[string]*pdp.Variable, len(ctx.vars)+1)
+	copy(vars, ctx.vars)
+	vars[len(ctx.vars)] = s
+	ctx.vars = vars
+	ctx.its = nil
+
+	return jparser.UnmarshalObject(d, func(k string, d *json.Decoder) error {
+		if _, ok := s[k]; ok {
+			return newDuplicateVariableError(k)
+		}
+
+		if err := jparser.CheckObjectStart(d, "variable"); err != nil {
+			return bindError(err, k)
+		}
+
+		e, err := ctx.unmarshalExpression(d)
+		if err != nil {
+			return bindError(err, k)
+		}
+
+		v := pdp.NewVariable(k, e)
+		s[k] = v
+
+		if ctx.putVars && !ctx.hidden {
+			symbols := ctx.symbols
+			if ctx.cmdVars != nil {
+				symbols = *ctx.cmdVars
+			}
+
+			if err := symbols.PutVariable(ctx.path, v); err != nil {
+				return bindError(err, k)
+			}
+		}
+
+		return nil
+	}, "variables")
+}
+
+// lookupVariable finds variable visible in current context. Inner variables
+// hide outer ones and variables of policies being parsed hide variables of
+// existing policies which come from symbol table.
+func (ctx context) lookupVariable(ID string) (pdp.Expression, error) {
+	for i := len(ctx.vars) - 1; i >= 0; i-- {
+		if v, ok := ctx.vars[i][ID]; ok {
+			return v, nil
+		}
+	}
+
+	if v, ok := ctx.symbols.GetVariable(ctx.path, ID); ok {
+		return v, nil
+	}
+
+	return nil, newUnknownVariableError(ID)
+}
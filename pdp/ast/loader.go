@@ -0,0 +1,148 @@
+package ast
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/infobloxopen/themis/pdp"
+)
+
+// LoadPolicies reads policy documents from given files and directories
+// (directory stands for all its regular files in lexical order except
+// files which names start with dot), parses them with given parser and
+// links them with pdp.LinkPolicies. Documents listed in "include" section
+// of a document are loaded before the document itself. Relative paths of
+// included documents are resolved against directory of the including
+// document. Each document is loaded only once regardless of how many times
+// it's mentioned. Resulting policy storage gets given tag.
+func LoadPolicies(p Parser, paths []string, tag *uuid.UUID) (*pdp.PolicyStorage, error) {
+	l := &policyLoader{
+		p:      p,
+		s:      pdp.MakeSymbols(),
+		loaded: make(map[string]bool),
+		busy:   make(map[string]bool),
+	}
+
+	for _, path := range paths {
+		files, err := expandPolicyPath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			if err := l.load(file, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return pdp.LinkPolicies(l.docs, tag)
+}
+
+type policyLoader struct {
+	p      Parser
+	s      pdp.Symbols
+	docs   []pdp.PolicyDocument
+	loaded map[string]bool
+	busy   map[string]bool
+}
+
+// load parses document at given path after documents it includes. Chain
+// is a list of documents which include the document directly or indirectly.
+func (l *policyLoader) load(path string, chain []string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return bindError(err, path)
+	}
+
+	chain = append(chain[:len(chain):len(chain)], path)
+	if l.busy[abs] {
+		return newIncludeCycleError(strings.Join(chain, " -> "))
+	}
+
+	if l.loaded[abs] {
+		return nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return bindError(err, path)
+	}
+
+	includes, err := l.p.GetIncludes(bytes.NewReader(b))
+	if err != nil {
+		return bindParserError(err, path)
+	}
+
+	l.busy[abs] = true
+	for _, include := range includes {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+
+		if err := l.load(include, chain); err != nil {
+			return err
+		}
+	}
+	delete(l.busy, abs)
+
+	s, err := l.p.UnmarshalDocument(bytes.NewReader(b), pdp.MakeDocumentSymbols(l.s))
+	if err != nil {
+		return bindParserError(err, path)
+	}
+
+	l.loaded[abs] = true
+	l.docs = append(l.docs, pdp.PolicyDocument{
+		Name:    path,
+		Storage: s,
+	})
+
+	return nil
+}
+
+// bindParserError adds given path to error returned by parser. Error which
+// knows its position in the document gets the path as file name of
+// the position.
+func bindParserError(err error, path string) error {
+	if pdp.SetErrorSourceFile(err, path) {
+		return err
+	}
+
+	return bindError(err, path)
+}
+
+func expandPolicyPath(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, bindError(err, path)
+	}
+
+	if info.Mode().IsRegular() {
+		return []string{path}, nil
+	}
+
+	if !info.IsDir() {
+		return nil, newPolicyPathError(path)
+	}
+
+	infos, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, bindError(err, path)
+	}
+
+	files := []string{}
+	for _, info := range infos {
+		if info.Mode().IsRegular() && !strings.HasPrefix(info.Name(), ".") {
+			files = append(files, filepath.Join(path, info.Name()))
+		}
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
@@ -0,0 +1,197 @@
+package ast
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/infobloxopen/themis/pdp"
+)
+
+const (
+	loaderTestRoot = `# Root document
+include:
+- common/attributes.yaml
+- admin.yaml
+
+policies:
+  id: Root
+  alg: FirstApplicableEffect
+  policies:
+  - ref: Admin
+  - ref: Users
+`
+
+	loaderTestAttributes = `# Attributes shared by all documents
+attributes:
+  role: string
+  r: string
+`
+
+	loaderTestAdmin = `# Admin policy
+include:
+- common/attributes.yaml
+
+variables:
+  isAdmin:
+    equal:
+    - attr: role
+    - val:
+        type: string
+        content: admin
+
+policies:
+  id: Admin
+  alg: FirstApplicableEffect
+  rules:
+  - condition:
+      var: isAdmin
+    effect: Permit
+    obligations:
+    - r:
+        val:
+          type: string
+          content: admin
+`
+
+	loaderTestUsers = `# Users policy
+policies:
+  id: Users
+  alg: FirstApplicableEffect
+  rules:
+  - effect: Deny
+    obligations:
+    - r:
+        attr: role
+`
+
+	loaderTestBad = `# Policy with unknown algorithm
+policies:
+  id: Bad
+  rules: []
+  alg: Unknown
+`
+
+	loaderTestCycle = `# Document which includes itself via other one
+include:
+- cycle-include.yaml
+`
+
+	loaderTestCycleInclude = `# Document which includes other one back
+include:
+- cycle.yaml
+`
+)
+
+func TestLoadPolicies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "themis-loader")
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeLoaderTestFile(t, filepath.Join(dir, "policies", "root.yaml"), loaderTestRoot)
+	writeLoaderTestFile(t, filepath.Join(dir, "policies", "admin.yaml"), loaderTestAdmin)
+	writeLoaderTestFile(t, filepath.Join(dir, "policies", "common", "attributes.yaml"), loaderTestAttributes)
+	writeLoaderTestFile(t, filepath.Join(dir, "policies", ".hidden.yaml"), "not a policy: [")
+	writeLoaderTestFile(t, filepath.Join(dir, "users.yaml"), loaderTestUsers)
+	writeLoaderTestFile(t, filepath.Join(dir, "cycle", "cycle.yaml"), loaderTestCycle)
+	writeLoaderTestFile(t, filepath.Join(dir, "cycle", "cycle-include.yaml"), loaderTestCycleInclude)
+
+	tag := uuid.New()
+	s, err := LoadPolicies(NewYAMLParser(), []string{
+		filepath.Join(dir, "policies"),
+		filepath.Join(dir, "users.yaml"),
+	}, &tag)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if err := s.CheckTag(&tag); err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	}
+
+	assertLoadedPolicies(t, s, "admin", pdp.EffectPermit, "admin")
+	assertLoadedPolicies(t, s, "user", pdp.EffectDeny, "user")
+
+	tr, err := s.NewTransaction(&tag)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if _, ok := tr.Symbols().GetVariable([]string{"Root", "Admin"}, "isAdmin"); !ok {
+		t.Errorf("Expected variable %q at %q", "isAdmin", "Root/Admin")
+	}
+
+	_, err = LoadPolicies(NewYAMLParser(), []string{filepath.Join(dir, "policies")}, nil)
+	if err == nil {
+		t.Errorf("Expected error for missing document but got nothing")
+	} else if !strings.Contains(err.Error(), "Users") {
+		t.Errorf("Expected unknown reference error but got %s", err)
+	}
+
+	_, err = LoadPolicies(NewYAMLParser(), []string{filepath.Join(dir, "cycle", "cycle.yaml")}, nil)
+	if err == nil {
+		t.Errorf("Expected error for include cycle but got nothing")
+	} else if _, ok := err.(*includeCycleError); !ok {
+		t.Errorf("Expected *includeCycleError but got %T (%s)", err, err)
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	writeLoaderTestFile(t, bad, loaderTestBad)
+	_, err = LoadPolicies(NewYAMLParser(), []string{bad}, nil)
+	if err == nil {
+		t.Errorf("Expected error for invalid document but got nothing")
+	} else if pos, ok := pdp.GetErrorPosition(err); !ok {
+		t.Errorf("Expected error with position but got %T (%s)", err, err)
+	} else if e := (pdp.SourcePosition{File: bad, Line: 5, Column: 8}); pos != e {
+		t.Errorf("Expected error at %s but got %s (%s)", e, pos, err)
+	}
+
+	_, err = LoadPolicies(NewYAMLParser(), []string{filepath.Join(dir, "missing.yaml")}, nil)
+	if err == nil {
+		t.Errorf("Expected error for missing file but got nothing")
+	}
+}
+
+func writeLoaderTestFile(t *testing.T, path, content string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+}
+
+func assertLoadedPolicies(t *testing.T, s *pdp.PolicyStorage, role string, effect int, o string) {
+	ctx, err := pdp.NewContext(nil, 1, func(i int) (string, pdp.AttributeValue, error) {
+		return "role", pdp.MakeStringValue(role), nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	r := s.Root().Calculate(ctx)
+	if r.Effect != effect {
+		t.Errorf("Expected %s for %q but got %s (%s)", pdp.EffectNameFromEnum(effect),
+			role, pdp.EffectNameFromEnum(r.Effect), r.Status)
+		return
+	}
+
+	if len(r.Obligations) != 1 {
+		t.Errorf("Expected single obligation for %q but got %d", role, len(r.Obligations))
+		return
+	}
+
+	_, _, v, err := r.Obligations[0].Serialize(ctx)
+	if err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	} else if v != o {
+		t.Errorf("Expected %q for %q but got %q", o, role, v)
+	}
+}
@@ -25,6 +25,16 @@ type Parser interface {
 	// policies tag to make update applicable. Value of newTag is set to policies
 	// when update is applied.
 	UnmarshalUpdate(in io.Reader, s pdp.Symbols, oldTag, newTag uuid.UUID) (*pdp.PolicyUpdate, error)
+
+	// UnmarshalDocument parses single policy document which can include
+	// other documents and refer their root policies. It puts types,
+	// attributes and variables to given symbol tables. Such documents should
+	// be linked with pdp.LinkPolicies (see LoadPolicies).
+	UnmarshalDocument(in io.Reader, s pdp.Symbols) (*pdp.PolicyStorage, error)
+
+	// GetIncludes returns paths of documents included by given policy
+	// document.
+	GetIncludes(in io.Reader) ([]string, error)
 }
 
 // NewJSONParser is a JSON AST parser constructor.
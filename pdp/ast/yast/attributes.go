@@ -34,7 +34,7 @@ func (ctx *context) unmarshalAttributeDeclarations(m map[interface{}]interface{}
 	for k, v := range attrs {
 		err = ctx.unmarshalAttributeDeclaration(k, v)
 		if err != nil {
-			return bindError(err, yastTagAttributes)
+			return bindError(ctx.locateKey(err, attrs, k), yastTagAttributes)
 		}
 	}
 
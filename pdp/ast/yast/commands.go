@@ -12,6 +12,10 @@ func (ctx *context) unmarshalEntity(m map[interface{}]interface{}) (interface{},
 		return nil, err
 	}
 
+	if _, ok := m[yastTagRef]; ok {
+		return nil, ctx.locateKey(newNotLinkedDocumentError(yastTagRef), m, yastTagRef)
+	}
+
 	ID, okID, err := ctx.extractStringOpt(m, yastTagID, "policy or set or rule id")
 	if err != nil {
 		return nil, err
@@ -36,22 +40,33 @@ func (ctx *context) unmarshalEntity(m map[interface{}]interface{}) (interface{},
 			tags = append(tags, yastTagEffect)
 		}
 
-		return nil, bindError(newEntityAmbiguityError(tags), src)
+		return nil, bindError(ctx.locate(newEntityAmbiguityError(tags), m), src)
 	}
 
-	if rOk {
-		return ctx.unmarshalPolicy(m, 0, ID, !okID, rules)
-	}
+	var (
+		e    interface{}
+		eErr boundError
+	)
+
+	switch {
+	case rOk:
+		e, eErr = ctx.unmarshalPolicy(m, 0, ID, !okID, rules)
+
+	case pOk:
+		e, eErr = ctx.unmarshalPolicySet(m, 0, ID, !okID, policies)
+
+	case eOk:
+		e, eErr = ctx.unmarshalRuleEntity(m, ID, !okID, effect)
 
-	if pOk {
-		return ctx.unmarshalPolicySet(m, 0, ID, !okID, policies)
+	default:
+		eErr = bindError(newEntityMissingKeyError(), src)
 	}
 
-	if eOk {
-		return ctx.unmarshalRuleEntity(m, ID, !okID, effect)
+	if eErr != nil {
+		return nil, ctx.locate(eErr, m)
 	}
 
-	return nil, bindError(newEntityMissingKeyError(), src)
+	return e, nil
 }
 
 func (ctx *context) unmarshalCommand(v interface{}, u *pdp.PolicyUpdate) error {
@@ -67,7 +82,7 @@ func (ctx *context) unmarshalCommand(v interface{}, u *pdp.PolicyUpdate) error {
 
 	op, ok := pdp.UpdateOpIDs[strings.ToLower(s)]
 	if !ok {
-		return newUnknownPolicyUpdateOperationError(s)
+		return ctx.locateKey(newUnknownPolicyUpdateOperationError(s), m, yastTagOp)
 	}
 
 	lst, err := ctx.extractList(m, yastTagPath, "path")
@@ -79,19 +94,28 @@ func (ctx *context) unmarshalCommand(v interface{}, u *pdp.PolicyUpdate) error {
 	for i, item := range lst {
 		s, ok := item.(string)
 		if !ok {
-			return newInvalidPolicyUpdatePathElementError(item, i+1)
+			return ctx.locateItem(newInvalidPolicyUpdatePathElementError(item, i+1), lst, i)
 		}
 
 		path[i] = s
 	}
 
 	if op == pdp.UOAdd {
-		entity, err := ctx.unmarshalEntity(m)
+		// Entity can refer variables visible at the path. Its own
+		// variables are collected separately and get to symbol tables
+		// only when the update is applied.
+		vars := pdp.MakeDocumentSymbols(ctx.symbols)
+		c := *ctx
+		c.path = path
+		c.putVars = true
+		c.cmdVars = &vars
+
+		entity, err := c.unmarshalEntity(m)
 		if err != nil {
 			return err
 		}
 
-		u.Append(op, path, entity)
+		u.AppendWithVariables(op, path, entity, vars)
 	} else {
 		u.Append(op, path, nil)
 	}
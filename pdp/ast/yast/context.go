@@ -8,6 +8,18 @@ import (
 
 type context struct {
 	symbols pdp.Symbols
+	its     []pdp.IterationVariable
+	pos     *positions
+
+	scopes  []*variableScope
+	path    []string
+	hidden  bool
+	putVars bool
+	linked  bool
+
+	// cmdVars collects variables of entity of policy update command.
+	// Variables of policy document go to symbols.
+	cmdVars *pdp.Symbols
 }
 
 func newContext() *context {
@@ -22,6 +34,42 @@ func newContextWithSymbols(symbols pdp.Symbols) *context {
 	}
 }
 
+// locate sets position of given map or list to the error.
+func (ctx context) locate(err boundError, v interface{}) boundError {
+	if ctx.pos != nil {
+		if pos, ok := ctx.pos.get(v); ok {
+			err.locate(pos)
+		}
+	}
+
+	return err
+}
+
+// locateKey sets position of value of given map at given key to the error.
+// It falls back to position of the map if there is no such key.
+func (ctx context) locateKey(err boundError, m map[interface{}]interface{}, k interface{}) boundError {
+	if ctx.pos != nil {
+		if pos, ok := ctx.pos.getKey(m, k); ok {
+			err.locate(pos)
+		} else {
+			ctx.locate(err, m)
+		}
+	}
+
+	return err
+}
+
+// locateItem sets position of i-th item of given list to the error.
+func (ctx context) locateItem(err boundError, l []interface{}, i int) boundError {
+	if ctx.pos != nil {
+		if pos, ok := ctx.pos.getItem(l, i); ok {
+			err.locate(pos)
+		}
+	}
+
+	return err
+}
+
 func (ctx context) validateString(v interface{}, desc string) (string, boundError) {
 	r, ok := v.(string)
 	if !ok {
@@ -34,10 +82,15 @@ func (ctx context) validateString(v interface{}, desc string) (string, boundErro
 func (ctx context) extractString(m map[interface{}]interface{}, k string, desc string) (string, boundError) {
 	v, ok := m[k]
 	if !ok {
-		return "", newMissingStringError(desc)
+		return "", ctx.locate(newMissingStringError(desc), m)
 	}
 
-	return ctx.validateString(v, desc)
+	s, err := ctx.validateString(v, desc)
+	if err != nil {
+		return "", ctx.locateKey(err, m, k)
+	}
+
+	return s, nil
 }
 
 func (ctx context) extractStringOpt(m map[interface{}]interface{}, k string, desc string) (string, bool, boundError) {
@@ -47,7 +100,11 @@ func (ctx context) extractStringOpt(m map[interface{}]interface{}, k string, des
 	}
 
 	s, err := ctx.validateString(v, desc)
-	return s, true, err
+	if err != nil {
+		return "", true, ctx.locateKey(err, m, k)
+	}
+
+	return s, true, nil
 }
 
 func (ctx context) validateInteger(v interface{}, desc string) (int64, boundError) {
@@ -97,7 +154,7 @@ func (ctx context) validateFloat(v interface{}, desc string) (float64, boundErro
 func (ctx context) validateMap(v interface{}, desc string) (map[interface{}]interface{}, boundError) {
 	r, ok := v.(map[interface{}]interface{})
 	if !ok {
-		return nil, newMapError(v, desc)
+		return nil, ctx.locate(newMapError(v, desc), v)
 	}
 
 	return r, nil
@@ -106,10 +163,15 @@ func (ctx context) validateMap(v interface{}, desc string) (map[interface{}]inte
 func (ctx context) extractMap(m map[interface{}]interface{}, k string, desc string) (map[interface{}]interface{}, boundError) {
 	v, ok := m[k]
 	if !ok {
-		return nil, newMissingMapError(desc)
+		return nil, ctx.locate(newMissingMapError(desc), m)
+	}
+
+	r, err := ctx.validateMap(v, desc)
+	if err != nil {
+		return nil, ctx.locateKey(err, m, k)
 	}
 
-	return ctx.validateMap(v, desc)
+	return r, nil
 }
 
 func (ctx context) extractMapOpt(m map[interface{}]interface{}, k string, desc string) (map[interface{}]interface{}, bool, boundError) {
@@ -118,14 +180,18 @@ func (ctx context) extractMapOpt(m map[interface{}]interface{}, k string, desc s
 		return nil, false, nil
 	}
 
-	m, err := ctx.validateMap(v, desc)
-	return m, true, err
+	r, err := ctx.validateMap(v, desc)
+	if err != nil {
+		return nil, true, ctx.locateKey(err, m, k)
+	}
+
+	return r, true, nil
 }
 
 func (ctx context) validateList(v interface{}, desc string) ([]interface{}, boundError) {
 	r, ok := v.([]interface{})
 	if !ok {
-		return nil, newListError(v, desc)
+		return nil, ctx.locate(newListError(v, desc), v)
 	}
 
 	return r, nil
@@ -134,10 +200,15 @@ func (ctx context) validateList(v interface{}, desc string) ([]interface{}, boun
 func (ctx context) extractList(m map[interface{}]interface{}, k, desc string) ([]interface{}, boundError) {
 	v, ok := m[k]
 	if !ok {
-		return nil, newMissingListError(desc)
+		return nil, ctx.locate(newMissingListError(desc), m)
 	}
 
-	return ctx.validateList(v, desc)
+	l, err := ctx.validateList(v, desc)
+	if err != nil {
+		return nil, ctx.locateKey(err, m, k)
+	}
+
+	return l, nil
 }
 
 func (ctx context) extractListOpt(m map[interface{}]interface{}, k, desc string) ([]interface{}, bool, boundError) {
@@ -147,17 +218,21 @@ func (ctx context) extractListOpt(m map[interface{}]interface{}, k, desc string)
 	}
 
 	l, err := ctx.validateList(v, desc)
-	return l, true, err
+	if err != nil {
+		return nil, true, ctx.locateKey(err, m, k)
+	}
+
+	return l, true, nil
 }
 
 func (ctx context) getSingleMapPair(m map[interface{}]interface{}, desc string) (interface{}, interface{}, boundError) {
 	if len(m) > 1 {
-		return nil, nil, newTooManySMPItemsError(desc, len(m))
+		return nil, nil, ctx.locate(newTooManySMPItemsError(desc, len(m)), m)
 	}
 
 	for k, v := range m {
 		return k, v, nil
 	}
 
-	return nil, nil, newNoSMPItemsError(desc, len(m))
+	return nil, nil, ctx.locate(newNoSMPItemsError(desc, len(m)), m)
 }
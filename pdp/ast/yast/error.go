@@ -5,6 +5,8 @@ package yast
 import (
 	"fmt"
 	"strings"
+
+	"github.com/infobloxopen/themis/pdp"
 )
 
 const errorSourcePathSeparator = ">"
@@ -12,6 +14,7 @@ const errorSourcePathSeparator = ">"
 type boundError interface {
 	error
 	bind(src string)
+	locate(pos pdp.SourcePosition)
 }
 
 func bindError(err error, src string) boundError {
@@ -31,18 +34,51 @@ func bindErrorf(err error, format string, args ...interface{}) boundError {
 type errorLink struct {
 	id   int
 	path []string
+	pos  *pdp.SourcePosition
 }
 
 func (e *errorLink) errorf(format string, args ...interface{}) string {
 	msg := fmt.Sprintf(format, args...)
 
 	if len(e.path) > 0 {
-		return fmt.Sprintf("#%02x (%s): %s", e.id, strings.Join(e.path, errorSourcePathSeparator), msg)
+		msg = fmt.Sprintf("#%02x (%s): %s", e.id, strings.Join(e.path, errorSourcePathSeparator), msg)
+	} else {
+		msg = fmt.Sprintf("#%02x: %s", e.id, msg)
+	}
+
+	if e.pos != nil {
+		return fmt.Sprintf("%s: %s", e.pos, msg)
 	}
 
-	return fmt.Sprintf("#%02x: %s", e.id, msg)
+	return msg
 }
 
 func (e *errorLink) bind(src string) {
 	e.path = append([]string{src}, e.path...)
 }
+
+// locate sets position of the error. The position is set only once so
+// the error keeps position of the innermost YAML node it's been found in.
+func (e *errorLink) locate(pos pdp.SourcePosition) {
+	if e.pos == nil {
+		e.pos = &pos
+	}
+}
+
+// Position implements pdp.PositionedError interface and returns position
+// of YAML node where the error has been found.
+func (e *errorLink) Position() (pdp.SourcePosition, bool) {
+	if e.pos == nil {
+		return pdp.SourcePosition{}, false
+	}
+
+	return *e.pos, true
+}
+
+// SetSourceFile implements pdp.PositionedError interface and sets name of
+// YAML document to the error's position.
+func (e *errorLink) SetSourceFile(name string) {
+	if e.pos != nil {
+		e.pos.File = name
+	}
+}
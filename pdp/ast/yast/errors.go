@@ -70,6 +70,14 @@ const (
 	unknownFlagNameErrorID                = 57
 	unknownAggregationTypeErrorID         = 58
 	invalidAggregationTypeErrorID         = 59
+	invalidDateTimeErrorID                = 60
+	invalidDurationErrorID                = 61
+	unknownVariableErrorID                = 62
+	quantifierCollectionTypeErrorID       = 63
+	variableCycleErrorID                  = 64
+	notLinkedDocumentErrorID              = 65
+	policyReferenceFieldsErrorID          = 66
+	mapKeyTypeErrorID                     = 67
 )
 
 type externalError struct {
@@ -1012,3 +1020,125 @@ func newInvalidAggregationTypeError(a string, t pdp.Type) *invalidAggregationTyp
 func (e *invalidAggregationTypeError) Error() string {
 	return e.errorf("Inappropriate aggregation type %q for selector type %q", e.a, e.t)
 }
+
+type invalidDateTimeError struct {
+	errorLink
+	s   string
+	err error
+}
+
+func newInvalidDateTimeError(s string, err error) *invalidDateTimeError {
+	return &invalidDateTimeError{
+		errorLink: errorLink{id: invalidDateTimeErrorID},
+		s:         s,
+		err:       err}
+}
+
+func (e *invalidDateTimeError) Error() string {
+	return e.errorf("Expected value of datetime type but got %q (%v)", e.s, e.err)
+}
+
+type invalidDurationError struct {
+	errorLink
+	s   string
+	err error
+}
+
+func newInvalidDurationError(s string, err error) *invalidDurationError {
+	return &invalidDurationError{
+		errorLink: errorLink{id: invalidDurationErrorID},
+		s:         s,
+		err:       err}
+}
+
+func (e *invalidDurationError) Error() string {
+	return e.errorf("Expected value of duration type but got %q (%v)", e.s, e.err)
+}
+
+type unknownVariableError struct {
+	errorLink
+	ID string
+}
+
+func newUnknownVariableError(ID string) *unknownVariableError {
+	return &unknownVariableError{
+		errorLink: errorLink{id: unknownVariableErrorID},
+		ID:        ID}
+}
+
+func (e *unknownVariableError) Error() string {
+	return e.errorf("Unknown variable %q", e.ID)
+}
+
+type quantifierCollectionTypeError struct {
+	errorLink
+	t pdp.Type
+}
+
+func newQuantifierCollectionTypeError(t pdp.Type) *quantifierCollectionTypeError {
+	return &quantifierCollectionTypeError{
+		errorLink: errorLink{id: quantifierCollectionTypeErrorID},
+		t:         t}
+}
+
+func (e *quantifierCollectionTypeError) Error() string {
+	return e.errorf("Expected list of strings, set of strings, set of networks or set of domains to iterate over but got %q", e.t)
+}
+
+type variableCycleError struct {
+	errorLink
+	ID string
+}
+
+func newVariableCycleError(ID string) *variableCycleError {
+	return &variableCycleError{
+		errorLink: errorLink{id: variableCycleErrorID},
+		ID:        ID}
+}
+
+func (e *variableCycleError) Error() string {
+	return e.errorf("Variable %q refers itself", e.ID)
+}
+
+type notLinkedDocumentError struct {
+	errorLink
+	tag string
+}
+
+func newNotLinkedDocumentError(tag string) *notLinkedDocumentError {
+	return &notLinkedDocumentError{
+		errorLink: errorLink{id: notLinkedDocumentErrorID},
+		tag:       tag}
+}
+
+func (e *notLinkedDocumentError) Error() string {
+	return e.errorf("%q is allowed only in policy documents loaded together with the documents they refer", e.tag)
+}
+
+type policyReferenceFieldsError struct {
+	errorLink
+}
+
+func newPolicyReferenceFieldsError() *policyReferenceFieldsError {
+	return &policyReferenceFieldsError{
+		errorLink: errorLink{id: policyReferenceFieldsErrorID}}
+}
+
+func (e *policyReferenceFieldsError) Error() string {
+	return e.errorf("Policy reference can't have any fields other than \"ref\"")
+}
+
+type mapKeyTypeError struct {
+	errorLink
+	k interface{}
+}
+
+func newMapKeyTypeError(k interface{}) *mapKeyTypeError {
+	return &mapKeyTypeError{
+		errorLink: errorLink{id: mapKeyTypeErrorID},
+		k:         k}
+}
+
+func (e *mapKeyTypeError) Error() string {
+	return e.errorf("Expected scalar as map key but got %T", e.k)
+}
@@ -26,7 +26,7 @@ func (ctx context) unmarshalArguments(v interface{}) ([]pdp.Expression, boundErr
 	for i, item := range items {
 		arg, err := ctx.unmarshalExpression(item)
 		if err != nil {
-			return nil, bindErrorf(err, "%d", i)
+			return nil, ctx.locateItem(bindErrorf(err, "%d", i), items, i)
 		}
 
 		args[i] = arg
@@ -48,9 +48,18 @@ func (ctx context) unmarshalExpression(v interface{}) (pdp.Expression, boundErro
 
 	ID, err := ctx.validateString(k, "specificator or function name")
 	if err != nil {
-		return nil, err
+		return nil, ctx.locate(err, m)
+	}
+
+	e, err := ctx.unmarshalExpressionByID(ID, v)
+	if err != nil {
+		return nil, ctx.locate(err, m)
 	}
 
+	return e, nil
+}
+
+func (ctx context) unmarshalExpressionByID(ID string, v interface{}) (pdp.Expression, boundError) {
 	switch ID {
 	case yastTagAttribute:
 		return ctx.unmarshalAttributeDesignator(v)
@@ -60,6 +69,18 @@ func (ctx context) unmarshalExpression(v interface{}) (pdp.Expression, boundErro
 
 	case yastTagSelector:
 		return ctx.unmarshalSelector(v)
+
+	case yastTagVariable:
+		return ctx.unmarshalVariable(v)
+	}
+
+	if q, ok := pdp.QuantifierIDs[ID]; ok {
+		e, err := ctx.unmarshalQuantifier(q, v)
+		if err != nil {
+			return nil, bindError(err, ID)
+		}
+
+		return e, nil
 	}
 
 	validators, ok := pdp.FunctionArgumentValidators[ID]
@@ -74,9 +95,78 @@ func (ctx context) unmarshalExpression(v interface{}) (pdp.Expression, boundErro
 
 	for _, validator := range validators {
 		if maker := validator(args); maker != nil {
-			return maker(args), nil
+			e := maker(args)
+			if err := pdp.ValidateExpression(e); err != nil {
+				return nil, bindError(err, ID)
+			}
+
+			return e, nil
 		}
 	}
 
 	return nil, newFunctionCastError(ID, args)
 }
+
+func (ctx context) unmarshalVariable(v interface{}) (pdp.Expression, boundError) {
+	ID, err := ctx.validateString(v, "variable name")
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(ctx.its) - 1; i >= 0; i-- {
+		if ctx.its[i].GetID() == ID {
+			return ctx.its[i], nil
+		}
+	}
+
+	return ctx.lookupVariable(ID)
+}
+
+func (ctx context) unmarshalQuantifier(q int, v interface{}) (pdp.Expression, boundError) {
+	m, err := ctx.validateMap(v, "quantifier")
+	if err != nil {
+		return nil, err
+	}
+
+	ID, err := ctx.extractString(m, yastTagVariable, "iteration variable name")
+	if err != nil {
+		return nil, err
+	}
+
+	om, err := ctx.extractMap(m, yastTagOver, "collection expression")
+	if err != nil {
+		return nil, err
+	}
+
+	over, err := ctx.unmarshalExpression(om)
+	if err != nil {
+		return nil, bindError(err, yastTagOver)
+	}
+
+	t, ok := pdp.GetIterationItemType(over.GetResultType())
+	if !ok {
+		return nil, ctx.locateKey(newQuantifierCollectionTypeError(over.GetResultType()), m, yastTagOver)
+	}
+
+	em, err := ctx.extractMap(m, yastTagExpression, "nested expression")
+	if err != nil {
+		return nil, err
+	}
+
+	its := make([]pdp.IterationVariable, len(ctx.its)+1)
+	copy(its, ctx.its)
+	its[len(ctx.its)] = pdp.MakeIterationVariable(ID, t)
+	ctx.its = its
+
+	e, err := ctx.unmarshalExpression(em)
+	if err != nil {
+		return nil, bindError(err, yastTagExpression)
+	}
+
+	r, qErr := pdp.MakeQuantifiedExpression(q, ID, over, e)
+	if qErr != nil {
+		return nil, ctx.locateKey(bindError(qErr, yastTagExpression), m, yastTagExpression)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,268 @@
+package yast
+
+import (
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/infobloxopen/themis/pdp"
+)
+
+// YAML 1.1 boolean literals which aren't booleans in YAML 1.2. YAST keeps
+// treating them as booleans.
+var yaml11Booleans = map[string]bool{
+	"y": true, "Y": true, "yes": true, "Yes": true, "YES": true, "on": true, "On": true, "ON": true,
+	"n": false, "N": false, "no": false, "No": false, "NO": false, "off": false, "Off": false, "OFF": false,
+}
+
+// positions keeps positions of YAML nodes. Parser works with maps, lists and
+// scalars decoded from YAML document. Maps and lists are identified by
+// pointers to their data. Positions of scalars are kept by their containers.
+type positions struct {
+	maps    map[uintptr]*mapPosition
+	lists   map[uintptr]*listPosition
+	aliases map[*yaml.Node]interface{}
+}
+
+type mapPosition struct {
+	pos  pdp.SourcePosition
+	keys map[interface{}]pdp.SourcePosition
+}
+
+type listPosition struct {
+	pos   pdp.SourcePosition
+	items []pdp.SourcePosition
+}
+
+func newPositions() *positions {
+	return &positions{
+		maps:    make(map[uintptr]*mapPosition),
+		lists:   make(map[uintptr]*listPosition),
+		aliases: make(map[*yaml.Node]interface{}),
+	}
+}
+
+// decodeYAML parses YAML document to maps (map[interface{}]interface{}),
+// lists ([]interface{}) and scalars and collects their positions. It returns
+// nil for empty document.
+func decodeYAML(b []byte) (interface{}, *positions, boundError) {
+	var n yaml.Node
+	if err := yaml.Unmarshal(b, &n); err != nil {
+		return nil, nil, newExternalError(err)
+	}
+
+	p := newPositions()
+	if n.Kind == 0 {
+		return nil, p, nil
+	}
+
+	v, err := p.decode(&n)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return v, p, nil
+}
+
+func makeNodePosition(n *yaml.Node) pdp.SourcePosition {
+	return pdp.SourcePosition{
+		Line:   n.Line,
+		Column: n.Column,
+	}
+}
+
+func (p *positions) decode(n *yaml.Node) (interface{}, boundError) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) <= 0 {
+			return nil, nil
+		}
+
+		return p.decode(n.Content[0])
+
+	case yaml.AliasNode:
+		// Anchored node is decoded once and all its aliases share
+		// the value.
+		if v, ok := p.aliases[n.Alias]; ok {
+			return v, nil
+		}
+
+		v, err := p.decode(n.Alias)
+		if err != nil {
+			return nil, err
+		}
+
+		p.aliases[n.Alias] = v
+		return v, nil
+
+	case yaml.MappingNode:
+		return p.decodeMap(n)
+
+	case yaml.SequenceNode:
+		return p.decodeList(n)
+	}
+
+	return p.decodeScalar(n)
+}
+
+func (p *positions) decodeScalar(n *yaml.Node) (interface{}, boundError) {
+	if n.Style&yaml.TaggedStyle == 0 {
+		switch n.ShortTag() {
+		case "!!timestamp":
+			// Untagged timestamp is kept as a string. Parser makes
+			// date and time values itself.
+			return n.Value, nil
+
+		case "!!str":
+			if n.Style == 0 {
+				if b, ok := yaml11Booleans[n.Value]; ok {
+					return b, nil
+				}
+			}
+		}
+	}
+
+	var v interface{}
+	if err := n.Decode(&v); err != nil {
+		err := newExternalError(err)
+		err.locate(makeNodePosition(n))
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func (p *positions) decodeList(n *yaml.Node) (interface{}, boundError) {
+	l := make([]interface{}, len(n.Content))
+	lp := &listPosition{
+		pos:   makeNodePosition(n),
+		items: make([]pdp.SourcePosition, len(n.Content)),
+	}
+
+	for i, item := range n.Content {
+		v, err := p.decode(item)
+		if err != nil {
+			return nil, err
+		}
+
+		l[i] = v
+		lp.items[i] = makeNodePosition(item)
+	}
+
+	if len(l) > 0 {
+		p.lists[reflect.ValueOf(l).Pointer()] = lp
+	}
+
+	return l, nil
+}
+
+func (p *positions) decodeMap(n *yaml.Node) (interface{}, boundError) {
+	m := make(map[interface{}]interface{}, len(n.Content)/2)
+	mp := &mapPosition{
+		pos:  makeNodePosition(n),
+		keys: make(map[interface{}]pdp.SourcePosition, len(n.Content)/2),
+	}
+
+	var merges []*yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		kn := n.Content[i]
+		vn := n.Content[i+1]
+		if kn.Kind == yaml.ScalarNode && kn.ShortTag() == "!!merge" {
+			merges = append(merges, vn)
+			continue
+		}
+
+		k, err := p.decode(kn)
+		if err != nil {
+			return nil, err
+		}
+
+		if k != nil && !reflect.TypeOf(k).Comparable() {
+			err := newMapKeyTypeError(k)
+			err.locate(makeNodePosition(kn))
+			return nil, err
+		}
+
+		v, err := p.decode(vn)
+		if err != nil {
+			return nil, err
+		}
+
+		m[k] = v
+		mp.keys[k] = makeNodePosition(vn)
+	}
+
+	// Keys of merged maps don't override keys defined explicitly.
+	for _, vn := range merges {
+		v, err := p.decode(vn)
+		if err != nil {
+			return nil, err
+		}
+
+		items, ok := v.([]interface{})
+		if !ok {
+			items = []interface{}{v}
+		}
+
+		for _, item := range items {
+			mm, ok := item.(map[interface{}]interface{})
+			if !ok {
+				err := newMapError(item, "map to merge")
+				err.locate(makeNodePosition(vn))
+				return nil, err
+			}
+
+			for k, v := range mm {
+				if _, ok := m[k]; !ok {
+					m[k] = v
+					if pos, ok := p.getKey(mm, k); ok {
+						mp.keys[k] = pos
+					}
+				}
+			}
+		}
+	}
+
+	p.maps[reflect.ValueOf(m).Pointer()] = mp
+	return m, nil
+}
+
+// get returns position of given map or list.
+func (p *positions) get(v interface{}) (pdp.SourcePosition, bool) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		if mp, ok := p.maps[reflect.ValueOf(v).Pointer()]; ok {
+			return mp.pos, true
+		}
+
+	case []interface{}:
+		if len(v) > 0 {
+			if lp, ok := p.lists[reflect.ValueOf(v).Pointer()]; ok {
+				return lp.pos, true
+			}
+		}
+	}
+
+	return pdp.SourcePosition{}, false
+}
+
+// getKey returns position of value of given map at given key.
+func (p *positions) getKey(m map[interface{}]interface{}, k interface{}) (pdp.SourcePosition, bool) {
+	if mp, ok := p.maps[reflect.ValueOf(m).Pointer()]; ok {
+		pos, ok := mp.keys[k]
+		return pos, ok
+	}
+
+	return pdp.SourcePosition{}, false
+}
+
+// getItem returns position of i-th item of given list.
+func (p *positions) getItem(l []interface{}, i int) (pdp.SourcePosition, bool) {
+	if i >= 0 && i < len(l) {
+		if lp, ok := p.lists[reflect.ValueOf(l).Pointer()]; ok && i < len(lp.items) {
+			return lp.items[i], true
+		}
+	}
+
+	return pdp.SourcePosition{}, false
+}
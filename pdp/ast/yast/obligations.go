@@ -20,17 +20,16 @@ func (ctx context) unmarshalObligationItem(v interface{}) (pdp.AttributeAssignme
 
 	a, ok := ctx.symbols.GetAttribute(ID)
 	if !ok {
-		return pdp.AttributeAssignment{}, newUnknownAttributeError(ID)
+		return pdp.AttributeAssignment{}, ctx.locate(newUnknownAttributeError(ID), m)
 	}
 
-	m, err = ctx.validateMap(v, "obligation assignment")
-	if err != nil {
-		return pdp.AttributeAssignment{}, bindError(err, ID)
+	if _, err := ctx.validateMap(v, "obligation assignment"); err != nil {
+		return pdp.AttributeAssignment{}, bindError(ctx.locateKey(err, m, k), ID)
 	}
 
 	e, err := ctx.unmarshalExpression(v)
 	if err != nil {
-		return pdp.AttributeAssignment{}, bindError(err, ID)
+		return pdp.AttributeAssignment{}, bindError(ctx.locateKey(err, m, k), ID)
 	}
 
 	return pdp.MakeAttributeAssignment(a, e), nil
@@ -50,7 +49,7 @@ func (ctx context) unmarshalObligations(m map[interface{}]interface{}) ([]pdp.At
 	for i, item := range items {
 		o, err := ctx.unmarshalObligationItem(item)
 		if err != nil {
-			return nil, bindError(bindErrorf(err, "%d", i), "obligations")
+			return nil, bindError(ctx.locateItem(bindErrorf(err, "%d", i), items, i), "obligations")
 		}
 
 		r = append(r, o)
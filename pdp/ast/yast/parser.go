@@ -6,7 +6,6 @@ import (
 	"io/ioutil"
 
 	"github.com/google/uuid"
-	"gopkg.in/yaml.v2"
 
 	"github.com/infobloxopen/themis/pdp"
 )
@@ -34,6 +33,12 @@ const (
 	yastTagAttribute   = "attr"
 	yastTagValue       = "val"
 	yastTagSelector    = "selector"
+	yastTagVariable    = "var"
+	yastTagOver        = "over"
+	yastTagExpression  = "expr"
+	yastTagVariables   = "variables"
+	yastTagRef         = "ref"
+	yastTagInclude     = "include"
 	yastTagType        = "type"
 	yastTagContent     = "content"
 	yastTagURI         = "uri"
@@ -47,39 +52,104 @@ type Parser struct{}
 
 // Unmarshal parses policies YAML representation to PDP's internal representation.
 func (p Parser) Unmarshal(in io.Reader, tag *uuid.UUID) (*pdp.PolicyStorage, error) {
-	b, err := ioutil.ReadAll(in)
+	m, pos, err := unmarshalRoot(in)
 	if err != nil {
 		return nil, err
 	}
 
-	m := make(map[interface{}]interface{})
-	err = yaml.Unmarshal(b, &m)
+	ctx := newContext()
+	ctx.pos = pos
+	if _, ok := m[yastTagInclude]; ok {
+		return nil, ctx.locateKey(newNotLinkedDocumentError(yastTagInclude), m, yastTagInclude)
+	}
+
+	rp, bErr := ctx.unmarshalDocument(m)
+	if bErr != nil {
+		return nil, ctx.locate(bErr, m)
+	}
+
+	if rp != nil {
+		return pdp.NewPolicyStorage(rp, ctx.symbols, tag), nil
+	}
+
+	return nil, ctx.locate(newRootKeysError(m), m)
+}
+
+// UnmarshalDocument parses policy document which can refer root policies
+// of other documents. It puts types and attributes to given symbol tables.
+// Resulting storage has no root policy if the document contains only
+// declarations.
+func (p Parser) UnmarshalDocument(in io.Reader, s pdp.Symbols) (*pdp.PolicyStorage, error) {
+	m, pos, err := unmarshalRoot(in)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx := newContext()
+	ctx := newContextWithSymbols(s)
+	ctx.pos = pos
+	ctx.linked = true
 
-	err = ctx.unmarshalTypeDeclarations(m)
+	rp, bErr := ctx.unmarshalDocument(m)
+	if bErr != nil {
+		return nil, ctx.locate(bErr, m)
+	}
+
+	return pdp.NewPolicyStorage(rp, ctx.symbols, nil), nil
+}
+
+// GetIncludes returns list of documents included by given document.
+func (p Parser) GetIncludes(in io.Reader) ([]string, error) {
+	m, pos, err := unmarshalRoot(in)
 	if err != nil {
 		return nil, err
 	}
 
-	err = ctx.unmarshalAttributeDeclarations(m)
+	ctx := newContext()
+	ctx.pos = pos
+	items, ok, err := ctx.extractListOpt(m, yastTagInclude, "list of included documents")
+	if !ok || err != nil {
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	paths := make([]string, len(items))
+	for i, item := range items {
+		s, err := ctx.validateString(item, "included document")
+		if err != nil {
+			return nil, bindErrorf(ctx.locateItem(err, items, i), "%d", i+1)
+		}
+
+		paths[i] = s
+	}
+
+	return paths, nil
+}
+
+func unmarshalRoot(in io.Reader) (map[interface{}]interface{}, *positions, error) {
+	b, err := ioutil.ReadAll(in)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	rp, err := ctx.unmarshalRootPolicy(m)
+	v, pos, err := decodeYAML(b)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if rp != nil {
-		return pdp.NewPolicyStorage(rp, ctx.symbols, tag), nil
+	if v == nil {
+		return make(map[interface{}]interface{}), pos, nil
+	}
+
+	ctx := context{pos: pos}
+	m, err := ctx.validateMap(v, "root")
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return nil, newRootKeysError(m)
+	return m, pos, nil
 }
 
 // UnmarshalUpdate parses policies update YAML representation to PDP's internal representation.
@@ -89,19 +159,27 @@ func (p Parser) UnmarshalUpdate(in io.Reader, s pdp.Symbols, oldTag, newTag uuid
 		return nil, err
 	}
 
-	a := []interface{}{}
-	err = yaml.Unmarshal(b, &a)
+	v, pos, err := decodeYAML(b)
 	if err != nil {
 		return nil, err
 	}
 
 	ctx := newContextWithSymbols(s)
+	ctx.pos = pos
+
+	var a []interface{}
+	if v != nil {
+		a, err = ctx.validateList(v, "update")
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	u := pdp.NewPolicyUpdate(oldTag, newTag)
 	for i, item := range a {
 		err := ctx.unmarshalCommand(item, u)
 		if err != nil {
-			return nil, bindErrorf(err, "%d", i)
+			return nil, ctx.locateItem(bindErrorf(err, "%d", i), a, i)
 		}
 	}
 
@@ -46,7 +46,7 @@ func unmarshalMapperRuleCombiningAlgParams(ctx context, m map[interface{}]interf
 	t := arg.GetResultType()
 	_, flagsOk := t.(*pdp.FlagsType)
 	if !flagsOk && t != pdp.TypeString && t != pdp.TypeSetOfStrings && t != pdp.TypeListOfStrings {
-		return nil, newMapperArgumentTypeError(t)
+		return nil, ctx.locateKey(newMapperArgumentTypeError(t), m, yastTagMap)
 	}
 
 	defID, defOk, err := ctx.extractStringOpt(m, yastTagDefault, "default rule id")
@@ -56,7 +56,7 @@ func unmarshalMapperRuleCombiningAlgParams(ctx context, m map[interface{}]interf
 
 	if defOk {
 		if !checkRuleID(defID, rules) {
-			return nil, newMissingDefaultRuleRCAError(defID)
+			return nil, ctx.locateKey(newMissingDefaultRuleRCAError(defID), m, yastTagDefault)
 		}
 	}
 
@@ -67,7 +67,7 @@ func unmarshalMapperRuleCombiningAlgParams(ctx context, m map[interface{}]interf
 
 	if errOk {
 		if !checkRuleID(errID, rules) {
-			return nil, newMissingErrorRuleRCAError(errID)
+			return nil, ctx.locateKey(newMissingErrorRuleRCAError(errID), m, yastTagError)
 		}
 	}
 
@@ -82,7 +82,7 @@ func unmarshalMapperRuleCombiningAlgParams(ctx context, m map[interface{}]interf
 		if ok {
 			order, ok = pdp.MapperRCAOrderIDs[strings.ToLower(s)]
 			if !ok {
-				return nil, newUnknownMapperRCAOrder(s)
+				return nil, ctx.locateKey(newUnknownMapperRCAOrder(s), m, yastTagOrder)
 			}
 		}
 
@@ -112,17 +112,20 @@ func (ctx context) unmarshalRuleCombiningAlgObj(m map[interface{}]interface{}, r
 	s := strings.ToLower(ID)
 	maker, ok := pdp.RuleCombiningParamAlgs[s]
 	if !ok {
-		return nil, nil, newUnknownRCAError(ID)
+		return nil, nil, ctx.locateKey(newUnknownRCAError(ID), m, yastTagID)
 	}
 
-	paramUnmarshaler, ok := ruleCombiningAlgParamUnmarshalers[s]
-	if !ok {
+	var params interface{}
+	if paramUnmarshaler, ok := ruleCombiningAlgParamUnmarshalers[s]; ok {
+		params, err = paramUnmarshaler(ctx, m, rules)
+	} else if parser, ok := pdp.RuleCombiningAlgParamsParsers[s]; ok {
+		params, err = ctx.unmarshalCustomCombiningAlgParams(m, parser)
+	} else {
 		return nil, nil, newNotImplementedRCAError(ID)
 	}
 
-	params, err := paramUnmarshaler(ctx, m, rules)
 	if err != nil {
-		return nil, nil, bindError(err, ID)
+		return nil, nil, ctx.locate(bindError(err, ID), m)
 	}
 
 	return maker, params, nil
@@ -138,7 +141,7 @@ func (ctx context) unmarshalRuleCombiningAlg(m map[interface{}]interface{}, rule
 	case string:
 		maker, ok := pdp.RuleCombiningAlgs[strings.ToLower(alg)]
 		if !ok {
-			return nil, nil, newUnknownRCAError(alg)
+			return nil, nil, ctx.locateKey(newUnknownRCAError(alg), m, yastTagAlg)
 		}
 
 		return maker, nil, nil
@@ -147,12 +150,92 @@ func (ctx context) unmarshalRuleCombiningAlg(m map[interface{}]interface{}, rule
 		return ctx.unmarshalRuleCombiningAlgObj(alg, rules)
 	}
 
-	return nil, nil, newInvalidRCAError(v)
+	return nil, nil, ctx.locateKey(newInvalidRCAError(v), m, yastTagAlg)
+}
+
+func (ctx context) unmarshalCustomCombiningAlgParams(m map[interface{}]interface{}, parser pdp.CombiningAlgParamsParser) (interface{}, boundError) {
+	raw := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		s, err := ctx.validateString(k, "algorithm parameter name")
+		if err != nil {
+			return nil, err
+		}
+
+		switch s {
+		case yastTagID, yastTagMap, yastTagDefault, yastTagError, yastTagOrder, yastTagAlg:
+			continue
+		}
+
+		v, err := ctx.unmarshalCustomCombiningAlgParam(v)
+		if err != nil {
+			return nil, ctx.locateKey(bindError(err, s), m, k)
+		}
+
+		raw[s] = v
+	}
+
+	params, err := parser(raw)
+	if err != nil {
+		return nil, ctx.locate(bindError(err, "parameters"), m)
+	}
+
+	return params, nil
+}
+
+func (ctx context) unmarshalCustomCombiningAlgParam(v interface{}) (interface{}, boundError) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), nil
+
+	case int64:
+		return float64(v), nil
+
+	case uint64:
+		return float64(v), nil
+
+	case []interface{}:
+		r := make([]interface{}, len(v))
+		for i, item := range v {
+			item, err := ctx.unmarshalCustomCombiningAlgParam(item)
+			if err != nil {
+				return nil, bindErrorf(err, "%d", i)
+			}
+
+			r[i] = item
+		}
+
+		return r, nil
+
+	case map[interface{}]interface{}:
+		r := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			s, err := ctx.validateString(k, "algorithm parameter key")
+			if err != nil {
+				return nil, err
+			}
+
+			item, err := ctx.unmarshalCustomCombiningAlgParam(item)
+			if err != nil {
+				return nil, bindError(err, s)
+			}
+
+			r[s] = item
+		}
+
+		return r, nil
+	}
+
+	return v, nil
 }
 
 func (ctx context) unmarshalPolicy(m map[interface{}]interface{}, i int, ID string, hidden bool, rules interface{}) (pdp.Evaluable, boundError) {
 	src := makeSource("policy", ID, hidden, i)
 
+	ctx, err := ctx.enterPolicy(ID, hidden).unmarshalVariables(m)
+	if err != nil {
+		return nil, bindError(err, src)
+	}
+
 	target, err := ctx.unmarshalTarget(m)
 	if err != nil {
 		return nil, bindError(err, src)
@@ -200,7 +283,7 @@ func unmarshalMapperPolicyCombiningAlgParams(ctx context, m map[interface{}]inte
 	t := arg.GetResultType()
 	_, flagsOk := t.(*pdp.FlagsType)
 	if !flagsOk && t != pdp.TypeString && t != pdp.TypeSetOfStrings && t != pdp.TypeListOfStrings {
-		return nil, newMapperArgumentTypeError(t)
+		return nil, ctx.locateKey(newMapperArgumentTypeError(t), m, yastTagMap)
 	}
 
 	defID, defOk, err := ctx.extractStringOpt(m, yastTagDefault, "default policy id")
@@ -210,7 +293,7 @@ func unmarshalMapperPolicyCombiningAlgParams(ctx context, m map[interface{}]inte
 
 	if defOk {
 		if !checkPolicyID(defID, policies) {
-			return nil, newMissingDefaultPolicyPCAError(defID)
+			return nil, ctx.locateKey(newMissingDefaultPolicyPCAError(defID), m, yastTagDefault)
 		}
 	}
 
@@ -221,7 +304,7 @@ func unmarshalMapperPolicyCombiningAlgParams(ctx context, m map[interface{}]inte
 
 	if errOk {
 		if !checkPolicyID(errID, policies) {
-			return nil, newMissingErrorPolicyPCAError(errID)
+			return nil, ctx.locateKey(newMissingErrorPolicyPCAError(errID), m, yastTagError)
 		}
 	}
 
@@ -236,7 +319,7 @@ func unmarshalMapperPolicyCombiningAlgParams(ctx context, m map[interface{}]inte
 		if ok {
 			order, ok = pdp.MapperPCAOrderIDs[strings.ToLower(s)]
 			if !ok {
-				return nil, newUnknownMapperPCAOrder(s)
+				return nil, ctx.locateKey(newUnknownMapperPCAOrder(s), m, yastTagOrder)
 			}
 		}
 
@@ -266,17 +349,20 @@ func (ctx context) unmarshalPolicyCombiningAlgObj(m map[interface{}]interface{},
 	s := strings.ToLower(ID)
 	maker, ok := pdp.PolicyCombiningParamAlgs[s]
 	if !ok {
-		return nil, nil, newUnknownPCAError(ID)
+		return nil, nil, ctx.locateKey(newUnknownPCAError(ID), m, yastTagID)
 	}
 
-	paramUnmarshaler, ok := policyCombiningAlgParamUnmarshalers[s]
-	if !ok {
+	var params interface{}
+	if paramUnmarshaler, ok := policyCombiningAlgParamUnmarshalers[s]; ok {
+		params, err = paramUnmarshaler(ctx, m, policies)
+	} else if parser, ok := pdp.PolicyCombiningAlgParamsParsers[s]; ok {
+		params, err = ctx.unmarshalCustomCombiningAlgParams(m, parser)
+	} else {
 		return nil, nil, newNotImplementedPCAError(ID)
 	}
 
-	params, err := paramUnmarshaler(ctx, m, policies)
 	if err != nil {
-		return nil, nil, bindError(err, ID)
+		return nil, nil, ctx.locate(bindError(err, ID), m)
 	}
 
 	return maker, params, nil
@@ -292,7 +378,7 @@ func (ctx context) unmarshalPolicyCombiningAlg(m map[interface{}]interface{}, po
 	case string:
 		maker, ok := pdp.PolicyCombiningAlgs[strings.ToLower(alg)]
 		if !ok {
-			return nil, nil, newUnknownPCAError(alg)
+			return nil, nil, ctx.locateKey(newUnknownPCAError(alg), m, yastTagAlg)
 		}
 
 		return maker, nil, nil
@@ -301,7 +387,7 @@ func (ctx context) unmarshalPolicyCombiningAlg(m map[interface{}]interface{}, po
 		return ctx.unmarshalPolicyCombiningAlgObj(alg, policies)
 	}
 
-	return nil, nil, newInvalidPCAError(v)
+	return nil, nil, ctx.locateKey(newInvalidPCAError(v), m, yastTagAlg)
 }
 
 func (ctx context) unmarshalPolicies(v interface{}) ([]pdp.Evaluable, boundError) {
@@ -314,7 +400,7 @@ func (ctx context) unmarshalPolicies(v interface{}) ([]pdp.Evaluable, boundError
 	for i, v := range items {
 		p, err := ctx.unmarshalItem(v, i+1)
 		if err != nil {
-			return nil, err
+			return nil, ctx.locateItem(err, items, i)
 		}
 
 		pols = append(pols, p)
@@ -326,6 +412,11 @@ func (ctx context) unmarshalPolicies(v interface{}) ([]pdp.Evaluable, boundError
 func (ctx context) unmarshalPolicySet(m map[interface{}]interface{}, i int, ID string, hidden bool, policies interface{}) (pdp.Evaluable, boundError) {
 	src := makeSource("policy set", ID, hidden, i)
 
+	ctx, err := ctx.enterPolicy(ID, hidden).unmarshalVariables(m)
+	if err != nil {
+		return nil, bindError(err, src)
+	}
+
 	target, err := ctx.unmarshalTarget(m)
 	if err != nil {
 		return nil, bindError(err, src)
@@ -372,6 +463,10 @@ func (ctx context) unmarshalItem(v interface{}, i int) (pdp.Evaluable, boundErro
 		return nil, err
 	}
 
+	if _, ok := m[yastTagRef]; ok {
+		return ctx.unmarshalPolicyReference(m, i)
+	}
+
 	ID, ok, err := ctx.extractStringOpt(m, yastTagID, "policy or policy set id")
 	if err != nil {
 		if i > 0 {
@@ -386,19 +481,51 @@ func (ctx context) unmarshalItem(v interface{}, i int) (pdp.Evaluable, boundErro
 	rules, rOk := m[yastTagRules]
 	policies, pOk := m[yastTagPolicies]
 
-	if rOk && pOk {
-		return nil, bindError(newPolicyAmbiguityError(), src)
+	var (
+		p    pdp.Evaluable
+		pErr boundError
+	)
+
+	switch {
+	case rOk && pOk:
+		pErr = bindError(newPolicyAmbiguityError(), src)
+
+	case rOk:
+		p, pErr = ctx.unmarshalPolicy(m, i, ID, !ok, rules)
+
+	case pOk:
+		p, pErr = ctx.unmarshalPolicySet(m, i, ID, !ok, policies)
+
+	default:
+		pErr = bindError(newPolicyMissingKeyError(), src)
 	}
 
-	if rOk {
-		return ctx.unmarshalPolicy(m, i, ID, !ok, rules)
+	if pErr != nil {
+		return nil, ctx.locate(pErr, m)
 	}
 
-	if pOk {
-		return ctx.unmarshalPolicySet(m, i, ID, !ok, policies)
+	return p, nil
+}
+
+func (ctx context) unmarshalPolicyReference(m map[interface{}]interface{}, i int) (pdp.Evaluable, boundError) {
+	var err boundError
+	if !ctx.linked {
+		err = newNotLinkedDocumentError(yastTagRef)
+	} else if len(m) > 1 {
+		err = newPolicyReferenceFieldsError()
+	} else {
+		var ID string
+		ID, err = ctx.extractString(m, yastTagRef, "policy reference")
+		if err == nil {
+			return pdp.NewPolicyReference(ID), nil
+		}
+	}
+
+	if i > 0 {
+		err = bindErrorf(err, "%d", i)
 	}
 
-	return nil, bindError(newPolicyMissingKeyError(), src)
+	return nil, ctx.locate(err, m)
 }
 
 func (ctx context) unmarshalRootPolicy(m map[interface{}]interface{}) (pdp.Evaluable, boundError) {
@@ -409,3 +536,23 @@ func (ctx context) unmarshalRootPolicy(m map[interface{}]interface{}) (pdp.Evalu
 
 	return ctx.unmarshalItem(m, 0)
 }
+
+func (ctx *context) unmarshalDocument(m map[interface{}]interface{}) (pdp.Evaluable, boundError) {
+	err := ctx.unmarshalTypeDeclarations(m)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ctx.unmarshalAttributeDeclarations(m)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.putVars = true
+	vctx, err := ctx.unmarshalVariables(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return vctx.unmarshalRootPolicy(m)
+}
@@ -14,12 +14,12 @@ func (ctx context) unmarshalCondition(m map[interface{}]interface{}) (pdp.Expres
 
 	e, err := ctx.unmarshalExpression(v)
 	if err != nil {
-		return nil, err
+		return nil, ctx.locateKey(err, m, yastTagCondition)
 	}
 
 	t := e.GetResultType()
 	if t != pdp.TypeBoolean {
-		return nil, newConditionTypeError(t)
+		return nil, ctx.locateKey(newConditionTypeError(t), m, yastTagCondition)
 	}
 
 	return e, nil
@@ -50,7 +50,7 @@ func (ctx context) unmarshalRule(m map[interface{}]interface{}, i int) (*pdp.Rul
 
 	effect, ok := pdp.EffectIDs[strings.ToLower(s)]
 	if !ok {
-		return nil, bindError(newUnknownEffectError(s), src)
+		return nil, bindError(ctx.locateKey(newUnknownEffectError(s), m, yastTagEffect), src)
 	}
 
 	obls, err := ctx.unmarshalObligations(m)
@@ -81,7 +81,7 @@ func (ctx context) unmarshalRuleEntity(m map[interface{}]interface{}, ID string,
 
 	eff, ok := pdp.EffectIDs[strings.ToLower(s)]
 	if !ok {
-		return nil, bindError(newUnknownEffectError(s), src)
+		return nil, bindError(ctx.locateKey(newUnknownEffectError(s), m, yastTagEffect), src)
 	}
 
 	obls, err := ctx.unmarshalObligations(m)
@@ -98,7 +98,12 @@ func (ctx context) unmarshalRulesItem(v interface{}, i int) (*pdp.Rule, boundErr
 		return nil, bindErrorf(err, "%d", i)
 	}
 
-	return ctx.unmarshalRule(m, i)
+	r, err := ctx.unmarshalRule(m, i)
+	if err != nil {
+		return nil, ctx.locate(err, m)
+	}
+
+	return r, nil
 }
 
 func (ctx context) unmarshalRules(v interface{}) ([]*pdp.Rule, boundError) {
@@ -112,7 +117,7 @@ func (ctx context) unmarshalRules(v interface{}) ([]*pdp.Rule, boundError) {
 	for i, item := range items {
 		r, err := ctx.unmarshalRulesItem(item, i+1)
 		if err != nil {
-			return nil, err
+			return nil, ctx.locateItem(err, items, i)
 		}
 
 		rules = append(rules, r)
@@ -20,7 +20,7 @@ func (ctx context) unmarshalSelector(v interface{}) (pdp.Expression, boundError)
 
 	id, ierr := url.Parse(uri)
 	if ierr != nil {
-		return nil, newSelectorURIError(uri, ierr)
+		return nil, ctx.locateKey(newSelectorURIError(uri, ierr), m, yastTagURI)
 	}
 
 	items, err := ctx.extractList(m, yastTagPath, "path")
@@ -32,7 +32,7 @@ func (ctx context) unmarshalSelector(v interface{}) (pdp.Expression, boundError)
 	for i, item := range items {
 		e, err := ctx.unmarshalExpression(item)
 		if err != nil {
-			return nil, bindErrorf(bindErrorf(err, "%d", i), "selector(%s)", uri)
+			return nil, bindErrorf(ctx.locateItem(bindErrorf(err, "%d", i), items, i), "selector(%s)", uri)
 		}
 
 		path[i] = e
@@ -45,11 +45,11 @@ func (ctx context) unmarshalSelector(v interface{}) (pdp.Expression, boundError)
 
 	t := ctx.symbols.GetType(st)
 	if t == nil {
-		return nil, bindErrorf(newUnknownTypeError(st), "selector(%s)", uri)
+		return nil, bindErrorf(ctx.locateKey(newUnknownTypeError(st), m, yastTagType), "selector(%s)", uri)
 	}
 
 	if t == pdp.TypeUndefined {
-		return nil, bindErrorf(newInvalidTypeError(t), "selector(%s)", uri)
+		return nil, bindErrorf(ctx.locateKey(newInvalidTypeError(t), m, yastTagType), "selector(%s)", uri)
 	}
 
 	var opts []pdp.SelectorOption
@@ -65,7 +65,7 @@ func (ctx context) unmarshalSelector(v interface{}) (pdp.Expression, boundError)
 		}
 		if defExp != nil {
 			if defExp.GetResultType() != t {
-				return nil, bindErrorf(newInvalidTypeError(t), "selector(%s).default", uri)
+				return nil, bindErrorf(ctx.locate(newInvalidTypeError(t), defMap), "selector(%s).default", uri)
 			}
 			opts = append(opts, pdp.SelectorOption{Name: pdp.SelectorOptionDefault, Data: defExp})
 		}
@@ -82,7 +82,7 @@ func (ctx context) unmarshalSelector(v interface{}) (pdp.Expression, boundError)
 		}
 		if errExp != nil {
 			if errExp.GetResultType() != t {
-				return nil, bindErrorf(newInvalidTypeError(t), "selector(%s).error", uri)
+				return nil, bindErrorf(ctx.locate(newInvalidTypeError(t), errMap), "selector(%s).error", uri)
 			}
 			opts = append(opts, pdp.SelectorOption{Name: pdp.SelectorOptionError, Data: errExp})
 		}
@@ -96,17 +96,19 @@ func (ctx context) unmarshalSelector(v interface{}) (pdp.Expression, boundError)
 	if ok && aggStr != "" {
 		a, ok := pdp.AggTypeIDs[strings.ToLower(aggStr)]
 		if !ok {
-			return nil, bindErrorf(newUnknownAggregationTypeError(aggStr), "selector(%s).aggregation", uri)
+			return nil, bindErrorf(ctx.locateKey(newUnknownAggregationTypeError(aggStr), m, yastTagAggregation),
+				"selector(%s).aggregation", uri)
 		}
 		if (a == pdp.AggTypeAppend || a == pdp.AggTypeAppendUnique) && t != pdp.TypeListOfStrings {
-			return nil, bindErrorf(newInvalidAggregationTypeError(aggStr, t), "selector(%s).aggregation", uri)
+			return nil, bindErrorf(ctx.locateKey(newInvalidAggregationTypeError(aggStr, t), m, yastTagAggregation),
+				"selector(%s).aggregation", uri)
 		}
 		opts = append(opts, pdp.SelectorOption{Name: pdp.SelectorOptionAggregation, Data: a})
 	}
 
 	e, eErr := pdp.MakeSelector(id, path, t, opts...)
 	if eErr != nil {
-		return nil, bindErrorf(eErr, "selector(%s)", uri)
+		return nil, ctx.locate(bindErrorf(eErr, "selector(%s)", uri), m)
 	}
 	return e, nil
 }
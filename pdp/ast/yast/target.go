@@ -23,17 +23,17 @@ func (ctx context) getTargetCompatibleArgument(v interface{}) (pdp.Expression, i
 func (ctx context) getAdjustedArgumentPair(v interface{}) (pdp.Expression, pdp.Expression, boundError) {
 	args, err := ctx.validateList(v, "function arguments")
 	if len(args) != 2 {
-		return nil, nil, newMatchFunctionArgsNumberError(len(args))
+		return nil, nil, ctx.locate(newMatchFunctionArgsNumberError(len(args)), v)
 	}
 
 	first, firstTCAID, err := ctx.getTargetCompatibleArgument(args[0])
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, ctx.locateItem(err, args, 0)
 	}
 
 	second, secondTCAID, err := ctx.getTargetCompatibleArgument(args[1])
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, ctx.locateItem(err, args, 1)
 	}
 
 	if firstTCAID == pdp.TargetCompatibleArgumentAttributeValue &&
@@ -73,7 +73,12 @@ func (ctx context) unmarshalTargetMatchExpression(ID string, v interface{}) (pdp
 		return nil, newMatchFunctionCastError(ID, firstType, secondType)
 	}
 
-	return maker(first, second), nil
+	e := maker(first, second)
+	if err := pdp.ValidateExpression(e); err != nil {
+		return nil, bindError(err, ID)
+	}
+
+	return e, nil
 }
 
 func (ctx context) unmarshalTargetAllOfItem(v interface{}) (pdp.Match, boundError) {
@@ -94,7 +99,7 @@ func (ctx context) unmarshalTargetAllOfItem(v interface{}) (pdp.Match, boundErro
 
 	e, err := ctx.unmarshalTargetMatchExpression(ID, v)
 	if err != nil {
-		return pdp.Match{}, err
+		return pdp.Match{}, ctx.locate(err, m)
 	}
 
 	return pdp.MakeMatch(e), nil
@@ -127,7 +132,7 @@ func (ctx context) unmarshalTargetAnyOfItem(v interface{}) (pdp.AllOf, boundErro
 		for i, item := range items {
 			m, err := ctx.unmarshalTargetAllOfItem(item)
 			if err != nil {
-				return al, bindError(bindErrorf(err, "%d", i+1), ID)
+				return al, bindError(ctx.locateItem(bindErrorf(err, "%d", i+1), items, i), ID)
 			}
 
 			al.Append(m)
@@ -135,7 +140,7 @@ func (ctx context) unmarshalTargetAnyOfItem(v interface{}) (pdp.AllOf, boundErro
 	} else {
 		e, err := ctx.unmarshalTargetMatchExpression(ID, v)
 		if err != nil {
-			return al, err
+			return al, ctx.locate(err, m)
 		}
 
 		m := pdp.MakeMatch(e)
@@ -172,7 +177,7 @@ func (ctx context) unmarshalTargetItem(v interface{}) (pdp.AnyOf, boundError) {
 		for i, item := range items {
 			al, err := ctx.unmarshalTargetAnyOfItem(item)
 			if err != nil {
-				return an, bindError(bindErrorf(err, "%d", i+1), ID)
+				return an, bindError(ctx.locateItem(bindErrorf(err, "%d", i+1), items, i), ID)
 			}
 
 			an.Append(al)
@@ -180,7 +185,7 @@ func (ctx context) unmarshalTargetItem(v interface{}) (pdp.AnyOf, boundError) {
 	} else {
 		e, err := ctx.unmarshalTargetMatchExpression(ID, v)
 		if err != nil {
-			return an, err
+			return an, ctx.locate(err, m)
 		}
 
 		m := pdp.MakeMatch(e)
@@ -207,7 +212,7 @@ func (ctx context) unmarshalTarget(m map[interface{}]interface{}) (pdp.Target, b
 	for i, item := range items {
 		a, err := ctx.unmarshalTargetItem(item)
 		if err != nil {
-			return t, bindError(bindErrorf(err, "%d", i+1), "target")
+			return t, bindError(ctx.locateItem(bindErrorf(err, "%d", i+1), items, i), "target")
 		}
 
 		t.Append(a)
@@ -16,7 +16,7 @@ func (ctx *context) unmarshalFlagsTypeDeclaration(id string, m map[interface{}]i
 	for i, v := range items {
 		s, err := ctx.validateString(v, "flag")
 		if err != nil {
-			return err
+			return ctx.locateItem(err, items, i)
 		}
 
 		flags[i] = s
@@ -24,11 +24,11 @@ func (ctx *context) unmarshalFlagsTypeDeclaration(id string, m map[interface{}]i
 
 	t, eErr := pdp.NewFlagsType(id, flags...)
 	if eErr != nil {
-		return newExternalError(eErr)
+		return ctx.locateKey(newExternalError(eErr), m, yastTagFlags)
 	}
 
 	if eErr := ctx.symbols.PutType(t); eErr != nil {
-		return newExternalError(eErr)
+		return ctx.locate(newExternalError(eErr), m)
 	}
 
 	return nil
@@ -40,7 +40,7 @@ func (ctx *context) unmarshalTypeDeclarationByMetaType(id, meta string, m map[in
 		return ctx.unmarshalFlagsTypeDeclaration(id, m)
 	}
 
-	return newUnknownMetaTypeError(meta)
+	return ctx.locateKey(newUnknownMetaTypeError(meta), m, yastTagMeta)
 }
 
 func (ctx *context) unmarshalTypeDeclaration(k, v interface{}) boundError {
@@ -75,7 +75,7 @@ func (ctx *context) unmarshalTypeDeclarations(m map[interface{}]interface{}) bou
 	for k, v := range types {
 		err = ctx.unmarshalTypeDeclaration(k, v)
 		if err != nil {
-			return bindError(err, yastTagTypes)
+			return bindError(ctx.locateKey(err, types, k), yastTagTypes)
 		}
 	}
 
@@ -2,6 +2,7 @@ package yast
 
 import (
 	"net"
+	"time"
 
 	"github.com/infobloxopen/go-trees/domain"
 	"github.com/infobloxopen/go-trees/domaintree"
@@ -79,6 +80,38 @@ func (ctx context) unmarshalDomainValue(v interface{}) (pdp.AttributeValue, boun
 	return pdp.MakeDomainValue(d), nil
 }
 
+func (ctx context) unmarshalDateTimeValue(v interface{}) (pdp.AttributeValue, boundError) {
+	if t, ok := v.(time.Time); ok {
+		return pdp.MakeDateTimeValue(t), nil
+	}
+
+	s, err := ctx.validateString(v, "value of datetime type")
+	if err != nil {
+		return pdp.UndefinedValue, err
+	}
+
+	t, ierr := time.Parse(time.RFC3339Nano, s)
+	if ierr != nil {
+		return pdp.UndefinedValue, newInvalidDateTimeError(s, ierr)
+	}
+
+	return pdp.MakeDateTimeValue(t), nil
+}
+
+func (ctx context) unmarshalDurationValue(v interface{}) (pdp.AttributeValue, boundError) {
+	s, err := ctx.validateString(v, "value of duration type")
+	if err != nil {
+		return pdp.UndefinedValue, err
+	}
+
+	d, ierr := time.ParseDuration(s)
+	if ierr != nil {
+		return pdp.UndefinedValue, newInvalidDurationError(s, ierr)
+	}
+
+	return pdp.MakeDurationValue(d), nil
+}
+
 func (ctx context) unmarshalSetOfStringsValueItem(v interface{}, i int, set *strtree.Tree) boundError {
 	s, err := ctx.validateString(v, "element")
 	if err != nil {
@@ -99,7 +132,7 @@ func (ctx context) unmarshalSetOfStringsValue(v interface{}) (pdp.AttributeValue
 	for i, item := range items {
 		err = ctx.unmarshalSetOfStringsValueItem(item, i, set)
 		if err != nil {
-			return pdp.UndefinedValue, bindError(bindErrorf(err, "%d", i), "set of strings")
+			return pdp.UndefinedValue, bindError(ctx.locateItem(bindErrorf(err, "%d", i), items, i), "set of strings")
 		}
 	}
 
@@ -132,7 +165,7 @@ func (ctx context) unmarshalSetOfNetworksValue(v interface{}) (pdp.AttributeValu
 	for i, item := range items {
 		err = ctx.unmarshalSetOfNetworksValueItem(item, i, set)
 		if err != nil {
-			return pdp.UndefinedValue, bindError(bindErrorf(err, "%d", i), "set of networks")
+			return pdp.UndefinedValue, bindError(ctx.locateItem(bindErrorf(err, "%d", i), items, i), "set of networks")
 		}
 	}
 
@@ -165,7 +198,7 @@ func (ctx context) unmarshalSetOfDomainsValue(v interface{}) (pdp.AttributeValue
 	for i, item := range items {
 		err = ctx.unmarshalSetOfDomainsValueItem(item, i, set)
 		if err != nil {
-			return pdp.UndefinedValue, bindError(bindErrorf(err, "%d", i), "set of domains")
+			return pdp.UndefinedValue, bindError(ctx.locateItem(bindErrorf(err, "%d", i), items, i), "set of domains")
 		}
 	}
 
@@ -191,7 +224,7 @@ func (ctx context) unmarshalListOfStringsValue(v interface{}) (pdp.AttributeValu
 	for i, item := range items {
 		list, err = ctx.unmarshalListOfStringsValueItem(item, list)
 		if err != nil {
-			return pdp.UndefinedValue, bindError(bindErrorf(err, "%d", i), "list of strings")
+			return pdp.UndefinedValue, bindError(ctx.locateItem(bindErrorf(err, "%d", i), items, i), "list of strings")
 		}
 	}
 
@@ -218,18 +251,18 @@ func (ctx context) unmarshalFlagsValue(v interface{}, t *pdp.FlagsType) (pdp.Att
 	return ctx.unmarshalFlags64Value(f, t)
 }
 
-func (ctx context) unmarshalFlags8Value(v []interface{}, t *pdp.FlagsType) (pdp.AttributeValue, boundError) {
+func (ctx context) unmarshalFlags8Value(flags []interface{}, t *pdp.FlagsType) (pdp.AttributeValue, boundError) {
 	var n uint8
 
-	for i, v := range v {
+	for i, v := range flags {
 		f, err := ctx.validateString(v, "flag name")
 		if err != nil {
-			return pdp.UndefinedValue, bindError(bindErrorf(err, "%d", i), "flag names")
+			return pdp.UndefinedValue, bindError(ctx.locateItem(bindErrorf(err, "%d", i), flags, i), "flag names")
 		}
 
 		b := t.GetFlagBit(f)
 		if b < 0 {
-			return pdp.UndefinedValue, bindError(bindErrorf(newUnknownFlagNameError(f, t), "%d", i), "flag names")
+			return pdp.UndefinedValue, bindError(ctx.locateItem(bindErrorf(newUnknownFlagNameError(f, t), "%d", i), flags, i), "flag names")
 		}
 
 		n |= 1 << uint(b)
@@ -238,18 +271,18 @@ func (ctx context) unmarshalFlags8Value(v []interface{}, t *pdp.FlagsType) (pdp.
 	return pdp.MakeFlagsValue8(n, t), nil
 }
 
-func (ctx context) unmarshalFlags16Value(v []interface{}, t *pdp.FlagsType) (pdp.AttributeValue, boundError) {
+func (ctx context) unmarshalFlags16Value(flags []interface{}, t *pdp.FlagsType) (pdp.AttributeValue, boundError) {
 	var n uint16
 
-	for i, v := range v {
+	for i, v := range flags {
 		f, err := ctx.validateString(v, "flag name")
 		if err != nil {
-			return pdp.UndefinedValue, bindError(bindErrorf(err, "%d", i), "flag names")
+			return pdp.UndefinedValue, bindError(ctx.locateItem(bindErrorf(err, "%d", i), flags, i), "flag names")
 		}
 
 		b := t.GetFlagBit(f)
 		if b < 0 {
-			return pdp.UndefinedValue, bindError(bindErrorf(newUnknownFlagNameError(f, t), "%d", i), "flag names")
+			return pdp.UndefinedValue, bindError(ctx.locateItem(bindErrorf(newUnknownFlagNameError(f, t), "%d", i), flags, i), "flag names")
 		}
 
 		n |= 1 << uint(b)
@@ -258,18 +291,18 @@ func (ctx context) unmarshalFlags16Value(v []interface{}, t *pdp.FlagsType) (pdp
 	return pdp.MakeFlagsValue16(n, t), nil
 }
 
-func (ctx context) unmarshalFlags32Value(v []interface{}, t *pdp.FlagsType) (pdp.AttributeValue, boundError) {
+func (ctx context) unmarshalFlags32Value(flags []interface{}, t *pdp.FlagsType) (pdp.AttributeValue, boundError) {
 	var n uint32
 
-	for i, v := range v {
+	for i, v := range flags {
 		f, err := ctx.validateString(v, "flag name")
 		if err != nil {
-			return pdp.UndefinedValue, bindError(bindErrorf(err, "%d", i), "flag names")
+			return pdp.UndefinedValue, bindError(ctx.locateItem(bindErrorf(err, "%d", i), flags, i), "flag names")
 		}
 
 		b := t.GetFlagBit(f)
 		if b < 0 {
-			return pdp.UndefinedValue, bindError(bindErrorf(newUnknownFlagNameError(f, t), "%d", i), "flag names")
+			return pdp.UndefinedValue, bindError(ctx.locateItem(bindErrorf(newUnknownFlagNameError(f, t), "%d", i), flags, i), "flag names")
 		}
 
 		n |= 1 << uint(b)
@@ -278,18 +311,18 @@ func (ctx context) unmarshalFlags32Value(v []interface{}, t *pdp.FlagsType) (pdp
 	return pdp.MakeFlagsValue32(n, t), nil
 }
 
-func (ctx context) unmarshalFlags64Value(v []interface{}, t *pdp.FlagsType) (pdp.AttributeValue, boundError) {
+func (ctx context) unmarshalFlags64Value(flags []interface{}, t *pdp.FlagsType) (pdp.AttributeValue, boundError) {
 	var n uint64
 
-	for i, v := range v {
+	for i, v := range flags {
 		f, err := ctx.validateString(v, "flag name")
 		if err != nil {
-			return pdp.UndefinedValue, bindError(bindErrorf(err, "%d", i), "flag names")
+			return pdp.UndefinedValue, bindError(ctx.locateItem(bindErrorf(err, "%d", i), flags, i), "flag names")
 		}
 
 		b := t.GetFlagBit(f)
 		if b < 0 {
-			return pdp.UndefinedValue, bindError(bindErrorf(newUnknownFlagNameError(f, t), "%d", i), "flag names")
+			return pdp.UndefinedValue, bindError(ctx.locateItem(bindErrorf(newUnknownFlagNameError(f, t), "%d", i), flags, i), "flag names")
 		}
 
 		n |= 1 << uint(b)
@@ -333,6 +366,12 @@ func (ctx context) unmarshalValueByType(t pdp.Type, v interface{}) (pdp.Attribut
 
 	case pdp.TypeListOfStrings:
 		return ctx.unmarshalListOfStringsValue(v)
+
+	case pdp.TypeDateTime:
+		return ctx.unmarshalDateTimeValue(v)
+
+	case pdp.TypeDuration:
+		return ctx.unmarshalDurationValue(v)
 	}
 
 	return pdp.UndefinedValue, newNotImplementedValueTypeError(t)
@@ -351,17 +390,22 @@ func (ctx context) unmarshalValue(v interface{}) (pdp.AttributeValue, boundError
 
 	t := ctx.symbols.GetType(strT)
 	if t == nil {
-		return pdp.UndefinedValue, newUnknownTypeError(strT)
+		return pdp.UndefinedValue, ctx.locateKey(newUnknownTypeError(strT), m, yastTagType)
 	}
 
 	if t == pdp.TypeUndefined {
-		return pdp.UndefinedValue, newInvalidTypeError(t)
+		return pdp.UndefinedValue, ctx.locateKey(newInvalidTypeError(t), m, yastTagType)
 	}
 
 	c, ok := m[yastTagContent]
 	if !ok {
-		return pdp.UndefinedValue, newMissingContentError()
+		return pdp.UndefinedValue, ctx.locate(newMissingContentError(), m)
+	}
+
+	av, err := ctx.unmarshalValueByType(t, c)
+	if err != nil {
+		return pdp.UndefinedValue, ctx.locateKey(err, m, yastTagContent)
 	}
 
-	return ctx.unmarshalValueByType(t, c)
+	return av, nil
 }
@@ -0,0 +1,135 @@
+package yast
+
+import (
+	"sort"
+
+	"github.com/infobloxopen/themis/pdp"
+)
+
+// variableScope holds variables of single variables section. Variables are
+// parsed on first reference so they can refer each other regardless of
+// order of definitions in the section.
+type variableScope struct {
+	path []string
+	m    map[interface{}]interface{}
+	raw  map[string]interface{}
+	vars map[string]*pdp.Variable
+	busy map[string]bool
+}
+
+// enterPolicy returns context for content of policy set or policy with
+// given ID.
+func (ctx context) enterPolicy(ID string, hidden bool) context {
+	if hidden {
+		ctx.hidden = true
+		return ctx
+	}
+
+	path := make([]string, len(ctx.path)+1)
+	copy(path, ctx.path)
+	path[len(ctx.path)] = ID
+	ctx.path = path
+
+	return ctx
+}
+
+// unmarshalVariables parses variables section of given map and returns
+// context where the variables are visible.
+func (ctx context) unmarshalVariables(m map[interface{}]interface{}) (context, boundError) {
+	vm, ok, err := ctx.extractMapOpt(m, yastTagVariables, "variables")
+	if !ok || err != nil {
+		return ctx, err
+	}
+
+	s := &variableScope{
+		path: ctx.path,
+		m:    vm,
+		raw:  make(map[string]interface{}, len(vm)),
+		vars: make(map[string]*pdp.Variable, len(vm)),
+		busy: make(map[string]bool, len(vm)),
+	}
+
+	IDs := make([]string, 0, len(vm))
+	for k, v := range vm {
+		ID, err := ctx.validateString(k, "variable name")
+		if err != nil {
+			return ctx, bindError(ctx.locateKey(err, vm, k), yastTagVariables)
+		}
+
+		s.raw[ID] = v
+		IDs = append(IDs, ID)
+	}
+	sort.Strings(IDs)
+
+	scopes := make([]*variableScope, len(ctx.scopes)+1)
+	copy(scopes, ctx.scopes)
+	scopes[len(ctx.scopes)] = s
+	ctx.scopes = scopes
+	ctx.its = nil
+
+	for _, ID := range IDs {
+		v, err := ctx.resolveVariable(len(scopes)-1, ID)
+		if err != nil {
+			return ctx, bindError(err, yastTagVariables)
+		}
+
+		if ctx.putVars && !ctx.hidden {
+			symbols := ctx.symbols
+			if ctx.cmdVars != nil {
+				symbols = *ctx.cmdVars
+			}
+
+			if err := symbols.PutVariable(ctx.path, v); err != nil {
+				return ctx, bindError(ctx.locateKey(bindError(err, ID), vm, ID), yastTagVariables)
+			}
+		}
+	}
+
+	return ctx, nil
+}
+
+// resolveVariable returns variable of scope with given index parsing it
+// if necessary.
+func (ctx context) resolveVariable(i int, ID string) (*pdp.Variable, boundError) {
+	s := ctx.scopes[i]
+	if v, ok := s.vars[ID]; ok {
+		return v, nil
+	}
+
+	if s.busy[ID] {
+		return nil, newVariableCycleError(ID)
+	}
+
+	s.busy[ID] = true
+	defer delete(s.busy, ID)
+
+	ctx.scopes = ctx.scopes[:i+1]
+	ctx.path = s.path
+	ctx.its = nil
+	e, err := ctx.unmarshalExpression(s.raw[ID])
+	if err != nil {
+		return nil, ctx.locateKey(bindError(err, ID), s.m, ID)
+	}
+
+	v := pdp.NewVariable(ID, e)
+	s.vars[ID] = v
+
+	return v, nil
+}
+
+// lookupVariable finds variable visible in current context. Inner variables
+// hide outer ones and variables of policies being parsed hide variables of
+// existing policies which come from symbol table.
+func (ctx context) lookupVariable(ID string) (pdp.Expression, boundError) {
+	for i := len(ctx.scopes) - 1; i >= 0; i-- {
+		if _, ok := ctx.scopes[i].raw[ID]; ok {
+			return ctx.resolveVariable(i, ID)
+		}
+	}
+
+	if v, ok := ctx.symbols.GetVariable(ctx.path, ID); ok {
+		return v, nil
+	}
+
+	return nil, newUnknownVariableError(ID)
+}
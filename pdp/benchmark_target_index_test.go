@@ -0,0 +1,71 @@
+// Performance measurement of target index
+package pdp
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/infobloxopen/go-trees/iptree"
+)
+
+func BenchmarkTargetIndex(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		policies := make([]Evaluable, n)
+		for i := range policies {
+			policies[i] = makeTargetIndexTestPolicy(fmt.Sprintf("p%d", i), EffectPermit,
+				makeSimpleStringTarget("s", fmt.Sprintf("v%d", i)))
+		}
+
+		p := NewPolicySet("test", false, MakeTarget(), policies, makeFirstApplicableEffectPCA, nil, nil)
+		plain := *p
+		plain.index = nil
+
+		ctx := &Context{a: map[string]interface{}{"s": MakeStringValue(fmt.Sprintf("v%d", n-1))}}
+
+		b.Run(fmt.Sprintf("Strings-%d-Linear", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				plain.Calculate(ctx)
+			}
+		})
+		b.Run(fmt.Sprintf("Strings-%d-Indexed", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				p.Calculate(ctx)
+			}
+		})
+	}
+
+	for _, n := range []int{100, 1000, 10000} {
+		policies := make([]Evaluable, n)
+		for i := range policies {
+			set := iptree.NewTree()
+			set.InplaceInsertNet(&net.IPNet{
+				IP:   net.IPv4(10, byte(i>>8), byte(i), 0).To4(),
+				Mask: net.CIDRMask(24, 32),
+			}, nil)
+
+			policies[i] = makeTargetIndexTestPolicy(fmt.Sprintf("p%d", i), EffectPermit,
+				Target{a: []AnyOf{{a: []AllOf{{m: []Match{{
+					m: functionSetOfNetworksContainsAddress{
+						set:   MakeSetOfNetworksValue(set),
+						value: MakeAddressDesignator("a")}}}}}}}})
+		}
+
+		p := NewPolicySet("test", false, MakeTarget(), policies, makeDenyOverridesPCA, nil, nil)
+		plain := *p
+		plain.index = nil
+
+		ctx := &Context{a: map[string]interface{}{"a": MakeAddressValue(net.IPv4(10, byte((n-1)>>8), byte(n-1), 1))}}
+
+		b.Run(fmt.Sprintf("Networks-%d-Linear", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				plain.Calculate(ctx)
+			}
+		})
+		b.Run(fmt.Sprintf("Networks-%d-Indexed", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				p.Calculate(ctx)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -76,6 +77,25 @@ func (s *LocalContentStorage) Add(c *LocalContent) *LocalContentStorage {
 	return &LocalContentStorage{r: s.r.Insert(c.id, c)}
 }
 
+// Delete removes content with given id from storage. If tag isn't nil
+// the content must have matching tag (see GetLocalContent). The method returns
+// copy of existing storage without the content. Existing storage isn't
+// affected by the operation.
+func (s *LocalContentStorage) Delete(cID string, tag *uuid.UUID) (*LocalContentStorage, error) {
+	if tag != nil {
+		if _, err := s.GetLocalContent(cID, tag); err != nil {
+			return nil, err
+		}
+	}
+
+	r, ok := s.r.Delete(cID)
+	if !ok {
+		return nil, newMissingContentError(cID)
+	}
+
+	return &LocalContentStorage{r: r}, nil
+}
+
 // GetLocalContent returns content from storage by given id only if the content
 // has its own tag and the tag matches to tag argument.
 func (s *LocalContentStorage) GetLocalContent(cID string, tag *uuid.UUID) (*LocalContent, error) {
@@ -104,6 +124,18 @@ func (s *LocalContentStorage) GetLocalContent(cID string, tag *uuid.UUID) (*Loca
 	return c, nil
 }
 
+// GetLocalContents returns all contents of the storage ordered by id.
+func (s *LocalContentStorage) GetLocalContents() []*LocalContent {
+	out := []*LocalContent{}
+	for p := range s.r.Enumerate() {
+		if c, ok := p.Value.(*LocalContent); ok {
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
 // NewTransaction creates new transaction for given content in the storage.
 func (s *LocalContentStorage) NewTransaction(cID string, tag *uuid.UUID) (*LocalContentStorageTransaction, error) {
 	c, err := s.GetLocalContent(cID, tag)
@@ -170,6 +202,20 @@ func (u *ContentUpdate) Append(op int, path []string, entity *ContentItem) {
 	u.cmds = append(u.cmds, &command{op: op, path: path, entity: entity})
 }
 
+// Iterate calls f for each command of the update in order with operation,
+// path and entity (nil for delete operation). It stops at first error
+// returned by f.
+func (u *ContentUpdate) Iterate(f func(op int, path []string, entity *ContentItem) error) error {
+	for _, cmd := range u.cmds {
+		e, _ := cmd.entity.(*ContentItem)
+		if err := f(cmd.op, cmd.path, e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // String implements Stringer interface.
 func (u *ContentUpdate) String() string {
 	if u == nil {
@@ -411,6 +457,31 @@ func NewLocalContent(id string, tag *uuid.UUID, symbols Symbols, items []*Conten
 	return c
 }
 
+// GetID returns id of the content.
+func (c *LocalContent) GetID() string {
+	return c.id
+}
+
+// GetTag returns tag of the content. It's nil for untagged content.
+func (c *LocalContent) GetTag() *uuid.UUID {
+	return c.tag
+}
+
+// GetItemsCount returns number of content items in the content.
+func (c *LocalContent) GetItemsCount() int {
+	n := 0
+	for range c.items.Enumerate() {
+		n++
+	}
+
+	return n
+}
+
+// GetSymbols returns read-only copy of symbol table of the content.
+func (c *LocalContent) GetSymbols() Symbols {
+	return c.symbols.makeROCopy()
+}
+
 // Get returns content item of given id.
 func (c *LocalContent) Get(ID string) (*ContentItem, error) {
 	v, ok := c.items.Get(ID)
@@ -531,18 +602,33 @@ func (c *ContentItem) typeCheck(path []AttributeValue, v interface{}) (ContentSu
 			return nil, newInvalidContentKeyTypeError(c.k[len(path)], ContentKeyTypes)
 
 		case TypeString:
-			if _, ok := item.r.(ContentStringMap); !ok {
+			switch item.r.(type) {
+			default:
 				return nil, newInvalidContentStringMapError(v)
+
+			case ContentStringMap,
+				ContentStringFlags8Map, ContentStringFlags16Map,
+				ContentStringFlags32Map, ContentStringFlags64Map:
 			}
 
 		case TypeAddress, TypeNetwork:
-			if _, ok := item.r.(ContentNetworkMap); !ok {
+			switch item.r.(type) {
+			default:
 				return nil, newInvalidContentNetworkMapError(v)
+
+			case ContentNetworkMap,
+				ContentNetworkFlags8Map, ContentNetworkFlags16Map,
+				ContentNetworkFlags32Map, ContentNetworkFlags64Map:
 			}
 
 		case TypeDomain:
-			if _, ok := item.r.(ContentDomainMap); !ok {
+			switch item.r.(type) {
+			default:
 				return nil, newInvalidContentDomainMapError(v)
+
+			case ContentDomainMap,
+				ContentDomainFlags8Map, ContentDomainFlags16Map,
+				ContentDomainFlags32Map, ContentDomainFlags64Map:
 			}
 		}
 
@@ -615,7 +701,7 @@ func (c *ContentItem) typeCheck(path []AttributeValue, v interface{}) (ContentSu
 			}
 
 		case TypeDomain:
-			if _, ok := subItem.value.(string); !ok {
+			if _, ok := subItem.value.(domain.Name); !ok {
 				return nil, newInvalidContentValueTypeError(subItem.value, c.t)
 			}
 
@@ -638,6 +724,16 @@ func (c *ContentItem) typeCheck(path []AttributeValue, v interface{}) (ContentSu
 			if _, ok := subItem.value.([]string); !ok {
 				return nil, newInvalidContentValueTypeError(subItem.value, c.t)
 			}
+
+		case TypeDateTime:
+			if _, ok := subItem.value.(time.Time); !ok {
+				return nil, newInvalidContentValueTypeError(subItem.value, c.t)
+			}
+
+		case TypeDuration:
+			if _, ok := subItem.value.(time.Duration); !ok {
+				return nil, newInvalidContentValueTypeError(subItem.value, c.t)
+			}
 		}
 	}
 
@@ -2124,6 +2220,12 @@ func (v ContentValue) getValue(key AttributeValue, t Type) (AttributeValue, erro
 
 	case TypeListOfStrings:
 		return MakeListOfStringsValue(v.value.([]string)), nil
+
+	case TypeDateTime:
+		return MakeDateTimeValue(v.value.(time.Time)), nil
+
+	case TypeDuration:
+		return MakeDurationValue(v.value.(time.Duration)), nil
 	}
 
 	panic(fmt.Errorf("can't convert to value of unknown type with index %d", t))
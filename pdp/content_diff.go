@@ -0,0 +1,305 @@
+package pdp
+
+import (
+	"net"
+	"reflect"
+
+	"github.com/google/uuid"
+)
+
+// DiffContent creates content update which turns old content into new one.
+// The update deletes removed items and map keys, adds new ones and replaces
+// changed values. It goes down to the deepest map level where content
+// differs so the update is usually much smaller than the new content itself.
+// If type or keys of an item change the update replaces the whole item.
+// The function returns error if content ids differ or if new content declares
+// custom types which aren't declared by old content the same way (an update
+// can't declare or change types).
+func DiffContent(old, new *LocalContent, oldTag, newTag uuid.UUID) (*ContentUpdate, error) {
+	if old.id != new.id {
+		return nil, newContentDiffIDMismatchError(old.id, new.id)
+	}
+
+	for ID, t := range new.symbols.types {
+		ot, ok := old.symbols.types[ID]
+		if !ok || !equalContentTypes(ot, t) {
+			return nil, newContentDiffTypeMismatchError(t)
+		}
+	}
+
+	u := NewContentUpdate(old.id, oldTag, newTag)
+
+	for p := range old.items.Enumerate() {
+		if _, ok := new.items.Get(p.Key); !ok {
+			u.Append(UODelete, []string{p.Key}, nil)
+		}
+	}
+
+	var err error
+	for p := range new.items.Enumerate() {
+		// Keep reading all items even after error to let enumeration finish.
+		if err == nil {
+			err = diffContentItems(u, old, p.Key, p.Value)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+func diffContentItems(u *ContentUpdate, old *LocalContent, ID string, v interface{}) error {
+	n, ok := v.(*ContentItem)
+	if !ok {
+		return bindError(newInvalidContentItemError(v), ID)
+	}
+
+	// Update is applied to old content so it should refer to types
+	// of old content.
+	n = MakeContentMappingItem(ID, old.symbols.rebindType(n.t), n.k, n.r)
+
+	v, ok = old.items.Get(ID)
+	if !ok {
+		u.Append(UOAdd, []string{ID}, n)
+		return nil
+	}
+
+	o, ok := v.(*ContentItem)
+	if !ok || o.t != n.t || !equalContentKeys(o.k, n.k) {
+		u.Append(UOAdd, []string{ID}, n)
+		return nil
+	}
+
+	if len(n.k) > 0 {
+		return diffContentSubItems(u, []string{ID}, n, o.r, n.r, 0)
+	}
+
+	eq, err := equalContentSubItems(o.r, n.r, n.t, 0)
+	if err != nil {
+		return bindError(err, ID)
+	}
+
+	if !eq {
+		u.Append(UOAdd, []string{ID}, n)
+	}
+
+	return nil
+}
+
+func diffContentSubItems(u *ContentUpdate, path []string, c *ContentItem, o, n ContentSubItem, level int) error {
+	oPairs, err := getContentSubItemPairs(o)
+	if err != nil {
+		return err
+	}
+
+	nPairs, err := getContentSubItemPairs(n)
+	if err != nil {
+		return err
+	}
+
+	nIdx := make(map[string]interface{}, len(nPairs))
+	for _, p := range nPairs {
+		nIdx[p.key] = p.value
+	}
+
+	oIdx := make(map[string]interface{}, len(oPairs))
+	for _, p := range oPairs {
+		oIdx[p.key] = p.value
+		if _, ok := nIdx[p.key]; !ok {
+			u.Append(UODelete, appendDiffPath(path, p.key), nil)
+		}
+	}
+
+	depth := len(c.k) - level - 1
+	for _, p := range nPairs {
+		v, ok := oIdx[p.key]
+		if ok {
+			if depth > 0 {
+				om, ok := v.(ContentSubItem)
+				if !ok {
+					return bindError(newMapContentSubitemError(), p.key)
+				}
+
+				nm, ok := p.value.(ContentSubItem)
+				if !ok {
+					return bindError(newMapContentSubitemError(), p.key)
+				}
+
+				if err := diffContentSubItems(u, appendDiffPath(path, p.key), c, om, nm, level+1); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			eq, err := equalContentSubItems(v, p.value, c.t, 0)
+			if err != nil {
+				return bindError(err, p.key)
+			}
+
+			if eq {
+				continue
+			}
+		}
+
+		var e *ContentItem
+		if depth > 0 {
+			m, ok := p.value.(ContentSubItem)
+			if !ok {
+				return bindError(newMapContentSubitemError(), p.key)
+			}
+
+			e = MakeContentMappingItem("", c.t, c.k[level+1:], m)
+		} else {
+			e = MakeContentValueItem("", c.t, p.value)
+		}
+
+		u.Append(UOAdd, appendDiffPath(path, p.key), e)
+	}
+
+	return nil
+}
+
+// equalContentSubItems compares content values or maps by their JCON
+// representation.
+func equalContentSubItems(a, b interface{}, t Type, depth int) (bool, error) {
+	av, err := marshalContentSubItem(a, t, depth)
+	if err != nil {
+		return false, err
+	}
+
+	bv, err := marshalContentSubItem(b, t, depth)
+	if err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(av, bv), nil
+}
+
+func equalContentTypes(a, b Type) bool {
+	if a == b {
+		return true
+	}
+
+	af, ok := a.(*FlagsType)
+	if !ok {
+		return false
+	}
+
+	bf, ok := b.(*FlagsType)
+	return ok && af.n == bf.n && reflect.DeepEqual(af.b, bf.b)
+}
+
+func equalContentKeys(a, b []Type) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i, t := range a {
+		if t != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+type contentPair struct {
+	key   string
+	value interface{}
+}
+
+func getContentSubItemPairs(m ContentSubItem) ([]contentPair, error) {
+	out := []contentPair{}
+	switch m := m.(type) {
+	default:
+		return nil, newMapContentSubitemError()
+
+	case ContentStringMap:
+		for p := range m.tree.Enumerate() {
+			out = append(out, contentPair{key: p.Key, value: p.Value})
+		}
+
+	case ContentStringFlags8Map:
+		for p := range m.tree.Enumerate() {
+			out = append(out, contentPair{key: p.Key, value: p.Value})
+		}
+
+	case ContentStringFlags16Map:
+		for p := range m.tree.Enumerate() {
+			out = append(out, contentPair{key: p.Key, value: p.Value})
+		}
+
+	case ContentStringFlags32Map:
+		for p := range m.tree.Enumerate() {
+			out = append(out, contentPair{key: p.Key, value: p.Value})
+		}
+
+	case ContentStringFlags64Map:
+		for p := range m.tree.Enumerate() {
+			out = append(out, contentPair{key: p.Key, value: p.Value})
+		}
+
+	case ContentNetworkMap:
+		for p := range m.tree.Enumerate() {
+			out = append(out, contentPair{key: describeContentNetworkKey(p.Key), value: p.Value})
+		}
+
+	case ContentNetworkFlags8Map:
+		for p := range m.tree.Enumerate() {
+			out = append(out, contentPair{key: describeContentNetworkKey(p.Key), value: p.Value})
+		}
+
+	case ContentNetworkFlags16Map:
+		for p := range m.tree.Enumerate() {
+			out = append(out, contentPair{key: describeContentNetworkKey(p.Key), value: p.Value})
+		}
+
+	case ContentNetworkFlags32Map:
+		for p := range m.tree.Enumerate() {
+			out = append(out, contentPair{key: describeContentNetworkKey(p.Key), value: p.Value})
+		}
+
+	case ContentNetworkFlags64Map:
+		for p := range m.tree.Enumerate() {
+			out = append(out, contentPair{key: describeContentNetworkKey(p.Key), value: p.Value})
+		}
+
+	case ContentDomainMap:
+		for p := range m.tree.Enumerate() {
+			out = append(out, contentPair{key: p.Key, value: p.Value})
+		}
+
+	case ContentDomainFlags8Map:
+		for p := range m.tree.Enumerate() {
+			out = append(out, contentPair{key: p.Key, value: p.Value})
+		}
+
+	case ContentDomainFlags16Map:
+		for p := range m.tree.Enumerate() {
+			out = append(out, contentPair{key: p.Key, value: p.Value})
+		}
+
+	case ContentDomainFlags32Map:
+		for p := range m.tree.Enumerate() {
+			out = append(out, contentPair{key: p.Key, value: p.Value})
+		}
+
+	case ContentDomainFlags64Map:
+		for p := range m.tree.Enumerate() {
+			out = append(out, contentPair{key: p.Key, value: p.Value})
+		}
+	}
+
+	return out, nil
+}
+
+func describeContentNetworkKey(n *net.IPNet) string {
+	if ones, bits := n.Mask.Size(); ones == bits {
+		return n.IP.String()
+	}
+
+	return n.String()
+}
@@ -0,0 +1,241 @@
+package pdp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/infobloxopen/go-trees/domaintree"
+	"github.com/infobloxopen/go-trees/iptree"
+	"github.com/infobloxopen/go-trees/strtree"
+	"github.com/infobloxopen/go-trees/uintX/domaintree8"
+)
+
+func TestDiffContent(t *testing.T) {
+	oldTag := uuid.New()
+	newTag := uuid.New()
+
+	oldSymbols, oldFlags := makeTestContentDiffSymbols(t)
+	newSymbols, newFlags := makeTestContentDiffSymbols(t)
+
+	old := NewLocalContent("content", &oldTag, oldSymbols, []*ContentItem{
+		makeTestContentDiffStringMap(map[string]map[string]string{
+			"a": {"10.0.0.0/8": "first", "192.0.2.1": "second"},
+			"b": {"10.0.0.0/8": "third"},
+		}),
+		makeTestContentDiffFlagsMap(oldFlags, map[string]uint8{
+			"example.com": 1,
+			"example.net": 2,
+			"example.org": 4,
+		}),
+		MakeContentValueItem("value", TypeInteger, int64(5)),
+		MakeContentValueItem("same", TypeString, "test"),
+		MakeContentValueItem("deleted", TypeString, "test"),
+		MakeContentValueItem("retyped", TypeString, "test"),
+	})
+
+	dTree := &domaintree.Node{}
+	dTree.InplaceInsert(makeTestDomain("example.com"), makeTestDomain("example.net"))
+
+	new := NewLocalContent("content", nil, newSymbols, []*ContentItem{
+		makeTestContentDiffStringMap(map[string]map[string]string{
+			"a": {"10.0.0.0/8": "first", "192.0.2.2": "second"},
+			"c": {"2001:db8::/32": "fourth"},
+		}),
+		makeTestContentDiffFlagsMap(newFlags, map[string]uint8{
+			"example.com": 1,
+			"example.net": 3,
+			"example.edu": 0,
+		}),
+		MakeContentValueItem("value", TypeInteger, int64(6)),
+		MakeContentValueItem("same", TypeString, "test"),
+		MakeContentValueItem("retyped", TypeInteger, int64(1)),
+		MakeContentMappingItem("added", TypeDomain, MakeSignature(TypeDomain), MakeContentDomainMap(dTree)),
+	})
+
+	u, err := DiffContent(old, new, oldTag, newTag)
+	if err != nil {
+		t.Fatalf("Expected update but got error %s", err)
+	}
+
+	cmds := describeTestContentUpdate(u)
+	expCmds := []string{
+		"delete deleted",
+		"add added: Domain[Domain]",
+		"delete flags/example.org",
+		"add flags/example.edu: flags",
+		"add flags/example.net: flags",
+		"add retyped: Integer",
+		"delete str-net-map/b",
+		"delete str-net-map/a/192.0.2.1",
+		"add str-net-map/a/192.0.2.2: String",
+		"add str-net-map/c: String[Network]",
+		"add value: Integer",
+	}
+	if !reflect.DeepEqual(cmds, expCmds) {
+		t.Errorf("Expected commands:\n\t%s\nbut got:\n\t%s", strings.Join(expCmds, "\n\t"), strings.Join(cmds, "\n\t"))
+	}
+
+	s := NewLocalContentStorage([]*LocalContent{old})
+	tr, err := s.NewTransaction("content", &oldTag)
+	if err != nil {
+		t.Fatalf("Expected transaction but got error %s", err)
+	}
+
+	if err := tr.Apply(u); err != nil {
+		t.Fatalf("Expected update to be applied but got error %s", err)
+	}
+
+	s, err = tr.Commit(s)
+	if err != nil {
+		t.Fatalf("Expected new storage but got error %s", err)
+	}
+
+	c, err := s.GetLocalContent("content", &newTag)
+	if err != nil {
+		t.Fatalf("Expected updated content but got error %s", err)
+	}
+
+	u, err = DiffContent(c, new, newTag, uuid.New())
+	if err != nil {
+		t.Errorf("Expected update but got error %s", err)
+	} else if cmds := describeTestContentUpdate(u); len(cmds) > 0 {
+		t.Errorf("Expected no difference between updated and new content but got:\n\t%s", strings.Join(cmds, "\n\t"))
+	}
+}
+
+func TestDiffContentErrors(t *testing.T) {
+	tag := uuid.New()
+
+	_, err := DiffContent(
+		NewLocalContent("first", nil, MakeSymbols(), nil),
+		NewLocalContent("second", nil, MakeSymbols(), nil),
+		tag, tag,
+	)
+	if _, ok := err.(*contentDiffIDMismatchError); !ok {
+		t.Errorf("Expected *contentDiffIDMismatchError but got %T (%s)", err, err)
+	}
+
+	newSymbols := MakeSymbols()
+	ft, err := NewFlagsType("flags", "first", "second", "third")
+	if err != nil {
+		t.Fatalf("Expected flags type but got error %s", err)
+	}
+
+	if err := newSymbols.PutType(ft); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	_, err = DiffContent(
+		NewLocalContent("content", nil, MakeSymbols(), nil),
+		NewLocalContent("content", nil, newSymbols, nil),
+		tag, tag,
+	)
+	if _, ok := err.(*contentDiffTypeMismatchError); !ok {
+		t.Errorf("Expected *contentDiffTypeMismatchError but got %T (%s)", err, err)
+	}
+}
+
+func TestContentUpdateMarshalJSON(t *testing.T) {
+	symbols, ft := makeTestContentDiffSymbols(t)
+	tag := uuid.New()
+
+	old := NewLocalContent("content", &tag, symbols, []*ContentItem{
+		makeTestContentDiffFlagsMap(ft, map[string]uint8{"example.com": 1}),
+	})
+	new := NewLocalContent("content", nil, symbols, []*ContentItem{
+		makeTestContentDiffFlagsMap(ft, map[string]uint8{"example.com": 5}),
+		makeTestContentDiffStringMap(map[string]map[string]string{"a": {"192.0.2.1": "first"}}),
+	})
+
+	u, err := DiffContent(old, new, tag, tag)
+	if err != nil {
+		t.Fatalf("Expected update but got error %s", err)
+	}
+
+	b, err := json.Marshal(u)
+	if err != nil {
+		t.Fatalf("Expected JSON but got error %s", err)
+	}
+
+	e := `[` +
+		`{"op":"add","path":["flags","example.com"],"entity":{"type":"flags","data":["first","third"]}},` +
+		`{"op":"add","path":["str-net-map"],"entity":{"type":"String","keys":["String","Network"],` +
+		`"data":{"a":{"192.0.2.1":"first"}}}}` +
+		`]`
+	if string(b) != e {
+		t.Errorf("Expected JSON:\n%s\nbut got:\n%s", e, b)
+	}
+}
+
+func makeTestContentDiffSymbols(t *testing.T) (Symbols, Type) {
+	ft, err := NewFlagsType("flags", "first", "second", "third")
+	if err != nil {
+		t.Fatalf("Expected flags type but got error %s", err)
+	}
+
+	s := MakeSymbols()
+	if err := s.PutType(ft); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	return s, ft
+}
+
+func makeTestContentDiffStringMap(m map[string]map[string]string) *ContentItem {
+	sTree := strtree.NewTree()
+	for k, sub := range m {
+		nTree := iptree.NewTree()
+		for n, v := range sub {
+			if strings.Contains(n, "/") {
+				nTree.InplaceInsertNet(makeTestNetwork(n), v)
+			} else {
+				nTree.InplaceInsertIP(net.ParseIP(n), v)
+			}
+		}
+
+		sTree.InplaceInsert(k, MakeContentNetworkMap(nTree))
+	}
+
+	return MakeContentMappingItem("str-net-map", TypeString, MakeSignature(TypeString, TypeNetwork),
+		MakeContentStringMap(sTree))
+}
+
+func makeTestContentDiffFlagsMap(t Type, m map[string]uint8) *ContentItem {
+	dTree := &domaintree8.Node{}
+	for k, v := range m {
+		dTree.InplaceInsert(makeTestDomain(k), v)
+	}
+
+	return MakeContentMappingItem("flags", t, MakeSignature(TypeDomain), MakeContentDomainFlags8Map(dTree))
+}
+
+func describeTestContentUpdate(u *ContentUpdate) []string {
+	out := []string{}
+	u.Iterate(func(op int, path []string, entity *ContentItem) error {
+		p := strings.Join(path, "/")
+		if entity == nil {
+			out = append(out, fmt.Sprintf("%s %s", strings.ToLower(UpdateOpNames[op]), p))
+			return nil
+		}
+
+		t := entity.t.String()
+		if len(entity.k) > 0 {
+			keys := make([]string, len(entity.k))
+			for i, k := range entity.k {
+				keys[i] = k.String()
+			}
+
+			t += "[" + strings.Join(keys, ", ") + "]"
+		}
+
+		out = append(out, fmt.Sprintf("%s %s: %s", strings.ToLower(UpdateOpNames[op]), p, t))
+		return nil
+	})
+
+	return out
+}
@@ -0,0 +1,191 @@
+package pdp
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/infobloxopen/go-trees/domain"
+	"github.com/infobloxopen/go-trees/domaintree"
+	"github.com/infobloxopen/go-trees/iptree"
+	"github.com/infobloxopen/go-trees/strtree"
+)
+
+// Content item representation for marshaling (the same as JCON content item)
+type contentItemFmt struct {
+	Type string      `json:"type"`
+	Keys []string    `json:"keys,omitempty"`
+	Data interface{} `json:"data"`
+}
+
+// Content update command representation for marshaling (the same as JCON
+// update command)
+type contentCommandFmt struct {
+	Op     string       `json:"op"`
+	Path   []string     `json:"path"`
+	Entity *ContentItem `json:"entity,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler interface. It represents content item
+// in JCON format. Custom type of the item is represented by its name so it
+// should be declared to get the item back.
+func (c *ContentItem) MarshalJSON() ([]byte, error) {
+	data, err := marshalContentSubItem(c.r, c.t, len(c.k))
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	if len(c.k) > 0 {
+		keys = make([]string, len(c.k))
+		for i, k := range c.k {
+			keys[i] = k.String()
+		}
+	}
+
+	return json.Marshal(contentItemFmt{
+		Type: c.t.String(),
+		Keys: keys,
+		Data: data,
+	})
+}
+
+// MarshalJSON implements json.Marshaler interface. It represents content
+// update as list of commands in JCON update format.
+func (u *ContentUpdate) MarshalJSON() ([]byte, error) {
+	cmds := make([]contentCommandFmt, len(u.cmds))
+	for i, cmd := range u.cmds {
+		cmds[i] = contentCommandFmt{
+			Op:   strings.ToLower(UpdateOpNames[cmd.op]),
+			Path: cmd.path,
+		}
+
+		if cmd.op == UOAdd {
+			if e, ok := cmd.entity.(*ContentItem); ok {
+				cmds[i].Entity = e
+			}
+		}
+	}
+
+	return json.Marshal(cmds)
+}
+
+func marshalContentSubItem(v interface{}, t Type, depth int) (interface{}, error) {
+	if depth <= 0 {
+		if cv, ok := v.(ContentValue); ok {
+			v = cv.value
+		}
+
+		return marshalContentValue(v, t)
+	}
+
+	m, ok := v.(ContentSubItem)
+	if !ok {
+		return nil, newInvalidContentValueError(v)
+	}
+
+	pairs, err := getContentSubItemPairs(m)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{}, len(pairs))
+	for _, p := range pairs {
+		item, err := marshalContentSubItem(p.value, t, depth-1)
+		if err != nil {
+			return nil, bindError(err, p.key)
+		}
+
+		out[p.key] = item
+	}
+
+	return out, nil
+}
+
+func marshalContentValue(v interface{}, t Type) (interface{}, error) {
+	if ft, ok := t.(*FlagsType); ok {
+		var n uint64
+		switch v := v.(type) {
+		default:
+			return nil, newInvalidContentValueTypeError(v, t)
+
+		case uint8:
+			n = uint64(v)
+
+		case uint16:
+			n = uint64(v)
+
+		case uint32:
+			n = uint64(v)
+
+		case uint64:
+			n = v
+		}
+
+		flags := []string{}
+		for i, f := range ft.b {
+			if n&(1<<uint(i)) != 0 {
+				flags = append(flags, f)
+			}
+		}
+
+		return flags, nil
+	}
+
+	switch t {
+	case TypeBoolean, TypeString, TypeInteger, TypeFloat, TypeListOfStrings:
+		return v, nil
+
+	case TypeAddress:
+		if a, ok := v.(net.IP); ok {
+			return a.String(), nil
+		}
+
+	case TypeNetwork:
+		if n, ok := v.(*net.IPNet); ok {
+			return n.String(), nil
+		}
+
+	case TypeDomain:
+		if d, ok := v.(domain.Name); ok {
+			return d.String(), nil
+		}
+
+	case TypeSetOfStrings:
+		if s, ok := v.(*strtree.Tree); ok {
+			return SortSetOfStrings(s), nil
+		}
+
+	case TypeSetOfNetworks:
+		if s, ok := v.(*iptree.Tree); ok {
+			n := SortSetOfNetworks(s)
+			out := make([]string, len(n))
+			for i, n := range n {
+				out[i] = n.String()
+			}
+
+			return out, nil
+		}
+
+	case TypeSetOfDomains:
+		if s, ok := v.(*domaintree.Node); ok {
+			return SortSetOfDomains(s), nil
+		}
+
+	case TypeDateTime:
+		if d, ok := v.(time.Time); ok {
+			return d.Format(time.RFC3339Nano), nil
+		}
+
+	case TypeDuration:
+		if d, ok := v.(time.Duration); ok {
+			return d.String(), nil
+		}
+
+	default:
+		return nil, newUnknownTypeSerializationError(t)
+	}
+
+	return nil, newInvalidContentValueTypeError(v, t)
+}
@@ -1051,6 +1051,79 @@ func TestLocalContentStorageGetByValues(t *testing.T) {
 	}
 }
 
+func TestLocalContentStorageGetLocalContents(t *testing.T) {
+	tag := uuid.New()
+
+	s := NewLocalContentStorage([]*LocalContent{
+		NewLocalContent("second", &tag, MakeSymbols(), []*ContentItem{
+			MakeContentValueItem("a", TypeString, MakeStringValue("a")),
+			MakeContentValueItem("b", TypeString, MakeStringValue("b")),
+		}),
+		NewLocalContent("first", nil, MakeSymbols(), nil),
+	})
+
+	cs := s.GetLocalContents()
+	if len(cs) != 2 {
+		t.Fatalf("Expected 2 contents but got %d", len(cs))
+	}
+
+	if cs[0].GetID() != "first" || cs[0].GetTag() != nil || cs[0].GetItemsCount() != 0 {
+		t.Errorf("Expected untagged empty %q content but got %q (%s) with %d items",
+			"first", cs[0].GetID(), cs[0], cs[0].GetItemsCount())
+	}
+
+	if cs[1].GetID() != "second" || cs[1].GetTag() != &tag || cs[1].GetItemsCount() != 2 {
+		t.Errorf("Expected %q content with tag %s and 2 items but got %q (%s) with %d items",
+			"second", tag, cs[1].GetID(), cs[1], cs[1].GetItemsCount())
+	}
+}
+
+func TestLocalContentStorageDelete(t *testing.T) {
+	tag := uuid.New()
+
+	s := NewLocalContentStorage([]*LocalContent{
+		NewLocalContent("tagged", &tag, MakeSymbols(), nil),
+		NewLocalContent("untagged", nil, MakeSymbols(), nil),
+	})
+
+	if _, err := s.Delete("missing", nil); err == nil {
+		t.Error("Expected *MissingContentError but got nothing")
+	} else if _, ok := err.(*MissingContentError); !ok {
+		t.Errorf("Expected *MissingContentError but got %T (%s)", err, err)
+	}
+
+	other := uuid.New()
+	if _, err := s.Delete("tagged", &other); err == nil {
+		t.Error("Expected *ContentTagsNotMatchError but got nothing")
+	} else if _, ok := err.(*ContentTagsNotMatchError); !ok {
+		t.Errorf("Expected *ContentTagsNotMatchError but got %T (%s)", err, err)
+	}
+
+	if _, err := s.Delete("untagged", &tag); err == nil {
+		t.Error("Expected *UntaggedContentModificationError but got nothing")
+	} else if _, ok := err.(*UntaggedContentModificationError); !ok {
+		t.Errorf("Expected *UntaggedContentModificationError but got %T (%s)", err, err)
+	}
+
+	ns, err := s.Delete("tagged", &tag)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	ns, err = ns.Delete("untagged", nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if cs := ns.GetLocalContents(); len(cs) != 0 {
+		t.Errorf("Expected no contents after delete but got %d", len(cs))
+	}
+
+	if cs := s.GetLocalContents(); len(cs) != 2 {
+		t.Errorf("Expected original storage to keep 2 contents but got %d", len(cs))
+	}
+}
+
 func TestLocalContentStorageGetAggregated(t *testing.T) {
 
 	sm1 := strtree.NewTree()
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/infobloxopen/go-trees/domain"
 	"github.com/infobloxopen/go-trees/domaintree"
@@ -62,6 +63,15 @@ var (
 type Context struct {
 	a map[string]interface{}
 	c *LocalContentStorage
+
+	clock func() time.Time
+	t     *time.Time
+
+	tr  *tracer
+	cov *coverer
+
+	it   map[string]AttributeValue
+	vars map[*Variable]variableValue
 }
 
 // EffectNameFromEnum returns human readable name for Effect enum
@@ -134,6 +144,30 @@ func NewContextFromBytes(c *LocalContentStorage, b []byte) (*Context, error) {
 	return ctx, nil
 }
 
+// SetClock sets function which context uses to get current time for "now"
+// and other time related expressions. Without the function (or if it's set
+// to nil) context uses time.Now. Current time is taken once per context so
+// all expressions of the same request see the same time.
+func (c *Context) SetClock(f func() time.Time) {
+	c.clock = f
+	c.t = nil
+}
+
+func (c *Context) now() time.Time {
+	if c.t == nil {
+		var t time.Time
+		if c.clock != nil {
+			t = c.clock()
+		} else {
+			t = time.Now()
+		}
+
+		c.t = &t
+	}
+
+	return *c.t
+}
+
 // String implements Stringer interface.
 func (c *Context) String() string {
 	lines := []string{}
@@ -346,6 +380,24 @@ func (c *Context) calculateListOfStringsExpression(e Expression) ([]string, erro
 	return v.listOfStrings()
 }
 
+func (c *Context) calculateDateTimeExpression(e Expression) (time.Time, error) {
+	v, err := e.Calculate(c)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return v.dateTime()
+}
+
+func (c *Context) calculateDurationExpression(e Expression) (time.Duration, error) {
+	v, err := e.Calculate(c)
+	if err != nil {
+		return 0, err
+	}
+
+	return v.duration()
+}
+
 func (c *Context) calculateFlags8Expression(e Expression) (uint8, error) {
 	v, err := e.Calculate(c)
 	if err != nil {
@@ -449,4 +501,5 @@ type Evaluable interface {
 	getOrder() int
 	setOrder(ord int)
 	describe() string
+	isApplicable(ctx *Context) (bool, boundError)
 }
@@ -95,6 +95,61 @@ func TestContext(t *testing.T) {
 	}
 }
 
+func TestContextFromBytesWithCollections(t *testing.T) {
+	b, err := MarshalRequestAssignments([]AttributeAssignment{
+		MakeSetOfStringsAssignment("groups", newStrTree("users", "admins")),
+		MakeSetOfNetworksAssignment("networks", newIPTree(makeTestNetwork("192.0.2.0/24"))),
+		MakeSetOfDomainsAssignment("domains", newDomainTree(makeTestDomain("example.com"))),
+		MakeListOfStringsAssignment("roles", []string{"reader", "writer"}),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	ctx, err := NewContextFromBytes(nil, b)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	testCases := []struct {
+		name string
+		args []Expression
+		r    bool
+	}{
+		{"contains", []Expression{MakeSetOfStringsDesignator("groups"), MakeStringValue("admins")}, true},
+		{"contains", []Expression{MakeSetOfStringsDesignator("groups"), MakeStringValue("guests")}, false},
+		{"contains", []Expression{MakeSetOfNetworksDesignator("networks"),
+			MakeAddressValue(net.ParseIP("192.0.2.1"))}, true},
+		{"contains", []Expression{MakeSetOfDomainsDesignator("domains"),
+			MakeDomainValue(makeTestDomain("www.example.com"))}, true},
+		{"contains", []Expression{MakeListOfStringsDesignator("roles"), MakeStringValue("writer")}, true},
+	}
+
+	for _, tc := range testCases {
+		desc := tc.name + "(" + describeTestArgs(tc.args) + ")"
+		maker := findValidator(tc.name, tc.args...)
+		if maker == nil {
+			t.Errorf("Expected function for %s but got nothing", desc)
+			continue
+		}
+
+		assertBooleanExpression(t, maker(tc.args), ctx, tc.r)
+	}
+
+	args := []Expression{MakeSetOfStringsDesignator("groups"), MakeSetOfStringsValue(newStrTree("admins", "ops"))}
+	maker := findValidator("intersect", args...)
+	if maker == nil {
+		t.Fatalf("Expected function for intersect(%s) but got nothing", describeTestArgs(args))
+	}
+
+	v, err := maker(args).Calculate(ctx)
+	if err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	} else if s, err := v.Serialize(); err != nil || s != "\"admins\"" {
+		t.Errorf("Expected \"admins\" as intersection but got %q (%v)", s, err)
+	}
+}
+
 func TestCalcValues(t *testing.T) {
 	testcases := []struct {
 		input    []AttributeAssignment
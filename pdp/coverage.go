@@ -0,0 +1,264 @@
+package pdp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Coverage collects statistics of policy sets, policies and rules evaluation
+// over a number of requests. Contexts with enabled coverage put their results
+// to the same collector so it can be shared by several goroutines.
+type Coverage struct {
+	lock sync.Mutex
+	hits map[interface{}]*coverageHits
+}
+
+type coverageHits struct {
+	hits      int
+	effects   map[string]int
+	target    map[string]int
+	condition map[string]int
+}
+
+type coverageRuleKey struct {
+	p   *Policy
+	ord int
+}
+
+type coverer struct {
+	c *Coverage
+	p *Policy
+}
+
+// NewCoverage creates empty coverage collector.
+func NewCoverage() *Coverage {
+	return &Coverage{hits: make(map[interface{}]*coverageHits)}
+}
+
+// EnableCoverage makes context to put results of each evaluated policy set,
+// policy and rule to given coverage collector.
+func (c *Context) EnableCoverage(cov *Coverage) {
+	c.cov = &coverer{c: cov}
+}
+
+func (c *Context) coverPolicy(p *Policy) *Policy {
+	if c == nil || c.cov == nil {
+		return nil
+	}
+
+	prev := c.cov.p
+	c.cov.p = p
+	return prev
+}
+
+func (c *Context) cover(e Evaluable, n *TraceNode) {
+	if c == nil || c.cov == nil {
+		return
+	}
+
+	c.cov.c.put(e, n)
+}
+
+func (c *Context) coverRule(r Rule, n *TraceNode) {
+	if c == nil || c.cov == nil || c.cov.p == nil {
+		return
+	}
+
+	c.cov.c.put(coverageRuleKey{p: c.cov.p, ord: r.ord}, n)
+}
+
+func (c *Coverage) put(key interface{}, n *TraceNode) {
+	if n == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	h, ok := c.hits[key]
+	if !ok {
+		h = &coverageHits{
+			effects:   make(map[string]int),
+			target:    make(map[string]int),
+			condition: make(map[string]int),
+		}
+		c.hits[key] = h
+	}
+
+	h.hits++
+	h.effects[n.Effect]++
+	if len(n.Target) > 0 {
+		h.target[n.Target]++
+	}
+
+	if len(n.Condition) > 0 {
+		h.condition[n.Condition]++
+	}
+}
+
+// CoverageReport represents coverage of policies. Requests is a number
+// of requests evaluated with the policies. Entities contains all policy sets,
+// policies and rules in depth-first order.
+type CoverageReport struct {
+	Requests int              `json:"requests"`
+	Covered  int              `json:"covered"`
+	Total    int              `json:"total"`
+	Entities []CoverageEntity `json:"entities"`
+}
+
+// CoverageEntity represents coverage of particular policy set, policy or rule.
+// Path contains ids in the form accepted by GetAtPath (hidden entity appears
+// as "#" followed by its position in parent). Hits is a number of times
+// the entity has been evaluated. Effects, Target and Condition count
+// evaluation results by effect name and by result of target matching
+// and rule's condition (with the same values as in evaluation trace).
+type CoverageEntity struct {
+	Kind      string         `json:"kind"`
+	Path      []string       `json:"path"`
+	Hits      int            `json:"hits"`
+	Effects   map[string]int `json:"effects,omitempty"`
+	Target    map[string]int `json:"target,omitempty"`
+	Condition map[string]int `json:"condition,omitempty"`
+}
+
+// Report creates coverage report for given policies.
+func (c *Coverage) Report(s *PolicyStorage) *CoverageReport {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	r := &CoverageReport{Entities: []CoverageEntity{}}
+	if s != nil && s.policies != nil {
+		c.reportEvaluable(r, s.policies, nil, 0)
+		r.Requests = r.Entities[0].Hits
+	}
+
+	return r
+}
+
+func (c *Coverage) reportEvaluable(r *CoverageReport, e Evaluable, parent []string, i int) {
+	switch e := e.(type) {
+	case *PolicySet:
+		path := c.reportEntity(r, TraceKindPolicySet, e, append(parent, lintPathItem(e.id, e.hidden, i)))
+		for i, p := range e.policies {
+			c.reportEvaluable(r, p, path, i)
+		}
+
+	case *Policy:
+		path := c.reportEntity(r, TraceKindPolicy, e, append(parent, lintPathItem(e.id, e.hidden, i)))
+		for i, rule := range e.rules {
+			c.reportEntity(r, TraceKindRule, coverageRuleKey{p: e, ord: rule.ord},
+				append(path, lintPathItem(rule.id, rule.hidden, i)))
+		}
+	}
+}
+
+func (c *Coverage) reportEntity(r *CoverageReport, kind string, key interface{}, path []string) []string {
+	p := make([]string, len(path))
+	copy(p, path)
+
+	e := CoverageEntity{
+		Kind: kind,
+		Path: p,
+	}
+
+	if h, ok := c.hits[key]; ok {
+		e.Hits = h.hits
+		e.Effects = copyCoverageCounts(h.effects)
+		e.Target = copyCoverageCounts(h.target)
+		e.Condition = copyCoverageCounts(h.condition)
+		r.Covered++
+	}
+
+	r.Total++
+	r.Entities = append(r.Entities, e)
+
+	return p
+}
+
+func copyCoverageCounts(m map[string]int) map[string]int {
+	if len(m) <= 0 {
+		return nil
+	}
+
+	out := make(map[string]int, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+
+	return out
+}
+
+// Uncovered returns entities which have never been evaluated.
+func (r *CoverageReport) Uncovered() []CoverageEntity {
+	out := []CoverageEntity{}
+	for _, e := range r.Entities {
+		if e.Hits <= 0 {
+			out = append(out, e)
+		}
+	}
+
+	return out
+}
+
+// String implements Stringer interface and returns human readable
+// representation of the report.
+func (r *CoverageReport) String() string {
+	percent := 0.0
+	if r.Total > 0 {
+		percent = 100 * float64(r.Covered) / float64(r.Total)
+	}
+
+	lines := []string{
+		fmt.Sprintf("coverage: %d of %d entities reached by %d requests (%.1f%%)",
+			r.Covered, r.Total, r.Requests, percent),
+	}
+
+	for _, e := range r.Entities {
+		line := fmt.Sprintf("%s %s: %d hits", e.Kind, strings.Join(e.Path, "/"), e.Hits)
+
+		details := []string{}
+		if len(e.Effects) > 0 {
+			details = append(details, describeCoverageCounts(e.Effects))
+		}
+
+		if len(e.Target) > 0 {
+			details = append(details, "target "+describeCoverageCounts(e.Target))
+		}
+
+		if len(e.Condition) > 0 {
+			details = append(details, "condition "+describeCoverageCounts(e.Condition))
+		}
+
+		if len(details) > 0 {
+			line += " (" + strings.Join(details, "; ") + ")"
+		}
+
+		lines = append(lines, line)
+	}
+
+	if u := r.Uncovered(); len(u) > 0 {
+		lines = append(lines, "never reached:")
+		for _, e := range u {
+			lines = append(lines, fmt.Sprintf("  %s %s", e.Kind, strings.Join(e.Path, "/")))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func describeCoverageCounts(m map[string]int) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]string, len(keys))
+	for i, k := range keys {
+		items[i] = fmt.Sprintf("%s: %d", k, m[k])
+	}
+
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,116 @@
+package pdp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	p := makeSimplePolicySet("root",
+		NewPolicy("first", false, makeSimpleStringTarget("s", "first"),
+			[]*Rule{
+				NewRule("condition", false, Target{},
+					functionStringEqual{
+						first:  MakeStringDesignator("x"),
+						second: MakeStringValue("test"),
+					}, EffectDeny, nil),
+				makeSimpleRule("permit", EffectPermit),
+			},
+			makeFirstApplicableEffectRCA, nil, nil),
+		makeSimplePolicy("second",
+			makeSimpleRule("deny", EffectDeny),
+			makeSimpleHiddenRule(EffectPermit),
+		),
+	)
+	s := NewPolicyStorage(p, Symbols{}, nil)
+
+	cov := NewCoverage()
+	for _, attrs := range [][]AttributeAssignment{
+		{MakeStringAssignment("s", "first"), MakeStringAssignment("x", "test")},
+		{MakeStringAssignment("s", "first"), MakeStringAssignment("x", "other")},
+		{MakeStringAssignment("s", "first")},
+	} {
+		ctx, err := NewContext(nil, len(attrs), func(i int) (string, AttributeValue, error) {
+			v, err := attrs[i].GetValue()
+			return attrs[i].GetID(), v, err
+		})
+		if err != nil {
+			t.Fatalf("Expected context but got error %s", err)
+		}
+
+		ctx.EnableCoverage(cov)
+		s.Root().Calculate(ctx)
+	}
+
+	e := &CoverageReport{
+		Requests: 3,
+		Covered:  4,
+		Total:    7,
+		Entities: []CoverageEntity{
+			{
+				Kind:    TraceKindPolicySet,
+				Path:    []string{"root"},
+				Hits:    3,
+				Effects: map[string]int{"Deny": 1, "Permit": 1, "Indeterminate{D}": 1},
+				Target:  map[string]int{TraceMatched: 3},
+			},
+			{
+				Kind:    TraceKindPolicy,
+				Path:    []string{"root", "first"},
+				Hits:    3,
+				Effects: map[string]int{"Deny": 1, "Permit": 1, "Indeterminate{D}": 1},
+				Target:  map[string]int{TraceMatched: 3},
+			},
+			{
+				Kind:      TraceKindRule,
+				Path:      []string{"root", "first", "condition"},
+				Hits:      3,
+				Effects:   map[string]int{"Deny": 1, "NotApplicable": 1, "Indeterminate{D}": 1},
+				Target:    map[string]int{TraceMatched: 3},
+				Condition: map[string]int{TraceTrue: 1, TraceFalse: 1, TraceError: 1},
+			},
+			{
+				Kind:    TraceKindRule,
+				Path:    []string{"root", "first", "permit"},
+				Hits:    1,
+				Effects: map[string]int{"Permit": 1},
+				Target:  map[string]int{TraceMatched: 1},
+			},
+			{
+				Kind: TraceKindPolicy,
+				Path: []string{"root", "second"},
+			},
+			{
+				Kind: TraceKindRule,
+				Path: []string{"root", "second", "deny"},
+			},
+			{
+				Kind: TraceKindRule,
+				Path: []string{"root", "second", "#1"},
+			},
+		},
+	}
+
+	r := cov.Report(s)
+	if !reflect.DeepEqual(r, e) {
+		b, _ := json.Marshal(r)
+		t.Errorf("Expected report:\n%s\nbut got:\n%s", e, b)
+	}
+
+	u := r.Uncovered()
+	if len(u) != 3 || !reflect.DeepEqual(u[0].Path, []string{"root", "second"}) {
+		t.Errorf("Expected policy \"second\" and its rules as uncovered but got %#v", u)
+	}
+
+	str := r.String()
+	if !strings.HasPrefix(str, "coverage: 4 of 7 entities reached by 3 requests (57.1%)\n") ||
+		!strings.HasSuffix(str, "never reached:\n  policy root/second\n  rule root/second/deny\n  rule root/second/#1") {
+		t.Errorf("Unexpected text report:\n%s", str)
+	}
+
+	if r := cov.Report(nil); r.Total != 0 || r.Requests != 0 {
+		t.Errorf("Expected empty report for no policies but got %#v", r)
+	}
+}
@@ -0,0 +1,209 @@
+package pdp
+
+import "strings"
+
+// CustomRuleCombiningAlg is an interface for rule combining algorithms
+// implemented outside of the package. Execute method gets all rules of policy
+// (or rules selected by mapper if the algorithm is used as nested mapper
+// algorithm) and should combine their responses to a single one. Rules can be
+// evaluated with their Calculate method. MarshalJSON method is used to dump
+// the algorithm by storage marshalling.
+type CustomRuleCombiningAlg interface {
+	Execute(rules []*Rule, ctx *Context) Response
+	MarshalJSON() ([]byte, error)
+}
+
+// CustomPolicyCombiningAlg is an interface for policy combining algorithms
+// implemented outside of the package. Execute method gets all child policies
+// and policy sets (or ones selected by mapper) and should combine their
+// responses to a single one. MarshalJSON method is used to dump the algorithm
+// by storage marshalling.
+type CustomPolicyCombiningAlg interface {
+	Execute(policies []Evaluable, ctx *Context) Response
+	MarshalJSON() ([]byte, error)
+}
+
+// CustomRuleCombiningAlgMaker creates instance of custom rule combining
+// algorithm. It gets the same arguments as RuleCombiningAlgMaker.
+type CustomRuleCombiningAlgMaker func(rules []*Rule, params interface{}) CustomRuleCombiningAlg
+
+// CustomPolicyCombiningAlgMaker creates instance of custom policy combining
+// algorithm. It gets the same arguments as PolicyCombiningAlgMaker.
+type CustomPolicyCombiningAlgMaker func(policies []Evaluable, params interface{}) CustomPolicyCombiningAlg
+
+// CombiningAlgParamsParser converts algorithm parameters as they are defined
+// in policies to parameters which algorithm maker expects. Raw parameters
+// contain all fields of algorithm definition except reserved ones ("id",
+// "map", "default", "error", "order" and "alg"). Values of the fields are
+// strings, float64 numbers, booleans, nils, []interface{} lists
+// and map[string]interface{} objects.
+type CombiningAlgParamsParser func(params map[string]interface{}) (interface{}, error)
+
+var (
+	// RuleCombiningAlgParamsParsers maps id of custom rule combining
+	// algorithm which requires parameters to parser of its parameters.
+	RuleCombiningAlgParamsParsers = map[string]CombiningAlgParamsParser{}
+
+	// PolicyCombiningAlgParamsParsers maps id of custom policy combining
+	// algorithm which requires parameters to parser of its parameters.
+	PolicyCombiningAlgParamsParsers = map[string]CombiningAlgParamsParser{}
+)
+
+// customRCA keeps id (in lower case) and parameters of the algorithm along
+// with its instance so policy diff can compare algorithms.
+type customRCA struct {
+	CustomRuleCombiningAlg
+	id     string
+	params interface{}
+}
+
+func (a customRCA) execute(rules []*Rule, ctx *Context) Response {
+	return a.Execute(rules, ctx)
+}
+
+// customPCA keeps id (in lower case) and parameters of the algorithm along
+// with its instance so policy diff can compare algorithms.
+type customPCA struct {
+	CustomPolicyCombiningAlg
+	id     string
+	params interface{}
+}
+
+func (a customPCA) execute(policies []Evaluable, ctx *Context) Response {
+	return a.Execute(policies, ctx)
+}
+
+// RegisterRuleCombiningAlg adds custom rule combining algorithm with no
+// parameters to RuleCombiningAlgs so YAST and JAST parsers can find it by id.
+// Algorithm id is case insensitive. The function returns an error if any
+// rule combining algorithm with the same id already exists. It isn't safe
+// for concurrent use with parsers and should be called on application start.
+func RegisterRuleCombiningAlg(ID string, maker CustomRuleCombiningAlgMaker) error {
+	key, err := checkCustomRuleCombiningAlg(ID, maker)
+	if err != nil {
+		return err
+	}
+
+	RuleCombiningAlgs[key] = wrapCustomRuleCombiningAlgMaker(key, maker)
+	return nil
+}
+
+// RegisterRuleCombiningParamAlg adds custom rule combining algorithm which
+// requires parameters to RuleCombiningParamAlgs and its parameters parser
+// to RuleCombiningAlgParamsParsers. Algorithm id is case insensitive.
+// The function returns an error if any rule combining algorithm with the same
+// id already exists. It isn't safe for concurrent use with parsers and should
+// be called on application start.
+func RegisterRuleCombiningParamAlg(ID string, maker CustomRuleCombiningAlgMaker, parser CombiningAlgParamsParser) error {
+	key, err := checkCustomRuleCombiningAlg(ID, maker)
+	if err != nil {
+		return err
+	}
+
+	if parser == nil {
+		return newMissingCombiningAlgParamsParserError(ID)
+	}
+
+	RuleCombiningParamAlgs[key] = wrapCustomRuleCombiningAlgMaker(key, maker)
+	RuleCombiningAlgParamsParsers[key] = parser
+	return nil
+}
+
+// RegisterPolicyCombiningAlg adds custom policy combining algorithm with no
+// parameters to PolicyCombiningAlgs so YAST and JAST parsers can find it
+// by id. Algorithm id is case insensitive. The function returns an error if
+// any policy combining algorithm with the same id already exists. It isn't
+// safe for concurrent use with parsers and should be called on application
+// start.
+func RegisterPolicyCombiningAlg(ID string, maker CustomPolicyCombiningAlgMaker) error {
+	key, err := checkCustomPolicyCombiningAlg(ID, maker)
+	if err != nil {
+		return err
+	}
+
+	PolicyCombiningAlgs[key] = wrapCustomPolicyCombiningAlgMaker(key, maker)
+	return nil
+}
+
+// RegisterPolicyCombiningParamAlg adds custom policy combining algorithm
+// which requires parameters to PolicyCombiningParamAlgs and its parameters
+// parser to PolicyCombiningAlgParamsParsers. Algorithm id is case
+// insensitive. The function returns an error if any policy combining
+// algorithm with the same id already exists. It isn't safe for concurrent use
+// with parsers and should be called on application start.
+func RegisterPolicyCombiningParamAlg(ID string, maker CustomPolicyCombiningAlgMaker, parser CombiningAlgParamsParser) error {
+	key, err := checkCustomPolicyCombiningAlg(ID, maker)
+	if err != nil {
+		return err
+	}
+
+	if parser == nil {
+		return newMissingCombiningAlgParamsParserError(ID)
+	}
+
+	PolicyCombiningParamAlgs[key] = wrapCustomPolicyCombiningAlgMaker(key, maker)
+	PolicyCombiningAlgParamsParsers[key] = parser
+	return nil
+}
+
+func checkCustomRuleCombiningAlg(ID string, maker CustomRuleCombiningAlgMaker) (string, error) {
+	if len(ID) <= 0 {
+		return "", newInvalidCombiningAlgIDError()
+	}
+
+	if maker == nil {
+		return "", newMissingCombiningAlgMakerError(ID)
+	}
+
+	key := strings.ToLower(ID)
+	if _, ok := RuleCombiningAlgs[key]; ok {
+		return "", newCombiningAlgRedefinitionError(ID)
+	}
+
+	if _, ok := RuleCombiningParamAlgs[key]; ok {
+		return "", newCombiningAlgRedefinitionError(ID)
+	}
+
+	return key, nil
+}
+
+func checkCustomPolicyCombiningAlg(ID string, maker CustomPolicyCombiningAlgMaker) (string, error) {
+	if len(ID) <= 0 {
+		return "", newInvalidCombiningAlgIDError()
+	}
+
+	if maker == nil {
+		return "", newMissingCombiningAlgMakerError(ID)
+	}
+
+	key := strings.ToLower(ID)
+	if _, ok := PolicyCombiningAlgs[key]; ok {
+		return "", newCombiningAlgRedefinitionError(ID)
+	}
+
+	if _, ok := PolicyCombiningParamAlgs[key]; ok {
+		return "", newCombiningAlgRedefinitionError(ID)
+	}
+
+	return key, nil
+}
+
+func wrapCustomRuleCombiningAlgMaker(ID string, maker CustomRuleCombiningAlgMaker) RuleCombiningAlgMaker {
+	return func(rules []*Rule, params interface{}) RuleCombiningAlg {
+		return customRCA{
+			CustomRuleCombiningAlg: maker(rules, params),
+			id:                     ID,
+			params:                 params,
+		}
+	}
+}
+
+func wrapCustomPolicyCombiningAlgMaker(ID string, maker CustomPolicyCombiningAlgMaker) PolicyCombiningAlgMaker {
+	return func(policies []Evaluable, params interface{}) PolicyCombiningAlg {
+		return customPCA{
+			CustomPolicyCombiningAlg: maker(policies, params),
+			id:                       ID,
+			params:                   params,
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package pdp
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+type testWeightedMajorityPCA struct {
+	policies []Evaluable
+	weights  map[string]float64
+}
+
+func makeTestWeightedMajorityPCA(policies []Evaluable, params interface{}) CustomPolicyCombiningAlg {
+	weights, _ := params.(map[string]float64)
+	return testWeightedMajorityPCA{
+		policies: policies,
+		weights:  weights,
+	}
+}
+
+func parseTestWeightedMajorityParams(params map[string]interface{}) (interface{}, error) {
+	m, ok := params["weights"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected weights map but got %T", params["weights"])
+	}
+
+	weights := make(map[string]float64, len(m))
+	for k, v := range m {
+		w, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("expected number as weight of %q but got %T", k, v)
+		}
+
+		weights[k] = w
+	}
+
+	return weights, nil
+}
+
+func (a testWeightedMajorityPCA) Execute(policies []Evaluable, ctx *Context) Response {
+	var permit, deny float64
+	for _, p := range policies {
+		w := 1.0
+		if id, ok := p.GetID(); ok {
+			if v, ok := a.weights[id]; ok {
+				w = v
+			}
+		}
+
+		switch p.Calculate(ctx).Effect {
+		case EffectPermit:
+			permit += w
+
+		case EffectDeny:
+			deny += w
+		}
+	}
+
+	if permit > deny {
+		return Response{Effect: EffectPermit}
+	}
+
+	if deny > 0 {
+		return Response{Effect: EffectDeny}
+	}
+
+	return Response{Effect: EffectNotApplicable}
+}
+
+func (a testWeightedMajorityPCA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"type":    "testWeightedMajorityPCA",
+		"weights": a.weights,
+	})
+}
+
+type testLastApplicableRCA struct{}
+
+func makeTestLastApplicableRCA(rules []*Rule, params interface{}) CustomRuleCombiningAlg {
+	return testLastApplicableRCA{}
+}
+
+func (a testLastApplicableRCA) Execute(rules []*Rule, ctx *Context) Response {
+	for i := len(rules) - 1; i >= 0; i-- {
+		r := rules[i].Calculate(ctx)
+		if r.Effect != EffectNotApplicable {
+			return r
+		}
+	}
+
+	return Response{Effect: EffectNotApplicable}
+}
+
+func (a testLastApplicableRCA) MarshalJSON() ([]byte, error) {
+	return []byte(`{"type":"testLastApplicableRCA"}`), nil
+}
+
+func TestRegisterPolicyCombiningParamAlg(t *testing.T) {
+	ID := "Test Weighted Majority"
+	defer delete(PolicyCombiningParamAlgs, "test weighted majority")
+	defer delete(PolicyCombiningAlgParamsParsers, "test weighted majority")
+
+	err := RegisterPolicyCombiningParamAlg(ID, makeTestWeightedMajorityPCA, parseTestWeightedMajorityParams)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	err = RegisterPolicyCombiningAlg(ID, makeTestWeightedMajorityPCA)
+	if _, ok := err.(*combiningAlgRedefinitionError); !ok {
+		t.Errorf("Expected *combiningAlgRedefinitionError but got %T (%s)", err, err)
+	}
+
+	maker, ok := PolicyCombiningParamAlgs["test weighted majority"]
+	if !ok {
+		t.Fatalf("Expected %q in policy combining algorithms with parameters", ID)
+	}
+
+	parser, ok := PolicyCombiningAlgParamsParsers["test weighted majority"]
+	if !ok {
+		t.Fatalf("Expected parameters parser for %q", ID)
+	}
+
+	params, err := parser(map[string]interface{}{
+		"weights": map[string]interface{}{
+			"Permit": 3.,
+			"Deny":   1.,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	permit := makeSimplePolicy("Permit", makeSimpleHiddenRule(EffectPermit))
+	deny := makeSimplePolicy("Deny", makeSimpleHiddenRule(EffectDeny))
+
+	c := &Context{}
+	p := NewPolicySet("test", false, Target{}, []Evaluable{permit, deny, deny}, maker, params, nil)
+	r := p.Calculate(c)
+	if r.Effect != EffectPermit {
+		t.Errorf("Expected %q for weighted majority but got %q (%s)",
+			effectNames[EffectPermit], effectNames[r.Effect], r.Status)
+	}
+
+	p = NewPolicySet("test", false, Target{}, []Evaluable{permit, deny, deny, deny, deny}, maker, params, nil)
+	r = p.Calculate(c)
+	if r.Effect != EffectDeny {
+		t.Errorf("Expected %q for weighted majority but got %q (%s)",
+			effectNames[EffectDeny], effectNames[r.Effect], r.Status)
+	}
+
+	b, err := json.Marshal(p.algorithm)
+	if err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	} else {
+		e := `{"type":"testWeightedMajorityPCA","weights":{"Deny":1,"Permit":3}}`
+		if string(b) != e {
+			t.Errorf("Expected %s but got %s", e, b)
+		}
+	}
+
+	if _, err := parser(map[string]interface{}{}); err == nil {
+		t.Errorf("Expected error for missing weights but got nothing")
+	}
+}
+
+func TestRegisterPolicyCombiningAlgInMapper(t *testing.T) {
+	ID := "test weighted majority"
+	defer delete(PolicyCombiningAlgs, ID)
+
+	err := RegisterPolicyCombiningAlg(ID, makeTestWeightedMajorityPCA)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	c := &Context{
+		a: map[string]interface{}{
+			"x": MakeListOfStringsValue([]string{"Deny", "Permit", "Other"})}}
+
+	deny := makeSimplePolicy("Deny", makeSimpleHiddenRule(EffectDeny))
+	permit := makeSimplePolicy("Permit", makeSimpleHiddenRule(EffectPermit))
+	other := makeSimplePolicy("Other", makeSimpleHiddenRule(EffectPermit))
+
+	p := NewPolicySet("test", false, Target{}, []Evaluable{deny, permit, other}, makeMapperPCA, MapperPCAParams{
+		Argument:  MakeListOfStringsDesignator("x"),
+		Algorithm: PolicyCombiningAlgs[ID](nil, nil),
+	}, nil)
+
+	r := p.Calculate(c)
+	if r.Effect != EffectPermit {
+		t.Errorf("Expected %q for mapper with weighted majority but got %q (%s)",
+			effectNames[EffectPermit], effectNames[r.Effect], r.Status)
+	}
+
+	b, err := p.algorithm.(mapperPCA).MarshalJSON()
+	if err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	} else {
+		e := `{"type":"mapperPCA","def":"\"\"","err":"\"\"","alg":{"type":"testWeightedMajorityPCA","weights":null}}`
+		if string(b) != e {
+			t.Errorf("Expected %s but got %s", e, b)
+		}
+	}
+}
+
+func TestRegisterRuleCombiningAlg(t *testing.T) {
+	ID := "test last applicable"
+	defer delete(RuleCombiningAlgs, ID)
+
+	err := RegisterRuleCombiningAlg(ID, makeTestLastApplicableRCA)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	err = RegisterRuleCombiningParamAlg(ID, makeTestLastApplicableRCA, func(params map[string]interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	if _, ok := err.(*combiningAlgRedefinitionError); !ok {
+		t.Errorf("Expected *combiningAlgRedefinitionError but got %T (%s)", err, err)
+	}
+
+	c := &Context{
+		a: map[string]interface{}{
+			"test-string": MakeStringValue("test")}}
+
+	permit := makeSimplePermitRuleWithObligations("Permit", makeSingleStringObligation("p", "permit"))
+	deny := NewRule("Deny", false, Target{}, nil, EffectDeny, makeSingleStringObligation("d", "deny"))
+	notApplicable := NewRule("NotApplicable", false, makeSimpleStringTarget("test-string", "example"), nil,
+		EffectPermit, nil)
+
+	assertRCAEffect(t, "last applicable with deny", RuleCombiningAlgs[ID], c, EffectDeny, 1,
+		permit, deny, notApplicable)
+	assertRCAEffect(t, "last applicable with permit", RuleCombiningAlgs[ID], c, EffectPermit, 1,
+		deny, permit)
+	assertRCAEffect(t, "last applicable with no rules", RuleCombiningAlgs[ID], c, EffectNotApplicable, 0)
+}
+
+func TestRegisterCombiningAlgInvalid(t *testing.T) {
+	parser := func(params map[string]interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	err := RegisterRuleCombiningAlg("", makeTestLastApplicableRCA)
+	if _, ok := err.(*invalidCombiningAlgIDError); !ok {
+		t.Errorf("Expected *invalidCombiningAlgIDError but got %T (%s)", err, err)
+	}
+
+	err = RegisterRuleCombiningAlg("test", nil)
+	if _, ok := err.(*missingCombiningAlgMakerError); !ok {
+		t.Errorf("Expected *missingCombiningAlgMakerError but got %T (%s)", err, err)
+	}
+
+	err = RegisterRuleCombiningParamAlg("test", makeTestLastApplicableRCA, nil)
+	if _, ok := err.(*missingCombiningAlgParamsParserError); !ok {
+		t.Errorf("Expected *missingCombiningAlgParamsParserError but got %T (%s)", err, err)
+	}
+
+	err = RegisterRuleCombiningAlg("DenyOverrides", makeTestLastApplicableRCA)
+	if _, ok := err.(*combiningAlgRedefinitionError); !ok {
+		t.Errorf("Expected *combiningAlgRedefinitionError but got %T (%s)", err, err)
+	}
+
+	err = RegisterPolicyCombiningAlg("", makeTestWeightedMajorityPCA)
+	if _, ok := err.(*invalidCombiningAlgIDError); !ok {
+		t.Errorf("Expected *invalidCombiningAlgIDError but got %T (%s)", err, err)
+	}
+
+	err = RegisterPolicyCombiningParamAlg("test", nil, parser)
+	if _, ok := err.(*missingCombiningAlgMakerError); !ok {
+		t.Errorf("Expected *missingCombiningAlgMakerError but got %T (%s)", err, err)
+	}
+
+	err = RegisterPolicyCombiningParamAlg("test", makeTestWeightedMajorityPCA, nil)
+	if _, ok := err.(*missingCombiningAlgParamsParserError); !ok {
+		t.Errorf("Expected *missingCombiningAlgParamsParserError but got %T (%s)", err, err)
+	}
+
+	err = RegisterPolicyCombiningParamAlg("Mapper", makeTestWeightedMajorityPCA, parser)
+	if _, ok := err.(*combiningAlgRedefinitionError); !ok {
+		t.Errorf("Expected *combiningAlgRedefinitionError but got %T (%s)", err, err)
+	}
+}
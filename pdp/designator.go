@@ -84,6 +84,18 @@ func MakeListOfStringsDesignator(id string) AttributeDesignator {
 	return MakeAttributeDesignator(MakeAttribute(id, TypeListOfStrings))
 }
 
+// MakeDateTimeDesignator creates datetime designator expression instance for
+// given attribute id.
+func MakeDateTimeDesignator(id string) AttributeDesignator {
+	return MakeAttributeDesignator(MakeAttribute(id, TypeDateTime))
+}
+
+// MakeDurationDesignator creates duration designator expression instance for
+// given attribute id.
+func MakeDurationDesignator(id string) AttributeDesignator {
+	return MakeAttributeDesignator(MakeAttribute(id, TypeDuration))
+}
+
 // GetID returns ID of wrapped attribute.
 func (d AttributeDesignator) GetID() string {
 	return d.a.id
@@ -0,0 +1,513 @@
+package pdp
+
+import (
+	"reflect"
+
+	"github.com/google/uuid"
+)
+
+// DiffPolicies creates policy update which turns policies of old storage into
+// policies of new one. The update consists of delete commands for removed
+// entities, add commands which replace changed entities and add commands for
+// new entities. Update commands can't get into hidden policy set or policy
+// or address hidden children so if any of them differs DiffPolicies replaces
+// closest parent which has id. Children of policies and policy sets with
+// FirstApplicableEffect algorithm (or mapper with such algorithm and internal
+// order) keep their order: added entities can only go after existing ones so
+// if order changes DiffPolicies replaces the parent as well. The function
+// returns error if new policies can't be made by update (for example if new
+// root is hidden or attribute declarations or document level variables
+// differ). Policy set or policy which variables differ is replaced as
+// a whole and add command keeps its new variables.
+func DiffPolicies(old, new *PolicyStorage, oldTag, newTag uuid.UUID) (*PolicyUpdate, error) {
+	for ID, a := range new.symbols.attrs {
+		b, ok := old.symbols.attrs[ID]
+		if !ok || a.t.GetKey() != b.t.GetKey() {
+			return nil, newPolicyDiffAttributeMismatchError(ID)
+		}
+	}
+
+	if !equalVariables(old.symbols.vars, new.symbols.vars) {
+		return nil, newPolicyDiffVariableMismatchError()
+	}
+
+	u := NewPolicyUpdate(oldTag, newTag)
+
+	o := old.policies
+	n := new.policies
+	oID, oOk := getDiffID(o)
+	nID, nOk := getDiffID(n)
+	oVars := old.symbols.vars.lookupChild(oID)
+	nVars := new.symbols.vars.lookupChild(nID)
+	if equalEvaluables(o, n, oVars, nVars) {
+		return u, nil
+	}
+
+	if n == nil {
+		if !oOk {
+			return nil, newHiddenRootPolicyDiffError()
+		}
+
+		u.Append(UODelete, []string{oID}, nil)
+		return u, nil
+	}
+
+	if !nOk {
+		return nil, newHiddenRootPolicyDiffError()
+	}
+
+	if oOk && oID == nID {
+		if cmds, ok := diffEvaluables([]string{nID}, o, n, oVars, nVars); ok {
+			u.cmds = append(u.cmds, cmds...)
+			return u, nil
+		}
+	}
+
+	u.cmds = append(u.cmds, &command{op: UOAdd, path: []string{}, entity: n, vars: nVars})
+	return u, nil
+}
+
+func getDiffID(e Evaluable) (string, bool) {
+	if e == nil {
+		return "", false
+	}
+
+	return e.GetID()
+}
+
+type diffItem struct {
+	id     string
+	hidden bool
+	e      interface{}
+}
+
+// diffEvaluables makes commands which turn o into n. Arguments oVars and
+// nVars are variable scopes of o and n respectively.
+func diffEvaluables(path []string, o, n Evaluable, oVars, nVars *variableScope) ([]*command, bool) {
+	switch o := o.(type) {
+	case *PolicySet:
+		n, ok := n.(*PolicySet)
+		if !ok || !equalPolicySetHeaders(o, n, oVars, nVars) {
+			return nil, false
+		}
+
+		oItems := make([]diffItem, len(o.policies))
+		for i, e := range o.policies {
+			ID, ok := e.GetID()
+			oItems[i] = diffItem{id: ID, hidden: !ok, e: e}
+		}
+
+		nItems := make([]diffItem, len(n.policies))
+		for i, e := range n.policies {
+			ID, ok := e.GetID()
+			nItems[i] = diffItem{id: ID, hidden: !ok, e: e}
+		}
+
+		return diffChildren(path, oItems, nItems, isOrderedPCA(o.algorithm), getPCARefs(o.algorithm), oVars, nVars)
+
+	case *Policy:
+		n, ok := n.(*Policy)
+		if !ok || !equalPolicyHeaders(o, n, oVars, nVars) {
+			return nil, false
+		}
+
+		oItems := make([]diffItem, len(o.rules))
+		for i, r := range o.rules {
+			oItems[i] = diffItem{id: r.id, hidden: r.hidden, e: r}
+		}
+
+		nItems := make([]diffItem, len(n.rules))
+		for i, r := range n.rules {
+			nItems[i] = diffItem{id: r.id, hidden: r.hidden, e: r}
+		}
+
+		return diffChildren(path, oItems, nItems, isOrderedRCA(o.algorithm), getRCARefs(o.algorithm), oVars, nVars)
+	}
+
+	return nil, false
+}
+
+func diffChildren(path []string, o, n []diffItem, ordered bool, refs []string, oVars, nVars *variableScope) ([]*command, bool) {
+	oIdx := make(map[string]int, len(o))
+	for i, item := range o {
+		if item.hidden {
+			return nil, false
+		}
+
+		oIdx[item.id] = i
+	}
+
+	nIdx := make(map[string]int, len(n))
+	for i, item := range n {
+		if item.hidden {
+			return nil, false
+		}
+
+		nIdx[item.id] = i
+	}
+
+	cmds := []*command{}
+	last := -1
+	for _, item := range o {
+		i, ok := nIdx[item.id]
+		if !ok {
+			for _, ID := range refs {
+				if ID == item.id {
+					return nil, false
+				}
+			}
+
+			cmds = append(cmds, &command{op: UODelete, path: appendDiffPath(path, item.id)})
+			continue
+		}
+
+		if ordered && i < last {
+			return nil, false
+		}
+		last = i
+	}
+
+	for _, item := range o {
+		i, ok := nIdx[item.id]
+		if !ok {
+			continue
+		}
+
+		switch e := item.e.(type) {
+		case *Rule:
+			r := n[i].e.(*Rule)
+			if !equalRules(e, r) {
+				cmds = append(cmds, &command{op: UOAdd, path: path, entity: r})
+			}
+
+		case Evaluable:
+			c := n[i].e.(Evaluable)
+			cVars := nVars.lookupChild(item.id)
+			eVars := oVars.lookupChild(item.id)
+			if equalEvaluables(e, c, eVars, cVars) {
+				continue
+			}
+
+			if sub, ok := diffEvaluables(appendDiffPath(path, item.id), e, c, eVars, cVars); ok {
+				cmds = append(cmds, sub...)
+			} else {
+				cmds = append(cmds, &command{op: UOAdd, path: path, entity: c, vars: cVars})
+			}
+		}
+	}
+
+	for i, item := range n {
+		if _, ok := oIdx[item.id]; ok {
+			continue
+		}
+
+		if ordered && i < last {
+			return nil, false
+		}
+
+		cmds = append(cmds, &command{op: UOAdd, path: path, entity: item.e, vars: nVars.lookupChild(item.id)})
+	}
+
+	return cmds, true
+}
+
+func appendDiffPath(path []string, ID string) []string {
+	out := make([]string, len(path)+1)
+	copy(out, path)
+	out[len(path)] = ID
+	return out
+}
+
+func isOrderedRCA(a RuleCombiningAlg) bool {
+	switch a := a.(type) {
+	case firstApplicableEffectRCA:
+		return true
+
+	case mapperRCA:
+		return a.order == MapperRCAInternalOrder && isOrderedRCA(a.algorithm)
+
+	case flagsMapperRCA:
+		return a.order == MapperRCAInternalOrder && isOrderedRCA(a.algorithm)
+	}
+
+	return false
+}
+
+func isOrderedPCA(a PolicyCombiningAlg) bool {
+	switch a := a.(type) {
+	case firstApplicableEffectPCA:
+		return true
+
+	case mapperPCA:
+		return a.order == MapperPCAInternalOrder && isOrderedPCA(a.algorithm)
+
+	case flagsMapperPCA:
+		return a.order == MapperPCAInternalOrder && isOrderedPCA(a.algorithm)
+	}
+
+	return false
+}
+
+func getRCARefs(a RuleCombiningAlg) []string {
+	switch a := a.(type) {
+	case mapperRCA:
+		return []string{getDiffRuleID(a.def), getDiffRuleID(a.err)}
+
+	case flagsMapperRCA:
+		return []string{getDiffRuleID(a.def), getDiffRuleID(a.err)}
+	}
+
+	return nil
+}
+
+func getPCARefs(a PolicyCombiningAlg) []string {
+	switch a := a.(type) {
+	case mapperPCA:
+		return []string{getDiffEvaluableID(a.def), getDiffEvaluableID(a.err)}
+
+	case flagsMapperPCA:
+		return []string{getDiffEvaluableID(a.def), getDiffEvaluableID(a.err)}
+	}
+
+	return nil
+}
+
+func getDiffRuleID(r *Rule) string {
+	if r == nil {
+		return ""
+	}
+
+	return r.id
+}
+
+func getDiffEvaluableID(e Evaluable) string {
+	ID, _ := getDiffID(e)
+	return ID
+}
+
+// equalEvaluables checks if policy sets or policies are the same. Arguments
+// aVars and bVars are variable scopes of a and b respectively.
+func equalEvaluables(a, b Evaluable, aVars, bVars *variableScope) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	switch a := a.(type) {
+	case *PolicySet:
+		b, ok := b.(*PolicySet)
+		if !ok || !equalPolicySetHeaders(a, b, aVars, bVars) || len(a.policies) != len(b.policies) {
+			return false
+		}
+
+		for i, p := range a.policies {
+			ID := getDiffEvaluableID(p)
+			if !equalEvaluables(p, b.policies[i], aVars.lookupChild(ID), bVars.lookupChild(ID)) {
+				return false
+			}
+		}
+
+		return true
+
+	case *Policy:
+		b, ok := b.(*Policy)
+		if !ok || !equalPolicyHeaders(a, b, aVars, bVars) || len(a.rules) != len(b.rules) {
+			return false
+		}
+
+		for i, r := range a.rules {
+			if !equalRules(r, b.rules[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func equalPolicySetHeaders(a, b *PolicySet, aVars, bVars *variableScope) bool {
+	return a.id == b.id && a.hidden == b.hidden &&
+		equalVariables(aVars, bVars) &&
+		equalTargets(a.target, b.target) &&
+		equalAssignments(a.obligations, b.obligations) &&
+		equalPCAs(a.algorithm, b.algorithm)
+}
+
+func equalPolicyHeaders(a, b *Policy, aVars, bVars *variableScope) bool {
+	return a.id == b.id && a.hidden == b.hidden &&
+		equalVariables(aVars, bVars) &&
+		equalTargets(a.target, b.target) &&
+		equalAssignments(a.obligations, b.obligations) &&
+		equalRCAs(a.algorithm, b.algorithm)
+}
+
+func equalRules(a, b *Rule) bool {
+	return a.id == b.id && a.hidden == b.hidden && a.effect == b.effect &&
+		equalTargets(a.target, b.target) &&
+		equalExpressions(a.condition, b.condition) &&
+		equalAssignments(a.obligations, b.obligations)
+}
+
+func equalTargets(a, b Target) bool {
+	if len(a.a) != len(b.a) {
+		return false
+	}
+
+	for i, any := range a.a {
+		if len(any.a) != len(b.a[i].a) {
+			return false
+		}
+
+		for j, all := range any.a {
+			if len(all.m) != len(b.a[i].a[j].m) {
+				return false
+			}
+
+			for k, m := range all.m {
+				if !equalExpressions(m.m, b.a[i].a[j].m[k].m) {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+func equalAssignments(a, b []AttributeAssignment) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i, o := range a {
+		if o.a.id != b[i].a.id || o.a.t.GetKey() != b[i].a.t.GetKey() || !equalExpressions(o.e, b[i].e) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equalExpressions checks if expressions are the same. Go functions are
+// never deeply equal so custom functions and pattern matching functions (which
+// keep implementation and pattern compiler respectively) are compared by name,
+// result type and arguments. Other functions of the package keep only
+// arguments and values derived from them so they are compared by type and
+// arguments. Selectors and values are compared as is.
+func equalExpressions(a, b Expression) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	switch a := a.(type) {
+	case functionCustom:
+		b, ok := b.(functionCustom)
+		return ok && a.name == b.name && a.result.GetKey() == b.result.GetKey() &&
+			equalExpressionLists(a.args, b.args)
+
+	case functionPatternMatch:
+		b, ok := b.(functionPatternMatch)
+		return ok && a.name == b.name && a.fold == b.fold &&
+			equalExpressions(a.value, b.value) && equalExpressions(a.pattern, b.pattern)
+
+	case functionQuantifier:
+		b, ok := b.(functionQuantifier)
+		return ok && a.q == b.q && a.id == b.id && equalExpressionArguments(a, b)
+
+	case functionSwitch:
+		b, ok := b.(functionSwitch)
+		return ok && len(a.cases) == len(b.cases) && equalExpressionArguments(a, b)
+
+	case *Variable:
+		b, ok := b.(*Variable)
+		return ok && (a == b || a.id == b.id && equalExpressions(a.e, b.e))
+
+	case SelectorExpression:
+		return reflect.DeepEqual(a, b)
+
+	case ExpressionWalker:
+		return reflect.TypeOf(a) == reflect.TypeOf(b) && equalExpressionArguments(a, b.(ExpressionWalker))
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func equalExpressionArguments(a, b ExpressionWalker) bool {
+	return equalExpressionLists(getExpressionArguments(a), getExpressionArguments(b))
+}
+
+func getExpressionArguments(w ExpressionWalker) []Expression {
+	args := []Expression{}
+	w.WalkArguments(func(e Expression) {
+		args = append(args, e)
+	})
+
+	return args
+}
+
+func equalExpressionLists(a, b []Expression) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i, e := range a {
+		if !equalExpressions(e, b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalRCAs(a, b RuleCombiningAlg) bool {
+	switch a := a.(type) {
+	case mapperRCA:
+		b, ok := b.(mapperRCA)
+		return ok && equalExpressions(a.argument, b.argument) &&
+			getDiffRuleID(a.def) == getDiffRuleID(b.def) &&
+			getDiffRuleID(a.err) == getDiffRuleID(b.err) &&
+			a.order == b.order && equalRCAs(a.algorithm, b.algorithm)
+
+	case flagsMapperRCA:
+		b, ok := b.(flagsMapperRCA)
+		return ok && equalExpressions(a.argument, b.argument) &&
+			getDiffRuleID(a.def) == getDiffRuleID(b.def) &&
+			getDiffRuleID(a.err) == getDiffRuleID(b.err) &&
+			a.order == b.order && equalRCAs(a.algorithm, b.algorithm)
+
+	case customRCA:
+		// Instances of custom algorithms may hold Go functions which
+		// are never deeply equal so only id and parameters matter.
+		b, ok := b.(customRCA)
+		return ok && a.id == b.id && reflect.DeepEqual(a.params, b.params)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func equalPCAs(a, b PolicyCombiningAlg) bool {
+	switch a := a.(type) {
+	case mapperPCA:
+		b, ok := b.(mapperPCA)
+		return ok && equalExpressions(a.argument, b.argument) &&
+			getDiffEvaluableID(a.def) == getDiffEvaluableID(b.def) &&
+			getDiffEvaluableID(a.err) == getDiffEvaluableID(b.err) &&
+			a.order == b.order && equalPCAs(a.algorithm, b.algorithm)
+
+	case flagsMapperPCA:
+		b, ok := b.(flagsMapperPCA)
+		return ok && equalExpressions(a.argument, b.argument) &&
+			getDiffEvaluableID(a.def) == getDiffEvaluableID(b.def) &&
+			getDiffEvaluableID(a.err) == getDiffEvaluableID(b.err) &&
+			a.order == b.order && equalPCAs(a.algorithm, b.algorithm)
+
+	case customPCA:
+		// Instances of custom algorithms may hold Go functions which
+		// are never deeply equal so only id and parameters matter.
+		b, ok := b.(customPCA)
+		return ok && a.id == b.id && reflect.DeepEqual(a.params, b.params)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,414 @@
+package pdp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestDiffPolicies(t *testing.T) {
+	defer registerTestDiffExtensions(t)()
+
+	cases := []struct {
+		name string
+		old  Evaluable
+		new  Evaluable
+		cmds []string
+		// res is expected result of update if it differs from new
+		// (only by order of children which doesn't matter).
+		res Evaluable
+	}{
+		{
+			name: "no changes",
+			old:  makeSimplePolicySet("root", makeSimplePolicy("p", makeSimpleRule("r", EffectPermit))),
+			new:  makeSimplePolicySet("root", makeSimplePolicy("p", makeSimpleRule("r", EffectPermit))),
+			cmds: []string{},
+		},
+		{
+			name: "no changes with custom function and algorithm",
+			old:  makeTestDiffCustomPolicySet(),
+			new:  makeTestDiffCustomPolicySet(),
+			cmds: []string{},
+		},
+		{
+			name: "changed rule",
+			old: makeSimplePolicySet("root",
+				makeSimplePolicy("p", makeSimpleRule("r1", EffectPermit), makeSimpleRule("r2", EffectPermit))),
+			new: makeSimplePolicySet("root",
+				makeSimplePolicy("p", makeSimpleRule("r1", EffectDeny), makeSimpleRule("r2", EffectPermit))),
+			cmds: []string{"add root/p: r1"},
+		},
+		{
+			name: "deleted and appended",
+			old: makeSimplePolicySet("root",
+				makeSimplePolicy("p1", makeSimpleRule("r", EffectPermit)),
+				makeSimplePolicy("p2", makeSimpleRule("r1", EffectPermit), makeSimpleRule("r2", EffectDeny))),
+			new: makeSimplePolicySet("root",
+				makeSimplePolicy("p2", makeSimpleRule("r2", EffectDeny), makeSimpleRule("r3", EffectDeny)),
+				makeSimplePolicy("p3", makeSimpleRule("r", EffectPermit))),
+			cmds: []string{"delete root/p1", "delete root/p2/r1", "add root/p2: r3", "add root: p3"},
+		},
+		{
+			name: "order changed in FirstApplicableEffect policy",
+			old: makeSimplePolicySet("root",
+				makeSimplePolicy("p", makeSimpleRule("r1", EffectPermit), makeSimpleRule("r2", EffectDeny))),
+			new: makeSimplePolicySet("root",
+				makeSimplePolicy("p", makeSimpleRule("r2", EffectDeny), makeSimpleRule("r1", EffectPermit))),
+			cmds: []string{"add root: p"},
+		},
+		{
+			name: "inserted before existing rule in FirstApplicableEffect policy",
+			old: makeSimplePolicySet("root",
+				makeSimplePolicy("p", makeSimpleRule("r1", EffectPermit))),
+			new: makeSimplePolicySet("root",
+				makeSimplePolicy("p", makeSimpleRule("r0", EffectDeny), makeSimpleRule("r1", EffectPermit))),
+			cmds: []string{"add root: p"},
+		},
+		{
+			name: "order changed in DenyOverrides policy",
+			old: makeSimplePolicySet("root",
+				NewPolicy("p", false, Target{},
+					[]*Rule{makeSimpleRule("r1", EffectPermit), makeSimpleRule("r2", EffectDeny)},
+					makeDenyOverridesRCA, nil, nil)),
+			new: makeSimplePolicySet("root",
+				NewPolicy("p", false, Target{},
+					[]*Rule{makeSimpleRule("r0", EffectPermit), makeSimpleRule("r2", EffectDeny)},
+					makeDenyOverridesRCA, nil, nil)),
+			cmds: []string{"delete root/p/r1", "add root/p: r0"},
+			res: makeSimplePolicySet("root",
+				NewPolicy("p", false, Target{},
+					[]*Rule{makeSimpleRule("r2", EffectDeny), makeSimpleRule("r0", EffectPermit)},
+					makeDenyOverridesRCA, nil, nil)),
+		},
+		{
+			name: "changed hidden rule",
+			old: makeSimplePolicySet("root",
+				makeSimplePolicy("p", makeSimpleHiddenRule(EffectPermit))),
+			new: makeSimplePolicySet("root",
+				makeSimplePolicy("p", makeSimpleHiddenRule(EffectDeny))),
+			cmds: []string{"add root: p"},
+		},
+		{
+			name: "changed policy algorithm",
+			old: makeSimplePolicySet("root",
+				makeSimplePolicy("p", makeSimpleRule("r", EffectPermit))),
+			new: makeSimplePolicySet("root",
+				NewPolicy("p", false, Target{}, []*Rule{makeSimpleRule("r", EffectPermit)},
+					makeDenyOverridesRCA, nil, nil)),
+			cmds: []string{"add root: p"},
+		},
+		{
+			name: "changed root",
+			old:  makeSimplePolicySet("root", makeSimplePolicy("p", makeSimpleRule("r", EffectPermit))),
+			new:  makeSimplePolicy("other", makeSimpleRule("r", EffectPermit)),
+			cmds: []string{"add .: other"},
+		},
+		{
+			name: "visible root instead of hidden",
+			old:  makeSimpleHiddenPolicySet(makeSimplePolicy("p", makeSimpleRule("r", EffectPermit))),
+			new:  makeSimplePolicySet("root", makeSimplePolicy("p", makeSimpleRule("r", EffectPermit))),
+			cmds: []string{"add .: root"},
+		},
+		{
+			name: "deleted root",
+			old:  makeSimplePolicySet("root", makeSimplePolicy("p", makeSimpleRule("r", EffectPermit))),
+			cmds: []string{"delete root"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			oldTag := uuid.New()
+			newTag := uuid.New()
+
+			old := NewPolicyStorage(c.old, Symbols{}, &oldTag)
+			u, err := DiffPolicies(old, NewPolicyStorage(c.new, Symbols{}, nil), oldTag, newTag)
+			if err != nil {
+				t.Fatalf("Expected update but got error %s", err)
+			}
+
+			if cmds := describeTestPolicyUpdate(u); !reflect.DeepEqual(cmds, c.cmds) {
+				t.Errorf("Expected commands %q but got %q", c.cmds, cmds)
+			}
+
+			tr, err := old.NewTransaction(&oldTag)
+			if err != nil {
+				t.Fatalf("Expected transaction but got error %s", err)
+			}
+
+			if err := tr.Apply(u); err != nil {
+				t.Fatalf("Expected update to be applied but got error %s", err)
+			}
+
+			s, err := tr.Commit()
+			if err != nil {
+				t.Fatalf("Expected new storage but got error %s", err)
+			}
+
+			res := c.res
+			if res == nil {
+				res = c.new
+			}
+
+			if !equalEvaluables(s.Root(), res, nil, nil) {
+				t.Errorf("Expected updated policies to be the same as new policies")
+			}
+		})
+	}
+}
+
+func TestDiffPoliciesErrors(t *testing.T) {
+	old := NewPolicyStorage(makeSimplePolicy("p", makeSimpleRule("r", EffectPermit)), MakeSymbols(), nil)
+
+	_, err := DiffPolicies(old, NewPolicyStorage(makeSimpleHiddenPolicy(), Symbols{}, nil), uuid.New(), uuid.New())
+	if _, ok := err.(*hiddenRootPolicyDiffError); !ok {
+		t.Errorf("Expected *hiddenRootPolicyDiffError but got %T (%s)", err, err)
+	}
+
+	symbols := MakeSymbols()
+	if err := symbols.PutAttribute(MakeAttribute("x", TypeString)); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	_, err = DiffPolicies(old, NewPolicyStorage(makeSimplePolicy("p"), symbols, nil), uuid.New(), uuid.New())
+	if _, ok := err.(*policyDiffAttributeMismatchError); !ok {
+		t.Errorf("Expected *policyDiffAttributeMismatchError but got %T (%s)", err, err)
+	}
+
+	symbols = MakeSymbols()
+	if err := symbols.PutVariable(nil, NewVariable("v", MakeStringValue("test"))); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	_, err = DiffPolicies(old, NewPolicyStorage(makeSimplePolicy("p"), symbols, nil), uuid.New(), uuid.New())
+	if _, ok := err.(*policyDiffVariableMismatchError); !ok {
+		t.Errorf("Expected *policyDiffVariableMismatchError but got %T (%s)", err, err)
+	}
+}
+
+func TestDiffPoliciesVariables(t *testing.T) {
+	oldSymbols := MakeSymbols()
+	oldV := NewVariable("v", MakeStringValue("a"))
+	if err := oldSymbols.PutVariable([]string{"root", "p"}, oldV); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	newSymbols := MakeSymbols()
+	newV := NewVariable("v", MakeStringValue("b"))
+	if err := newSymbols.PutVariable([]string{"root", "p"}, newV); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	newW := NewVariable("w", MakeStringValue("c"))
+	if err := newSymbols.PutVariable([]string{"root", "q"}, newW); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	makeTestPolicies := func(v *Variable) Evaluable {
+		return makeSimplePolicySet("root",
+			makeSimplePolicy("p",
+				NewRule("r", false, Target{},
+					functionStringEqual{
+						first:  v,
+						second: MakeStringDesignator("s"),
+					}, EffectPermit, nil)),
+			makeSimplePolicy("q", makeSimpleRule("r", EffectPermit)))
+	}
+
+	oldTag := uuid.New()
+	newTag := uuid.New()
+
+	old := NewPolicyStorage(makeTestPolicies(oldV), oldSymbols, &oldTag)
+	new := NewPolicyStorage(makeTestPolicies(newV), newSymbols, nil)
+	u, err := DiffPolicies(old, new, oldTag, newTag)
+	if err != nil {
+		t.Fatalf("Expected update but got error %s", err)
+	}
+
+	e := []string{"add root: p", "add root: q"}
+	if cmds := describeTestPolicyUpdate(u); !reflect.DeepEqual(cmds, e) {
+		t.Errorf("Expected commands %q but got %q", e, cmds)
+	}
+
+	tr, err := old.NewTransaction(&oldTag)
+	if err != nil {
+		t.Fatalf("Expected transaction but got error %s", err)
+	}
+
+	if err := tr.Apply(u); err != nil {
+		t.Fatalf("Expected update to be applied but got error %s", err)
+	}
+
+	s, err := tr.Commit()
+	if err != nil {
+		t.Fatalf("Expected new storage but got error %s", err)
+	}
+
+	if v, ok := s.GetSymbols().GetVariable([]string{"root", "p"}, "v"); !ok || v != newV {
+		t.Errorf("Expected variable %p but got %p", newV, v)
+	}
+
+	if v, ok := s.GetSymbols().GetVariable([]string{"root", "q"}, "w"); !ok || v != newW {
+		t.Errorf("Expected variable %p but got %p", newW, v)
+	}
+
+	u, err = DiffPolicies(s, new, newTag, uuid.New())
+	if err != nil {
+		t.Fatalf("Expected update but got error %s", err)
+	}
+
+	if cmds := describeTestPolicyUpdate(u); len(cmds) > 0 {
+		t.Errorf("Expected no commands for updated policies but got %q", cmds)
+	}
+}
+
+func TestDiffPoliciesCustomFunctionVariables(t *testing.T) {
+	defer registerTestDiffExtensions(t)()
+
+	makeTestStorage := func() *PolicyStorage {
+		symbols := MakeSymbols()
+		v := NewVariable("v", makeTestDiffCustomFunction(MakeStringDesignator("s"), MakeStringValue("Test")))
+		if err := symbols.PutVariable(nil, v); err != nil {
+			t.Fatalf("Expected no error but got %s", err)
+		}
+
+		if err := symbols.PutVariable([]string{"root"}, NewVariable("w", v)); err != nil {
+			t.Fatalf("Expected no error but got %s", err)
+		}
+
+		return NewPolicyStorage(makeTestDiffCustomPolicySet(), symbols, nil)
+	}
+
+	u, err := DiffPolicies(makeTestStorage(), makeTestStorage(), uuid.New(), uuid.New())
+	if err != nil {
+		t.Fatalf("Expected update but got error %s", err)
+	}
+
+	if cmds := describeTestPolicyUpdate(u); len(cmds) > 0 {
+		t.Errorf("Expected no commands for the same policies but got %q", cmds)
+	}
+}
+
+const (
+	testDiffFunctionName = "test diff equal fold"
+	testDiffRCAID        = "test diff first"
+)
+
+// registerTestDiffExtensions registers custom function and algorithm which
+// keep Go functions and returns function which removes them.
+func registerTestDiffExtensions(t *testing.T) func() {
+	err := RegisterFunction(testDiffFunctionName, FunctionSignature{
+		Args:   MakeSignature(TypeString, TypeString),
+		Result: TypeBoolean,
+	}, func(ctx *Context, args []AttributeValue) (AttributeValue, error) {
+		a, err := args[0].GetString()
+		if err != nil {
+			return UndefinedValue, err
+		}
+
+		b, err := args[1].GetString()
+		if err != nil {
+			return UndefinedValue, err
+		}
+
+		return MakeBooleanValue(strings.EqualFold(a, b)), nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	err = RegisterRuleCombiningAlg(testDiffRCAID, func(rules []*Rule, params interface{}) CustomRuleCombiningAlg {
+		return testDiffRCA{
+			rules: rules,
+			stop: func(r Response) bool {
+				return r.Effect != EffectNotApplicable
+			},
+		}
+	})
+	if err != nil {
+		delete(FunctionArgumentValidators, testDiffFunctionName)
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	return func() {
+		delete(FunctionArgumentValidators, testDiffFunctionName)
+		delete(RuleCombiningAlgs, strings.ToLower(testDiffRCAID))
+	}
+}
+
+func makeTestDiffCustomFunction(args ...Expression) Expression {
+	return findValidator(testDiffFunctionName, args...)(args)
+}
+
+func makeTestDiffCustomPolicySet() Evaluable {
+	all := MakeAllOf()
+	all.Append(MakeMatch(makeTestDiffCustomFunction(MakeStringDesignator("s"), MakeStringValue("Test"))))
+
+	any := MakeAnyOf()
+	any.Append(all)
+
+	target := MakeTarget()
+	target.Append(any)
+
+	return makeSimplePolicySet("root",
+		NewPolicy("p", false, target,
+			[]*Rule{
+				NewRule("r", false, Target{},
+					makeFunctionGlob(MakeStringDesignator("s"), MakeStringValue("t*")),
+					EffectPermit,
+					[]AttributeAssignment{
+						MakeAttributeAssignment(MakeAttribute("o", TypeBoolean),
+							makeTestDiffCustomFunction(MakeStringDesignator("s"), MakeStringValue("test"))),
+					}),
+			},
+			RuleCombiningAlgs[strings.ToLower(testDiffRCAID)], nil, nil))
+}
+
+// testDiffRCA is a custom algorithm which keeps Go function.
+type testDiffRCA struct {
+	rules []*Rule
+	stop  func(r Response) bool
+}
+
+func (a testDiffRCA) Execute(rules []*Rule, ctx *Context) Response {
+	for _, rule := range rules {
+		if r := rule.Calculate(ctx); a.stop(r) {
+			return r
+		}
+	}
+
+	return Response{Effect: EffectNotApplicable}
+}
+
+func (a testDiffRCA) MarshalJSON() ([]byte, error) {
+	return []byte(`{"type":"testDiffRCA"}`), nil
+}
+
+func describeTestPolicyUpdate(u *PolicyUpdate) []string {
+	out := []string{}
+	u.Iterate(func(op int, path []string, entity interface{}) error {
+		p := strings.Join(path, "/")
+		if len(path) <= 0 {
+			p = "."
+		}
+
+		switch e := entity.(type) {
+		case *Rule:
+			out = append(out, fmt.Sprintf("add %s: %s", p, e.id))
+
+		case Evaluable:
+			ID, _ := e.GetID()
+			out = append(out, fmt.Sprintf("add %s: %s", p, ID))
+
+		default:
+			out = append(out, fmt.Sprintf("%s %s", strings.ToLower(UpdateOpNames[op]), p))
+		}
+
+		return nil
+	})
+
+	return out
+}
@@ -195,6 +195,52 @@ const (
 	policyCalculationErrorID                              = 178
 	obligationCalculationErrorID                          = 179
 	noInformationalErrorID                                = 180
+	tooManyApplicableErrorID                              = 181
+	invalidFunctionNameErrorID                            = 182
+	missingFunctionImplementationErrorID                  = 183
+	missingFunctionResultTypeErrorID                      = 184
+	functionRedefinitionErrorID                           = 185
+	customFunctionResultTypeErrorID                       = 186
+	invalidCombiningAlgIDErrorID                          = 187
+	missingCombiningAlgMakerErrorID                       = 188
+	missingCombiningAlgParamsParserErrorID                = 189
+	combiningAlgRedefinitionErrorID                       = 190
+	invalidRegexpErrorID                                  = 191
+	invalidGlobErrorID                                    = 192
+	negativeSubstringStartErrorID                         = 193
+	negativeSubstringLengthErrorID                        = 194
+	invalidDateTimeStringCastErrorID                      = 195
+	invalidDurationStringCastErrorID                      = 196
+	requestUnmarshalDateTimeConstErrorID                  = 197
+	requestUnmarshalDateTimeTypeErrorID                   = 198
+	requestUnmarshalDurationConstErrorID                  = 199
+	requestUnmarshalDurationTypeErrorID                   = 200
+	invalidTimeZoneErrorID                                = 201
+	invalidTimeOfDayErrorID                               = 202
+	requestAttributeUnmarshallingDateTimeTypeErrorID      = 203
+	requestAttributeUnmarshallingDurationTypeErrorID      = 204
+	hiddenRootPolicyDiffErrorID                           = 205
+	policyDiffAttributeMismatchErrorID                    = 206
+	contentDiffIDMismatchErrorID                          = 207
+	contentDiffTypeMismatchErrorID                        = 208
+	switchCaseValueErrorID                                = 209
+	switchDuplicateCaseErrorID                            = 210
+	missingIterationVariableErrorID                       = 211
+	unknownQuantifierErrorID                              = 212
+	quantifierCollectionTypeErrorID                       = 213
+	quantifierExpressionTypeErrorID                       = 214
+	nilVariableErrorID                                    = 215
+	duplicateVariableErrorID                              = 216
+	unresolvedPolicyReferenceErrorID                      = 217
+	noPolicyDocumentsErrorID                              = 218
+	duplicatePolicyDocumentIDErrorID                      = 219
+	policyDocumentRootErrorID                             = 220
+	unlinkedPolicyDocumentErrorID                         = 221
+	unknownPolicyReferenceErrorID                         = 222
+	policyReferenceCycleErrorID                           = 223
+	duplicatePolicyReferenceErrorID                       = 224
+	rootPolicyReferenceErrorID                            = 225
+	policyDiffVariableMismatchErrorID                     = 226
 )
 
 type externalError struct {
@@ -3023,3 +3069,709 @@ func newNoInformationalError() *noInformationalError {
 func (e *noInformationalError) Error() string {
 	return e.errorf("No information error providied to marshaller")
 }
+
+type tooManyApplicableError struct {
+	errorLink
+	n int
+}
+
+func newTooManyApplicableError(n int) *tooManyApplicableError {
+	return &tooManyApplicableError{
+		errorLink: errorLink{id: tooManyApplicableErrorID},
+		n:         n}
+}
+
+func (e *tooManyApplicableError) Error() string {
+	return e.errorf("Expected only one applicable item but got %d", e.n)
+}
+
+type invalidFunctionNameError struct {
+	errorLink
+}
+
+func newInvalidFunctionNameError() *invalidFunctionNameError {
+	return &invalidFunctionNameError{
+		errorLink: errorLink{id: invalidFunctionNameErrorID}}
+}
+
+func (e *invalidFunctionNameError) Error() string {
+	return e.errorf("Can't register function with empty name")
+}
+
+type missingFunctionImplementationError struct {
+	errorLink
+	name string
+}
+
+func newMissingFunctionImplementationError(name string) *missingFunctionImplementationError {
+	return &missingFunctionImplementationError{
+		errorLink: errorLink{id: missingFunctionImplementationErrorID},
+		name:      name}
+}
+
+func (e *missingFunctionImplementationError) Error() string {
+	return e.errorf("Can't register function %q with no implementation", e.name)
+}
+
+type missingFunctionResultTypeError struct {
+	errorLink
+	name string
+}
+
+func newMissingFunctionResultTypeError(name string) *missingFunctionResultTypeError {
+	return &missingFunctionResultTypeError{
+		errorLink: errorLink{id: missingFunctionResultTypeErrorID},
+		name:      name}
+}
+
+func (e *missingFunctionResultTypeError) Error() string {
+	return e.errorf("Can't register function %q with no result type", e.name)
+}
+
+type functionRedefinitionError struct {
+	errorLink
+	name string
+	args Signature
+}
+
+func newFunctionRedefinitionError(name string, args Signature) *functionRedefinitionError {
+	return &functionRedefinitionError{
+		errorLink: errorLink{id: functionRedefinitionErrorID},
+		name:      name,
+		args:      args}
+}
+
+func (e *functionRedefinitionError) Error() string {
+	return e.errorf("Function %q for arguments %s has been already defined", e.name, e.args)
+}
+
+type customFunctionResultTypeError struct {
+	errorLink
+	expected Type
+	actual   Type
+}
+
+func newCustomFunctionResultTypeError(expected, actual Type) *customFunctionResultTypeError {
+	return &customFunctionResultTypeError{
+		errorLink: errorLink{id: customFunctionResultTypeErrorID},
+		expected:  expected,
+		actual:    actual}
+}
+
+func (e *customFunctionResultTypeError) Error() string {
+	return e.errorf("Expected %q as function result but got %q", e.expected, e.actual)
+}
+
+type invalidCombiningAlgIDError struct {
+	errorLink
+}
+
+func newInvalidCombiningAlgIDError() *invalidCombiningAlgIDError {
+	return &invalidCombiningAlgIDError{
+		errorLink: errorLink{id: invalidCombiningAlgIDErrorID}}
+}
+
+func (e *invalidCombiningAlgIDError) Error() string {
+	return e.errorf("Can't register combining algorithm with empty id")
+}
+
+type missingCombiningAlgMakerError struct {
+	errorLink
+	ID string
+}
+
+func newMissingCombiningAlgMakerError(ID string) *missingCombiningAlgMakerError {
+	return &missingCombiningAlgMakerError{
+		errorLink: errorLink{id: missingCombiningAlgMakerErrorID},
+		ID:        ID}
+}
+
+func (e *missingCombiningAlgMakerError) Error() string {
+	return e.errorf("Can't register combining algorithm %q with no maker", e.ID)
+}
+
+type missingCombiningAlgParamsParserError struct {
+	errorLink
+	ID string
+}
+
+func newMissingCombiningAlgParamsParserError(ID string) *missingCombiningAlgParamsParserError {
+	return &missingCombiningAlgParamsParserError{
+		errorLink: errorLink{id: missingCombiningAlgParamsParserErrorID},
+		ID:        ID}
+}
+
+func (e *missingCombiningAlgParamsParserError) Error() string {
+	return e.errorf("Can't register parametrized combining algorithm %q with no parameters parser", e.ID)
+}
+
+type combiningAlgRedefinitionError struct {
+	errorLink
+	ID string
+}
+
+func newCombiningAlgRedefinitionError(ID string) *combiningAlgRedefinitionError {
+	return &combiningAlgRedefinitionError{
+		errorLink: errorLink{id: combiningAlgRedefinitionErrorID},
+		ID:        ID}
+}
+
+func (e *combiningAlgRedefinitionError) Error() string {
+	return e.errorf("Combining algorithm %q has been already defined", e.ID)
+}
+
+type invalidRegexpError struct {
+	errorLink
+	pattern string
+	err     error
+}
+
+func newInvalidRegexpError(pattern string, err error) *invalidRegexpError {
+	return &invalidRegexpError{
+		errorLink: errorLink{id: invalidRegexpErrorID},
+		pattern:   pattern,
+		err:       err}
+}
+
+func (e *invalidRegexpError) Error() string {
+	return e.errorf("Can't compile regular expression %q (%s)", e.pattern, e.err)
+}
+
+type invalidGlobError struct {
+	errorLink
+	pattern string
+	err     error
+}
+
+func newInvalidGlobError(pattern string, err error) *invalidGlobError {
+	return &invalidGlobError{
+		errorLink: errorLink{id: invalidGlobErrorID},
+		pattern:   pattern,
+		err:       err}
+}
+
+func (e *invalidGlobError) Error() string {
+	return e.errorf("Can't compile glob pattern %q (%s)", e.pattern, e.err)
+}
+
+type negativeSubstringStartError struct {
+	errorLink
+	start int64
+}
+
+func newNegativeSubstringStartError(start int64) *negativeSubstringStartError {
+	return &negativeSubstringStartError{
+		errorLink: errorLink{id: negativeSubstringStartErrorID},
+		start:     start}
+}
+
+func (e *negativeSubstringStartError) Error() string {
+	return e.errorf("Expected non-negative start of substring but got %d", e.start)
+}
+
+type negativeSubstringLengthError struct {
+	errorLink
+	length int64
+}
+
+func newNegativeSubstringLengthError(length int64) *negativeSubstringLengthError {
+	return &negativeSubstringLengthError{
+		errorLink: errorLink{id: negativeSubstringLengthErrorID},
+		length:    length}
+}
+
+func (e *negativeSubstringLengthError) Error() string {
+	return e.errorf("Expected non-negative length of substring but got %d", e.length)
+}
+
+type invalidDateTimeStringCastError struct {
+	errorLink
+	s   string
+	err error
+}
+
+func newInvalidDateTimeStringCastError(s string, err error) *invalidDateTimeStringCastError {
+	return &invalidDateTimeStringCastError{
+		errorLink: errorLink{id: invalidDateTimeStringCastErrorID},
+		s:         s,
+		err:       err}
+}
+
+func (e *invalidDateTimeStringCastError) Error() string {
+	return e.errorf("Can't treat %q as date and time (%s)", e.s, e.err)
+}
+
+type invalidDurationStringCastError struct {
+	errorLink
+	s   string
+	err error
+}
+
+func newInvalidDurationStringCastError(s string, err error) *invalidDurationStringCastError {
+	return &invalidDurationStringCastError{
+		errorLink: errorLink{id: invalidDurationStringCastErrorID},
+		s:         s,
+		err:       err}
+}
+
+func (e *invalidDurationStringCastError) Error() string {
+	return e.errorf("Can't treat %q as duration (%s)", e.s, e.err)
+}
+
+type requestUnmarshalDateTimeConstError struct {
+	errorLink
+	v reflect.Value
+}
+
+func newRequestUnmarshalDateTimeConstError(v reflect.Value) *requestUnmarshalDateTimeConstError {
+	return &requestUnmarshalDateTimeConstError{
+		errorLink: errorLink{id: requestUnmarshalDateTimeConstErrorID},
+		v:         v}
+}
+
+func (e *requestUnmarshalDateTimeConstError) Error() string {
+	return e.errorf("Can't unmarshal date and time to unchengeable %s", e.v.Type())
+}
+
+type requestUnmarshalDateTimeTypeError struct {
+	errorLink
+	v reflect.Value
+}
+
+func newRequestUnmarshalDateTimeTypeError(v reflect.Value) *requestUnmarshalDateTimeTypeError {
+	return &requestUnmarshalDateTimeTypeError{
+		errorLink: errorLink{id: requestUnmarshalDateTimeTypeErrorID},
+		v:         v}
+}
+
+func (e *requestUnmarshalDateTimeTypeError) Error() string {
+	return e.errorf("Can't unmarshal date and time to %s", e.v.Type())
+}
+
+type requestUnmarshalDurationConstError struct {
+	errorLink
+	v reflect.Value
+}
+
+func newRequestUnmarshalDurationConstError(v reflect.Value) *requestUnmarshalDurationConstError {
+	return &requestUnmarshalDurationConstError{
+		errorLink: errorLink{id: requestUnmarshalDurationConstErrorID},
+		v:         v}
+}
+
+func (e *requestUnmarshalDurationConstError) Error() string {
+	return e.errorf("Can't unmarshal duration to unchengeable %s", e.v.Type())
+}
+
+type requestUnmarshalDurationTypeError struct {
+	errorLink
+	v reflect.Value
+}
+
+func newRequestUnmarshalDurationTypeError(v reflect.Value) *requestUnmarshalDurationTypeError {
+	return &requestUnmarshalDurationTypeError{
+		errorLink: errorLink{id: requestUnmarshalDurationTypeErrorID},
+		v:         v}
+}
+
+func (e *requestUnmarshalDurationTypeError) Error() string {
+	return e.errorf("Can't unmarshal duration to %s", e.v.Type())
+}
+
+type invalidTimeZoneError struct {
+	errorLink
+	name string
+	err  error
+}
+
+func newInvalidTimeZoneError(name string, err error) *invalidTimeZoneError {
+	return &invalidTimeZoneError{
+		errorLink: errorLink{id: invalidTimeZoneErrorID},
+		name:      name,
+		err:       err}
+}
+
+func (e *invalidTimeZoneError) Error() string {
+	return e.errorf("Can't load time zone %q (%s)", e.name, e.err)
+}
+
+type invalidTimeOfDayError struct {
+	errorLink
+	s string
+}
+
+func newInvalidTimeOfDayError(s string) *invalidTimeOfDayError {
+	return &invalidTimeOfDayError{
+		errorLink: errorLink{id: invalidTimeOfDayErrorID},
+		s:         s}
+}
+
+func (e *invalidTimeOfDayError) Error() string {
+	return e.errorf("Can't treat %q as time of day (expected \"hh:mm\" or \"hh:mm:ss\")", e.s)
+}
+
+type requestAttributeUnmarshallingDateTimeTypeError struct {
+	errorLink
+	t int
+}
+
+func newRequestAttributeUnmarshallingDateTimeTypeError(t int) *requestAttributeUnmarshallingDateTimeTypeError {
+	return &requestAttributeUnmarshallingDateTimeTypeError{
+		errorLink: errorLink{id: requestAttributeUnmarshallingDateTimeTypeErrorID},
+		t:         t}
+}
+
+func (e *requestAttributeUnmarshallingDateTimeTypeError) Error() string {
+	return e.errorf("Expected %q value but got %q", getRequestWireTypeName(requestWireTypeDateTime), getRequestWireTypeName(e.t))
+}
+
+type requestAttributeUnmarshallingDurationTypeError struct {
+	errorLink
+	t int
+}
+
+func newRequestAttributeUnmarshallingDurationTypeError(t int) *requestAttributeUnmarshallingDurationTypeError {
+	return &requestAttributeUnmarshallingDurationTypeError{
+		errorLink: errorLink{id: requestAttributeUnmarshallingDurationTypeErrorID},
+		t:         t}
+}
+
+func (e *requestAttributeUnmarshallingDurationTypeError) Error() string {
+	return e.errorf("Expected %q value but got %q", getRequestWireTypeName(requestWireTypeDuration), getRequestWireTypeName(e.t))
+}
+
+type hiddenRootPolicyDiffError struct {
+	errorLink
+}
+
+func newHiddenRootPolicyDiffError() *hiddenRootPolicyDiffError {
+	return &hiddenRootPolicyDiffError{
+		errorLink: errorLink{id: hiddenRootPolicyDiffErrorID}}
+}
+
+func (e *hiddenRootPolicyDiffError) Error() string {
+	return e.errorf("Can't make update which puts or removes hidden root policy set or policy")
+}
+
+type policyDiffAttributeMismatchError struct {
+	errorLink
+	ID string
+}
+
+func newPolicyDiffAttributeMismatchError(ID string) *policyDiffAttributeMismatchError {
+	return &policyDiffAttributeMismatchError{
+		errorLink: errorLink{id: policyDiffAttributeMismatchErrorID},
+		ID:        ID}
+}
+
+func (e *policyDiffAttributeMismatchError) Error() string {
+	return e.errorf("Can't make update as attribute %q is declared differently (policy update can't change attribute declarations)", e.ID)
+}
+
+type contentDiffIDMismatchError struct {
+	errorLink
+	oldID string
+	newID string
+}
+
+func newContentDiffIDMismatchError(oldID, newID string) *contentDiffIDMismatchError {
+	return &contentDiffIDMismatchError{
+		errorLink: errorLink{id: contentDiffIDMismatchErrorID},
+		oldID:     oldID,
+		newID:     newID}
+}
+
+func (e *contentDiffIDMismatchError) Error() string {
+	return e.errorf("Can't make update for content %q from content %q", e.oldID, e.newID)
+}
+
+type contentDiffTypeMismatchError struct {
+	errorLink
+	t Type
+}
+
+func newContentDiffTypeMismatchError(t Type) *contentDiffTypeMismatchError {
+	return &contentDiffTypeMismatchError{
+		errorLink: errorLink{id: contentDiffTypeMismatchErrorID},
+		t:         t}
+}
+
+func (e *contentDiffTypeMismatchError) Error() string {
+	return e.errorf("Can't make update as type %q is declared differently (content update can't change type declarations)", e.t)
+}
+
+type switchCaseValueError struct {
+	errorLink
+	n int
+}
+
+func newSwitchCaseValueError(n int) *switchCaseValueError {
+	return &switchCaseValueError{
+		errorLink: errorLink{id: switchCaseValueErrorID},
+		n:         n}
+}
+
+func (e *switchCaseValueError) Error() string {
+	return e.errorf("Expected immediate value for case %d of switch", e.n)
+}
+
+type switchDuplicateCaseError struct {
+	errorLink
+	value string
+}
+
+func newSwitchDuplicateCaseError(value string) *switchDuplicateCaseError {
+	return &switchDuplicateCaseError{
+		errorLink: errorLink{id: switchDuplicateCaseErrorID},
+		value:     value}
+}
+
+func (e *switchDuplicateCaseError) Error() string {
+	return e.errorf("Duplicate case %q of switch", e.value)
+}
+
+type missingIterationVariableError struct {
+	errorLink
+}
+
+func newMissingIterationVariableError() *missingIterationVariableError {
+	return &missingIterationVariableError{
+		errorLink: errorLink{id: missingIterationVariableErrorID}}
+}
+
+func (e *missingIterationVariableError) Error() string {
+	return e.errorf("Iteration variable isn't bound")
+}
+
+type unknownQuantifierError struct {
+	errorLink
+	q int
+}
+
+func newUnknownQuantifierError(q int) *unknownQuantifierError {
+	return &unknownQuantifierError{
+		errorLink: errorLink{id: unknownQuantifierErrorID},
+		q:         q}
+}
+
+func (e *unknownQuantifierError) Error() string {
+	return e.errorf("Unknown quantifier %d", e.q)
+}
+
+type quantifierCollectionTypeError struct {
+	errorLink
+	name string
+	t    Type
+}
+
+func newQuantifierCollectionTypeError(name string, t Type) *quantifierCollectionTypeError {
+	return &quantifierCollectionTypeError{
+		errorLink: errorLink{id: quantifierCollectionTypeErrorID},
+		name:      name,
+		t:         t}
+}
+
+func (e *quantifierCollectionTypeError) Error() string {
+	return e.errorf("Expected list of strings, set of strings, set of networks or set of domains for %q but got %q", e.name, e.t)
+}
+
+type quantifierExpressionTypeError struct {
+	errorLink
+	name     string
+	expected Type
+	actual   Type
+}
+
+func newQuantifierExpressionTypeError(name string, expected, actual Type) *quantifierExpressionTypeError {
+	return &quantifierExpressionTypeError{
+		errorLink: errorLink{id: quantifierExpressionTypeErrorID},
+		name:      name,
+		expected:  expected,
+		actual:    actual}
+}
+
+func (e *quantifierExpressionTypeError) Error() string {
+	return e.errorf("Expected %q expression for %q but got %q", e.expected, e.name, e.actual)
+}
+
+type nilVariableError struct {
+	errorLink
+}
+
+func newNilVariableError() *nilVariableError {
+	return &nilVariableError{
+		errorLink: errorLink{id: nilVariableErrorID}}
+}
+
+func (e *nilVariableError) Error() string {
+	return e.errorf("Can't put nil variable into symbol table")
+}
+
+type duplicateVariableError struct {
+	errorLink
+	ID string
+}
+
+func newDuplicateVariableError(ID string) *duplicateVariableError {
+	return &duplicateVariableError{
+		errorLink: errorLink{id: duplicateVariableErrorID},
+		ID:        ID}
+}
+
+func (e *duplicateVariableError) Error() string {
+	return e.errorf("Can't put variable %q into symbol table as it already contains variable with the same name at the same level", e.ID)
+}
+
+type unresolvedPolicyReferenceError struct {
+	errorLink
+	ID string
+}
+
+func newUnresolvedPolicyReferenceError(ID string) *unresolvedPolicyReferenceError {
+	return &unresolvedPolicyReferenceError{
+		errorLink: errorLink{id: unresolvedPolicyReferenceErrorID},
+		ID:        ID}
+}
+
+func (e *unresolvedPolicyReferenceError) Error() string {
+	return e.errorf("Reference to %q hasn't been linked", e.ID)
+}
+
+type noPolicyDocumentsError struct {
+	errorLink
+}
+
+func newNoPolicyDocumentsError() *noPolicyDocumentsError {
+	return &noPolicyDocumentsError{
+		errorLink: errorLink{id: noPolicyDocumentsErrorID}}
+}
+
+func (e *noPolicyDocumentsError) Error() string {
+	return e.errorf("Expected at least one policy document to link")
+}
+
+type duplicatePolicyDocumentIDError struct {
+	errorLink
+	ID   string
+	name string
+}
+
+func newDuplicatePolicyDocumentIDError(ID, name string) *duplicatePolicyDocumentIDError {
+	return &duplicatePolicyDocumentIDError{
+		errorLink: errorLink{id: duplicatePolicyDocumentIDErrorID},
+		ID:        ID,
+		name:      name}
+}
+
+func (e *duplicatePolicyDocumentIDError) Error() string {
+	return e.errorf("Root policy %q has been already defined in %q", e.ID, e.name)
+}
+
+type policyDocumentRootError struct {
+	errorLink
+	names []string
+}
+
+func newPolicyDocumentRootError(names []string) *policyDocumentRootError {
+	return &policyDocumentRootError{
+		errorLink: errorLink{id: policyDocumentRootErrorID},
+		names:     names}
+}
+
+func (e *policyDocumentRootError) Error() string {
+	n := len(e.names)
+
+	s := strings.Join(e.names, ", ")
+
+	return e.errorf("Expected exactly one policy document which isn't referred by others but got %d: %s", n, s)
+}
+
+type unlinkedPolicyDocumentError struct {
+	errorLink
+	ID string
+}
+
+func newUnlinkedPolicyDocumentError(ID string) *unlinkedPolicyDocumentError {
+	return &unlinkedPolicyDocumentError{
+		errorLink: errorLink{id: unlinkedPolicyDocumentErrorID},
+		ID:        ID}
+}
+
+func (e *unlinkedPolicyDocumentError) Error() string {
+	return e.errorf("Root policy %q isn't reachable from root of linked policies", e.ID)
+}
+
+type unknownPolicyReferenceError struct {
+	errorLink
+	ID string
+}
+
+func newUnknownPolicyReferenceError(ID string) *unknownPolicyReferenceError {
+	return &unknownPolicyReferenceError{
+		errorLink: errorLink{id: unknownPolicyReferenceErrorID},
+		ID:        ID}
+}
+
+func (e *unknownPolicyReferenceError) Error() string {
+	return e.errorf("Reference to unknown policy %q", e.ID)
+}
+
+type policyReferenceCycleError struct {
+	errorLink
+	ID   string
+	name string
+}
+
+func newPolicyReferenceCycleError(ID, name string) *policyReferenceCycleError {
+	return &policyReferenceCycleError{
+		errorLink: errorLink{id: policyReferenceCycleErrorID},
+		ID:        ID,
+		name:      name}
+}
+
+func (e *policyReferenceCycleError) Error() string {
+	return e.errorf("Reference to %q from %q makes a cycle", e.ID, e.name)
+}
+
+type duplicatePolicyReferenceError struct {
+	errorLink
+	ID   string
+	name string
+}
+
+func newDuplicatePolicyReferenceError(ID, name string) *duplicatePolicyReferenceError {
+	return &duplicatePolicyReferenceError{
+		errorLink: errorLink{id: duplicatePolicyReferenceErrorID},
+		ID:        ID,
+		name:      name}
+}
+
+func (e *duplicatePolicyReferenceError) Error() string {
+	return e.errorf("Policy %q from %q has been already referred", e.ID, e.name)
+}
+
+type rootPolicyReferenceError struct {
+	errorLink
+}
+
+func newRootPolicyReferenceError() *rootPolicyReferenceError {
+	return &rootPolicyReferenceError{
+		errorLink: errorLink{id: rootPolicyReferenceErrorID}}
+}
+
+func (e *rootPolicyReferenceError) Error() string {
+	return e.errorf("Root of policy document can't be a policy reference")
+}
+
+type policyDiffVariableMismatchError struct {
+	errorLink
+}
+
+func newPolicyDiffVariableMismatchError() *policyDiffVariableMismatchError {
+	return &policyDiffVariableMismatchError{
+		errorLink: errorLink{id: policyDiffVariableMismatchErrorID}}
+}
+
+func (e *policyDiffVariableMismatchError) Error() string {
+	return e.errorf("Can't make update as document level variables differ (policy update can't change them)")
+}
@@ -18,6 +18,13 @@ func (f functionConcat) describe() string {
 	return "concat"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionConcat) WalkArguments(visit func(e Expression)) {
+	for _, arg := range f.args {
+		visit(arg)
+	}
+}
+
 // Calculate implements Expression interface and returns calculated value.
 func (f functionConcat) Calculate(ctx *Context) (AttributeValue, error) {
 	var err error
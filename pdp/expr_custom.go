@@ -0,0 +1,168 @@
+package pdp
+
+import "strings"
+
+// FunctionSignature describes types of arguments and result of a custom
+// function.
+type FunctionSignature struct {
+	// Args lists types of function arguments in order.
+	Args Signature
+	// Variadic indicates that the last argument type can be repeated zero
+	// or more times.
+	Variadic bool
+	// Result is a type of function result.
+	Result Type
+}
+
+// FunctionImplementation calculates value of a custom function. It gets
+// request context and values of all arguments already calculated and checked
+// against the function signature. The function should return value of result
+// type defined by signature or an error.
+type FunctionImplementation func(ctx *Context, args []AttributeValue) (AttributeValue, error)
+
+type functionCustom struct {
+	name   string
+	result Type
+	impl   FunctionImplementation
+	args   []Expression
+}
+
+// RegisterFunction makes custom function available for policies under given
+// name. The function is added to FunctionArgumentValidators so YAST and JAST
+// parsers pick it up and choose it among other functions with the same name
+// by types of arguments. If the function has two arguments and boolean result
+// it also can be used as a match expression in targets. RegisterFunction
+// returns an error if other function with the same name already accepts
+// arguments of given signature. The function isn't safe for concurrent use
+// with parsers and should be called on application start (for example from
+// init function).
+func RegisterFunction(name string, sig FunctionSignature, impl FunctionImplementation) error {
+	if len(name) <= 0 {
+		return newInvalidFunctionNameError()
+	}
+
+	if impl == nil {
+		return newMissingFunctionImplementationError(name)
+	}
+
+	if sig.Result == nil {
+		return newMissingFunctionResultTypeError(name)
+	}
+
+	probe := make([]Expression, len(sig.Args))
+	for i, t := range sig.Args {
+		probe[i] = MakeDesignator("", t)
+	}
+
+	for _, validator := range FunctionArgumentValidators[name] {
+		if validator(probe) != nil {
+			return newFunctionRedefinitionError(name, sig.Args)
+		}
+	}
+
+	FunctionArgumentValidators[name] = append(FunctionArgumentValidators[name], makeFunctionCustomValidator(name, sig, impl))
+
+	if sig.Result == TypeBoolean && len(sig.Args) == 2 && !sig.Variadic {
+		registerTargetCompatibleFunction(strings.ToLower(name), sig.Args[0], sig.Args[1], func(first, second Expression) Expression {
+			return makeFunctionCustom(name, sig.Result, impl, []Expression{first, second})
+		})
+	}
+
+	return nil
+}
+
+func registerTargetCompatibleFunction(name string, first, second Type, maker twoArgumentsFunctionType) {
+	byFirst, ok := TargetCompatibleExpressions[name]
+	if !ok {
+		byFirst = make(map[Type]map[Type]twoArgumentsFunctionType)
+		TargetCompatibleExpressions[name] = byFirst
+	}
+
+	bySecond, ok := byFirst[first]
+	if !ok {
+		bySecond = make(map[Type]twoArgumentsFunctionType)
+		byFirst[first] = bySecond
+	}
+
+	if _, ok := bySecond[second]; !ok {
+		bySecond[second] = maker
+	}
+}
+
+func makeFunctionCustom(name string, result Type, impl FunctionImplementation, args []Expression) Expression {
+	return functionCustom{
+		name:   name,
+		result: result,
+		impl:   impl,
+		args:   args,
+	}
+}
+
+func (f functionCustom) GetResultType() Type {
+	return f.result
+}
+
+func (f functionCustom) describe() string {
+	return f.name
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionCustom) WalkArguments(visit func(e Expression)) {
+	for _, arg := range f.args {
+		visit(arg)
+	}
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionCustom) Calculate(ctx *Context) (AttributeValue, error) {
+	args := make([]AttributeValue, len(f.args))
+	for i, arg := range f.args {
+		v, err := arg.Calculate(ctx)
+		if err != nil {
+			return UndefinedValue, bindError(bindErrorf(err, "%d", i), f.describe())
+		}
+
+		args[i] = v
+	}
+
+	v, err := f.impl(ctx, args)
+	if err != nil {
+		return UndefinedValue, bindError(err, f.describe())
+	}
+
+	if t := v.GetResultType(); !f.result.Match(t) {
+		return UndefinedValue, bindError(newCustomFunctionResultTypeError(f.result, t), f.describe())
+	}
+
+	return v, nil
+}
+
+func makeFunctionCustomValidator(name string, sig FunctionSignature, impl FunctionImplementation) functionArgumentValidator {
+	return func(args []Expression) functionMaker {
+		variadic := sig.Variadic && len(sig.Args) > 0
+
+		min := len(sig.Args)
+		if variadic {
+			min--
+		}
+
+		if len(args) < min || !variadic && len(args) > len(sig.Args) {
+			return nil
+		}
+
+		for i, arg := range args {
+			t := sig.Args[len(sig.Args)-1]
+			if i < len(sig.Args) {
+				t = sig.Args[i]
+			}
+
+			if !t.Match(arg.GetResultType()) {
+				return nil
+			}
+		}
+
+		return func(args []Expression) Expression {
+			return makeFunctionCustom(name, sig.Result, impl, args)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package pdp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRegisterFunction(t *testing.T) {
+	name := "test repeat"
+	defer delete(FunctionArgumentValidators, name)
+
+	err := RegisterFunction(name, FunctionSignature{
+		Args:   MakeSignature(TypeString, TypeInteger),
+		Result: TypeString,
+	}, func(ctx *Context, args []AttributeValue) (AttributeValue, error) {
+		s, err := args[0].GetString()
+		if err != nil {
+			return UndefinedValue, err
+		}
+
+		n, err := args[1].GetInteger()
+		if err != nil {
+			return UndefinedValue, err
+		}
+
+		if n < 0 {
+			return UndefinedValue, fmt.Errorf("negative count %d", n)
+		}
+
+		return MakeStringValue(strings.Repeat(s, int(n))), nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	err = RegisterFunction(name, FunctionSignature{
+		Args:   MakeSignature(TypeString, TypeInteger),
+		Result: TypeBoolean,
+	}, func(ctx *Context, args []AttributeValue) (AttributeValue, error) {
+		return MakeBooleanValue(true), nil
+	})
+	if _, ok := err.(*functionRedefinitionError); !ok {
+		t.Errorf("Expected *functionRedefinitionError but got %T (%s)", err, err)
+	}
+
+	args := []Expression{MakeStringDesignator("s"), MakeIntegerValue(3)}
+	maker := findValidator(name, args...)
+	if maker == nil {
+		t.Fatalf("Expected function %q for string and integer arguments but got nothing", name)
+	}
+
+	e := maker(args)
+
+	if rt := e.GetResultType(); rt != TypeString {
+		t.Errorf("Expected %q as result type but got %q", TypeString, rt)
+	}
+
+	ctx := &Context{
+		a: map[string]interface{}{
+			"s": MakeStringValue("ab")}}
+
+	v, err := e.Calculate(ctx)
+	if err != nil {
+		t.Errorf("Expected no error but got %T (%s)", err, err)
+	} else if s, err := v.GetString(); err != nil || s != "ababab" {
+		t.Errorf("Expected %q but got %q (%v)", "ababab", s, err)
+	}
+
+	args = []Expression{MakeStringDesignator("s"), MakeIntegerValue(-1)}
+	if _, err := maker(args).Calculate(ctx); err == nil {
+		t.Errorf("Expected error for negative count but got nothing")
+	}
+
+	if findValidator(name, MakeIntegerValue(3), MakeStringDesignator("s")) != nil {
+		t.Errorf("Expected no function for arguments in wrong order")
+	}
+}
+
+func TestRegisterFunctionVariadic(t *testing.T) {
+	name := "test all"
+	defer delete(FunctionArgumentValidators, name)
+	defer delete(TargetCompatibleExpressions, name)
+
+	err := RegisterFunction(name, FunctionSignature{
+		Args:     MakeSignature(TypeBoolean, TypeBoolean),
+		Variadic: true,
+		Result:   TypeBoolean,
+	}, func(ctx *Context, args []AttributeValue) (AttributeValue, error) {
+		for _, arg := range args {
+			b, err := arg.GetBoolean()
+			if err != nil {
+				return UndefinedValue, err
+			}
+
+			if !b {
+				return MakeBooleanValue(false), nil
+			}
+		}
+
+		return MakeBooleanValue(true), nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	if _, ok := TargetCompatibleExpressions[name]; ok {
+		t.Errorf("Expected variadic function not to be target compatible")
+	}
+
+	if findValidator(name) != nil {
+		t.Errorf("Expected no function for no arguments")
+	}
+
+	args := []Expression{MakeBooleanValue(true), MakeBooleanValue(true), MakeBooleanValue(false)}
+	maker := findValidator(name, args...)
+	if maker == nil {
+		t.Fatalf("Expected function %q for three boolean arguments but got nothing", name)
+	}
+
+	v, err := maker(args).Calculate(&Context{})
+	if err != nil {
+		t.Errorf("Expected no error but got %T (%s)", err, err)
+	} else if b, err := v.GetBoolean(); err != nil || b {
+		t.Errorf("Expected false but got %v (%v)", b, err)
+	}
+}
+
+func TestRegisterFunctionTargetCompatible(t *testing.T) {
+	name := "Test Prefix"
+	defer delete(FunctionArgumentValidators, name)
+	defer delete(TargetCompatibleExpressions, "test prefix")
+
+	err := RegisterFunction(name, FunctionSignature{
+		Args:   MakeSignature(TypeString, TypeString),
+		Result: TypeBoolean,
+	}, func(ctx *Context, args []AttributeValue) (AttributeValue, error) {
+		return MakeStringValue("wrong type"), nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	maker, ok := TargetCompatibleExpressions["test prefix"][TypeString][TypeString]
+	if !ok {
+		t.Fatalf("Expected %q to be target compatible", name)
+	}
+
+	_, err = maker(MakeStringValue("a"), MakeStringValue("b")).Calculate(&Context{})
+	if _, ok := err.(*customFunctionResultTypeError); !ok {
+		t.Errorf("Expected *customFunctionResultTypeError but got %T (%s)", err, err)
+	}
+}
+
+func TestRegisterFunctionInvalid(t *testing.T) {
+	impl := func(ctx *Context, args []AttributeValue) (AttributeValue, error) {
+		return UndefinedValue, nil
+	}
+
+	err := RegisterFunction("", FunctionSignature{Result: TypeString}, impl)
+	if _, ok := err.(*invalidFunctionNameError); !ok {
+		t.Errorf("Expected *invalidFunctionNameError but got %T (%s)", err, err)
+	}
+
+	err = RegisterFunction("test", FunctionSignature{Result: TypeString}, nil)
+	if _, ok := err.(*missingFunctionImplementationError); !ok {
+		t.Errorf("Expected *missingFunctionImplementationError but got %T (%s)", err, err)
+	}
+
+	err = RegisterFunction("test", FunctionSignature{}, impl)
+	if _, ok := err.(*missingFunctionResultTypeError); !ok {
+		t.Errorf("Expected *missingFunctionResultTypeError but got %T (%s)", err, err)
+	}
+
+	err = RegisterFunction("equal", FunctionSignature{
+		Args:   MakeSignature(TypeString, TypeString),
+		Result: TypeBoolean,
+	}, impl)
+	if _, ok := err.(*functionRedefinitionError); !ok {
+		t.Errorf("Expected *functionRedefinitionError but got %T (%s)", err, err)
+	}
+}
@@ -0,0 +1,59 @@
+package pdp
+
+import "fmt"
+
+type functionDateTimeAdd struct {
+	first  Expression
+	second Expression
+}
+
+func makeFunctionDateTimeAdd(first, second Expression) Expression {
+	return functionDateTimeAdd{
+		first:  first,
+		second: second}
+}
+
+func makeFunctionDateTimeAddAlt(args []Expression) Expression {
+	if len(args) != 2 {
+		panic(fmt.Errorf("function \"add\" for DateTime needs exactly two arguments but got %d", len(args)))
+	}
+
+	return makeFunctionDateTimeAdd(args[0], args[1])
+}
+
+func (f functionDateTimeAdd) GetResultType() Type {
+	return TypeDateTime
+}
+
+func (f functionDateTimeAdd) describe() string {
+	return "add"
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionDateTimeAdd) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionDateTimeAdd) Calculate(ctx *Context) (AttributeValue, error) {
+	first, err := ctx.calculateDateTimeExpression(f.first)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "first argument"), f.describe())
+	}
+
+	second, err := ctx.calculateDurationExpression(f.second)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "second argument"), f.describe())
+	}
+
+	return MakeDateTimeValue(first.Add(second)), nil
+}
+
+func functionDateTimeAddValidator(args []Expression) functionMaker {
+	if len(args) != 2 || args[0].GetResultType() != TypeDateTime || args[1].GetResultType() != TypeDuration {
+		return nil
+	}
+
+	return makeFunctionDateTimeAddAlt
+}
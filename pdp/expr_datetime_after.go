@@ -0,0 +1,59 @@
+package pdp
+
+import "fmt"
+
+type functionDateTimeAfter struct {
+	first  Expression
+	second Expression
+}
+
+func makeFunctionDateTimeAfter(first, second Expression) Expression {
+	return functionDateTimeAfter{
+		first:  first,
+		second: second}
+}
+
+func makeFunctionDateTimeAfterAlt(args []Expression) Expression {
+	if len(args) != 2 {
+		panic(fmt.Errorf("function \"after\" for DateTime needs exactly two arguments but got %d", len(args)))
+	}
+
+	return makeFunctionDateTimeAfter(args[0], args[1])
+}
+
+func (f functionDateTimeAfter) GetResultType() Type {
+	return TypeBoolean
+}
+
+func (f functionDateTimeAfter) describe() string {
+	return "after"
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionDateTimeAfter) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionDateTimeAfter) Calculate(ctx *Context) (AttributeValue, error) {
+	first, err := ctx.calculateDateTimeExpression(f.first)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "first argument"), f.describe())
+	}
+
+	second, err := ctx.calculateDateTimeExpression(f.second)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "second argument"), f.describe())
+	}
+
+	return MakeBooleanValue(first.After(second)), nil
+}
+
+func functionDateTimeAfterValidator(args []Expression) functionMaker {
+	if len(args) != 2 || args[0].GetResultType() != TypeDateTime || args[1].GetResultType() != TypeDateTime {
+		return nil
+	}
+
+	return makeFunctionDateTimeAfterAlt
+}
@@ -0,0 +1,59 @@
+package pdp
+
+import "fmt"
+
+type functionDateTimeBefore struct {
+	first  Expression
+	second Expression
+}
+
+func makeFunctionDateTimeBefore(first, second Expression) Expression {
+	return functionDateTimeBefore{
+		first:  first,
+		second: second}
+}
+
+func makeFunctionDateTimeBeforeAlt(args []Expression) Expression {
+	if len(args) != 2 {
+		panic(fmt.Errorf("function \"before\" for DateTime needs exactly two arguments but got %d", len(args)))
+	}
+
+	return makeFunctionDateTimeBefore(args[0], args[1])
+}
+
+func (f functionDateTimeBefore) GetResultType() Type {
+	return TypeBoolean
+}
+
+func (f functionDateTimeBefore) describe() string {
+	return "before"
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionDateTimeBefore) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionDateTimeBefore) Calculate(ctx *Context) (AttributeValue, error) {
+	first, err := ctx.calculateDateTimeExpression(f.first)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "first argument"), f.describe())
+	}
+
+	second, err := ctx.calculateDateTimeExpression(f.second)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "second argument"), f.describe())
+	}
+
+	return MakeBooleanValue(first.Before(second)), nil
+}
+
+func functionDateTimeBeforeValidator(args []Expression) functionMaker {
+	if len(args) != 2 || args[0].GetResultType() != TypeDateTime || args[1].GetResultType() != TypeDateTime {
+		return nil
+	}
+
+	return makeFunctionDateTimeBeforeAlt
+}
@@ -0,0 +1,50 @@
+package pdp
+
+import "fmt"
+
+type functionDateTimeDayOfWeek struct {
+	e Expression
+}
+
+func makeFunctionDateTimeDayOfWeek(e Expression) Expression {
+	return functionDateTimeDayOfWeek{e: e}
+}
+
+func makeFunctionDateTimeDayOfWeekAlt(args []Expression) Expression {
+	if len(args) != 1 {
+		panic(fmt.Errorf("function \"day of week\" for DateTime needs exactly one argument but got %d", len(args)))
+	}
+
+	return makeFunctionDateTimeDayOfWeek(args[0])
+}
+
+func (f functionDateTimeDayOfWeek) GetResultType() Type {
+	return TypeString
+}
+
+func (f functionDateTimeDayOfWeek) describe() string {
+	return "day of week"
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionDateTimeDayOfWeek) WalkArguments(visit func(e Expression)) {
+	visit(f.e)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionDateTimeDayOfWeek) Calculate(ctx *Context) (AttributeValue, error) {
+	t, err := ctx.calculateDateTimeExpression(f.e)
+	if err != nil {
+		return UndefinedValue, bindError(err, f.describe())
+	}
+
+	return MakeStringValue(t.Weekday().String()), nil
+}
+
+func functionDateTimeDayOfWeekValidator(args []Expression) functionMaker {
+	if len(args) != 1 || args[0].GetResultType() != TypeDateTime {
+		return nil
+	}
+
+	return makeFunctionDateTimeDayOfWeekAlt
+}
@@ -0,0 +1,97 @@
+package pdp
+
+import (
+	"fmt"
+	"time"
+)
+
+type functionDateTimeInTimeZone struct {
+	e    Expression
+	zone Expression
+	loc  *time.Location
+	err  error
+}
+
+func makeFunctionDateTimeInTimeZone(e, zone Expression) Expression {
+	f := functionDateTimeInTimeZone{
+		e:    e,
+		zone: zone}
+
+	if v, ok := zone.(AttributeValue); ok {
+		if s, err := v.str(); err == nil {
+			f.loc, f.err = loadTimeZone(s)
+		}
+	}
+
+	return f
+}
+
+func makeFunctionDateTimeInTimeZoneAlt(args []Expression) Expression {
+	if len(args) != 2 {
+		panic(fmt.Errorf("function \"in time zone\" for DateTime needs exactly two arguments but got %d", len(args)))
+	}
+
+	return makeFunctionDateTimeInTimeZone(args[0], args[1])
+}
+
+func (f functionDateTimeInTimeZone) GetResultType() Type {
+	return TypeDateTime
+}
+
+func (f functionDateTimeInTimeZone) describe() string {
+	return "in time zone"
+}
+
+func (f functionDateTimeInTimeZone) validate() error {
+	return f.err
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionDateTimeInTimeZone) WalkArguments(visit func(e Expression)) {
+	visit(f.e)
+	visit(f.zone)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionDateTimeInTimeZone) Calculate(ctx *Context) (AttributeValue, error) {
+	t, err := ctx.calculateDateTimeExpression(f.e)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "first argument"), f.describe())
+	}
+
+	loc := f.loc
+	if loc == nil {
+		if f.err != nil {
+			return UndefinedValue, bindError(f.err, f.describe())
+		}
+
+		zone, err := ctx.calculateStringExpression(f.zone)
+		if err != nil {
+			return UndefinedValue, bindError(bindError(err, "time zone argument"), f.describe())
+		}
+
+		loc, err = loadTimeZone(zone)
+		if err != nil {
+			return UndefinedValue, bindError(err, f.describe())
+		}
+	}
+
+	return MakeDateTimeValue(t.In(loc)), nil
+}
+
+func functionDateTimeInTimeZoneValidator(args []Expression) functionMaker {
+	if len(args) != 2 || args[0].GetResultType() != TypeDateTime || args[1].GetResultType() != TypeString {
+		return nil
+	}
+
+	return makeFunctionDateTimeInTimeZoneAlt
+}
+
+func loadTimeZone(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, newInvalidTimeZoneError(name, err)
+	}
+
+	return loc, nil
+}
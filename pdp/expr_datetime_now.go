@@ -0,0 +1,38 @@
+package pdp
+
+import "fmt"
+
+type functionDateTimeNow struct{}
+
+func makeFunctionDateTimeNow() Expression {
+	return functionDateTimeNow{}
+}
+
+func makeFunctionDateTimeNowAlt(args []Expression) Expression {
+	if len(args) != 0 {
+		panic(fmt.Errorf("function \"now\" needs no arguments but got %d", len(args)))
+	}
+
+	return makeFunctionDateTimeNow()
+}
+
+func (f functionDateTimeNow) GetResultType() Type {
+	return TypeDateTime
+}
+
+func (f functionDateTimeNow) describe() string {
+	return "now"
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionDateTimeNow) Calculate(ctx *Context) (AttributeValue, error) {
+	return MakeDateTimeValue(ctx.now()), nil
+}
+
+func functionDateTimeNowValidator(args []Expression) functionMaker {
+	if len(args) != 0 {
+		return nil
+	}
+
+	return makeFunctionDateTimeNowAlt
+}
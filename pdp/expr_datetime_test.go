@@ -0,0 +1,196 @@
+package pdp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateTimeFunctions(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Expected New York time zone but got error %s", err)
+	}
+
+	ctx := &Context{
+		a: map[string]interface{}{
+			"t":    MakeDateTimeValue(time.Date(2019, 1, 4, 17, 30, 0, 0, time.UTC)),
+			"d":    MakeDurationValue(15 * time.Minute),
+			"zone": MakeStringValue("America/New_York")}}
+	ctx.SetClock(func() time.Time {
+		return time.Date(2019, 1, 4, 17, 40, 0, 0, time.UTC)
+	})
+
+	testCases := []struct {
+		name string
+		args []Expression
+		t    Type
+		v    AttributeValue
+	}{
+		{"now", []Expression{}, TypeDateTime,
+			MakeDateTimeValue(time.Date(2019, 1, 4, 17, 40, 0, 0, time.UTC))},
+		{"add", []Expression{MakeDateTimeDesignator("t"), MakeDurationDesignator("d")}, TypeDateTime,
+			MakeDateTimeValue(time.Date(2019, 1, 4, 17, 45, 0, 0, time.UTC))},
+		{"add", []Expression{MakeDurationDesignator("d"), MakeDurationValue(time.Hour)}, TypeDuration,
+			MakeDurationValue(75 * time.Minute)},
+		{"before", []Expression{MakeDateTimeDesignator("t"), makeFunctionDateTimeNow()}, TypeBoolean,
+			MakeBooleanValue(true)},
+		{"after", []Expression{MakeDateTimeDesignator("t"), makeFunctionDateTimeNow()}, TypeBoolean,
+			MakeBooleanValue(false)},
+		{"before", []Expression{
+			makeFunctionDateTimeAdd(MakeDateTimeDesignator("t"), MakeDurationDesignator("d")),
+			makeFunctionDateTimeNow()}, TypeBoolean, MakeBooleanValue(false)},
+		{"day of week", []Expression{MakeDateTimeDesignator("t")}, TypeString, MakeStringValue("Friday")},
+		{"in time zone", []Expression{MakeDateTimeDesignator("t"), MakeStringValue("America/New_York")},
+			TypeDateTime, MakeDateTimeValue(time.Date(2019, 1, 4, 12, 30, 0, 0, ny))},
+		{"in time zone", []Expression{MakeDateTimeDesignator("t"), MakeStringDesignator("zone")},
+			TypeDateTime, MakeDateTimeValue(time.Date(2019, 1, 4, 12, 30, 0, 0, ny))},
+		{"time of day in range", []Expression{MakeDateTimeDesignator("t"), MakeStringValue("09:00"),
+			MakeStringValue("18:00")}, TypeBoolean, MakeBooleanValue(true)},
+		{"time of day in range", []Expression{MakeDateTimeDesignator("t"), MakeStringValue("09:00"),
+			MakeStringValue("17:30")}, TypeBoolean, MakeBooleanValue(false)},
+		{"time of day in range", []Expression{MakeDateTimeDesignator("t"), MakeStringValue("22:00"),
+			MakeStringValue("06:00")}, TypeBoolean, MakeBooleanValue(false)},
+		{"time of day in range", []Expression{
+			makeFunctionDateTimeInTimeZone(MakeDateTimeDesignator("t"), MakeStringValue("Asia/Tokyo")),
+			MakeStringValue("22:00"), MakeStringValue("06:00:30")}, TypeBoolean, MakeBooleanValue(true)},
+	}
+
+	for _, tc := range testCases {
+		desc := tc.name + "(" + describeTestArgs(tc.args) + ")"
+
+		maker := findValidator(tc.name, tc.args...)
+		if maker == nil {
+			t.Errorf("Expected function for %s but got nothing", desc)
+			continue
+		}
+
+		e := maker(tc.args)
+		if err := ValidateExpression(e); err != nil {
+			t.Errorf("Expected no validation error for %s but got %s", desc, err)
+			continue
+		}
+
+		if rt := e.GetResultType(); rt != tc.t {
+			t.Errorf("Expected %q as result type of %s but got %q", tc.t, desc, rt)
+		}
+
+		v, err := e.Calculate(ctx)
+		if err != nil {
+			t.Errorf("Expected no error for %s but got %s", desc, err)
+			continue
+		}
+
+		if s, err := v.Serialize(); err != nil {
+			t.Errorf("Expected serializable value for %s but got error %s", desc, err)
+		} else if es, _ := tc.v.Serialize(); s != es || v.GetResultType() != tc.v.GetResultType() {
+			t.Errorf("Expected %s for %s but got %s", tc.v.describe(), desc, v.describe())
+		}
+	}
+}
+
+func TestContextClock(t *testing.T) {
+	ctx, err := NewContext(nil, 0, nil)
+	if err != nil {
+		t.Fatalf("Expected context but got error %s", err)
+	}
+
+	calls := 0
+	ctx.SetClock(func() time.Time {
+		calls++
+		return time.Date(2019, 1, 4, 17, 40, calls, 0, time.UTC)
+	})
+
+	e := makeFunctionDateTimeNow()
+	for i := 0; i < 3; i++ {
+		v, err := e.Calculate(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error but got %s", err)
+		}
+
+		d, err := v.dateTime()
+		if err != nil {
+			t.Fatalf("Expected datetime value but got error %s", err)
+		}
+
+		if ed := time.Date(2019, 1, 4, 17, 40, 1, 0, time.UTC); !d.Equal(ed) {
+			t.Errorf("Expected %s but got %s", ed, d)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected clock to be called once per context but got %d calls", calls)
+	}
+
+	ctx.SetClock(nil)
+	before := time.Now()
+	v, err := e.Calculate(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if d, err := v.dateTime(); err != nil {
+		t.Errorf("Expected datetime value but got error %s", err)
+	} else if d.Before(before) || d.After(time.Now()) {
+		t.Errorf("Expected current time for context without clock but got %s", d)
+	}
+}
+
+func TestDateTimeFunctionsErrors(t *testing.T) {
+	for _, s := range []string{"9:00", "09:00:00:00", "24:00", "09:60", "09:00:60", "+9:00", "noon"} {
+		e := makeFunctionDateTimeTimeOfDayInRange(MakeDateTimeDesignator("t"), MakeStringValue(s),
+			MakeStringValue("18:00"))
+		if err := ValidateExpression(e); err == nil {
+			t.Errorf("Expected error for invalid time of day %q but got nothing", s)
+		} else if _, ok := err.(*invalidTimeOfDayError); !ok {
+			t.Errorf("Expected *invalidTimeOfDayError for %q but got %T (%s)", s, err, err)
+		}
+	}
+
+	e := makeFunctionDateTimeInTimeZone(MakeDateTimeDesignator("t"), MakeStringValue("Mars/Olympus_Mons"))
+	if err := ValidateExpression(e); err == nil {
+		t.Errorf("Expected error for invalid time zone but got nothing")
+	} else if _, ok := err.(*invalidTimeZoneError); !ok {
+		t.Errorf("Expected *invalidTimeZoneError but got %T (%s)", err, err)
+	}
+
+	ctx := &Context{
+		a: map[string]interface{}{
+			"t":    MakeDateTimeValue(time.Date(2019, 1, 4, 17, 30, 0, 0, time.UTC)),
+			"from": MakeStringValue("9 AM"),
+			"zone": MakeStringValue("Mars/Olympus_Mons")}}
+
+	e = makeFunctionDateTimeTimeOfDayInRange(MakeDateTimeDesignator("t"), MakeStringDesignator("from"),
+		MakeStringValue("18:00"))
+	if _, err := e.Calculate(ctx); err == nil {
+		t.Errorf("Expected error for invalid time of day from attribute but got nothing")
+	}
+
+	e = makeFunctionDateTimeInTimeZone(MakeDateTimeDesignator("t"), MakeStringDesignator("zone"))
+	if _, err := e.Calculate(ctx); err == nil {
+		t.Errorf("Expected error for invalid time zone from attribute but got nothing")
+	}
+
+	for _, tc := range []struct {
+		name string
+		args []Expression
+	}{
+		{"now", []Expression{MakeDateTimeDesignator("t")}},
+		{"add", []Expression{MakeDurationDesignator("d"), MakeDateTimeDesignator("t")}},
+		{"before", []Expression{MakeDateTimeDesignator("t"), MakeStringValue("2019-01-04T17:30:00Z")}},
+		{"day of week", []Expression{MakeStringValue("2019-01-04T17:30:00Z")}},
+		{"time of day in range", []Expression{MakeDateTimeDesignator("t"), MakeStringValue("09:00")}},
+		{"in time zone", []Expression{MakeStringValue("UTC"), MakeDateTimeDesignator("t")}},
+	} {
+		if findValidator(tc.name, tc.args...) != nil {
+			t.Errorf("Expected no function for %s(%s)", tc.name, describeTestArgs(tc.args))
+		}
+	}
+
+	if _, ok := TargetCompatibleExpressions["before"][TypeDateTime][TypeDateTime]; !ok {
+		t.Errorf("Expected \"before\" to be target compatible")
+	}
+
+	if _, ok := TargetCompatibleExpressions["after"][TypeDateTime][TypeDateTime]; !ok {
+		t.Errorf("Expected \"after\" to be target compatible")
+	}
+}
@@ -0,0 +1,156 @@
+package pdp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type functionDateTimeTimeOfDayInRange struct {
+	e    Expression
+	from Expression
+	to   Expression
+
+	fromSec int
+	toSec   int
+	err     error
+}
+
+func makeFunctionDateTimeTimeOfDayInRange(e, from, to Expression) Expression {
+	f := functionDateTimeTimeOfDayInRange{
+		e:       e,
+		from:    from,
+		to:      to,
+		fromSec: -1,
+		toSec:   -1}
+
+	f.fromSec, f.err = parseImmediateTimeOfDay(from)
+	if f.err == nil {
+		f.toSec, f.err = parseImmediateTimeOfDay(to)
+	}
+
+	return f
+}
+
+func makeFunctionDateTimeTimeOfDayInRangeAlt(args []Expression) Expression {
+	if len(args) != 3 {
+		panic(fmt.Errorf("function \"time of day in range\" for DateTime needs exactly three arguments but got %d",
+			len(args)))
+	}
+
+	return makeFunctionDateTimeTimeOfDayInRange(args[0], args[1], args[2])
+}
+
+func (f functionDateTimeTimeOfDayInRange) GetResultType() Type {
+	return TypeBoolean
+}
+
+func (f functionDateTimeTimeOfDayInRange) describe() string {
+	return "time of day in range"
+}
+
+func (f functionDateTimeTimeOfDayInRange) validate() error {
+	return f.err
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionDateTimeTimeOfDayInRange) WalkArguments(visit func(e Expression)) {
+	visit(f.e)
+	visit(f.from)
+	visit(f.to)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionDateTimeTimeOfDayInRange) Calculate(ctx *Context) (AttributeValue, error) {
+	if f.err != nil {
+		return UndefinedValue, bindError(f.err, f.describe())
+	}
+
+	t, err := ctx.calculateDateTimeExpression(f.e)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "first argument"), f.describe())
+	}
+
+	from := f.fromSec
+	if from < 0 {
+		from, err = calculateTimeOfDay(ctx, f.from)
+		if err != nil {
+			return UndefinedValue, bindError(bindError(err, "from argument"), f.describe())
+		}
+	}
+
+	to := f.toSec
+	if to < 0 {
+		to, err = calculateTimeOfDay(ctx, f.to)
+		if err != nil {
+			return UndefinedValue, bindError(bindError(err, "to argument"), f.describe())
+		}
+	}
+
+	h, m, s := t.Clock()
+	sec := h*3600 + m*60 + s
+
+	if from <= to {
+		return MakeBooleanValue(sec >= from && sec < to), nil
+	}
+
+	return MakeBooleanValue(sec >= from || sec < to), nil
+}
+
+func functionDateTimeTimeOfDayInRangeValidator(args []Expression) functionMaker {
+	if len(args) != 3 || args[0].GetResultType() != TypeDateTime ||
+		args[1].GetResultType() != TypeString || args[2].GetResultType() != TypeString {
+		return nil
+	}
+
+	return makeFunctionDateTimeTimeOfDayInRangeAlt
+}
+
+func parseImmediateTimeOfDay(e Expression) (int, error) {
+	if v, ok := e.(AttributeValue); ok {
+		if s, err := v.str(); err == nil {
+			return parseTimeOfDay(s)
+		}
+	}
+
+	return -1, nil
+}
+
+func calculateTimeOfDay(ctx *Context, e Expression) (int, error) {
+	s, err := ctx.calculateStringExpression(e)
+	if err != nil {
+		return -1, err
+	}
+
+	return parseTimeOfDay(s)
+}
+
+// parseTimeOfDay converts "hh:mm" or "hh:mm:ss" string to number of seconds
+// since midnight.
+func parseTimeOfDay(s string) (int, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return -1, newInvalidTimeOfDayError(s)
+	}
+
+	limits := []int{24, 60, 60}
+	n := 0
+	for i, p := range parts {
+		if len(p) != 2 || p[0] < '0' || p[0] > '9' || p[1] < '0' || p[1] > '9' {
+			return -1, newInvalidTimeOfDayError(s)
+		}
+
+		x, err := strconv.Atoi(p)
+		if err != nil || x >= limits[i] {
+			return -1, newInvalidTimeOfDayError(s)
+		}
+
+		n = n*60 + x
+	}
+
+	if len(parts) == 2 {
+		n *= 60
+	}
+
+	return n, nil
+}
@@ -29,6 +29,12 @@ func (f functionSetOfDomainsContains) describe() string {
 	return "contains"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionSetOfDomainsContains) WalkArguments(visit func(e Expression)) {
+	visit(f.set)
+	visit(f.value)
+}
+
 func (f functionSetOfDomainsContains) Calculate(ctx *Context) (AttributeValue, error) {
 	set, err := ctx.calculateSetOfDomainsExpression(f.set)
 	if err != nil {
@@ -0,0 +1,59 @@
+package pdp
+
+import "fmt"
+
+type functionDurationAdd struct {
+	first  Expression
+	second Expression
+}
+
+func makeFunctionDurationAdd(first, second Expression) Expression {
+	return functionDurationAdd{
+		first:  first,
+		second: second}
+}
+
+func makeFunctionDurationAddAlt(args []Expression) Expression {
+	if len(args) != 2 {
+		panic(fmt.Errorf("function \"add\" for Duration needs exactly two arguments but got %d", len(args)))
+	}
+
+	return makeFunctionDurationAdd(args[0], args[1])
+}
+
+func (f functionDurationAdd) GetResultType() Type {
+	return TypeDuration
+}
+
+func (f functionDurationAdd) describe() string {
+	return "add"
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionDurationAdd) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionDurationAdd) Calculate(ctx *Context) (AttributeValue, error) {
+	first, err := ctx.calculateDurationExpression(f.first)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "first argument"), f.describe())
+	}
+
+	second, err := ctx.calculateDurationExpression(f.second)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "second argument"), f.describe())
+	}
+
+	return MakeDurationValue(first + second), nil
+}
+
+func functionDurationAddValidator(args []Expression) functionMaker {
+	if len(args) != 2 || args[0].GetResultType() != TypeDuration || args[1].GetResultType() != TypeDuration {
+		return nil
+	}
+
+	return makeFunctionDurationAddAlt
+}
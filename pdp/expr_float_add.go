@@ -43,6 +43,12 @@ func (f functionFloatAdd) describe() string {
 	return "add"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionFloatAdd) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 func (f functionFloatAdd) Calculate(ctx *Context) (AttributeValue, error) {
 	first, err := ctx.calculateFloatOrIntegerExpression(f.first)
 	if err != nil {
@@ -30,6 +30,12 @@ func (f functionFloatDivide) describe() string {
 	return "divide"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionFloatDivide) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 func (f functionFloatDivide) Calculate(ctx *Context) (AttributeValue, error) {
 	first, err := ctx.calculateFloatOrIntegerExpression(f.first)
 	if err != nil {
@@ -30,6 +30,12 @@ func (f functionFloatEqual) describe() string {
 	return "equal"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionFloatEqual) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 func (f functionFloatEqual) Calculate(ctx *Context) (AttributeValue, error) {
 	first, err := ctx.calculateFloatOrIntegerExpression(f.first)
 	if err != nil {
@@ -30,6 +30,12 @@ func (f functionFloatGreater) describe() string {
 	return "greater"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionFloatGreater) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 func (f functionFloatGreater) Calculate(ctx *Context) (AttributeValue, error) {
 	first, err := ctx.calculateFloatOrIntegerExpression(f.first)
 	if err != nil {
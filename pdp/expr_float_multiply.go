@@ -30,6 +30,12 @@ func (f functionFloatMultiply) describe() string {
 	return "multiply"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionFloatMultiply) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 func (f functionFloatMultiply) Calculate(ctx *Context) (AttributeValue, error) {
 	first, err := ctx.calculateFloatOrIntegerExpression(f.first)
 	if err != nil {
@@ -32,6 +32,13 @@ func (f functionFloatRange) describe() string {
 	return "range"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionFloatRange) WalkArguments(visit func(e Expression)) {
+	visit(f.min)
+	visit(f.max)
+	visit(f.val)
+}
+
 func (f functionFloatRange) Calculate(ctx *Context) (AttributeValue, error) {
 	min, err := ctx.calculateFloatOrIntegerExpression(f.min)
 	if err != nil {
@@ -30,6 +30,12 @@ func (f functionFloatSubtract) describe() string {
 	return "subtract"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionFloatSubtract) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 func (f functionFloatSubtract) Calculate(ctx *Context) (AttributeValue, error) {
 	first, err := ctx.calculateFloatOrIntegerExpression(f.first)
 	if err != nil {
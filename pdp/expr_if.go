@@ -0,0 +1,76 @@
+package pdp
+
+import "fmt"
+
+type functionIf struct {
+	condition Expression
+	first     Expression
+	second    Expression
+}
+
+func makeFunctionIf(condition, first, second Expression) Expression {
+	return functionIf{
+		condition: condition,
+		first:     first,
+		second:    second}
+}
+
+func makeFunctionIfAlt(args []Expression) Expression {
+	if len(args) != 3 {
+		panic(fmt.Errorf("function \"if\" needs exactly three arguments but got %d", len(args)))
+	}
+
+	return makeFunctionIf(args[0], args[1], args[2])
+}
+
+func (f functionIf) GetResultType() Type {
+	return f.first.GetResultType()
+}
+
+func (f functionIf) describe() string {
+	return "if"
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionIf) WalkArguments(visit func(e Expression)) {
+	visit(f.condition)
+	visit(f.first)
+	visit(f.second)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionIf) Calculate(ctx *Context) (AttributeValue, error) {
+	c, err := ctx.calculateBooleanExpression(f.condition)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "condition"), f.describe())
+	}
+
+	if c {
+		v, err := f.first.Calculate(ctx)
+		if err != nil {
+			return UndefinedValue, bindError(bindError(err, "then"), f.describe())
+		}
+
+		return v, nil
+	}
+
+	v, err := f.second.Calculate(ctx)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "else"), f.describe())
+	}
+
+	return v, nil
+}
+
+func functionIfValidator(args []Expression) functionMaker {
+	if len(args) != 3 || args[0].GetResultType() != TypeBoolean {
+		return nil
+	}
+
+	t := args[1].GetResultType()
+	if t == TypeUndefined || args[2].GetResultType() != t {
+		return nil
+	}
+
+	return makeFunctionIfAlt
+}
@@ -30,6 +30,12 @@ func (f functionIntegerAdd) describe() string {
 	return "add"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionIntegerAdd) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 func (f functionIntegerAdd) Calculate(ctx *Context) (AttributeValue, error) {
 	first, err := ctx.calculateIntegerExpression(f.first)
 	if err != nil {
@@ -30,6 +30,12 @@ func (f functionIntegerDivide) describe() string {
 	return "divide"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionIntegerDivide) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 func (f functionIntegerDivide) Calculate(ctx *Context) (AttributeValue, error) {
 	first, err := ctx.calculateIntegerExpression(f.first)
 	if err != nil {
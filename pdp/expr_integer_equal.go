@@ -30,6 +30,12 @@ func (f functionIntegerEqual) describe() string {
 	return "equal"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionIntegerEqual) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 func (f functionIntegerEqual) Calculate(ctx *Context) (AttributeValue, error) {
 	first, err := ctx.calculateIntegerExpression(f.first)
 	if err != nil {
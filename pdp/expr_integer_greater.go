@@ -30,6 +30,12 @@ func (f functionIntegerGreater) describe() string {
 	return "greater"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionIntegerGreater) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 func (f functionIntegerGreater) Calculate(ctx *Context) (AttributeValue, error) {
 	first, err := ctx.calculateIntegerExpression(f.first)
 	if err != nil {
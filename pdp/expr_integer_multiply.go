@@ -30,6 +30,12 @@ func (f functionIntegerMultiply) describe() string {
 	return "multiply"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionIntegerMultiply) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 func (f functionIntegerMultiply) Calculate(ctx *Context) (AttributeValue, error) {
 	first, err := ctx.calculateIntegerExpression(f.first)
 	if err != nil {
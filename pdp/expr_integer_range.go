@@ -33,6 +33,13 @@ func (f functionIntegerRange) describe() string {
 	return "range"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionIntegerRange) WalkArguments(visit func(e Expression)) {
+	visit(f.min)
+	visit(f.max)
+	visit(f.val)
+}
+
 func (f functionIntegerRange) Calculate(ctx *Context) (AttributeValue, error) {
 	min, err := ctx.calculateIntegerExpression(f.min)
 	if err != nil {
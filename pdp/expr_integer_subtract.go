@@ -30,6 +30,12 @@ func (f functionIntegerSubtract) describe() string {
 	return "subtract"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionIntegerSubtract) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 func (f functionIntegerSubtract) Calculate(ctx *Context) (AttributeValue, error) {
 	first, err := ctx.calculateIntegerExpression(f.first)
 	if err != nil {
@@ -28,6 +28,11 @@ func (f functionListOfStrings) describe() string {
 	return "list of strings"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionListOfStrings) WalkArguments(visit func(e Expression)) {
+	visit(f.e)
+}
+
 // Calculate implements Expression interface and returns calculated value
 func (f functionListOfStrings) Calculate(ctx *Context) (AttributeValue, error) {
 	t := f.e.GetResultType()
@@ -31,6 +31,12 @@ func (f functionListOfStringsContains) describe() string {
 	return "contains"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionListOfStringsContains) WalkArguments(visit func(e Expression)) {
+	visit(f.list)
+	visit(f.value)
+}
+
 // Calculate implements Expression interface and returns calculated value
 func (f functionListOfStringsContains) Calculate(ctx *Context) (AttributeValue, error) {
 	list, err := ctx.calculateListOfStringsExpression(f.list)
@@ -30,6 +30,12 @@ func (f functionListOfStringsEqual) describe() string {
 	return "equal"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionListOfStringsEqual) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 // Calculate implements Expression interface and returns calculated value
 func (f functionListOfStringsEqual) Calculate(ctx *Context) (AttributeValue, error) {
 	first, err := ctx.calculateListOfStringsExpression(f.first)
@@ -31,6 +31,12 @@ func (f functionListOfStringsIntersect) describe() string {
 	return "intersect"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionListOfStringsIntersect) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 // Calculate implements Expression interface and returns calculated value
 func (f functionListOfStringsIntersect) Calculate(ctx *Context) (AttributeValue, error) {
 	first, err := ctx.calculateListOfStringsExpression(f.first)
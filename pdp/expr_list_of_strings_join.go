@@ -0,0 +1,77 @@
+package pdp
+
+import (
+	"fmt"
+	"strings"
+)
+
+type functionListOfStringsJoin struct {
+	list Expression
+	sep  Expression
+}
+
+func makeFunctionListOfStringsJoin(list, sep Expression) Expression {
+	return functionListOfStringsJoin{
+		list: list,
+		sep:  sep}
+}
+
+func makeFunctionListOfStringsJoinAlt(args []Expression) Expression {
+	if len(args) != 2 {
+		panic(fmt.Errorf("function \"join\" for List of Strings needs exactly two arguments but got %d", len(args)))
+	}
+
+	return makeFunctionListOfStringsJoin(args[0], args[1])
+}
+
+func (f functionListOfStringsJoin) GetResultType() Type {
+	return TypeString
+}
+
+func (f functionListOfStringsJoin) describe() string {
+	return "join"
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionListOfStringsJoin) WalkArguments(visit func(e Expression)) {
+	visit(f.list)
+	visit(f.sep)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionListOfStringsJoin) Calculate(ctx *Context) (AttributeValue, error) {
+	var list []string
+	if f.list.GetResultType() == TypeSetOfStrings {
+		set, err := ctx.calculateSetOfStringsExpression(f.list)
+		if err != nil {
+			return UndefinedValue, bindError(bindError(err, "first argument"), f.describe())
+		}
+
+		list = SortSetOfStrings(set)
+	} else {
+		var err error
+		list, err = ctx.calculateListOfStringsExpression(f.list)
+		if err != nil {
+			return UndefinedValue, bindError(bindError(err, "first argument"), f.describe())
+		}
+	}
+
+	sep, err := ctx.calculateStringExpression(f.sep)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "separator argument"), f.describe())
+	}
+
+	return MakeStringValue(strings.Join(list, sep)), nil
+}
+
+func functionListOfStringsJoinValidator(args []Expression) functionMaker {
+	if len(args) != 2 || args[1].GetResultType() != TypeString {
+		return nil
+	}
+
+	if t := args[0].GetResultType(); t != TypeListOfStrings && t != TypeSetOfStrings {
+		return nil
+	}
+
+	return makeFunctionListOfStringsJoinAlt
+}
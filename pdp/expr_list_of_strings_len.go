@@ -27,6 +27,11 @@ func (f functionListOfStringsLen) describe() string {
 	return "len"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionListOfStringsLen) WalkArguments(visit func(e Expression)) {
+	visit(f.e)
+}
+
 // Calculate implements Expression interface and returns calculated value
 func (f functionListOfStringsLen) Calculate(ctx *Context) (AttributeValue, error) {
 	s, err := ctx.calculateListOfStringsExpression(f.e)
@@ -31,6 +31,12 @@ func (f functionNetworkContainsAddress) describe() string {
 	return "contains"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionNetworkContainsAddress) WalkArguments(visit func(e Expression)) {
+	visit(f.network)
+	visit(f.address)
+}
+
 // Calculate implements Expression interface and returns calculated value
 func (f functionNetworkContainsAddress) Calculate(ctx *Context) (AttributeValue, error) {
 	n, err := ctx.calculateNetworkExpression(f.network)
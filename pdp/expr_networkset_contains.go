@@ -30,6 +30,12 @@ func (f functionSetOfNetworksContainsAddress) describe() string {
 	return "contains"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionSetOfNetworksContainsAddress) WalkArguments(visit func(e Expression)) {
+	visit(f.set)
+	visit(f.value)
+}
+
 // Calculate implements Expression interface and returns calculated value
 func (f functionSetOfNetworksContainsAddress) Calculate(ctx *Context) (AttributeValue, error) {
 	set, err := ctx.calculateSetOfNetworksExpression(f.set)
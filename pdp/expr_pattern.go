@@ -0,0 +1,220 @@
+package pdp
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type patternCompiler func(pattern string, fold bool) (*regexp.Regexp, error)
+
+type functionPatternMatch struct {
+	name    string
+	compile patternCompiler
+	fold    bool
+	value   Expression
+	pattern Expression
+	re      *regexp.Regexp
+	err     error
+}
+
+func makeFunctionPatternMatch(name string, compile patternCompiler, value, pattern Expression) Expression {
+	f := functionPatternMatch{
+		name:    name,
+		compile: compile,
+		fold:    value.GetResultType() == TypeDomain,
+		value:   value,
+		pattern: pattern}
+
+	if v, ok := pattern.(AttributeValue); ok {
+		if s, err := v.str(); err == nil {
+			f.re, f.err = compile(s, f.fold)
+		}
+	}
+
+	return f
+}
+
+func makeFunctionMatch(value, pattern Expression) Expression {
+	return makeFunctionPatternMatch("match", compileRegexp, value, pattern)
+}
+
+func makeFunctionMatchAlt(args []Expression) Expression {
+	if len(args) != 2 {
+		panic(fmt.Errorf("function \"match\" needs exactly two arguments but got %d", len(args)))
+	}
+
+	return makeFunctionMatch(args[0], args[1])
+}
+
+func makeFunctionGlob(value, pattern Expression) Expression {
+	return makeFunctionPatternMatch("glob", compileGlob, value, pattern)
+}
+
+func makeFunctionGlobAlt(args []Expression) Expression {
+	if len(args) != 2 {
+		panic(fmt.Errorf("function \"glob\" needs exactly two arguments but got %d", len(args)))
+	}
+
+	return makeFunctionGlob(args[0], args[1])
+}
+
+func (f functionPatternMatch) GetResultType() Type {
+	return TypeBoolean
+}
+
+func (f functionPatternMatch) describe() string {
+	return f.name
+}
+
+func (f functionPatternMatch) validate() error {
+	return f.err
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionPatternMatch) WalkArguments(visit func(e Expression)) {
+	visit(f.value)
+	visit(f.pattern)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionPatternMatch) Calculate(ctx *Context) (AttributeValue, error) {
+	v, err := f.value.Calculate(ctx)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "first argument"), f.describe())
+	}
+
+	var s string
+	if f.fold {
+		d, err := v.domain()
+		if err != nil {
+			return UndefinedValue, bindError(bindError(err, "first argument"), f.describe())
+		}
+
+		s = d.String()
+	} else {
+		s, err = v.str()
+		if err != nil {
+			return UndefinedValue, bindError(bindError(err, "first argument"), f.describe())
+		}
+	}
+
+	re := f.re
+	if re == nil {
+		if f.err != nil {
+			return UndefinedValue, bindError(f.err, f.describe())
+		}
+
+		pattern, err := ctx.calculateStringExpression(f.pattern)
+		if err != nil {
+			return UndefinedValue, bindError(bindError(err, "pattern argument"), f.describe())
+		}
+
+		re, err = f.compile(pattern, f.fold)
+		if err != nil {
+			return UndefinedValue, bindError(err, f.describe())
+		}
+	}
+
+	return MakeBooleanValue(re.MatchString(s)), nil
+}
+
+func functionMatchValidator(args []Expression) functionMaker {
+	if !checkPatternMatchArgs(args) {
+		return nil
+	}
+
+	return makeFunctionMatchAlt
+}
+
+func functionGlobValidator(args []Expression) functionMaker {
+	if !checkPatternMatchArgs(args) {
+		return nil
+	}
+
+	return makeFunctionGlobAlt
+}
+
+func checkPatternMatchArgs(args []Expression) bool {
+	if len(args) != 2 || args[1].GetResultType() != TypeString {
+		return false
+	}
+
+	t := args[0].GetResultType()
+	return t == TypeString || t == TypeDomain
+}
+
+func compileRegexp(pattern string, fold bool) (*regexp.Regexp, error) {
+	s := pattern
+	if fold {
+		s = "(?i)" + s
+	}
+
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return nil, newInvalidRegexpError(pattern, err)
+	}
+
+	return re, nil
+}
+
+var (
+	errGlobTrailingEscape = errors.New("trailing backslash")
+	errGlobUnclosedClass  = errors.New("missing closing bracket")
+)
+
+// compileGlob converts glob pattern to anchored regular expression. The "*"
+// matches any sequence of characters (including dots), "?" matches any
+// single character, "[...]" (or "[!...]" for negation) matches a character
+// from class and backslash escapes next character.
+func compileGlob(pattern string, fold bool) (*regexp.Regexp, error) {
+	var b strings.Builder
+	if fold {
+		b.WriteString("(?i)")
+	}
+
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+
+		case '*':
+			b.WriteString(".*")
+
+		case '?':
+			b.WriteString(".")
+
+		case '\\':
+			i++
+			if i >= len(pattern) {
+				return nil, newInvalidGlobError(pattern, errGlobTrailingEscape)
+			}
+
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+
+		case '[':
+			j := strings.IndexByte(pattern[i+1:], ']')
+			if j < 0 {
+				return nil, newInvalidGlobError(pattern, errGlobUnclosedClass)
+			}
+
+			class := pattern[i+1 : i+1+j]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+
+			b.WriteString("[" + strings.Replace(class, "\\", "\\\\", -1) + "]")
+			i += j + 1
+		}
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, newInvalidGlobError(pattern, err)
+	}
+
+	return re, nil
+}
@@ -0,0 +1,158 @@
+package pdp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/infobloxopen/go-trees/domain"
+)
+
+func TestFunctionMatch(t *testing.T) {
+	ctx, err := NewContext(nil, 0, nil)
+	if err != nil {
+		t.Fatalf("Expected context but got error %s", err)
+	}
+
+	testCases := []struct {
+		v AttributeValue
+		p string
+		r bool
+	}{
+		{v: MakeStringValue("test-string"), p: "^test-[a-z]+$", r: true},
+		{v: MakeStringValue("test-string"), p: "string", r: true},
+		{v: MakeStringValue("test-string"), p: "^string", r: false},
+		{v: MakeStringValue("Test"), p: "^test$", r: false},
+		{v: makeTestDomainValue(t, "www.example.com"), p: `^www\.example\.com$`, r: true},
+		{v: makeTestDomainValue(t, "WWW.Example.COM"), p: `^www\.example\.com$`, r: true},
+		{v: makeTestDomainValue(t, "www.example.com"), p: `\.net$`, r: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("match %s %q", tc.v.describe(), tc.p), func(t *testing.T) {
+			e := makeFunctionMatch(tc.v, MakeStringValue(tc.p))
+			if err := ValidateExpression(e); err != nil {
+				t.Fatalf("Expected no error but got %s", err)
+			}
+
+			assertBooleanExpression(t, e, ctx, tc.r)
+		})
+	}
+}
+
+func TestFunctionGlob(t *testing.T) {
+	ctx, err := NewContext(nil, 0, nil)
+	if err != nil {
+		t.Fatalf("Expected context but got error %s", err)
+	}
+
+	testCases := []struct {
+		v AttributeValue
+		p string
+		r bool
+	}{
+		{v: MakeStringValue("test-string"), p: "test-*", r: true},
+		{v: MakeStringValue("test-string"), p: "string", r: false},
+		{v: MakeStringValue("test.string"), p: "test?string", r: true},
+		{v: MakeStringValue("a+b"), p: "a+b", r: true},
+		{v: MakeStringValue("aab"), p: "a+b", r: false},
+		{v: MakeStringValue("a*b"), p: `a\*b`, r: true},
+		{v: MakeStringValue("axb"), p: `a\*b`, r: false},
+		{v: MakeStringValue("host1"), p: "host[0-9]", r: true},
+		{v: MakeStringValue("host1"), p: "host[!0-9]", r: false},
+		{v: makeTestDomainValue(t, "www.corp.example.com"), p: "*.corp.example.com", r: true},
+		{v: makeTestDomainValue(t, "a.b.Corp.Example.com"), p: "*.corp.example.com", r: true},
+		{v: makeTestDomainValue(t, "corp.example.com"), p: "*.corp.example.com", r: false},
+		{v: makeTestDomainValue(t, "www.corp.example.org"), p: "*.corp.example.com", r: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("glob %s %q", tc.v.describe(), tc.p), func(t *testing.T) {
+			e := makeFunctionGlob(tc.v, MakeStringValue(tc.p))
+			if err := ValidateExpression(e); err != nil {
+				t.Fatalf("Expected no error but got %s", err)
+			}
+
+			assertBooleanExpression(t, e, ctx, tc.r)
+		})
+	}
+}
+
+func TestFunctionPatternMatchInvalid(t *testing.T) {
+	e := makeFunctionMatch(MakeStringDesignator("s"), MakeStringValue("[a-"))
+	if err := ValidateExpression(e); err == nil {
+		t.Errorf("Expected error for invalid regular expression but got nothing")
+	} else if _, ok := err.(*invalidRegexpError); !ok {
+		t.Errorf("Expected *invalidRegexpError but got %T (%s)", err, err)
+	}
+
+	for _, p := range []string{`test\`, "test[0-9", "test[]"} {
+		e := makeFunctionGlob(MakeStringDesignator("s"), MakeStringValue(p))
+		if err := ValidateExpression(e); err == nil {
+			t.Errorf("Expected error for invalid glob %q but got nothing", p)
+		} else if _, ok := err.(*invalidGlobError); !ok {
+			t.Errorf("Expected *invalidGlobError for %q but got %T (%s)", p, err, err)
+		}
+	}
+
+	ctx := &Context{
+		a: map[string]interface{}{
+			"s": MakeStringValue("test"),
+			"p": MakeStringValue("^t.*t$"),
+			"x": MakeStringValue("[a-")}}
+
+	e = makeFunctionMatch(MakeStringDesignator("s"), MakeStringDesignator("p"))
+	if err := ValidateExpression(e); err != nil {
+		t.Errorf("Expected no error for pattern from attribute but got %s", err)
+	}
+
+	assertBooleanExpression(t, e, ctx, true)
+
+	e = makeFunctionMatch(MakeStringDesignator("s"), MakeStringDesignator("x"))
+	if _, err := e.Calculate(ctx); err == nil {
+		t.Errorf("Expected error for invalid regular expression from attribute but got nothing")
+	}
+}
+
+func TestFunctionPatternMatchValidators(t *testing.T) {
+	for _, name := range []string{"match", "glob"} {
+		if findValidator(name, MakeStringDesignator("s"), MakeStringValue("test")) == nil {
+			t.Errorf("Expected %q for string and string arguments", name)
+		}
+
+		if findValidator(name, MakeDomainDesignator("d"), MakeStringValue("test")) == nil {
+			t.Errorf("Expected %q for domain and string arguments", name)
+		}
+
+		if findValidator(name, MakeStringValue("test"), MakeDomainDesignator("d")) != nil {
+			t.Errorf("Expected no %q for string and domain arguments", name)
+		}
+
+		if findValidator(name, MakeStringDesignator("s")) != nil {
+			t.Errorf("Expected no %q for single argument", name)
+		}
+	}
+}
+
+func makeTestDomainValue(t *testing.T, s string) AttributeValue {
+	d, err := domain.MakeNameFromString(s)
+	if err != nil {
+		t.Fatalf("Expected domain name from %q but got error %s", s, err)
+	}
+
+	return MakeDomainValue(d)
+}
+
+func assertBooleanExpression(t *testing.T, e Expression, ctx *Context, expected bool) {
+	v, err := e.Calculate(ctx)
+	if err != nil {
+		t.Errorf("Expected no error but got %s", err)
+		return
+	}
+
+	b, err := v.boolean()
+	if err != nil {
+		t.Errorf("Expected boolean value but got error %s", err)
+	} else if b != expected {
+		t.Errorf("Expected %v but got %v", expected, b)
+	}
+}
@@ -0,0 +1,354 @@
+package pdp
+
+import (
+	"github.com/infobloxopen/go-trees/domain"
+	"github.com/infobloxopen/go-trees/domaintree"
+	"github.com/infobloxopen/go-trees/iptree"
+	"github.com/infobloxopen/go-trees/strtree"
+)
+
+// Quantifier* constants identify kinds of quantified expressions.
+const (
+	// QuantifierAny stands for expression which is true if nested boolean
+	// expression is true for at least one item of collection.
+	QuantifierAny = iota
+	// QuantifierAll stands for expression which is true if nested boolean
+	// expression is true for all items of collection.
+	QuantifierAll
+	// QuantifierFilter stands for expression which returns collection of
+	// the same type with only items for which nested boolean expression
+	// is true.
+	QuantifierFilter
+	// QuantifierMap stands for expression which returns list of strings made
+	// of results of nested string expression for each item of collection.
+	QuantifierMap
+
+	totalQuantifiers
+)
+
+var (
+	// QuantifierNames is a list of quantified expression names. The order
+	// must be kept in sync with Quantifier* constants order.
+	QuantifierNames = []string{
+		"any",
+		"all",
+		"filter",
+		"map",
+	}
+
+	// QuantifierIDs maps quantified expression names to Quantifier*
+	// constants. The map is filled by init function.
+	QuantifierIDs = map[string]int{}
+)
+
+func init() {
+	for i := 0; i < totalQuantifiers; i++ {
+		QuantifierIDs[QuantifierNames[i]] = i
+	}
+}
+
+// IterationVariable represents item of collection which quantified expression
+// iterates over. It can be used only inside nested expression of
+// the quantified expression.
+type IterationVariable struct {
+	id string
+	t  Type
+}
+
+// MakeIterationVariable creates reference to iteration variable with given
+// name and type.
+func MakeIterationVariable(ID string, t Type) IterationVariable {
+	return IterationVariable{
+		id: ID,
+		t:  t,
+	}
+}
+
+// GetID returns name of iteration variable.
+func (v IterationVariable) GetID() string {
+	return v.id
+}
+
+// GetResultType implements Expression interface and returns type of
+// the variable.
+func (v IterationVariable) GetResultType() Type {
+	return v.t
+}
+
+// Calculate implements Expression interface and returns current item
+// of collection.
+func (v IterationVariable) Calculate(ctx *Context) (AttributeValue, error) {
+	if r, ok := ctx.it[v.id]; ok && r.t == v.t {
+		return r, nil
+	}
+
+	return UndefinedValue, bindErrorf(newMissingIterationVariableError(), "variable %q", v.id)
+}
+
+// GetIterationItemType returns type of items of collection of given type.
+// It returns false if quantified expressions can't iterate over the type.
+func GetIterationItemType(t Type) (Type, bool) {
+	switch t {
+	case TypeListOfStrings, TypeSetOfStrings:
+		return TypeString, true
+
+	case TypeSetOfNetworks:
+		return TypeNetwork, true
+
+	case TypeSetOfDomains:
+		return TypeDomain, true
+	}
+
+	return nil, false
+}
+
+type functionQuantifier struct {
+	q    int
+	id   string
+	over Expression
+	e    Expression
+}
+
+// MakeQuantifiedExpression creates quantified expression of given kind
+// (one of Quantifier* constants). The expression binds iteration variable
+// with given name to each item of collection calculated by over expression
+// and calculates nested expression e for the item. The nested expression
+// should refer the variable with IterationVariable of type returned by
+// GetIterationItemType. It should be boolean for any, all and filter
+// and string for map.
+func MakeQuantifiedExpression(q int, ID string, over, e Expression) (Expression, error) {
+	if q < 0 || q >= totalQuantifiers {
+		return nil, newUnknownQuantifierError(q)
+	}
+
+	t := over.GetResultType()
+	if _, ok := GetIterationItemType(t); !ok {
+		return nil, newQuantifierCollectionTypeError(QuantifierNames[q], t)
+	}
+
+	et := TypeBoolean
+	if q == QuantifierMap {
+		et = TypeString
+	}
+
+	if t := e.GetResultType(); t != et {
+		return nil, newQuantifierExpressionTypeError(QuantifierNames[q], et, t)
+	}
+
+	return functionQuantifier{
+		q:    q,
+		id:   ID,
+		over: over,
+		e:    e,
+	}, nil
+}
+
+func (f functionQuantifier) GetResultType() Type {
+	switch f.q {
+	case QuantifierFilter:
+		return f.over.GetResultType()
+
+	case QuantifierMap:
+		return TypeListOfStrings
+	}
+
+	return TypeBoolean
+}
+
+func (f functionQuantifier) describe() string {
+	return QuantifierNames[f.q]
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionQuantifier) WalkArguments(visit func(e Expression)) {
+	visit(f.over)
+	visit(f.e)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionQuantifier) Calculate(ctx *Context) (AttributeValue, error) {
+	v, err := f.over.Calculate(ctx)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "collection"), f.describe())
+	}
+
+	items, err := getIterationItems(v)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "collection"), f.describe())
+	}
+
+	if ctx.it == nil {
+		ctx.it = make(map[string]AttributeValue)
+	}
+
+	prev, ok := ctx.it[f.id]
+	defer func() {
+		if ok {
+			ctx.it[f.id] = prev
+		} else {
+			delete(ctx.it, f.id)
+		}
+	}()
+
+	var (
+		strs []string
+		idx  []int
+	)
+	for i, item := range items {
+		ctx.it[f.id] = item
+
+		if f.q == QuantifierMap {
+			s, err := ctx.calculateStringExpression(f.e)
+			if err != nil {
+				return UndefinedValue, bindError(bindErrorf(err, "item %d", i+1), f.describe())
+			}
+
+			strs = append(strs, s)
+			continue
+		}
+
+		b, err := ctx.calculateBooleanExpression(f.e)
+		if err != nil {
+			return UndefinedValue, bindError(bindErrorf(err, "item %d", i+1), f.describe())
+		}
+
+		switch f.q {
+		case QuantifierAny:
+			if b {
+				return MakeBooleanValue(true), nil
+			}
+
+		case QuantifierAll:
+			if !b {
+				return MakeBooleanValue(false), nil
+			}
+
+		case QuantifierFilter:
+			if b {
+				idx = append(idx, i)
+			}
+		}
+	}
+
+	switch f.q {
+	case QuantifierAny:
+		return MakeBooleanValue(false), nil
+
+	case QuantifierAll:
+		return MakeBooleanValue(true), nil
+
+	case QuantifierMap:
+		if strs == nil {
+			strs = []string{}
+		}
+
+		return MakeListOfStringsValue(strs), nil
+	}
+
+	return makeFilteredCollection(v.t, items, idx), nil
+}
+
+// getIterationItems returns items of collection in their order.
+func getIterationItems(v AttributeValue) ([]AttributeValue, error) {
+	switch v.t {
+	case TypeListOfStrings:
+		list, err := v.listOfStrings()
+		if err != nil {
+			return nil, err
+		}
+
+		out := make([]AttributeValue, len(list))
+		for i, s := range list {
+			out[i] = MakeStringValue(s)
+		}
+
+		return out, nil
+
+	case TypeSetOfStrings:
+		set, err := v.setOfStrings()
+		if err != nil {
+			return nil, err
+		}
+
+		list := SortSetOfStrings(set)
+		out := make([]AttributeValue, len(list))
+		for i, s := range list {
+			out[i] = MakeStringValue(s)
+		}
+
+		return out, nil
+
+	case TypeSetOfNetworks:
+		set, err := v.setOfNetworks()
+		if err != nil {
+			return nil, err
+		}
+
+		list := SortSetOfNetworks(set)
+		out := make([]AttributeValue, len(list))
+		for i, n := range list {
+			out[i] = MakeNetworkValue(n)
+		}
+
+		return out, nil
+
+	case TypeSetOfDomains:
+		set, err := v.setOfDomains()
+		if err != nil {
+			return nil, err
+		}
+
+		list := SortSetOfDomains(set)
+		out := make([]AttributeValue, len(list))
+		for i, s := range list {
+			d, err := domain.MakeNameFromString(s)
+			if err != nil {
+				return nil, err
+			}
+
+			out[i] = MakeDomainValue(d)
+		}
+
+		return out, nil
+	}
+
+	return nil, newQuantifierCollectionTypeError("collection", v.t)
+}
+
+func makeFilteredCollection(t Type, items []AttributeValue, idx []int) AttributeValue {
+	switch t {
+	case TypeSetOfStrings:
+		set := strtree.NewTree()
+		for i, j := range idx {
+			s, _ := items[j].str()
+			set.InplaceInsert(s, i)
+		}
+
+		return MakeSetOfStringsValue(set)
+
+	case TypeSetOfNetworks:
+		set := iptree.NewTree()
+		for i, j := range idx {
+			n, _ := items[j].network()
+			set.InplaceInsertNet(n, i)
+		}
+
+		return MakeSetOfNetworksValue(set)
+
+	case TypeSetOfDomains:
+		set := &domaintree.Node{}
+		for i, j := range idx {
+			d, _ := items[j].domain()
+			set.InplaceInsert(d, i)
+		}
+
+		return MakeSetOfDomainsValue(set)
+	}
+
+	list := make([]string, len(idx))
+	for i, j := range idx {
+		list[i], _ = items[j].str()
+	}
+
+	return MakeListOfStringsValue(list)
+}
@@ -0,0 +1,100 @@
+package pdp
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestQuantifiedExpressions(t *testing.T) {
+	ctx := &Context{a: map[string]interface{}{
+		"l": MakeListOfStringsValue([]string{"first", "second", "third"}),
+		"s": MakeSetOfStringsValue(newStrTree("first", "second", "third")),
+		"n": MakeSetOfNetworksValue(newIPTree(makeTestNetwork("192.0.2.0/24"), makeTestNetwork("2001:db8::/32"))),
+		"a": MakeAddressValue(net.ParseIP("192.0.2.1")),
+	}}
+
+	x := MakeIterationVariable("x", TypeString)
+	isSecond := makeFunctionStringEqual(x, MakeStringValue("second"))
+	notFourth := makeFunctionBooleanNot([]Expression{makeFunctionStringEqual(x, MakeStringValue("fourth"))})
+
+	e, err := MakeQuantifiedExpression(QuantifierAny, "x", MakeListOfStringsDesignator("l"), isSecond)
+	assertQuantifiedExpression(t, "any", e, err, ctx, MakeBooleanValue(true))
+
+	e, err = MakeQuantifiedExpression(QuantifierAll, "x", MakeSetOfStringsDesignator("s"), isSecond)
+	assertQuantifiedExpression(t, "all", e, err, ctx, MakeBooleanValue(false))
+
+	e, err = MakeQuantifiedExpression(QuantifierAll, "x", MakeSetOfStringsDesignator("s"), notFourth)
+	assertQuantifiedExpression(t, "all", e, err, ctx, MakeBooleanValue(true))
+
+	e, err = MakeQuantifiedExpression(QuantifierFilter, "x", MakeListOfStringsDesignator("l"),
+		makeFunctionStringContains(x, MakeStringValue("ir")))
+	assertQuantifiedExpression(t, "filter", e, err, ctx, MakeListOfStringsValue([]string{"first", "third"}))
+
+	e, err = MakeQuantifiedExpression(QuantifierMap, "x", MakeSetOfStringsDesignator("s"),
+		makeFunctionStringUpper(x))
+	assertQuantifiedExpression(t, "map", e, err, ctx,
+		MakeListOfStringsValue([]string{"FIRST", "SECOND", "THIRD"}))
+
+	n := MakeIterationVariable("n", TypeNetwork)
+	e, err = MakeQuantifiedExpression(QuantifierFilter, "n", MakeSetOfNetworksDesignator("n"),
+		makeFunctionNetworkContainsAddress(n, MakeAddressDesignator("a")))
+	assertQuantifiedExpression(t, "filter", e, err, ctx,
+		MakeSetOfNetworksValue(newIPTree(makeTestNetwork("192.0.2.0/24"))))
+
+	// Nested quantifier shadows outer variable with the same name.
+	inner, err := MakeQuantifiedExpression(QuantifierAny, "x", MakeListOfStringsDesignator("l"), isSecond)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	e, err = MakeQuantifiedExpression(QuantifierAll, "x", MakeSetOfStringsDesignator("s"), inner)
+	assertQuantifiedExpression(t, "nested all", e, err, ctx, MakeBooleanValue(true))
+	if len(ctx.it) > 0 {
+		t.Errorf("Expected no iteration variables after calculation but got %v", ctx.it)
+	}
+
+	if v, err := x.Calculate(ctx); err == nil {
+		t.Errorf("Expected error for unbound iteration variable but got %s", v.describe())
+	}
+
+	if e, err := MakeQuantifiedExpression(QuantifierAny, "x", MakeStringDesignator("l"), isSecond); err == nil {
+		t.Errorf("Expected error for string collection but got %#v", e)
+	}
+
+	if e, err := MakeQuantifiedExpression(QuantifierMap, "x", MakeListOfStringsDesignator("l"), isSecond); err == nil {
+		t.Errorf("Expected error for boolean map expression but got %#v", e)
+	}
+}
+
+func assertQuantifiedExpression(t *testing.T, desc string, e Expression, err error, ctx *Context, ev AttributeValue) {
+	if err != nil {
+		t.Errorf("Expected no error for %q but got %s", desc, err)
+		return
+	}
+
+	if e.GetResultType() != ev.GetResultType() {
+		t.Errorf("Expected %q as result type of %q but got %q", ev.GetResultType(), desc, e.GetResultType())
+	}
+
+	v, err := e.Calculate(ctx)
+	if err != nil {
+		t.Errorf("Expected no error for %q but got %s", desc, err)
+		return
+	}
+
+	s, err := v.Serialize()
+	if err != nil {
+		t.Errorf("Expected no error for %q but got %s", desc, err)
+		return
+	}
+
+	es, err := ev.Serialize()
+	if err != nil {
+		t.Fatalf("Expected no error for %q but got %s", desc, err)
+	}
+
+	if !reflect.DeepEqual(s, es) {
+		t.Errorf("Expected %q for %q but got %q", es, desc, s)
+	}
+}
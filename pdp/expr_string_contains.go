@@ -32,6 +32,12 @@ func (f functionStringContains) describe() string {
 	return "contains"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionStringContains) WalkArguments(visit func(e Expression)) {
+	visit(f.str)
+	visit(f.substr)
+}
+
 // Calculate implements Expression interface and returns calculated value
 func (f functionStringContains) Calculate(ctx *Context) (AttributeValue, error) {
 	str, err := ctx.calculateStringExpression(f.str)
@@ -29,6 +29,12 @@ func (f functionStringEqual) describe() string {
 	return "equal"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionStringEqual) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 // Calculate implements Expression interface and returns calculated value
 func (f functionStringEqual) Calculate(ctx *Context) (AttributeValue, error) {
 	first, err := ctx.calculateStringExpression(f.first)
@@ -0,0 +1,62 @@
+package pdp
+
+import (
+	"fmt"
+	"strings"
+)
+
+type functionStringHasPrefix struct {
+	str    Expression
+	prefix Expression
+}
+
+func makeFunctionStringHasPrefix(str, prefix Expression) Expression {
+	return functionStringHasPrefix{
+		str:    str,
+		prefix: prefix}
+}
+
+func makeFunctionStringHasPrefixAlt(args []Expression) Expression {
+	if len(args) != 2 {
+		panic(fmt.Errorf("function \"has prefix\" for String needs exactly two arguments but got %d", len(args)))
+	}
+
+	return makeFunctionStringHasPrefix(args[0], args[1])
+}
+
+func (f functionStringHasPrefix) GetResultType() Type {
+	return TypeBoolean
+}
+
+func (f functionStringHasPrefix) describe() string {
+	return "has prefix"
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionStringHasPrefix) WalkArguments(visit func(e Expression)) {
+	visit(f.str)
+	visit(f.prefix)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionStringHasPrefix) Calculate(ctx *Context) (AttributeValue, error) {
+	str, err := ctx.calculateStringExpression(f.str)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "string argument"), f.describe())
+	}
+
+	prefix, err := ctx.calculateStringExpression(f.prefix)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "prefix argument"), f.describe())
+	}
+
+	return MakeBooleanValue(strings.HasPrefix(str, prefix)), nil
+}
+
+func functionStringHasPrefixValidator(args []Expression) functionMaker {
+	if len(args) != 2 || args[0].GetResultType() != TypeString || args[1].GetResultType() != TypeString {
+		return nil
+	}
+
+	return makeFunctionStringHasPrefixAlt
+}
@@ -0,0 +1,62 @@
+package pdp
+
+import (
+	"fmt"
+	"strings"
+)
+
+type functionStringHasSuffix struct {
+	str    Expression
+	suffix Expression
+}
+
+func makeFunctionStringHasSuffix(str, suffix Expression) Expression {
+	return functionStringHasSuffix{
+		str:    str,
+		suffix: suffix}
+}
+
+func makeFunctionStringHasSuffixAlt(args []Expression) Expression {
+	if len(args) != 2 {
+		panic(fmt.Errorf("function \"has suffix\" for String needs exactly two arguments but got %d", len(args)))
+	}
+
+	return makeFunctionStringHasSuffix(args[0], args[1])
+}
+
+func (f functionStringHasSuffix) GetResultType() Type {
+	return TypeBoolean
+}
+
+func (f functionStringHasSuffix) describe() string {
+	return "has suffix"
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionStringHasSuffix) WalkArguments(visit func(e Expression)) {
+	visit(f.str)
+	visit(f.suffix)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionStringHasSuffix) Calculate(ctx *Context) (AttributeValue, error) {
+	str, err := ctx.calculateStringExpression(f.str)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "string argument"), f.describe())
+	}
+
+	suffix, err := ctx.calculateStringExpression(f.suffix)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "suffix argument"), f.describe())
+	}
+
+	return MakeBooleanValue(strings.HasSuffix(str, suffix)), nil
+}
+
+func functionStringHasSuffixValidator(args []Expression) functionMaker {
+	if len(args) != 2 || args[0].GetResultType() != TypeString || args[1].GetResultType() != TypeString {
+		return nil
+	}
+
+	return makeFunctionStringHasSuffixAlt
+}
@@ -0,0 +1,53 @@
+package pdp
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+type functionStringLen struct {
+	e Expression
+}
+
+func makeFunctionStringLen(e Expression) Expression {
+	return functionStringLen{e: e}
+}
+
+func makeFunctionStringLenAlt(args []Expression) Expression {
+	if len(args) != 1 {
+		panic(fmt.Errorf("function \"string length\" for String needs exactly one argument but got %d", len(args)))
+	}
+
+	return makeFunctionStringLen(args[0])
+}
+
+func (f functionStringLen) GetResultType() Type {
+	return TypeInteger
+}
+
+func (f functionStringLen) describe() string {
+	return "string length"
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionStringLen) WalkArguments(visit func(e Expression)) {
+	visit(f.e)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionStringLen) Calculate(ctx *Context) (AttributeValue, error) {
+	s, err := ctx.calculateStringExpression(f.e)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "argument"), f.describe())
+	}
+
+	return MakeIntegerValue(int64(utf8.RuneCountInString(s))), nil
+}
+
+func functionStringLenValidator(args []Expression) functionMaker {
+	if len(args) != 1 || args[0].GetResultType() != TypeString {
+		return nil
+	}
+
+	return makeFunctionStringLenAlt
+}
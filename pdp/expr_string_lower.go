@@ -0,0 +1,53 @@
+package pdp
+
+import (
+	"fmt"
+	"strings"
+)
+
+type functionStringLower struct {
+	e Expression
+}
+
+func makeFunctionStringLower(e Expression) Expression {
+	return functionStringLower{e: e}
+}
+
+func makeFunctionStringLowerAlt(args []Expression) Expression {
+	if len(args) != 1 {
+		panic(fmt.Errorf("function \"lower\" for String needs exactly one argument but got %d", len(args)))
+	}
+
+	return makeFunctionStringLower(args[0])
+}
+
+func (f functionStringLower) GetResultType() Type {
+	return TypeString
+}
+
+func (f functionStringLower) describe() string {
+	return "lower"
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionStringLower) WalkArguments(visit func(e Expression)) {
+	visit(f.e)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionStringLower) Calculate(ctx *Context) (AttributeValue, error) {
+	s, err := ctx.calculateStringExpression(f.e)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "argument"), f.describe())
+	}
+
+	return MakeStringValue(strings.ToLower(s)), nil
+}
+
+func functionStringLowerValidator(args []Expression) functionMaker {
+	if len(args) != 1 || args[0].GetResultType() != TypeString {
+		return nil
+	}
+
+	return makeFunctionStringLowerAlt
+}
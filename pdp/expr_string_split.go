@@ -0,0 +1,66 @@
+package pdp
+
+import (
+	"fmt"
+	"strings"
+)
+
+type functionStringSplit struct {
+	str Expression
+	sep Expression
+}
+
+func makeFunctionStringSplit(str, sep Expression) Expression {
+	return functionStringSplit{
+		str: str,
+		sep: sep}
+}
+
+func makeFunctionStringSplitAlt(args []Expression) Expression {
+	if len(args) != 2 {
+		panic(fmt.Errorf("function \"split\" for String needs exactly two arguments but got %d", len(args)))
+	}
+
+	return makeFunctionStringSplit(args[0], args[1])
+}
+
+func (f functionStringSplit) GetResultType() Type {
+	return TypeListOfStrings
+}
+
+func (f functionStringSplit) describe() string {
+	return "split"
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionStringSplit) WalkArguments(visit func(e Expression)) {
+	visit(f.str)
+	visit(f.sep)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionStringSplit) Calculate(ctx *Context) (AttributeValue, error) {
+	str, err := ctx.calculateStringExpression(f.str)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "string argument"), f.describe())
+	}
+
+	sep, err := ctx.calculateStringExpression(f.sep)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "separator argument"), f.describe())
+	}
+
+	if len(str) <= 0 {
+		return MakeListOfStringsValue([]string{}), nil
+	}
+
+	return MakeListOfStringsValue(strings.Split(str, sep)), nil
+}
+
+func functionStringSplitValidator(args []Expression) functionMaker {
+	if len(args) != 2 || args[0].GetResultType() != TypeString || args[1].GetResultType() != TypeString {
+		return nil
+	}
+
+	return makeFunctionStringSplitAlt
+}
@@ -0,0 +1,97 @@
+package pdp
+
+import "fmt"
+
+type functionStringSubstring struct {
+	str    Expression
+	start  Expression
+	length Expression
+}
+
+func makeFunctionStringSubstring(str, start, length Expression) Expression {
+	return functionStringSubstring{
+		str:    str,
+		start:  start,
+		length: length}
+}
+
+func makeFunctionStringSubstringAlt(args []Expression) Expression {
+	switch len(args) {
+	case 2:
+		return makeFunctionStringSubstring(args[0], args[1], nil)
+
+	case 3:
+		return makeFunctionStringSubstring(args[0], args[1], args[2])
+	}
+
+	panic(fmt.Errorf("function \"substring\" for String needs two or three arguments but got %d", len(args)))
+}
+
+func (f functionStringSubstring) GetResultType() Type {
+	return TypeString
+}
+
+func (f functionStringSubstring) describe() string {
+	return "substring"
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionStringSubstring) WalkArguments(visit func(e Expression)) {
+	visit(f.str)
+	visit(f.start)
+	visit(f.length)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionStringSubstring) Calculate(ctx *Context) (AttributeValue, error) {
+	str, err := ctx.calculateStringExpression(f.str)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "string argument"), f.describe())
+	}
+
+	start, err := ctx.calculateIntegerExpression(f.start)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "start argument"), f.describe())
+	}
+
+	if start < 0 {
+		return UndefinedValue, bindError(newNegativeSubstringStartError(start), f.describe())
+	}
+
+	r := []rune(str)
+	if start >= int64(len(r)) {
+		return MakeStringValue(""), nil
+	}
+
+	r = r[start:]
+
+	if f.length != nil {
+		length, err := ctx.calculateIntegerExpression(f.length)
+		if err != nil {
+			return UndefinedValue, bindError(bindError(err, "length argument"), f.describe())
+		}
+
+		if length < 0 {
+			return UndefinedValue, bindError(newNegativeSubstringLengthError(length), f.describe())
+		}
+
+		if length < int64(len(r)) {
+			r = r[:length]
+		}
+	}
+
+	return MakeStringValue(string(r)), nil
+}
+
+func functionStringSubstringValidator(args []Expression) functionMaker {
+	if len(args) < 2 || len(args) > 3 ||
+		args[0].GetResultType() != TypeString || args[1].GetResultType() != TypeInteger {
+		return nil
+	}
+
+	if len(args) > 2 && args[2].GetResultType() != TypeInteger {
+		return nil
+	}
+
+	return makeFunctionStringSubstringAlt
+}
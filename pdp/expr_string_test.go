@@ -0,0 +1,135 @@
+package pdp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStringFunctions(t *testing.T) {
+	ctx := &Context{
+		a: map[string]interface{}{
+			"s":  MakeStringValue("  Test-String  "),
+			"u":  MakeStringValue("тест-строка"),
+			"l":  MakeListOfStringsValue([]string{"one", "two", "three"}),
+			"ss": MakeSetOfStringsValue(newStrTree("one", "two", "three"))}}
+
+	testCases := []struct {
+		name string
+		args []Expression
+		t    Type
+		v    AttributeValue
+	}{
+		{"lower", []Expression{MakeStringDesignator("s")}, TypeString, MakeStringValue("  test-string  ")},
+		{"upper", []Expression{MakeStringDesignator("s")}, TypeString, MakeStringValue("  TEST-STRING  ")},
+		{"trim", []Expression{MakeStringDesignator("s")}, TypeString, MakeStringValue("Test-String")},
+		{"has prefix", []Expression{MakeStringDesignator("s"), MakeStringValue("  Test")}, TypeBoolean,
+			MakeBooleanValue(true)},
+		{"has prefix", []Expression{MakeStringDesignator("s"), MakeStringValue("Test")}, TypeBoolean,
+			MakeBooleanValue(false)},
+		{"has suffix", []Expression{MakeStringDesignator("s"), MakeStringValue("String  ")}, TypeBoolean,
+			MakeBooleanValue(true)},
+		{"has suffix", []Expression{MakeStringDesignator("s"), MakeStringValue("String")}, TypeBoolean,
+			MakeBooleanValue(false)},
+		{"substring", []Expression{MakeStringDesignator("u"), MakeIntegerValue(5)}, TypeString,
+			MakeStringValue("строка")},
+		{"substring", []Expression{MakeStringDesignator("u"), MakeIntegerValue(0), MakeIntegerValue(4)}, TypeString,
+			MakeStringValue("тест")},
+		{"substring", []Expression{MakeStringDesignator("u"), MakeIntegerValue(5), MakeIntegerValue(100)},
+			TypeString, MakeStringValue("строка")},
+		{"substring", []Expression{MakeStringDesignator("u"), MakeIntegerValue(100)}, TypeString,
+			MakeStringValue("")},
+		{"string length", []Expression{MakeStringDesignator("u")}, TypeInteger, MakeIntegerValue(11)},
+		{"split", []Expression{MakeStringValue("one,two,,three"), MakeStringValue(",")}, TypeListOfStrings,
+			MakeListOfStringsValue([]string{"one", "two", "", "three"})},
+		{"split", []Expression{MakeStringValue(""), MakeStringValue(",")}, TypeListOfStrings,
+			MakeListOfStringsValue([]string{})},
+		{"join", []Expression{MakeListOfStringsDesignator("l"), MakeStringValue(", ")}, TypeString,
+			MakeStringValue("one, two, three")},
+		{"join", []Expression{MakeSetOfStringsDesignator("ss"), MakeStringValue("|")}, TypeString,
+			MakeStringValue("one|two|three")},
+	}
+
+	for _, tc := range testCases {
+		desc := tc.name + "(" + describeTestArgs(tc.args) + ")"
+
+		maker := findValidator(tc.name, tc.args...)
+		if maker == nil {
+			t.Errorf("Expected function for %s but got nothing", desc)
+			continue
+		}
+
+		e := maker(tc.args)
+		if rt := e.GetResultType(); rt != tc.t {
+			t.Errorf("Expected %q as result type of %s but got %q", tc.t, desc, rt)
+		}
+
+		v, err := e.Calculate(ctx)
+		if err != nil {
+			t.Errorf("Expected no error for %s but got %s", desc, err)
+			continue
+		}
+
+		if s, err := v.Serialize(); err != nil {
+			t.Errorf("Expected serializable value for %s but got error %s", desc, err)
+		} else if es, _ := tc.v.Serialize(); s != es || v.GetResultType() != tc.v.GetResultType() {
+			t.Errorf("Expected %s for %s but got %s", tc.v.describe(), desc, v.describe())
+		}
+	}
+}
+
+func TestStringFunctionsErrors(t *testing.T) {
+	ctx := &Context{
+		a: map[string]interface{}{
+			"s": MakeStringValue("test")}}
+
+	for _, args := range [][]Expression{
+		{MakeStringDesignator("s"), MakeIntegerValue(-1)},
+		{MakeStringDesignator("s"), MakeIntegerValue(0), MakeIntegerValue(-1)},
+		{MakeStringDesignator("missing"), MakeIntegerValue(0)},
+	} {
+		maker := findValidator("substring", args...)
+		if maker == nil {
+			t.Errorf("Expected function for substring(%s) but got nothing", describeTestArgs(args))
+			continue
+		}
+
+		if _, err := maker(args).Calculate(ctx); err == nil {
+			t.Errorf("Expected error for substring(%s) but got nothing", describeTestArgs(args))
+		}
+	}
+
+	for _, tc := range []struct {
+		name string
+		args []Expression
+	}{
+		{"lower", []Expression{MakeIntegerValue(1)}},
+		{"trim", []Expression{MakeStringValue("a"), MakeStringValue("b")}},
+		{"has prefix", []Expression{MakeStringValue("a")}},
+		{"substring", []Expression{MakeStringValue("a"), MakeStringValue("b")}},
+		{"substring", []Expression{MakeStringValue("a"), MakeIntegerValue(0), MakeIntegerValue(1),
+			MakeIntegerValue(2)}},
+		{"split", []Expression{MakeListOfStringsDesignator("l"), MakeStringValue(",")}},
+		{"join", []Expression{MakeStringValue("a"), MakeStringValue(",")}},
+	} {
+		if findValidator(tc.name, tc.args...) != nil {
+			t.Errorf("Expected no function for %s(%s)", tc.name, describeTestArgs(tc.args))
+		}
+	}
+
+	if _, ok := TargetCompatibleExpressions["has prefix"][TypeString][TypeString]; !ok {
+		t.Errorf("Expected \"has prefix\" to be target compatible")
+	}
+
+	if _, ok := TargetCompatibleExpressions["has suffix"][TypeString][TypeString]; !ok {
+		t.Errorf("Expected \"has suffix\" to be target compatible")
+	}
+}
+
+func describeTestArgs(args []Expression) string {
+	s := make([]string, len(args))
+	for i, arg := range args {
+		s[i] = arg.GetResultType().String()
+	}
+
+	return strings.Join(s, ", ")
+}
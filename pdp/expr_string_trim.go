@@ -0,0 +1,53 @@
+package pdp
+
+import (
+	"fmt"
+	"strings"
+)
+
+type functionStringTrim struct {
+	e Expression
+}
+
+func makeFunctionStringTrim(e Expression) Expression {
+	return functionStringTrim{e: e}
+}
+
+func makeFunctionStringTrimAlt(args []Expression) Expression {
+	if len(args) != 1 {
+		panic(fmt.Errorf("function \"trim\" for String needs exactly one argument but got %d", len(args)))
+	}
+
+	return makeFunctionStringTrim(args[0])
+}
+
+func (f functionStringTrim) GetResultType() Type {
+	return TypeString
+}
+
+func (f functionStringTrim) describe() string {
+	return "trim"
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionStringTrim) WalkArguments(visit func(e Expression)) {
+	visit(f.e)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionStringTrim) Calculate(ctx *Context) (AttributeValue, error) {
+	s, err := ctx.calculateStringExpression(f.e)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "argument"), f.describe())
+	}
+
+	return MakeStringValue(strings.TrimSpace(s)), nil
+}
+
+func functionStringTrimValidator(args []Expression) functionMaker {
+	if len(args) != 1 || args[0].GetResultType() != TypeString {
+		return nil
+	}
+
+	return makeFunctionStringTrimAlt
+}
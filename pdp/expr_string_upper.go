@@ -0,0 +1,53 @@
+package pdp
+
+import (
+	"fmt"
+	"strings"
+)
+
+type functionStringUpper struct {
+	e Expression
+}
+
+func makeFunctionStringUpper(e Expression) Expression {
+	return functionStringUpper{e: e}
+}
+
+func makeFunctionStringUpperAlt(args []Expression) Expression {
+	if len(args) != 1 {
+		panic(fmt.Errorf("function \"upper\" for String needs exactly one argument but got %d", len(args)))
+	}
+
+	return makeFunctionStringUpper(args[0])
+}
+
+func (f functionStringUpper) GetResultType() Type {
+	return TypeString
+}
+
+func (f functionStringUpper) describe() string {
+	return "upper"
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionStringUpper) WalkArguments(visit func(e Expression)) {
+	visit(f.e)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionStringUpper) Calculate(ctx *Context) (AttributeValue, error) {
+	s, err := ctx.calculateStringExpression(f.e)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "argument"), f.describe())
+	}
+
+	return MakeStringValue(strings.ToUpper(s)), nil
+}
+
+func functionStringUpperValidator(args []Expression) functionMaker {
+	if len(args) != 1 || args[0].GetResultType() != TypeString {
+		return nil
+	}
+
+	return makeFunctionStringUpperAlt
+}
@@ -31,6 +31,12 @@ func (f functionSetOfStringsContains) describe() string {
 	return "contains"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionSetOfStringsContains) WalkArguments(visit func(e Expression)) {
+	visit(f.set)
+	visit(f.value)
+}
+
 // Calculate implements Expression interface and returns calculated value
 func (f functionSetOfStringsContains) Calculate(ctx *Context) (AttributeValue, error) {
 	set, err := ctx.calculateSetOfStringsExpression(f.set)
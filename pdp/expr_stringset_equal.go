@@ -32,6 +32,12 @@ func (f functionSetOfStringsEqual) describe() string {
 	return "equal"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionSetOfStringsEqual) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 // Calculate implements Expression interface and returns calculated value
 func (f functionSetOfStringsEqual) Calculate(ctx *Context) (AttributeValue, error) {
 	firstSet, err := ctx.calculateSetOfStringsExpression(f.first)
@@ -32,6 +32,12 @@ func (f functionSetOfStringsIntersect) describe() string {
 	return "intersect"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionSetOfStringsIntersect) WalkArguments(visit func(e Expression)) {
+	visit(f.first)
+	visit(f.second)
+}
+
 // Calculate implements Expression interface and returns calculated value
 func (f functionSetOfStringsIntersect) Calculate(ctx *Context) (AttributeValue, error) {
 	firstSet, err := ctx.calculateSetOfStringsExpression(f.first)
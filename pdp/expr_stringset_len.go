@@ -27,6 +27,11 @@ func (f functionSetOfStringsLen) describe() string {
 	return "len"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionSetOfStringsLen) WalkArguments(visit func(e Expression)) {
+	visit(f.e)
+}
+
 // Calculate implements Expression interface and returns calculated value
 func (f functionSetOfStringsLen) Calculate(ctx *Context) (AttributeValue, error) {
 	set, err := ctx.calculateSetOfStringsExpression(f.e)
@@ -0,0 +1,167 @@
+package pdp
+
+import "fmt"
+
+type functionSwitch struct {
+	value   Expression
+	cases   []Expression
+	results []Expression
+	def     Expression
+
+	strs map[string]int
+	ints map[int64]int
+	err  error
+}
+
+// makeFunctionSwitch creates switch expression. Arguments are value to
+// switch on followed by pairs of case value and result and default result
+// at the end.
+func makeFunctionSwitch(args []Expression) Expression {
+	if len(args) < 4 || len(args)%2 != 0 {
+		panic(fmt.Errorf("function \"switch\" needs value, at least one case with result and default "+
+			"but got %d arguments", len(args)))
+	}
+
+	n := len(args)/2 - 1
+	f := functionSwitch{
+		value:   args[0],
+		cases:   make([]Expression, n),
+		results: make([]Expression, n),
+		def:     args[len(args)-1],
+	}
+
+	switch f.value.GetResultType() {
+	case TypeString:
+		f.strs = make(map[string]int, n)
+
+	case TypeInteger:
+		f.ints = make(map[int64]int, n)
+	}
+
+	for i := 0; i < n; i++ {
+		f.cases[i] = args[2*i+1]
+		f.results[i] = args[2*i+2]
+
+		if f.err != nil {
+			continue
+		}
+
+		v, ok := f.cases[i].(AttributeValue)
+		if !ok {
+			f.err = newSwitchCaseValueError(i + 1)
+			continue
+		}
+
+		switch v.t {
+		case TypeString:
+			s, _ := v.str()
+			if _, ok := f.strs[s]; ok {
+				f.err = newSwitchDuplicateCaseError(s)
+				continue
+			}
+
+			f.strs[s] = i
+
+		case TypeInteger:
+			n, _ := v.integer()
+			if _, ok := f.ints[n]; ok {
+				f.err = newSwitchDuplicateCaseError(fmt.Sprintf("%d", n))
+				continue
+			}
+
+			f.ints[n] = i
+		}
+	}
+
+	return f
+}
+
+func (f functionSwitch) GetResultType() Type {
+	return f.def.GetResultType()
+}
+
+func (f functionSwitch) describe() string {
+	return "switch"
+}
+
+func (f functionSwitch) validate() error {
+	return f.err
+}
+
+// WalkArguments implements ExpressionWalker interface
+func (f functionSwitch) WalkArguments(visit func(e Expression)) {
+	visit(f.value)
+
+	for _, arg := range f.cases {
+		visit(arg)
+	}
+
+	for _, arg := range f.results {
+		visit(arg)
+	}
+
+	visit(f.def)
+}
+
+// Calculate implements Expression interface and returns calculated value
+func (f functionSwitch) Calculate(ctx *Context) (AttributeValue, error) {
+	v, err := f.value.Calculate(ctx)
+	if err != nil {
+		return UndefinedValue, bindError(bindError(err, "value"), f.describe())
+	}
+
+	i := -1
+	switch v.t {
+	case TypeString:
+		s, _ := v.str()
+		if n, ok := f.strs[s]; ok {
+			i = n
+		}
+
+	case TypeInteger:
+		s, _ := v.integer()
+		if n, ok := f.ints[s]; ok {
+			i = n
+		}
+	}
+
+	if i < 0 {
+		r, err := f.def.Calculate(ctx)
+		if err != nil {
+			return UndefinedValue, bindError(bindError(err, "default"), f.describe())
+		}
+
+		return r, nil
+	}
+
+	r, err := f.results[i].Calculate(ctx)
+	if err != nil {
+		return UndefinedValue, bindError(bindErrorf(err, "case %d", i+1), f.describe())
+	}
+
+	return r, nil
+}
+
+func functionSwitchValidator(args []Expression) functionMaker {
+	if len(args) < 4 || len(args)%2 != 0 {
+		return nil
+	}
+
+	t := args[0].GetResultType()
+	if t != TypeString && t != TypeInteger {
+		return nil
+	}
+
+	rt := args[len(args)-1].GetResultType()
+	if rt == TypeUndefined {
+		return nil
+	}
+
+	for i := 1; i < len(args)-1; i += 2 {
+		if args[i].GetResultType() != t || args[i+1].GetResultType() != rt {
+			return nil
+		}
+	}
+
+	return makeFunctionSwitch
+}
@@ -0,0 +1,76 @@
+package pdp
+
+import "testing"
+
+func TestFunctionIf(t *testing.T) {
+	f := makeFunctionIf(
+		makeFunctionStringEqual(MakeStringDesignator("s"), MakeStringValue("test")),
+		MakeIntegerValue(1),
+		MakeStringDesignator("missing"),
+	)
+	if f := functionIfValidator([]Expression{
+		MakeBooleanValue(true), MakeIntegerValue(1), MakeStringValue("test"),
+	}); f != nil {
+		t.Errorf("Expected no function for branches of different types but got %p", f)
+	}
+
+	ctx := &Context{a: map[string]interface{}{"s": MakeStringValue("test")}}
+	v, err := f.Calculate(ctx)
+	if err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	} else if n, err := v.integer(); err != nil || n != 1 {
+		t.Errorf("Expected 1 but got %s (%v)", v.describe(), err)
+	}
+
+	ctx = &Context{a: map[string]interface{}{"s": MakeStringValue("other")}}
+	if v, err := f.Calculate(ctx); err == nil {
+		t.Errorf("Expected error for else branch but got %s", v.describe())
+	}
+}
+
+func TestFunctionSwitch(t *testing.T) {
+	args := []Expression{
+		MakeIntegerDesignator("i"),
+		MakeIntegerValue(1), MakeStringValue("first"),
+		MakeIntegerValue(2), MakeStringValue("second"),
+		MakeStringValue("other"),
+	}
+
+	maker := functionSwitchValidator(args)
+	if maker == nil {
+		t.Fatalf("Expected switch function but got nothing")
+	}
+
+	f := maker(args)
+	if err := ValidateExpression(f); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	for i, e := range []string{"other", "first", "second", "other"} {
+		ctx := &Context{a: map[string]interface{}{"i": MakeIntegerValue(int64(i))}}
+		v, err := f.Calculate(ctx)
+		if err != nil {
+			t.Errorf("Expected %q for %d but got error %s", e, i, err)
+		} else if s, err := v.str(); err != nil || s != e {
+			t.Errorf("Expected %q for %d but got %s (%v)", e, i, v.describe(), err)
+		}
+	}
+
+	args[3] = MakeIntegerValue(1)
+	if err := ValidateExpression(makeFunctionSwitch(args)); err == nil {
+		t.Errorf("Expected error for duplicate case but got nothing")
+	} else if _, ok := err.(*switchDuplicateCaseError); !ok {
+		t.Errorf("Expected *switchDuplicateCaseError but got %T (%s)", err, err)
+	}
+
+	args[3] = MakeIntegerDesignator("j")
+	if err := ValidateExpression(makeFunctionSwitch(args)); err == nil {
+		t.Errorf("Expected error for case which isn't immediate value but got nothing")
+	} else if _, ok := err.(*switchCaseValueError); !ok {
+		t.Errorf("Expected *switchCaseValueError but got %T (%s)", err, err)
+	}
+
+	if f := functionSwitchValidator(args[:5]); f != nil {
+		t.Errorf("Expected no function for odd number of arguments but got %p", f)
+	}
+}
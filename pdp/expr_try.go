@@ -22,6 +22,13 @@ func (f functionTry) describe() string {
 	return "try"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionTry) WalkArguments(visit func(e Expression)) {
+	for _, arg := range f.args {
+		visit(arg)
+	}
+}
+
 func (f functionTry) Calculate(ctx *Context) (AttributeValue, error) {
 	var (
 		v   AttributeValue
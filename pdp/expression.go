@@ -8,9 +8,49 @@ type Expression interface {
 	Calculate(ctx *Context) (AttributeValue, error)
 }
 
+// ExpressionWalker is implemented by expressions which have nested
+// expressions. WalkArguments calls visit for each nested expression (function
+// arguments, selector path, default and error values and so on). Lint and
+// partial evaluation look into expressions only by means of the interface.
+type ExpressionWalker interface {
+	WalkArguments(visit func(e Expression))
+}
+
+// walkExpression calls f for given expression and then for all its nested
+// expressions recursively. It skips nil expressions.
+func walkExpression(e Expression, f func(e Expression)) {
+	if e == nil {
+		return
+	}
+
+	f(e)
+
+	if w, ok := e.(ExpressionWalker); ok {
+		w.WalkArguments(func(e Expression) {
+			walkExpression(e, f)
+		})
+	}
+}
+
 type functionMaker func(args []Expression) Expression
 type functionArgumentValidator func(args []Expression) functionMaker
 
+type validatedExpression interface {
+	validate() error
+}
+
+// ValidateExpression checks if expression made by function maker has valid
+// immediate arguments (for example, if pattern of "match" function is
+// a correct regular expression). Policy parsers call it for any function
+// expression they make to report such errors at loading time.
+func ValidateExpression(e Expression) error {
+	if v, ok := e.(validatedExpression); ok {
+		return v.validate()
+	}
+
+	return nil
+}
+
 // FunctionArgumentValidators maps function name to list of validators.
 // For given set of arguments validator returns nil if the function
 // doesn't accept the arguments or function which creates expression based
@@ -30,6 +70,8 @@ var FunctionArgumentValidators = map[string][]functionArgumentValidator{
 	"add": {
 		functionIntegerAddValidator,
 		functionFloatAddValidator,
+		functionDateTimeAddValidator,
+		functionDurationAddValidator,
 	},
 	"subtract": {
 		functionIntegerSubtractValidator,
@@ -75,4 +117,61 @@ var FunctionArgumentValidators = map[string][]functionArgumentValidator{
 	"try": {
 		functionTryValidator,
 	},
+	"if": {
+		functionIfValidator,
+	},
+	"switch": {
+		functionSwitchValidator,
+	},
+	"match": {
+		functionMatchValidator,
+	},
+	"glob": {
+		functionGlobValidator,
+	},
+	"lower": {
+		functionStringLowerValidator,
+	},
+	"upper": {
+		functionStringUpperValidator,
+	},
+	"trim": {
+		functionStringTrimValidator,
+	},
+	"has prefix": {
+		functionStringHasPrefixValidator,
+	},
+	"has suffix": {
+		functionStringHasSuffixValidator,
+	},
+	"substring": {
+		functionStringSubstringValidator,
+	},
+	"string length": {
+		functionStringLenValidator,
+	},
+	"split": {
+		functionStringSplitValidator,
+	},
+	"join": {
+		functionListOfStringsJoinValidator,
+	},
+	"now": {
+		functionDateTimeNowValidator,
+	},
+	"before": {
+		functionDateTimeBeforeValidator,
+	},
+	"after": {
+		functionDateTimeAfterValidator,
+	},
+	"time of day in range": {
+		functionDateTimeTimeOfDayInRangeValidator,
+	},
+	"day of week": {
+		functionDateTimeDayOfWeekValidator,
+	},
+	"in time zone": {
+		functionDateTimeInTimeZoneValidator,
+	},
 }
@@ -4,7 +4,6 @@ package jcon
 
 import (
 	"encoding/json"
-
 	"github.com/infobloxopen/themis/pdp"
 )
 
@@ -42,6 +41,8 @@ const (
 	missingCommandEntityErrorID           = 30
 	unknownContentUpdateOperationErrorID  = 31
 	arrayEndDelimiterErrorID              = 32
+	dateTimeCastErrorID                   = 33
+	durationCastErrorID                   = 34
 )
 
 type externalError struct {
@@ -544,3 +545,37 @@ func newArrayEndDelimiterError(actual json.Delim, expected, desc string) *arrayE
 func (e *arrayEndDelimiterError) Error() string {
 	return e.errorf("Expected %s JSON array end %q but got delimiter %q", e.desc, e.expected, e.actual)
 }
+
+type dateTimeCastError struct {
+	errorLink
+	s   string
+	err error
+}
+
+func newDateTimeCastError(s string, err error) *dateTimeCastError {
+	return &dateTimeCastError{
+		errorLink: errorLink{id: dateTimeCastErrorID},
+		s:         s,
+		err:       err}
+}
+
+func (e *dateTimeCastError) Error() string {
+	return e.errorf("Can't treat %q as date and time (%s)", e.s, e.err)
+}
+
+type durationCastError struct {
+	errorLink
+	s   string
+	err error
+}
+
+func newDurationCastError(s string, err error) *durationCastError {
+	return &durationCastError{
+		errorLink: errorLink{id: durationCastErrorID},
+		s:         s,
+		err:       err}
+}
+
+func (e *durationCastError) Error() string {
+	return e.errorf("Can't treat %q as duration (%s)", e.s, e.err)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/infobloxopen/go-trees/domain"
 	"github.com/infobloxopen/go-trees/domaintree"
@@ -24,9 +25,10 @@ type contentItem struct {
 	t   pdp.Type
 	tOk bool
 
-	v      interface{}
-	vOk    bool
-	vReady bool
+	v       interface{}
+	vOk     bool
+	vReady  bool
+	vOffset int64
 }
 
 func (c *contentItem) unmarshalTypeField(d *json.Decoder) error {
@@ -392,6 +394,32 @@ func (c *contentItem) unmarshalValue(d *json.Decoder) (interface{}, error) {
 		}
 
 		return lst, nil
+
+	case pdp.TypeDateTime:
+		s, err := jparser.GetString(d, "date and time value")
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, newDateTimeCastError(s, err)
+		}
+
+		return t, nil
+
+	case pdp.TypeDuration:
+		s, err := jparser.GetString(d, "duration value")
+		if err != nil {
+			return nil, err
+		}
+
+		x, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, newDurationCastError(s, err)
+		}
+
+		return x, nil
 	}
 
 	return nil, newInvalidContentItemTypeError(c.t)
@@ -519,6 +547,7 @@ func (c *contentItem) unmarshalDataField(d *json.Decoder) error {
 			c.v = v
 		}
 	} else {
+		c.vOffset = d.InputOffset()
 		v, err := jparser.GetUndefined(d, "content")
 		if err != nil {
 			return err
@@ -570,7 +599,7 @@ func (c *contentItem) get() (*pdp.ContentItem, error) {
 
 	v, err := c.postProcess(c.v, 0)
 	if err != nil {
-		return nil, err
+		return nil, locateError(err, c.vOffset)
 	}
 
 	if len(c.k) <= 0 {
@@ -33,9 +33,11 @@ type mapUnmarshaller interface {
 func newTypedMap(c *contentItem, keyIdx int) (mapUnmarshaller, error) {
 	t := c.k[keyIdx]
 
+	// Only the last level map holds flags values directly. Maps of other
+	// levels hold submaps.
 	switch t {
 	case pdp.TypeString:
-		if t, ok := c.t.(*pdp.FlagsType); ok {
+		if t, ok := c.t.(*pdp.FlagsType); ok && keyIdx == len(c.k)-1 {
 			switch t.Capacity() {
 			case 8:
 				return &string8Map{
@@ -63,7 +65,7 @@ func newTypedMap(c *contentItem, keyIdx int) (mapUnmarshaller, error) {
 			m:               strtree.NewTree()}, nil
 
 	case pdp.TypeAddress, pdp.TypeNetwork:
-		if t, ok := c.t.(*pdp.FlagsType); ok {
+		if t, ok := c.t.(*pdp.FlagsType); ok && keyIdx == len(c.k)-1 {
 			switch t.Capacity() {
 			case 8:
 				return &network8Map{
@@ -91,7 +93,7 @@ func newTypedMap(c *contentItem, keyIdx int) (mapUnmarshaller, error) {
 			m:               iptree.NewTree()}, nil
 
 	case pdp.TypeDomain:
-		if t, ok := c.t.(*pdp.FlagsType); ok {
+		if t, ok := c.t.(*pdp.FlagsType); ok && keyIdx == len(c.k)-1 {
 			switch t.Capacity() {
 			case 8:
 				return &domain8Map{
@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/infobloxopen/themis/jparser"
 	"github.com/infobloxopen/themis/pdp"
 )
 
@@ -17,9 +18,12 @@ func Unmarshal(r io.Reader, tag *uuid.UUID) (*pdp.LocalContent, error) {
 	c := &content{
 		symbols: pdp.MakeSymbols(),
 	}
-	err := c.unmarshal(json.NewDecoder(r))
+
+	lines := jparser.NewLineCounter(r)
+	d := json.NewDecoder(lines)
+	err := c.unmarshal(d)
 	if err != nil {
-		return nil, err
+		return nil, resolveError(err, d, lines)
 	}
 
 	return pdp.NewLocalContent(c.id, tag, c.symbols, c.items), nil
@@ -30,9 +34,12 @@ func Unmarshal(r io.Reader, tag *uuid.UUID) (*pdp.LocalContent, error) {
 // Value of newTag is set to the content when update is applied.
 func UnmarshalUpdate(r io.Reader, cID string, oldTag, newTag uuid.UUID, s pdp.Symbols) (*pdp.ContentUpdate, error) {
 	u := pdp.NewContentUpdate(cID, oldTag, newTag)
-	err := unmarshalCommands(json.NewDecoder(r), s, u)
+
+	lines := jparser.NewLineCounter(r)
+	d := json.NewDecoder(lines)
+	err := unmarshalCommands(d, s, u)
 	if err != nil {
-		return nil, err
+		return nil, resolveError(err, d, lines)
 	}
 
 	return u, nil
@@ -1,6 +1,8 @@
 package jcon
 
 import (
+	"bytes"
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -2149,3 +2151,266 @@ func makeTestDN(t *testing.T, s string) domain.Name {
 
 	return d
 }
+
+func TestUnmarshalDateTimeAndDuration(t *testing.T) {
+	c, err := Unmarshal(strings.NewReader(`{
+	"ID": "Test",
+	"Items": {
+		"expires": {
+			"type": "datetime",
+			"keys": ["string"],
+			"data": {
+				"key": "2019-01-04T17:30:00+03:00"
+			}
+		},
+		"ttl": {
+			"data": {
+				"key": "1h30m"
+			},
+			"type": "duration",
+			"keys": ["string"]
+		}
+	}
+}`), nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got (%T):\n\t%s", err, err)
+	}
+
+	path := []pdp.Expression{pdp.MakeStringValue("key")}
+	for id, e := range map[string]string{
+		"expires": "2019-01-04T17:30:00+03:00",
+		"ttl":     "1h30m0s",
+	} {
+		lc, err := c.Get(id)
+		if err != nil {
+			t.Errorf("Expected no error for %q but got (%T):\n\t%s", id, err, err)
+			continue
+		}
+
+		r, err := lc.Get(path, nil)
+		if err != nil {
+			t.Errorf("Expected no error for %q but got (%T):\n\t%s", id, err, err)
+			continue
+		}
+
+		s, err := r.Serialize()
+		if err != nil {
+			t.Errorf("Expected no error for %q but got (%T):\n\t%s", id, err, err)
+		} else if s != e {
+			t.Errorf("Expected %q for %q but got %q", e, id, s)
+		}
+	}
+
+	_, err = Unmarshal(strings.NewReader(`{
+	"ID": "Test",
+	"Items": {
+		"ttl": {
+			"type": "duration",
+			"keys": ["string"],
+			"data": {
+				"key": "90 minutes"
+			}
+		}
+	}
+}`), nil)
+	if err == nil {
+		t.Errorf("Expected error for invalid duration but got nothing")
+	} else if !strings.Contains(err.Error(), "duration") {
+		t.Errorf("Expected duration cast error but got (%T):\n\t%s", err, err)
+	}
+}
+
+func TestUnmarshalDiffContentUpdate(t *testing.T) {
+	oldStream := `{
+	"ID": "Test",
+	"Items": {
+		"tags": {
+			"type": {"meta": "flags", "name": "tags", "flags": ["red", "green", "blue"]},
+			"keys": ["string", "network"],
+			"data": {
+				"first": {"192.0.2.0/24": ["red"], "2001:db8::1": ["green"]},
+				"second": {"192.0.2.0/24": ["blue"]}
+			}
+		},
+		"values": {
+			"type": "set of networks",
+			"keys": ["domain"],
+			"data": {
+				"example.com": ["192.0.2.0/24", "2001:db8::/32"],
+				"example.net": ["192.0.2.1"]
+			}
+		},
+		"expires": {"type": "datetime", "data": "2019-01-04T17:30:00+03:00"},
+		"names": {"type": "list of strings", "data": ["first", "second"]}
+	}
+}`
+
+	newStream := `{
+	"ID": "Test",
+	"Items": {
+		"tags": {
+			"type": {"meta": "flags", "name": "tags", "flags": ["red", "green", "blue"]},
+			"keys": ["string", "network"],
+			"data": {
+				"first": {"192.0.2.0/24": ["red", "blue"]},
+				"third": {"198.51.100.0/24": []}
+			}
+		},
+		"values": {
+			"type": "set of networks",
+			"keys": ["domain"],
+			"data": {
+				"example.com": ["192.0.2.0/24", "2001:db8::/32"],
+				"example.org": ["198.51.100.1"]
+			}
+		},
+		"expires": {"type": "datetime", "data": "2020-01-04T17:30:00+03:00"},
+		"names": {"type": "list of strings", "data": ["first", "second"]},
+		"domains": {"type": "set of domains", "data": ["example.com"]}
+	}
+}`
+
+	tag := uuid.New()
+	old, err := Unmarshal(strings.NewReader(oldStream), &tag)
+	if err != nil {
+		t.Fatalf("Expected no error but got (%T):\n\t%s", err, err)
+	}
+
+	new, err := Unmarshal(strings.NewReader(newStream), nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got (%T):\n\t%s", err, err)
+	}
+
+	newTag := uuid.New()
+	u, err := pdp.DiffContent(old, new, tag, newTag)
+	if err != nil {
+		t.Fatalf("Expected no error but got (%T):\n\t%s", err, err)
+	}
+
+	b, err := json.Marshal(u)
+	if err != nil {
+		t.Fatalf("Expected no error but got (%T):\n\t%s", err, err)
+	}
+
+	s := pdp.NewLocalContentStorage([]*pdp.LocalContent{old})
+	tr, err := s.NewTransaction("Test", &tag)
+	if err != nil {
+		t.Fatalf("Expected no error but got (%T):\n\t%s", err, err)
+	}
+
+	u, err = UnmarshalUpdate(bytes.NewReader(b), "Test", tag, newTag, tr.Symbols())
+	if err != nil {
+		t.Fatalf("Expected no error for update:\n%s\nbut got (%T):\n\t%s", b, err, err)
+	}
+
+	if err = tr.Apply(u); err != nil {
+		t.Fatalf("Expected no error for update:\n%s\nbut got (%T):\n\t%s", b, err, err)
+	}
+
+	s, err = tr.Commit(s)
+	if err != nil {
+		t.Fatalf("Expected no error but got (%T):\n\t%s", err, err)
+	}
+
+	c, err := s.GetLocalContent("Test", &newTag)
+	if err != nil {
+		t.Fatalf("Expected no error but got (%T):\n\t%s", err, err)
+	}
+
+	u, err = pdp.DiffContent(c, new, newTag, newTag)
+	if err != nil {
+		t.Fatalf("Expected no error but got (%T):\n\t%s", err, err)
+	}
+
+	if b, err := json.Marshal(u); err != nil {
+		t.Errorf("Expected no error but got (%T):\n\t%s", err, err)
+	} else if string(b) != "[]" {
+		t.Errorf("Expected no difference between updated and new content but got:\n%s", b)
+	}
+}
+
+func TestUnmarshalErrorPositions(t *testing.T) {
+	for _, tc := range []struct {
+		desc    string
+		content string
+		pos     pdp.SourcePosition
+	}{
+		{
+			desc: "streamed value",
+			content: `{
+  "id": "test",
+  "items": {
+    "first": {
+      "keys": ["network"],
+      "type": "string",
+      "data": {
+        "192.0.2.0/24": 1
+      }
+    }
+  }
+}`,
+			pos: pdp.SourcePosition{Line: 8, Column: 26},
+		},
+		{
+			desc: "postprocessed key",
+			content: `{
+  "id": "test",
+  "items": {
+    "first": {
+      "data": {
+        "192.0.2.0/24": "x",
+        "bad-net": "y"
+      },
+      "keys": ["network"],
+      "type": "string"
+    }
+  }
+}`,
+			pos: pdp.SourcePosition{Line: 7, Column: 18},
+		},
+		{
+			desc: "postprocessed value",
+			content: `{
+  "id": "test",
+  "items": {
+    "first": {
+      "data": {
+        "192.0.2.0/24": {
+          "example.com": 1
+        }
+      },
+      "keys": ["network", "domain"],
+      "type": "string"
+    }
+  }
+}`,
+			pos: pdp.SourcePosition{Line: 7, Column: 24},
+		},
+		{
+			desc: "postprocessed item value",
+			content: `{
+  "id": "test",
+  "items": {
+    "first": {
+      "data": ["192.0.2.0/24", "bad-net"],
+      "type": "set of networks"
+    }
+  }
+}`,
+			pos: pdp.SourcePosition{Line: 5, Column: 13},
+		},
+	} {
+		_, err := Unmarshal(strings.NewReader(tc.content), nil)
+		if err == nil {
+			t.Errorf("Expected error for %s but got nothing", tc.desc)
+			continue
+		}
+
+		pos, ok := pdp.GetErrorPosition(err)
+		if !ok {
+			t.Errorf("Expected position of error for %s but got nothing (%s)", tc.desc, err)
+		} else if pos != tc.pos {
+			t.Errorf("Expected error for %s at %s but got %s (%s)", tc.desc, tc.pos, pos, err)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package jcon
 import (
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/infobloxopen/go-trees/domain"
 	"github.com/infobloxopen/go-trees/domaintree"
@@ -26,7 +27,7 @@ func (c *contentItem) ppMap(v interface{}, keyIdx int) (interface{}, error) {
 	for _, p := range pairs {
 		err = m.postProcess(p)
 		if err != nil {
-			return nil, err
+			return nil, locateError(err, p.Offset)
 		}
 	}
 
@@ -37,7 +38,7 @@ func ppStringSequenceFromPairs(v []jparser.Pair, desc string, f func(s string) e
 	for i, p := range v {
 		err := f(p.K)
 		if err != nil {
-			return bindErrorf(err, "%d", i+1)
+			return bindErrorf(locateError(err, p.Offset), "%d", i+1)
 		}
 	}
 
@@ -253,6 +254,32 @@ func (c *contentItem) ppValue(v interface{}) (interface{}, error) {
 		}
 
 		return lst, nil
+
+	case pdp.TypeDateTime:
+		s, ok := v.(string)
+		if !ok {
+			return nil, newStringCastError(v, "date and time value")
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, newDateTimeCastError(s, err)
+		}
+
+		return t, nil
+
+	case pdp.TypeDuration:
+		s, ok := v.(string)
+		if !ok {
+			return nil, newStringCastError(v, "duration value")
+		}
+
+		x, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, newDurationCastError(s, err)
+		}
+
+		return x, nil
 	}
 
 	return nil, newInvalidContentItemTypeError(c.t)
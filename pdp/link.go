@@ -0,0 +1,247 @@
+package pdp
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// PolicyDocument is a policy storage parsed from single policy document
+// along with name of the document (usually file name). Storages of linked
+// documents should share type and attribute symbol tables (see
+// MakeDocumentSymbols) so each document can use types and attributes
+// declared in others.
+type PolicyDocument struct {
+	Name    string
+	Storage *PolicyStorage
+}
+
+// LinkPolicies links root policy sets and policies of given documents into
+// single policy storage with given tag. Each reference made by
+// NewPolicyReference is replaced with root policy of document which has
+// the same id. Exactly one of documents with policies should be referred
+// by no other (it becomes the root of resulting storage) and any other
+// document with policies should be referred exactly once. Variables defined
+// in documents become visible for updates at paths where the documents are
+// linked. Documents with no policies provide only declarations.
+func LinkPolicies(docs []PolicyDocument, tag *uuid.UUID) (*PolicyStorage, error) {
+	if len(docs) <= 0 {
+		return nil, newNoPolicyDocumentsError()
+	}
+
+	l := &policyLinker{
+		docs: make(map[string]*linkedPolicyDocument),
+		busy: make(map[string]bool),
+	}
+
+	var (
+		s      Symbols
+		hidden []*linkedPolicyDocument
+	)
+
+	refs := make(map[string]bool)
+	for _, d := range docs {
+		if d.Storage == nil {
+			continue
+		}
+
+		if s.types == nil {
+			s = d.Storage.symbols
+		}
+
+		if d.Storage.policies == nil {
+			continue
+		}
+
+		if _, ok := d.Storage.policies.(*policyReference); ok {
+			return nil, bindError(newRootPolicyReferenceError(), d.Name)
+		}
+
+		ld := &linkedPolicyDocument{
+			name: d.Name,
+			p:    d.Storage.policies,
+			vars: d.Storage.symbols.vars,
+		}
+		if ld.vars == nil {
+			ld.vars = newVariableScope()
+		}
+
+		collectPolicyReferences(d.Storage.policies, refs)
+
+		ID, ok := d.Storage.policies.GetID()
+		if !ok {
+			hidden = append(hidden, ld)
+			continue
+		}
+
+		if prev, ok := l.docs[ID]; ok {
+			return nil, bindError(newDuplicatePolicyDocumentIDError(ID, prev.name), d.Name)
+		}
+
+		l.docs[ID] = ld
+	}
+
+	roots := []string{}
+	for _, d := range hidden {
+		roots = append(roots, d.name)
+		l.root = d
+	}
+
+	for ID, d := range l.docs {
+		if !refs[ID] {
+			roots = append(roots, d.name)
+			l.root = d
+		}
+	}
+
+	if len(roots) != 1 {
+		sort.Strings(roots)
+		return nil, newPolicyDocumentRootError(roots)
+	}
+
+	root := l.root
+	root.used = true
+	if ID, ok := root.p.GetID(); ok {
+		l.busy[ID] = true
+	}
+
+	p, err := l.link(root, root.p, nil, root.vars)
+	if err != nil {
+		return nil, bindError(err, root.name)
+	}
+
+	for ID, d := range l.docs {
+		if !d.used {
+			// Documents which are referred only by themselves or by
+			// each other.
+			return nil, bindError(newUnlinkedPolicyDocumentError(ID), d.name)
+		}
+	}
+
+	s.vars = root.vars
+	s.ro = false
+
+	return NewPolicyStorage(p, s, tag), nil
+}
+
+type linkedPolicyDocument struct {
+	name string
+	p    Evaluable
+	vars *variableScope
+	used bool
+}
+
+type policyLinker struct {
+	root *linkedPolicyDocument
+	docs map[string]*linkedPolicyDocument
+	busy map[string]bool
+}
+
+// link returns copy of given policy set or policy with references replaced
+// by root policies of referred documents. Path is a list of IDs from root
+// of resulting storage to parent of the policy and scope is variable scope
+// at the path.
+func (l *policyLinker) link(d *linkedPolicyDocument, e Evaluable, path []string, scope *variableScope) (Evaluable, error) {
+	p, ok := e.(*PolicySet)
+	if !ok {
+		return e, nil
+	}
+
+	if !p.hidden {
+		path = append(path[:len(path):len(path)], p.id)
+		scope = scope.getChild(p.id)
+	}
+
+	var policies []Evaluable
+	algorithm := p.algorithm
+	for i, child := range p.policies {
+		linked, err := l.linkChild(d, child, path, scope)
+		if err != nil {
+			return nil, bindError(err, p.describe())
+		}
+
+		if linked == child {
+			continue
+		}
+
+		if policies == nil {
+			policies = make([]Evaluable, len(p.policies))
+			copy(policies, p.policies)
+		}
+
+		linked.setOrder(child.getOrder())
+		policies[i] = linked
+
+		if ID, ok := child.GetID(); ok {
+			switch a := algorithm.(type) {
+			case mapperPCA:
+				algorithm = a.add(ID, linked, child)
+
+			case flagsMapperPCA:
+				algorithm = a.add(ID, linked, child)
+			}
+		}
+	}
+
+	if policies == nil {
+		return p, nil
+	}
+
+	return &PolicySet{
+		ord:         p.ord,
+		id:          p.id,
+		hidden:      p.hidden,
+		target:      p.target,
+		policies:    policies,
+		obligations: p.obligations,
+		algorithm:   algorithm,
+		index:       makePolicySetTargetIndex(policies, algorithm),
+	}, nil
+}
+
+func (l *policyLinker) linkChild(d *linkedPolicyDocument, e Evaluable, path []string, scope *variableScope) (Evaluable, error) {
+	r, ok := e.(*policyReference)
+	if !ok {
+		return l.link(d, e, path, scope)
+	}
+
+	ref, ok := l.docs[r.id]
+	if !ok {
+		return nil, newUnknownPolicyReferenceError(r.id)
+	}
+
+	if l.busy[r.id] {
+		return nil, newPolicyReferenceCycleError(r.id, ref.name)
+	}
+
+	if ref.used {
+		return nil, newDuplicatePolicyReferenceError(r.id, ref.name)
+	}
+
+	ref.used = true
+	l.busy[r.id] = true
+	defer delete(l.busy, r.id)
+
+	scope.graft(r.id, ref.vars)
+
+	p, err := l.link(ref, ref.p, path, scope)
+	if err != nil {
+		return nil, bindError(err, ref.name)
+	}
+
+	return p, nil
+}
+
+// collectPolicyReferences puts IDs of all policies referred by given policy
+// set to refs.
+func collectPolicyReferences(e Evaluable, refs map[string]bool) {
+	switch e := e.(type) {
+	case *PolicySet:
+		for _, child := range e.policies {
+			collectPolicyReferences(child, refs)
+		}
+
+	case *policyReference:
+		refs[e.id] = true
+	}
+}
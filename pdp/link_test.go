@@ -0,0 +1,132 @@
+package pdp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestLinkPolicies(t *testing.T) {
+	shared := MakeSymbols()
+
+	root := makeLinkTestDocument("root.yaml", shared, NewPolicySet("root", false, MakeTarget(), []Evaluable{
+		NewPolicyReference("admin"),
+		NewPolicyReference("users"),
+	}, makeFirstApplicableEffectPCA, nil, nil))
+
+	admin := makeLinkTestDocument("admin.yaml", shared, makeTargetIndexTestPolicy("admin", EffectPermit,
+		makeSimpleStringTarget("role", "admin")))
+	if err := admin.Storage.symbols.PutVariable(nil, NewVariable("v", MakeStringValue("admin"))); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	users := makeLinkTestDocument("users.yaml", shared, NewPolicySet("users", false, MakeTarget(), []Evaluable{
+		NewPolicyReference("user"),
+	}, makeFirstApplicableEffectPCA, nil, nil))
+
+	user := makeLinkTestDocument("user.yaml", shared, makeTargetIndexTestPolicy("user", EffectDeny,
+		makeSimpleStringTarget("role", "user")))
+
+	decls := PolicyDocument{Name: "types.yaml", Storage: NewPolicyStorage(nil, MakeDocumentSymbols(shared), nil)}
+
+	tag := uuid.New()
+	s, err := LinkPolicies([]PolicyDocument{decls, user, admin, users, root}, &tag)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if err := s.CheckTag(&tag); err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	}
+
+	for role, effect := range map[string]int{
+		"admin": EffectPermit,
+		"user":  EffectDeny,
+		"guest": EffectNotApplicable,
+	} {
+		r := s.Root().Calculate(&Context{a: map[string]interface{}{"role": MakeStringValue(role)}})
+		if r.Effect != effect {
+			t.Errorf("Expected %q for %q but got %q (%v)", effectNames[effect], role, effectNames[r.Effect], r.Status)
+		}
+	}
+
+	if v, ok := s.symbols.GetVariable([]string{"root", "admin"}, "v"); !ok {
+		t.Errorf("Expected variable %q at %q but got nothing", "v", "root/admin")
+	} else if v.GetID() != "v" {
+		t.Errorf("Expected variable %q but got %q", "v", v.GetID())
+	}
+
+	if _, ok := s.symbols.GetVariable([]string{"root", "users"}, "v"); ok {
+		t.Errorf("Expected no variable %q at %q", "v", "root/users")
+	}
+
+	if r := root.Storage.Root().Calculate(&Context{}); r.Effect != EffectIndeterminate {
+		t.Errorf("Expected original root to keep references but got %q", effectNames[r.Effect])
+	}
+}
+
+func TestLinkPoliciesErrors(t *testing.T) {
+	shared := MakeSymbols()
+	makeRefs := func(name, ID string, refs ...string) PolicyDocument {
+		policies := make([]Evaluable, len(refs))
+		for i, ref := range refs {
+			policies[i] = NewPolicyReference(ref)
+		}
+
+		return makeLinkTestDocument(name, shared,
+			NewPolicySet(ID, false, MakeTarget(), policies, makeFirstApplicableEffectPCA, nil, nil))
+	}
+
+	leaf := makeLinkTestDocument("leaf.yaml", shared, makeTargetIndexTestPolicy("leaf", EffectPermit, MakeTarget()))
+
+	assertLinkPoliciesError(t, "no documents", nil, "at least one policy document")
+	assertLinkPoliciesError(t, "unknown reference", []PolicyDocument{
+		makeRefs("root.yaml", "root", "unknown"),
+	}, "unknown policy")
+	assertLinkPoliciesError(t, "two roots", []PolicyDocument{
+		makeRefs("root.yaml", "root", "leaf"),
+		makeRefs("other.yaml", "other"),
+		leaf,
+	}, "exactly one")
+	assertLinkPoliciesError(t, "duplicate id", []PolicyDocument{
+		makeRefs("root.yaml", "root", "leaf"),
+		leaf,
+		makeLinkTestDocument("copy.yaml", shared, makeTargetIndexTestPolicy("leaf", EffectDeny, MakeTarget())),
+	}, "already defined")
+	assertLinkPoliciesError(t, "duplicate reference", []PolicyDocument{
+		makeRefs("root.yaml", "root", "leaf", "leaf"),
+		leaf,
+	}, "already referred")
+	assertLinkPoliciesError(t, "cycle", []PolicyDocument{
+		makeRefs("root.yaml", "root", "first"),
+		makeRefs("first.yaml", "first", "second"),
+		makeRefs("second.yaml", "second", "first"),
+	}, "makes a cycle")
+	assertLinkPoliciesError(t, "unlinked", []PolicyDocument{
+		makeRefs("root.yaml", "root", "leaf"),
+		makeRefs("first.yaml", "first", "second"),
+		makeRefs("second.yaml", "second", "first"),
+		leaf,
+	}, "isn't reachable")
+	assertLinkPoliciesError(t, "root reference", []PolicyDocument{
+		makeLinkTestDocument("ref.yaml", shared, NewPolicyReference("leaf")),
+		leaf,
+	}, "can't be a policy reference")
+}
+
+func makeLinkTestDocument(name string, s Symbols, p Evaluable) PolicyDocument {
+	return PolicyDocument{
+		Name:    name,
+		Storage: NewPolicyStorage(p, MakeDocumentSymbols(s), nil),
+	}
+}
+
+func assertLinkPoliciesError(t *testing.T, desc string, docs []PolicyDocument, substr string) {
+	s, err := LinkPolicies(docs, nil)
+	if err == nil {
+		t.Errorf("Expected error for %s but got storage %#v", desc, s)
+	} else if !strings.Contains(err.Error(), substr) {
+		t.Errorf("Expected error for %s containing %q but got %q", desc, substr, err)
+	}
+}
@@ -0,0 +1,477 @@
+package pdp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/infobloxopen/go-trees/strtree"
+)
+
+// Lint* constants define kinds of problems reported by Lint.
+const (
+	// LintShadowedRule marks rule which is never evaluated because
+	// an earlier rule of FirstApplicableEffect policy always applies.
+	LintShadowedRule = "shadowed-rule"
+	// LintUnreachableMapperChild marks child of mapper policy or policy set
+	// which id can never be selected by mapper argument.
+	LintUnreachableMapperChild = "unreachable-mapper-child"
+	// LintUnusedAttribute marks declared attribute which isn't used by any
+	// policy set, policy or rule.
+	LintUnusedAttribute = "unused-attribute"
+	// LintMissingContent marks selector which points to content missing
+	// in given content storage.
+	LintMissingContent = "missing-content"
+	// LintMissingContentItem marks selector which points to content item
+	// missing in given content storage.
+	LintMissingContentItem = "missing-content-item"
+	// LintConflictingObligation marks obligation attribute which is emitted
+	// with different types.
+	LintConflictingObligation = "conflicting-obligation"
+	// LintParseError marks policy document which can't be parsed. Lint
+	// doesn't report it by itself but linter applications do.
+	LintParseError = "parse-error"
+)
+
+// LintIssue represents a problem found by Lint. Path contains ids of policy
+// sets, policies and rules (in the form accepted by GetAtPath) leading to
+// the problem. Hidden entity appears in the path as "#" followed by its
+// position in parent. Path is empty for problems which don't belong
+// to particular entity. Position points to the problem in source document
+// if it's known.
+type LintIssue struct {
+	Kind     string          `json:"kind"`
+	Path     []string        `json:"path,omitempty"`
+	Position *SourcePosition `json:"position,omitempty"`
+	Message  string          `json:"message"`
+}
+
+// String implements Stringer interface.
+func (i LintIssue) String() string {
+	if len(i.Path) <= 0 {
+		return fmt.Sprintf("%s (%s)", i.Message, i.Kind)
+	}
+
+	return fmt.Sprintf("%s: %s (%s)", strings.Join(i.Path, "/"), i.Message, i.Kind)
+}
+
+// LocalContentReader is implemented by selector expressions which get values
+// from local content storage. GetContentReference returns id of content and
+// id of content item the selector reads.
+type LocalContentReader interface {
+	GetContentReference() (string, string)
+}
+
+// Lint checks policies for problems which don't prevent them from loading but
+// most likely are mistakes: rules shadowed by an earlier unconditional rule
+// of FirstApplicableEffect policy, mapper children which can never be
+// selected, declared but unused attributes and obligations emitted with
+// conflicting types. If content storage is provided Lint also checks that all
+// local selectors point to existing content and content items (and uses
+// the content to find values mapper arguments can take).
+func (s *PolicyStorage) Lint(c *LocalContentStorage) []LintIssue {
+	l := &linter{
+		c:     c,
+		used:  make(map[string]struct{}),
+		oblig: make(map[string]map[string][]string),
+	}
+
+	if s.policies != nil {
+		l.lintEvaluable(s.policies, nil, 0)
+	}
+
+	attrs := make([]string, 0, len(s.symbols.attrs))
+	for ID := range s.symbols.attrs {
+		if _, ok := l.used[ID]; !ok {
+			attrs = append(attrs, ID)
+		}
+	}
+	sort.Strings(attrs)
+
+	for _, ID := range attrs {
+		l.report(LintUnusedAttribute, nil, "attribute %q is declared but never used", ID)
+	}
+
+	obligs := make([]string, 0, len(l.oblig))
+	for ID, types := range l.oblig {
+		if len(types) > 1 {
+			obligs = append(obligs, ID)
+		}
+	}
+	sort.Strings(obligs)
+
+	for _, ID := range obligs {
+		types := l.oblig[ID]
+		keys := make([]string, 0, len(types))
+		for t := range types {
+			keys = append(keys, t)
+		}
+		sort.Strings(keys)
+
+		desc := make([]string, len(keys))
+		for i, t := range keys {
+			desc[i] = fmt.Sprintf("%q at %s", t, strings.Join(types[t], ", "))
+		}
+
+		l.report(LintConflictingObligation, nil, "obligation %q is emitted with different types: %s",
+			ID, strings.Join(desc, "; "))
+	}
+
+	return l.issues
+}
+
+type linter struct {
+	c      *LocalContentStorage
+	issues []LintIssue
+	used   map[string]struct{}
+	oblig  map[string]map[string][]string
+}
+
+func (l *linter) report(kind string, path []string, format string, args ...interface{}) {
+	var p []string
+	if len(path) > 0 {
+		p = make([]string, len(path))
+		copy(p, path)
+	}
+
+	l.issues = append(l.issues, LintIssue{
+		Kind:    kind,
+		Path:    p,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func lintPathItem(ID string, hidden bool, i int) string {
+	if hidden {
+		return fmt.Sprintf("#%d", i)
+	}
+
+	return ID
+}
+
+func (l *linter) lintEvaluable(e Evaluable, parent []string, i int) {
+	switch e := e.(type) {
+	case *PolicySet:
+		path := append(parent, lintPathItem(e.id, e.hidden, i))
+		l.lintTarget(path, e.target)
+		l.lintAlgorithm(path, e.algorithm)
+		l.lintObligations(path, e.obligations)
+		l.lintPolicyMapper(path, e)
+
+		for i, p := range e.policies {
+			l.lintEvaluable(p, path, i)
+		}
+
+	case *Policy:
+		path := append(parent, lintPathItem(e.id, e.hidden, i))
+		l.lintTarget(path, e.target)
+		l.lintAlgorithm(path, e.algorithm)
+		l.lintObligations(path, e.obligations)
+		l.lintRuleMapper(path, e)
+		l.lintShadowedRules(path, e)
+
+		for i, r := range e.rules {
+			rPath := append(path, lintPathItem(r.id, r.hidden, i))
+			l.lintTarget(rPath, r.target)
+			l.lintExpression(rPath, r.condition)
+			l.lintObligations(rPath, r.obligations)
+		}
+	}
+}
+
+func (l *linter) lintObligations(path []string, obligations []AttributeAssignment) {
+	for _, o := range obligations {
+		l.used[o.a.id] = struct{}{}
+
+		types, ok := l.oblig[o.a.id]
+		if !ok {
+			types = make(map[string][]string)
+			l.oblig[o.a.id] = types
+		}
+
+		t := o.a.t.String()
+		types[t] = append(types[t], lintDescribePath(path))
+	}
+
+	for _, o := range obligations {
+		l.lintExpression(path, o.e)
+	}
+}
+
+func lintDescribePath(path []string) string {
+	if len(path) <= 0 {
+		return "root"
+	}
+
+	return strings.Join(path, "/")
+}
+
+func (l *linter) lintTarget(path []string, t Target) {
+	for _, any := range t.a {
+		for _, all := range any.a {
+			for _, m := range all.m {
+				l.lintExpression(path, m.m)
+			}
+		}
+	}
+}
+
+// lintAlgorithm checks argument of mapper combining algorithms. Custom
+// algorithms are checked if they implement ExpressionWalker interface.
+func (l *linter) lintAlgorithm(path []string, a interface{}) {
+	switch a := a.(type) {
+	case mapperRCA:
+		l.lintExpression(path, a.argument)
+		l.lintAlgorithm(path, a.algorithm)
+
+	case flagsMapperRCA:
+		l.lintExpression(path, a.argument)
+		l.lintAlgorithm(path, a.algorithm)
+
+	case mapperPCA:
+		l.lintExpression(path, a.argument)
+		l.lintAlgorithm(path, a.algorithm)
+
+	case flagsMapperPCA:
+		l.lintExpression(path, a.argument)
+		l.lintAlgorithm(path, a.algorithm)
+
+	case customRCA:
+		l.lintAlgorithm(path, a.CustomRuleCombiningAlg)
+
+	case customPCA:
+		l.lintAlgorithm(path, a.CustomPolicyCombiningAlg)
+
+	case ExpressionWalker:
+		a.WalkArguments(func(e Expression) {
+			l.lintExpression(path, e)
+		})
+	}
+}
+
+func (l *linter) lintExpression(path []string, e Expression) {
+	walkExpression(e, func(e Expression) {
+		switch e := e.(type) {
+		case AttributeDesignator:
+			l.used[e.GetID()] = struct{}{}
+
+		case LocalContentReader:
+			l.lintContentReference(path, e)
+		}
+	})
+}
+
+func (l *linter) lintContentReference(path []string, r LocalContentReader) {
+	if l.c == nil {
+		return
+	}
+
+	cID, iID := r.GetContentReference()
+	v, ok := l.c.r.Get(cID)
+	if !ok {
+		l.report(LintMissingContent, path, "selector points to missing content %q", cID)
+		return
+	}
+
+	if c, ok := v.(*LocalContent); ok {
+		if _, err := c.Get(iID); err != nil {
+			l.report(LintMissingContentItem, path, "selector points to missing item %q of content %q", iID, cID)
+		}
+	}
+}
+
+func (l *linter) lintShadowedRules(path []string, p *Policy) {
+	if _, ok := p.algorithm.(firstApplicableEffectRCA); !ok {
+		return
+	}
+
+	for i, r := range p.rules {
+		if len(r.target.a) > 0 || r.condition != nil {
+			continue
+		}
+
+		by := lintPathItem(r.id, r.hidden, i)
+		for j := i + 1; j < len(p.rules); j++ {
+			s := p.rules[j]
+			l.report(LintShadowedRule, append(path, lintPathItem(s.id, s.hidden, j)),
+				"rule is never evaluated because rule %q above has no target and condition", by)
+		}
+
+		return
+	}
+}
+
+func (l *linter) lintRuleMapper(path []string, p *Policy) {
+	var (
+		def, err *Rule
+		keys     map[string]struct{}
+		known    bool
+	)
+
+	switch a := p.algorithm.(type) {
+	default:
+		return
+
+	case mapperRCA:
+		def, err = a.def, a.err
+		keys, known = l.getMapperKeys(a.argument)
+
+	case flagsMapperRCA:
+		def, err = a.def, a.err
+		keys, known = make(map[string]struct{}, len(a.rules)), true
+		for _, r := range a.rules {
+			if r != nil {
+				keys[r.id] = struct{}{}
+			}
+		}
+	}
+
+	for i, r := range p.rules {
+		if def == r || err == r {
+			continue
+		}
+
+		if r.hidden {
+			l.report(LintUnreachableMapperChild, append(path, lintPathItem(r.id, r.hidden, i)),
+				"hidden rule can't be selected by mapper")
+			continue
+		}
+
+		if _, ok := keys[r.id]; known && !ok {
+			l.report(LintUnreachableMapperChild, append(path, r.id),
+				"rule can't be selected by mapper argument")
+		}
+	}
+}
+
+func (l *linter) lintPolicyMapper(path []string, p *PolicySet) {
+	var (
+		def, err Evaluable
+		keys     map[string]struct{}
+		known    bool
+	)
+
+	switch a := p.algorithm.(type) {
+	default:
+		return
+
+	case mapperPCA:
+		def, err = a.def, a.err
+		keys, known = l.getMapperKeys(a.argument)
+
+	case flagsMapperPCA:
+		def, err = a.def, a.err
+		keys, known = make(map[string]struct{}, len(a.policies)), true
+		for _, e := range a.policies {
+			if e != nil {
+				if ID, ok := e.GetID(); ok {
+					keys[ID] = struct{}{}
+				}
+			}
+		}
+	}
+
+	for i, e := range p.policies {
+		if def == e || err == e {
+			continue
+		}
+
+		ID, ok := e.GetID()
+		if !ok {
+			l.report(LintUnreachableMapperChild, append(path, fmt.Sprintf("#%d", i)),
+				"hidden policy can't be selected by mapper")
+			continue
+		}
+
+		if _, ok := keys[ID]; known && !ok {
+			l.report(LintUnreachableMapperChild, append(path, ID),
+				"policy can't be selected by mapper argument")
+		}
+	}
+}
+
+func (l *linter) getMapperKeys(e Expression) (map[string]struct{}, bool) {
+	keys := make(map[string]struct{})
+
+	switch e := e.(type) {
+	case AttributeValue:
+		return keys, collectLintKeys(keys, e.v)
+
+	case LocalContentReader:
+		if l.c == nil {
+			return nil, false
+		}
+
+		item, err := l.c.Get(e.GetContentReference())
+		if err != nil {
+			return nil, false
+		}
+
+		return keys, collectLintContentKeys(keys, item.r)
+	}
+
+	return nil, false
+}
+
+func collectLintContentKeys(keys map[string]struct{}, v interface{}) bool {
+	switch v := v.(type) {
+	case ContentValue:
+		return collectLintKeys(keys, v.value)
+
+	case ContentStringMap:
+		// Enumeration channel must be drained even if a value isn't known
+		// otherwise its goroutine leaks.
+		ok := true
+		for p := range v.tree.Enumerate() {
+			if ok {
+				ok = collectLintContentKeys(keys, p.Value)
+			}
+		}
+
+		return ok
+
+	case ContentNetworkMap:
+		ok := true
+		for p := range v.tree.Enumerate() {
+			if ok {
+				ok = collectLintContentKeys(keys, p.Value)
+			}
+		}
+
+		return ok
+
+	case ContentDomainMap:
+		ok := true
+		for p := range v.tree.Enumerate() {
+			if ok {
+				ok = collectLintContentKeys(keys, p.Value)
+			}
+		}
+
+		return ok
+	}
+
+	return collectLintKeys(keys, v)
+}
+
+func collectLintKeys(keys map[string]struct{}, v interface{}) bool {
+	switch v := v.(type) {
+	case string:
+		keys[v] = struct{}{}
+
+	case *strtree.Tree:
+		for p := range v.Enumerate() {
+			keys[p.Key] = struct{}{}
+		}
+
+	case []string:
+		for _, s := range v {
+			keys[s] = struct{}{}
+		}
+
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,263 @@
+package pdp
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/infobloxopen/go-trees/strtree"
+)
+
+type testLintSelector struct {
+	content string
+	item    string
+	path    []Expression
+	t       Type
+}
+
+func (s testLintSelector) GetResultType() Type {
+	return s.t
+}
+
+func (s testLintSelector) Calculate(ctx *Context) (AttributeValue, error) {
+	return UndefinedValue, newMissingValueError()
+}
+
+func (s testLintSelector) GetContentReference() (string, string) {
+	return s.content, s.item
+}
+
+func (s testLintSelector) WalkArguments(visit func(e Expression)) {
+	for _, e := range s.path {
+		visit(e)
+	}
+}
+
+func TestLint(t *testing.T) {
+	symbols := MakeSymbols()
+	for _, a := range []Attribute{
+		MakeAttribute("s", TypeString),
+		MakeAttribute("x", TypeString),
+		MakeAttribute("unused", TypeInteger),
+	} {
+		if err := symbols.PutAttribute(a); err != nil {
+			t.Fatalf("Expected no error but got %s", err)
+		}
+	}
+
+	p := makeSimplePolicySet("root",
+		NewPolicy("first", false, makeSimpleStringTarget("s", "test"),
+			[]*Rule{
+				NewRule("conditional", false, Target{},
+					functionStringEqual{
+						first:  MakeStringDesignator("x"),
+						second: MakeStringValue("test"),
+					}, EffectDeny,
+					[]AttributeAssignment{MakeAttributeAssignment(MakeAttribute("o", TypeString), MakeStringValue("v"))}),
+				makeSimpleRule("permit", EffectPermit),
+				makeSimpleRule("shadowed", EffectDeny),
+				makeSimpleHiddenRule(EffectDeny),
+			},
+			makeFirstApplicableEffectRCA, nil, nil),
+		NewPolicy("mapper", false, Target{},
+			[]*Rule{
+				makeSimpleRule("a", EffectPermit),
+				makeSimpleRule("b", EffectPermit),
+				makeSimpleHiddenRule(EffectDeny),
+				makeSimpleRule("default", EffectDeny),
+			},
+			makeMapperRCA, MapperRCAParams{
+				Argument: MakeListOfStringsValue([]string{"a"}),
+				DefOk:    true,
+				Def:      "default",
+			},
+			[]AttributeAssignment{MakeAttributeAssignment(MakeAttribute("o", TypeInteger), MakeIntegerValue(1))}),
+		NewPolicy("content", false, Target{},
+			[]*Rule{
+				NewRule("missing-content", false, Target{},
+					functionStringEqual{
+						first:  testLintSelector{content: "missing", item: "item", t: TypeString},
+						second: MakeStringValue("test"),
+					}, EffectDeny, nil),
+				NewRule("missing-item", false, Target{},
+					functionStringEqual{
+						first:  testLintSelector{content: "content", item: "missing", t: TypeString},
+						second: MakeStringValue("test"),
+					}, EffectDeny, nil),
+				NewRule("existing", false, Target{},
+					functionStringEqual{
+						first:  testLintSelector{content: "content", item: "item", t: TypeString},
+						second: MakeStringValue("test"),
+					}, EffectDeny, nil),
+			},
+			makeMapperRCA, MapperRCAParams{
+				Argument: testLintSelector{content: "content", item: "item", t: TypeString},
+			},
+			nil),
+	)
+
+	s := NewPolicyStorage(p, symbols, nil)
+	c := NewLocalContentStorage([]*LocalContent{
+		NewLocalContent("content", nil, MakeSymbols(), []*ContentItem{
+			MakeContentValueItem("item", TypeString, "existing"),
+		}),
+	})
+
+	e := []LintIssue{
+		{
+			Kind:    LintShadowedRule,
+			Path:    []string{"root", "first", "shadowed"},
+			Message: "rule is never evaluated because rule \"permit\" above has no target and condition",
+		},
+		{
+			Kind:    LintShadowedRule,
+			Path:    []string{"root", "first", "#3"},
+			Message: "rule is never evaluated because rule \"permit\" above has no target and condition",
+		},
+		{
+			Kind:    LintUnreachableMapperChild,
+			Path:    []string{"root", "mapper", "b"},
+			Message: "rule can't be selected by mapper argument",
+		},
+		{
+			Kind:    LintUnreachableMapperChild,
+			Path:    []string{"root", "mapper", "#2"},
+			Message: "hidden rule can't be selected by mapper",
+		},
+		{
+			Kind:    LintUnreachableMapperChild,
+			Path:    []string{"root", "content", "missing-content"},
+			Message: "rule can't be selected by mapper argument",
+		},
+		{
+			Kind:    LintUnreachableMapperChild,
+			Path:    []string{"root", "content", "missing-item"},
+			Message: "rule can't be selected by mapper argument",
+		},
+		{
+			Kind:    LintMissingContent,
+			Path:    []string{"root", "content", "missing-content"},
+			Message: "selector points to missing content \"missing\"",
+		},
+		{
+			Kind:    LintMissingContentItem,
+			Path:    []string{"root", "content", "missing-item"},
+			Message: "selector points to missing item \"missing\" of content \"content\"",
+		},
+		{
+			Kind:    LintUnusedAttribute,
+			Message: "attribute \"unused\" is declared but never used",
+		},
+		{
+			Kind: LintConflictingObligation,
+			Message: "obligation \"o\" is emitted with different types: " +
+				"\"Integer\" at root/mapper; \"String\" at root/first/conditional",
+		},
+	}
+
+	issues := s.Lint(c)
+	if !reflect.DeepEqual(issues, e) {
+		t.Errorf("Expected issues:\n%#v\nbut got:\n%#v", e, issues)
+	}
+
+	issues = s.Lint(nil)
+	for _, i := range issues {
+		if i.Kind == LintMissingContent || i.Kind == LintMissingContentItem {
+			t.Errorf("Expected no content issues without content storage but got %s", i)
+		}
+	}
+
+	if len(issues) != 6 {
+		t.Errorf("Expected %d issues without content storage but got %d:\n%#v", 6, len(issues), issues)
+	}
+
+	es := "root/first/shadowed: rule is never evaluated because rule \"permit\" above has no target and condition " +
+		"(shadowed-rule)"
+	if s := e[0].String(); s != es {
+		t.Errorf("Expected %q but got %q", es, s)
+	}
+
+	es = "attribute \"unused\" is declared but never used (unused-attribute)"
+	if s := e[8].String(); s != es {
+		t.Errorf("Expected %q but got %q", es, s)
+	}
+}
+
+func TestLintNestedExpressions(t *testing.T) {
+	symbols := MakeSymbols()
+	for _, a := range []Attribute{
+		MakeAttribute("path", TypeString),
+		MakeAttribute("value", TypeString),
+		MakeAttribute("default", TypeString),
+		MakeAttribute("unused", TypeInteger),
+	} {
+		if err := symbols.PutAttribute(a); err != nil {
+			t.Fatalf("Expected no error but got %s", err)
+		}
+	}
+
+	p := makeSimplePolicySet("root",
+		NewPolicy("policy", false, Target{},
+			[]*Rule{
+				NewRule("selector", false, Target{},
+					functionStringEqual{
+						first: testLintSelector{
+							content: "content",
+							item:    "item",
+							path:    []Expression{MakeStringDesignator("path")},
+							t:       TypeString,
+						},
+						second: MakeStringValue("test"),
+					}, EffectDeny, nil),
+				NewRule("switch", false, Target{},
+					functionStringEqual{
+						first: functionSwitch{
+							value:   MakeStringDesignator("value"),
+							cases:   []Expression{MakeStringValue("test")},
+							results: []Expression{MakeStringValue("test")},
+							def:     MakeStringDesignator("default"),
+						},
+						second: MakeStringValue("test"),
+					}, EffectDeny, nil),
+			},
+			makeFirstApplicableEffectRCA, nil, nil),
+	)
+
+	e := []LintIssue{
+		{
+			Kind:    LintUnusedAttribute,
+			Message: "attribute \"unused\" is declared but never used",
+		},
+	}
+
+	issues := NewPolicyStorage(p, symbols, nil).Lint(nil)
+	if !reflect.DeepEqual(issues, e) {
+		t.Errorf("Expected issues:\n%#v\nbut got:\n%#v", e, issues)
+	}
+}
+
+func TestCollectLintContentKeysDrainsEnumeration(t *testing.T) {
+	tree := strtree.NewTree()
+	for _, k := range []string{"a", "b", "c", "d"} {
+		tree.InplaceInsert(k, MakeContentValue(int64(1)))
+	}
+	m := MakeContentStringMap(tree)
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		if collectLintContentKeys(make(map[string]struct{}), m) {
+			t.Fatalf("Expected unknown keys for map of integers")
+		}
+	}
+
+	after := runtime.NumGoroutine()
+	for i := 0; i < 100 && after > before; i++ {
+		time.Sleep(time.Millisecond)
+		after = runtime.NumGoroutine()
+	}
+
+	if after > before {
+		t.Errorf("Expected no more than %d goroutines after collecting keys but got %d", before, after)
+	}
+}
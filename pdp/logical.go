@@ -30,6 +30,11 @@ func (f functionBooleanNot) describe() string {
 	return "not"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionBooleanNot) WalkArguments(visit func(e Expression)) {
+	visit(f.arg)
+}
+
 func (f functionBooleanNot) Calculate(ctx *Context) (AttributeValue, error) {
 	a, err := ctx.calculateBooleanExpression(f.arg)
 	if err != nil {
@@ -63,6 +68,13 @@ func (f functionBooleanOr) describe() string {
 	return "or"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionBooleanOr) WalkArguments(visit func(e Expression)) {
+	for _, arg := range f.args {
+		visit(arg)
+	}
+}
+
 func (f functionBooleanOr) Calculate(ctx *Context) (AttributeValue, error) {
 	for i, arg := range f.args {
 		a, err := ctx.calculateBooleanExpression(arg)
@@ -108,6 +120,13 @@ func (f functionBooleanAnd) describe() string {
 	return "and"
 }
 
+// WalkArguments implements ExpressionWalker interface
+func (f functionBooleanAnd) WalkArguments(visit func(e Expression)) {
+	for _, arg := range f.args {
+		visit(arg)
+	}
+}
+
 func (f functionBooleanAnd) Calculate(ctx *Context) (AttributeValue, error) {
 	for i, arg := range f.args {
 		a, err := ctx.calculateBooleanExpression(arg)
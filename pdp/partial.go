@@ -0,0 +1,386 @@
+package pdp
+
+const (
+	partialUnknown = iota
+	partialFalse
+	partialTrue
+)
+
+// PartialEvaluate simplifies policy set or policy for requests which contain
+// attributes of given context. Matches of targets and conditions which depend
+// only on the attributes are calculated and folded away and child policy
+// sets, policies and rules which can never be applicable are dropped (they
+// are kept for mapper and custom combining algorithms which may refer them).
+// Parts which depend on other attributes, selectors, current time or custom
+// functions remain as is as well as parts which fail to evaluate. Resulting
+// policy set or policy (the residual) gives the same decisions as original
+// one for any request which contains the same values of the attributes and
+// can be dumped with MarshalWithDepth. Original policies aren't modified.
+func PartialEvaluate(e Evaluable, ctx *Context) Evaluable {
+	pe := partialEvaluator{ctx: ctx}
+	r, ok := pe.evaluable(e)
+	if !ok {
+		return pe.makeNeverApplicable(r)
+	}
+
+	return r
+}
+
+type partialEvaluator struct {
+	ctx *Context
+}
+
+// evaluable returns residual for given policy set or policy and false if
+// it can never be applicable.
+func (pe partialEvaluator) evaluable(e Evaluable) (Evaluable, bool) {
+	switch e := e.(type) {
+	case *PolicySet:
+		return pe.policySet(e)
+
+	case *Policy:
+		return pe.policy(e)
+	}
+
+	return e, true
+}
+
+func (pe partialEvaluator) policySet(p *PolicySet) (Evaluable, bool) {
+	t, res := pe.target(p.target)
+	if res == partialFalse {
+		return p, false
+	}
+
+	policies := p.policies
+	algorithm := p.algorithm
+	switch a := algorithm.(type) {
+	case firstApplicableEffectPCA, denyOverridesPCA, permitOverridesPCA,
+		denyUnlessPermitPCA, permitUnlessDenyPCA, onlyOneApplicablePCA:
+		policies = []Evaluable{}
+		for _, child := range p.policies {
+			if r, ok := pe.evaluable(child); ok {
+				policies = append(policies, r)
+			}
+		}
+
+	case mapperPCA:
+		policies = make([]Evaluable, len(p.policies))
+		for i, child := range p.policies {
+			policies[i] = pe.mappedEvaluable(child)
+			if ID, ok := child.GetID(); ok {
+				a = a.add(ID, policies[i], child).(mapperPCA)
+			}
+		}
+
+		algorithm = a
+
+	case flagsMapperPCA:
+		policies = make([]Evaluable, len(p.policies))
+		for i, child := range p.policies {
+			policies[i] = pe.mappedEvaluable(child)
+			if ID, ok := child.GetID(); ok {
+				a = a.add(ID, policies[i], child).(flagsMapperPCA)
+			}
+		}
+
+		algorithm = a
+	}
+
+	return &PolicySet{
+		ord:         p.ord,
+		id:          p.id,
+		hidden:      p.hidden,
+		target:      t,
+		policies:    policies,
+		obligations: p.obligations,
+		algorithm:   algorithm,
+		index:       makePolicySetTargetIndex(policies, algorithm),
+	}, true
+}
+
+func (pe partialEvaluator) policy(p *Policy) (Evaluable, bool) {
+	t, res := pe.target(p.target)
+	if res == partialFalse {
+		return p, false
+	}
+
+	rules := p.rules
+	algorithm := p.algorithm
+	switch a := algorithm.(type) {
+	case firstApplicableEffectRCA:
+		rules = []*Rule{}
+		for _, rule := range p.rules {
+			r, ok := pe.rule(rule)
+			if !ok {
+				continue
+			}
+
+			rules = append(rules, r)
+			if len(r.target.a) <= 0 && r.condition == nil {
+				// The rule is always applicable so the rest is never
+				// evaluated.
+				break
+			}
+		}
+
+	case denyOverridesRCA, permitOverridesRCA, denyUnlessPermitRCA,
+		permitUnlessDenyRCA, onlyOneApplicableRCA:
+		rules = []*Rule{}
+		for _, rule := range p.rules {
+			if r, ok := pe.rule(rule); ok {
+				rules = append(rules, r)
+			}
+		}
+
+	case mapperRCA:
+		rules = make([]*Rule, len(p.rules))
+		for i, rule := range p.rules {
+			rules[i] = pe.mappedRule(rule)
+			if ID, ok := rule.GetID(); ok {
+				a = a.add(ID, rules[i], rule).(mapperRCA)
+			}
+		}
+
+		algorithm = a
+
+	case flagsMapperRCA:
+		rules = make([]*Rule, len(p.rules))
+		for i, rule := range p.rules {
+			rules[i] = pe.mappedRule(rule)
+			if ID, ok := rule.GetID(); ok {
+				a = a.add(ID, rules[i], rule).(flagsMapperRCA)
+			}
+		}
+
+		algorithm = a
+	}
+
+	return &Policy{
+		ord:         p.ord,
+		id:          p.id,
+		hidden:      p.hidden,
+		target:      t,
+		rules:       rules,
+		obligations: p.obligations,
+		algorithm:   algorithm,
+		index:       makePolicyTargetIndex(rules, algorithm),
+	}, true
+}
+
+// rule returns residual copy of given rule and false if the rule can never
+// be applicable.
+func (pe partialEvaluator) rule(r *Rule) (*Rule, bool) {
+	t, res := pe.target(r.target)
+	if res == partialFalse {
+		return r, false
+	}
+
+	c := r.condition
+	if c != nil && pe.isKnown(c) {
+		if v, err := pe.ctx.calculateBooleanExpression(c); err == nil {
+			if v {
+				c = nil
+			} else if res == partialTrue {
+				return r, false
+			} else {
+				// Target still can fail so keep the rule.
+				c = MakeBooleanValue(false)
+			}
+		}
+	}
+
+	return &Rule{
+		ord:         r.ord,
+		id:          r.id,
+		hidden:      r.hidden,
+		target:      t,
+		condition:   c,
+		effect:      r.effect,
+		obligations: r.obligations,
+	}, true
+}
+
+func (pe partialEvaluator) mappedEvaluable(e Evaluable) Evaluable {
+	r, ok := pe.evaluable(e)
+	if !ok {
+		return pe.makeNeverApplicable(r)
+	}
+
+	return r
+}
+
+func (pe partialEvaluator) mappedRule(r *Rule) *Rule {
+	out, ok := pe.rule(r)
+	if !ok {
+		out = &Rule{
+			ord:         r.ord,
+			id:          r.id,
+			hidden:      r.hidden,
+			target:      makeNeverMatchingTarget(),
+			condition:   r.condition,
+			effect:      r.effect,
+			obligations: r.obligations,
+		}
+	}
+
+	return out
+}
+
+func (pe partialEvaluator) makeNeverApplicable(e Evaluable) Evaluable {
+	switch e := e.(type) {
+	case *PolicySet:
+		return &PolicySet{
+			ord:         e.ord,
+			id:          e.id,
+			hidden:      e.hidden,
+			target:      makeNeverMatchingTarget(),
+			policies:    e.policies,
+			obligations: e.obligations,
+			algorithm:   e.algorithm,
+			index:       e.index,
+		}
+
+	case *Policy:
+		return &Policy{
+			ord:         e.ord,
+			id:          e.id,
+			hidden:      e.hidden,
+			target:      makeNeverMatchingTarget(),
+			rules:       e.rules,
+			obligations: e.obligations,
+			algorithm:   e.algorithm,
+			index:       e.index,
+		}
+	}
+
+	return e
+}
+
+// makeNeverMatchingTarget creates target with single empty AnyOf expression
+// which is always false.
+func makeNeverMatchingTarget() Target {
+	t := MakeTarget()
+	t.Append(MakeAnyOf())
+	return t
+}
+
+// target simplifies target and returns partialTrue if the target always
+// matches (then resulting target is empty), partialFalse if it never matches
+// and partialUnknown otherwise. Target, AnyOf and AllOf expressions stop at
+// first error so decided expressions are dropped only before the first
+// undecided one. After it they are replaced by constants.
+func (pe partialEvaluator) target(t Target) (Target, int) {
+	out := MakeTarget()
+	for _, a := range t.a {
+		r, res := pe.anyOf(a)
+		switch res {
+		case partialTrue:
+			continue
+
+		case partialFalse:
+			if len(out.a) <= 0 {
+				return out, partialFalse
+			}
+
+			out.Append(MakeAnyOf())
+			return out, partialUnknown
+		}
+
+		out.Append(r)
+	}
+
+	if len(out.a) <= 0 {
+		return out, partialTrue
+	}
+
+	return out, partialUnknown
+}
+
+func (pe partialEvaluator) anyOf(a AnyOf) (AnyOf, int) {
+	out := MakeAnyOf()
+	for _, e := range a.a {
+		r, res := pe.allOf(e)
+		switch res {
+		case partialFalse:
+			continue
+
+		case partialTrue:
+			if len(out.a) <= 0 {
+				return out, partialTrue
+			}
+
+			out.Append(MakeAllOf())
+			return out, partialUnknown
+		}
+
+		out.Append(r)
+	}
+
+	if len(out.a) <= 0 {
+		return out, partialFalse
+	}
+
+	return out, partialUnknown
+}
+
+func (pe partialEvaluator) allOf(a AllOf) (AllOf, int) {
+	out := MakeAllOf()
+	for _, m := range a.m {
+		switch pe.match(m) {
+		case partialTrue:
+			continue
+
+		case partialFalse:
+			if len(out.m) <= 0 {
+				return out, partialFalse
+			}
+
+			out.Append(MakeMatch(MakeBooleanValue(false)))
+			return out, partialUnknown
+		}
+
+		out.Append(m)
+	}
+
+	if len(out.m) <= 0 {
+		return out, partialTrue
+	}
+
+	return out, partialUnknown
+}
+
+func (pe partialEvaluator) match(m Match) int {
+	if !pe.isKnown(m.m) {
+		return partialUnknown
+	}
+
+	v, err := pe.ctx.calculateBooleanExpression(m.m)
+	if err != nil {
+		return partialUnknown
+	}
+
+	if v {
+		return partialTrue
+	}
+
+	return partialFalse
+}
+
+// isKnown checks if expression depends only on attributes of partial request
+// and gives the same result each time. Selectors, current time and custom
+// functions are never known.
+func (pe partialEvaluator) isKnown(e Expression) bool {
+	known := true
+	walkExpression(e, func(e Expression) {
+		switch e := e.(type) {
+		case AttributeDesignator:
+			if _, err := pe.ctx.getAttribute(e.a); err != nil {
+				known = false
+			}
+
+		case SelectorExpression, functionDateTimeNow, functionCustom:
+			known = false
+		}
+	})
+
+	return known
+}
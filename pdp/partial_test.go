@@ -0,0 +1,166 @@
+package pdp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPartialEvaluate(t *testing.T) {
+	mixed := MakeTarget()
+	anyOf := MakeAnyOf()
+	all := MakeAllOf()
+	all.Append(MakeMatch(makeFunctionStringEqual(MakeStringDesignator("resource"), MakeStringValue("x"))))
+	all.Append(MakeMatch(makeFunctionStringEqual(MakeStringDesignator("role"), MakeStringValue("user"))))
+	anyOf.Append(all)
+	mixed.Append(anyOf)
+
+	root := NewPolicySet("root", false, MakeTarget(), []Evaluable{
+		NewPolicy("admin", false, makeSimpleStringTarget("role", "admin"), []*Rule{
+			NewRule("permit", false, MakeTarget(), nil, EffectPermit, makeSingleStringObligation("r", "admin")),
+		}, makeFirstApplicableEffectRCA, nil, nil),
+		NewPolicy("user", false, makeSimpleStringTarget("role", "user"), []*Rule{
+			NewRule("public", false, makeSimpleStringTarget("resource", "public"), nil, EffectPermit,
+				makeSingleStringObligation("r", "public")),
+			NewRule("eng", false, MakeTarget(),
+				makeFunctionStringEqual(MakeStringDesignator("dept"), MakeStringValue("eng")), EffectDeny,
+				makeSingleStringObligation("r", "eng")),
+			NewRule("permit", false, MakeTarget(), nil, EffectPermit, makeSingleStringObligation("r", "permit")),
+		}, makeFirstApplicableEffectRCA, nil, nil),
+		NewPolicy("mixed", false, mixed, []*Rule{
+			NewRule("permit", false, MakeTarget(), nil, EffectPermit, makeSingleStringObligation("r", "mixed")),
+		}, makeFirstApplicableEffectRCA, nil, nil),
+		NewPolicy("mapper", false, MakeTarget(), []*Rule{
+			NewRule("public", false, makeSimpleStringTarget("role", "admin"), nil, EffectPermit,
+				makeSingleStringObligation("r", "mapper-public")),
+			NewRule("x", false, MakeTarget(), nil, EffectDeny, makeSingleStringObligation("r", "mapper-x")),
+		}, makeMapperRCA, MapperRCAParams{Argument: MakeStringDesignator("resource")}, nil),
+	}, makeDenyOverridesPCA, nil, nil)
+
+	ctx := &Context{a: map[string]interface{}{
+		"role": MakeStringValue("user"),
+		"dept": MakeStringValue("eng"),
+	}}
+
+	e := PartialEvaluate(root, ctx)
+	r, ok := e.(*PolicySet)
+	if !ok {
+		t.Fatalf("Expected *PolicySet but got %T", e)
+	}
+
+	IDs := []string{}
+	for _, p := range r.policies {
+		ID, _ := p.GetID()
+		IDs = append(IDs, ID)
+	}
+
+	if eIDs := []string{"user", "mixed", "mapper"}; !reflect.DeepEqual(IDs, eIDs) {
+		t.Errorf("Expected policies %v but got %v", eIDs, IDs)
+	}
+
+	if p, ok := r.policies[0].(*Policy); ok {
+		if len(p.target.a) > 0 {
+			t.Errorf("Expected empty target for policy %q but got %d expressions", "user", len(p.target.a))
+		}
+
+		IDs := []string{}
+		for _, r := range p.rules {
+			IDs = append(IDs, r.id)
+		}
+
+		if eIDs := []string{"public", "eng"}; !reflect.DeepEqual(IDs, eIDs) {
+			t.Errorf("Expected rules %v but got %v", eIDs, IDs)
+		} else if p.rules[1].condition != nil {
+			t.Errorf("Expected no condition for rule %q but got %#v", "eng", p.rules[1].condition)
+		}
+	} else {
+		t.Errorf("Expected *Policy but got %T", r.policies[0])
+	}
+
+	if p, ok := r.policies[1].(*Policy); ok {
+		if len(p.target.a) != 1 || len(p.target.a[0].a) != 1 || len(p.target.a[0].a[0].m) != 1 {
+			t.Errorf("Expected target with single match for policy %q but got %#v", "mixed", p.target)
+		}
+	} else {
+		t.Errorf("Expected *Policy but got %T", r.policies[1])
+	}
+
+	for _, resource := range []string{"", "public", "x", "other"} {
+		a := map[string]interface{}{
+			"role": MakeStringValue("user"),
+			"dept": MakeStringValue("eng"),
+		}
+		if len(resource) > 0 {
+			a["resource"] = MakeStringValue(resource)
+		}
+
+		ctx := &Context{a: a}
+		er := root.Calculate(ctx)
+		rr := e.Calculate(ctx)
+		if rr.Effect != er.Effect || (rr.Status == nil) != (er.Status == nil) ||
+			!reflect.DeepEqual(rr.Obligations, er.Obligations) {
+			t.Errorf("Expected for resource %q:\n\t%s, %v, %v\nbut got:\n\t%s, %v, %v", resource,
+				effectNames[er.Effect], er.Status, er.Obligations,
+				effectNames[rr.Effect], rr.Status, rr.Obligations)
+		}
+	}
+
+	if !reflect.DeepEqual(root.policies[1].(*Policy).rules[1].condition,
+		makeFunctionStringEqual(MakeStringDesignator("dept"), MakeStringValue("eng"))) {
+		t.Errorf("Expected original policy to be kept intact")
+	}
+}
+
+func TestPartialEvaluateNeverApplicable(t *testing.T) {
+	p := NewPolicy("test", false, makeSimpleStringTarget("role", "admin"), []*Rule{
+		NewRule("permit", false, MakeTarget(), nil, EffectPermit, nil),
+	}, makeFirstApplicableEffectRCA, nil, nil)
+
+	e := PartialEvaluate(p, &Context{a: map[string]interface{}{"role": MakeStringValue("user")}})
+	r := e.Calculate(&Context{a: map[string]interface{}{}})
+	if r.Effect != EffectNotApplicable || r.Status != nil {
+		t.Errorf("Expected %q without status but got %q (%v)",
+			effectNames[EffectNotApplicable], effectNames[r.Effect], r.Status)
+	}
+}
+
+func TestPartialEvaluateUnknownExpressions(t *testing.T) {
+	pe := partialEvaluator{ctx: &Context{a: map[string]interface{}{"s": MakeStringValue("test")}}}
+
+	if !pe.isKnown(makeFunctionStringEqual(MakeStringDesignator("s"), MakeStringValue("test"))) {
+		t.Errorf("Expected expression with known attribute to be known")
+	}
+
+	if pe.isKnown(makeFunctionStringEqual(MakeStringDesignator("u"), MakeStringValue("test"))) {
+		t.Errorf("Expected expression with unknown attribute to be unknown")
+	}
+
+	if pe.isKnown(functionDateTimeNow{}) {
+		t.Errorf("Expected current time to be unknown")
+	}
+
+	if pe.isKnown(makeFunctionStringEqual(testPartialSelector{}, MakeStringValue("test"))) {
+		t.Errorf("Expected selector to be unknown")
+	}
+
+	if pe.isKnown(functionCustom{
+		name:   "test",
+		result: TypeString,
+		args:   []Expression{MakeStringDesignator("s")},
+	}) {
+		t.Errorf("Expected custom function to be unknown")
+	}
+}
+
+type testPartialSelector struct{}
+
+func (s testPartialSelector) GetResultType() Type {
+	return TypeString
+}
+
+func (s testPartialSelector) Calculate(ctx *Context) (AttributeValue, error) {
+	return MakeStringValue("test"), nil
+}
+
+func (s testPartialSelector) GetSelectorURI() string {
+	return "test://selector"
+}
@@ -26,7 +26,11 @@ var (
 	// parameters.
 	RuleCombiningAlgs = map[string]RuleCombiningAlgMaker{
 		"firstapplicableeffect": makeFirstApplicableEffectRCA,
-		"denyoverrides":         makeDenyOverridesRCA}
+		"denyoverrides":         makeDenyOverridesRCA,
+		"permitoverrides":       makePermitOverridesRCA,
+		"denyunlesspermit":      makeDenyUnlessPermitRCA,
+		"permitunlessdeny":      makePermitUnlessDenyRCA,
+		"onlyoneapplicable":     makeOnlyOneApplicableRCA}
 
 	// RuleCombiningParamAlgs defines map of algorithm id to particular maker
 	// of the algorithm. Contains only algorithms which require parameters.
@@ -45,6 +49,7 @@ type Policy struct {
 	rules       []*Rule
 	obligations []AttributeAssignment
 	algorithm   RuleCombiningAlg
+	index       *targetIndex
 }
 
 // NewPolicy creates new instance of policy with given id (or hidden), target,
@@ -56,13 +61,15 @@ func NewPolicy(ID string, hidden bool, target Target, rules []*Rule, makeRCA Rul
 		r.ord = i
 	}
 
+	algorithm := makeRCA(rules, params)
 	return &Policy{
 		id:          ID,
 		hidden:      hidden,
 		target:      target,
 		rules:       rules,
 		obligations: obligations,
-		algorithm:   makeRCA(rules, params)}
+		algorithm:   algorithm,
+		index:       makePolicyTargetIndex(rules, algorithm)}
 }
 
 func (p *Policy) describe() string {
@@ -82,9 +89,22 @@ func (p *Policy) GetID() (string, bool) {
 // Calculate implements Evaluable interface and evaluates policy for given
 // request contest.
 func (p *Policy) Calculate(ctx *Context) Response {
+	n := ctx.traceEnter(TraceKindPolicy, p.id, p.hidden)
+	prev := ctx.coverPolicy(p)
+	r := p.evaluate(ctx, n)
+	ctx.coverPolicy(prev)
+	ctx.traceLeave(n, r)
+	ctx.cover(p, n)
+
+	return r
+}
+
+func (p *Policy) evaluate(ctx *Context, n *TraceNode) Response {
 	match, err := p.target.calculate(ctx)
+	n.setTarget(match, err)
 	if err != nil {
-		r := combineEffectAndStatus(err, p.algorithm.execute(p.rules, ctx))
+		n.setAlgorithm(p.algorithm)
+		r := combineEffectAndStatus(err, p.algorithm.execute(p.getCandidates(ctx), ctx))
 		if r.Status != nil {
 			r.Status = bindError(r.Status, p.describe())
 		}
@@ -95,7 +115,8 @@ func (p *Policy) Calculate(ctx *Context) Response {
 		return Response{EffectNotApplicable, nil, nil}
 	}
 
-	r := p.algorithm.execute(p.rules, ctx)
+	n.setAlgorithm(p.algorithm)
+	r := p.algorithm.execute(p.getCandidates(ctx), ctx)
 	if r.Effect == EffectDeny || r.Effect == EffectPermit {
 		r.Obligations = append(r.Obligations, p.obligations...)
 	}
@@ -158,6 +179,10 @@ func (p *Policy) Delete(path []string) (Evaluable, error) {
 	return r, nil
 }
 
+func (p *Policy) isApplicable(ctx *Context) (bool, boundError) {
+	return p.target.calculate(ctx)
+}
+
 func (p *Policy) getOrder() int {
 	return p.ord
 }
@@ -174,7 +199,46 @@ func (p *Policy) updatedCopy(rules []*Rule, algorithm RuleCombiningAlg) *Policy
 		rules:       rules,
 		obligations: p.obligations,
 		algorithm:   algorithm,
+		index:       makePolicyTargetIndex(rules, algorithm),
+	}
+}
+
+// getCandidates returns rules which can match the request in their original
+// order.
+func (p *Policy) getCandidates(ctx *Context) []*Rule {
+	idx, ok := p.index.lookup(ctx)
+	if !ok {
+		return p.rules
+	}
+
+	rules := make([]*Rule, len(idx))
+	for i, j := range idx {
+		rules[i] = p.rules[j]
+	}
+
+	return rules
+}
+
+// makePolicyTargetIndex builds target index for algorithms which simply go
+// through rules in order.
+func makePolicyTargetIndex(rules []*Rule, algorithm RuleCombiningAlg) *targetIndex {
+	switch algorithm.(type) {
+	default:
+		return nil
+
+	case firstApplicableEffectRCA, denyOverridesRCA:
 	}
+
+	if len(rules) < minTargetIndexSize {
+		return nil
+	}
+
+	targets := make([]Target, len(rules))
+	for i, r := range rules {
+		targets[i] = r.target
+	}
+
+	return newTargetIndex(targets)
 }
 
 func (p *Policy) getChild(ID string) (int, *Rule, error) {
@@ -0,0 +1,57 @@
+package pdp
+
+import "fmt"
+
+// policyReference stands for policy set or policy defined as root of other
+// policy document. LinkPolicies replaces references with policies they refer.
+type policyReference struct {
+	ord int
+	id  string
+}
+
+// NewPolicyReference creates reference to policy set or policy with given
+// id which is root of other policy document. The reference can be used as
+// child of policy set. Policies with references should be linked with
+// LinkPolicies before evaluation.
+func NewPolicyReference(ID string) Evaluable {
+	return &policyReference{id: ID}
+}
+
+func (r *policyReference) describe() string {
+	return fmt.Sprintf("reference to %q", r.id)
+}
+
+// GetID implements Evaluable interface and returns id of referred policy.
+func (r *policyReference) GetID() (string, bool) {
+	return r.id, true
+}
+
+// Calculate implements Evaluable interface. Unresolved reference always
+// gives indeterminate result.
+func (r *policyReference) Calculate(ctx *Context) Response {
+	return Response{EffectIndeterminate, bindError(newUnresolvedPolicyReferenceError(r.id), r.describe()), nil}
+}
+
+// Append implements Evaluable interface. Unresolved reference can't be
+// modified.
+func (r *policyReference) Append(path []string, v interface{}) (Evaluable, error) {
+	return r, bindError(newUnresolvedPolicyReferenceError(r.id), r.describe())
+}
+
+// Delete implements Evaluable interface. Unresolved reference can't be
+// modified.
+func (r *policyReference) Delete(path []string) (Evaluable, error) {
+	return r, bindError(newUnresolvedPolicyReferenceError(r.id), r.describe())
+}
+
+func (r *policyReference) getOrder() int {
+	return r.ord
+}
+
+func (r *policyReference) setOrder(ord int) {
+	r.ord = ord
+}
+
+func (r *policyReference) isApplicable(ctx *Context) (bool, boundError) {
+	return false, bindError(newUnresolvedPolicyReferenceError(r.id), r.describe())
+}
@@ -28,7 +28,11 @@ var (
 	// any parameters.
 	PolicyCombiningAlgs = map[string]PolicyCombiningAlgMaker{
 		"firstapplicableeffect": makeFirstApplicableEffectPCA,
-		"denyoverrides":         makeDenyOverridesPCA}
+		"denyoverrides":         makeDenyOverridesPCA,
+		"permitoverrides":       makePermitOverridesPCA,
+		"denyunlesspermit":      makeDenyUnlessPermitPCA,
+		"permitunlessdeny":      makePermitUnlessDenyPCA,
+		"onlyoneapplicable":     makeOnlyOneApplicablePCA}
 
 	// PolicyCombiningParamAlgs defines map of algorithm id to particular maker
 	// of the algorithm. Contains only algorithms which require parameters.
@@ -47,6 +51,7 @@ type PolicySet struct {
 	policies    []Evaluable
 	obligations []AttributeAssignment
 	algorithm   PolicyCombiningAlg
+	index       *targetIndex
 }
 
 // NewPolicySet creates new instance of policy set with given id (or hidden),
@@ -58,13 +63,15 @@ func NewPolicySet(ID string, hidden bool, target Target, policies []Evaluable, m
 		p.setOrder(i)
 	}
 
+	algorithm := makePCA(policies, params)
 	return &PolicySet{
 		id:          ID,
 		hidden:      hidden,
 		target:      target,
 		policies:    policies,
 		obligations: obligations,
-		algorithm:   makePCA(policies, params)}
+		algorithm:   algorithm,
+		index:       makePolicySetTargetIndex(policies, algorithm)}
 }
 
 func (p *PolicySet) describe() string {
@@ -84,9 +91,20 @@ func (p *PolicySet) GetID() (string, bool) {
 // Calculate implements Evaluable interface and evaluates policy set for given
 // request context.
 func (p *PolicySet) Calculate(ctx *Context) Response {
+	n := ctx.traceEnter(TraceKindPolicySet, p.id, p.hidden)
+	r := p.evaluate(ctx, n)
+	ctx.traceLeave(n, r)
+	ctx.cover(p, n)
+
+	return r
+}
+
+func (p *PolicySet) evaluate(ctx *Context, n *TraceNode) Response {
 	match, err := p.target.calculate(ctx)
+	n.setTarget(match, err)
 	if err != nil {
-		r := combineEffectAndStatus(err, p.algorithm.execute(p.policies, ctx))
+		n.setAlgorithm(p.algorithm)
+		r := combineEffectAndStatus(err, p.algorithm.execute(p.getCandidates(ctx), ctx))
 		if r.Status != nil {
 			r.Status = bindError(err, p.describe())
 		}
@@ -97,7 +115,8 @@ func (p *PolicySet) Calculate(ctx *Context) Response {
 		return Response{EffectNotApplicable, nil, nil}
 	}
 
-	r := p.algorithm.execute(p.policies, ctx)
+	n.setAlgorithm(p.algorithm)
+	r := p.algorithm.execute(p.getCandidates(ctx), ctx)
 	if r.Effect == EffectDeny || r.Effect == EffectPermit {
 		r.Obligations = append(r.Obligations, p.obligations...)
 	}
@@ -187,6 +206,10 @@ func (p *PolicySet) Delete(path []string) (Evaluable, error) {
 	return r, nil
 }
 
+func (p *PolicySet) isApplicable(ctx *Context) (bool, boundError) {
+	return p.target.calculate(ctx)
+}
+
 func (p *PolicySet) getOrder() int {
 	return p.ord
 }
@@ -203,9 +226,57 @@ func (p *PolicySet) updatedCopy(policies []Evaluable, algorithm PolicyCombiningA
 		policies:    policies,
 		obligations: p.obligations,
 		algorithm:   algorithm,
+		index:       makePolicySetTargetIndex(policies, algorithm),
 	}
 }
 
+// getCandidates returns child policy sets and policies which can match
+// the request in their original order.
+func (p *PolicySet) getCandidates(ctx *Context) []Evaluable {
+	idx, ok := p.index.lookup(ctx)
+	if !ok {
+		return p.policies
+	}
+
+	policies := make([]Evaluable, len(idx))
+	for i, j := range idx {
+		policies[i] = p.policies[j]
+	}
+
+	return policies
+}
+
+// makePolicySetTargetIndex builds target index for algorithms which simply
+// go through child policy sets and policies in order.
+func makePolicySetTargetIndex(policies []Evaluable, algorithm PolicyCombiningAlg) *targetIndex {
+	switch algorithm.(type) {
+	default:
+		return nil
+
+	case firstApplicableEffectPCA, denyOverridesPCA:
+	}
+
+	if len(policies) < minTargetIndexSize {
+		return nil
+	}
+
+	targets := make([]Target, len(policies))
+	for i, p := range policies {
+		switch p := p.(type) {
+		default:
+			targets[i] = MakeTarget()
+
+		case *PolicySet:
+			targets[i] = p.target
+
+		case *Policy:
+			targets[i] = p.target
+		}
+	}
+
+	return newTargetIndex(targets)
+}
+
 func (p *PolicySet) getChild(ID string) (int, Evaluable, error) {
 	for i, child := range p.policies {
 		if pID, ok := child.GetID(); ok && pID == ID {
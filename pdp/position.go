@@ -0,0 +1,53 @@
+package pdp
+
+import "fmt"
+
+// SourcePosition points to a place in policy or content document. Line and
+// column start from 1. File is empty if parser hasn't got name of
+// the document.
+type SourcePosition struct {
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// String implements Stringer interface and formats position as
+// "file:line:column" (or "line:column" if there is no file name).
+func (p SourcePosition) String() string {
+	if len(p.File) > 0 {
+		return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+	}
+
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// PositionedError is implemented by errors of policy and content parsers
+// which know where in source document they have been found. Position
+// method returns false if the error has no position. SetSourceFile sets
+// name of the document to the position.
+type PositionedError interface {
+	error
+	Position() (SourcePosition, bool)
+	SetSourceFile(name string)
+}
+
+// GetErrorPosition returns position in source document of given error if
+// the error has one.
+func GetErrorPosition(err error) (SourcePosition, bool) {
+	if err, ok := err.(PositionedError); ok {
+		return err.Position()
+	}
+
+	return SourcePosition{}, false
+}
+
+// SetErrorSourceFile sets name of source document to position of given
+// error. It returns false if the error has no position.
+func SetErrorSourceFile(err error, name string) bool {
+	if _, ok := GetErrorPosition(err); !ok {
+		return false
+	}
+
+	err.(PositionedError).SetSourceFile(name)
+	return true
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"net"
 	"reflect"
+	"time"
 	"unsafe"
 
 	"github.com/infobloxopen/go-trees/domain"
@@ -25,6 +26,8 @@ var (
 	reflectTypeIPTree     = reflect.TypeOf((*iptree.Tree)(nil))
 	reflectTypeDomaintree = reflect.TypeOf((*domaintree.Node)(nil))
 	reflectTypeStrings    = reflect.TypeOf([]string(nil))
+	reflectTypeTime       = reflect.TypeOf(time.Time{})
+	reflectTypeDuration   = reflect.TypeOf(time.Duration(0))
 )
 
 func setEffect(v reflect.Value, effect int) error {
@@ -324,8 +327,22 @@ func getSetOfStrings(v reflect.Value) *strtree.Tree {
 	}
 
 	t := v.Type()
-	if t == reflectTypeStrtree {
+	switch t {
+	case reflectTypeStrtree:
 		return (*strtree.Tree)(unsafe.Pointer(v.Pointer()))
+
+	case reflectTypeStrings:
+		ss := strtree.NewTree()
+		n := 0
+		for i := 0; i < v.Len(); i++ {
+			s := v.Index(i).String()
+			if _, ok := ss.Get(s); !ok {
+				ss.InplaceInsert(s, n)
+				n++
+			}
+		}
+
+		return ss
 	}
 
 	panic(fmt.Errorf("can't marshal %s as set of strings value", t))
@@ -340,11 +357,17 @@ func setSetOfStrings(v reflect.Value, ss *strtree.Tree) error {
 		return newRequestUnmarshalSetOfStringsConstError(v)
 	}
 
-	if v.Type() != reflectTypeStrtree {
+	switch v.Type() {
+	case reflectTypeStrtree:
+		v.Set(reflect.ValueOf(ss))
+
+	case reflectTypeStrings:
+		v.Set(reflect.ValueOf(SortSetOfStrings(ss)))
+
+	default:
 		return newRequestUnmarshalSetOfStringsTypeError(v)
 	}
 
-	v.Set(reflect.ValueOf(ss))
 	return nil
 }
 
@@ -442,3 +465,73 @@ func setListOfStrings(v reflect.Value, ls []string) error {
 	v.Set(reflect.ValueOf(ls))
 	return nil
 }
+
+// reflectTime mirrors layout of time.Time.
+type reflectTime struct {
+	wall uint64
+	ext  int64
+	loc  *time.Location
+}
+
+func getDateTime(v reflect.Value) time.Time {
+	if v == reflectValueNil {
+		return time.Time{}
+	}
+
+	t := v.Type()
+	if t == reflectTypeTime {
+		if v.CanInterface() {
+			return v.Interface().(time.Time)
+		}
+
+		if v.CanAddr() {
+			return *(*time.Time)(unsafe.Pointer(v.UnsafeAddr()))
+		}
+
+		// Value of unexported field can't be accessed directly so copy
+		// it field by field to structure with the same layout.
+		c := reflectTime{
+			wall: v.Field(0).Uint(),
+			ext:  v.Field(1).Int(),
+			loc:  (*time.Location)(unsafe.Pointer(v.Field(2).Pointer())),
+		}
+
+		return *(*time.Time)(unsafe.Pointer(&c))
+	}
+
+	panic(fmt.Errorf("can't marshal %s as date and time value", t))
+}
+
+func setDateTime(v reflect.Value, d time.Time) error {
+	if v == reflectValueNil {
+		return nil
+	}
+
+	if !v.CanSet() {
+		return newRequestUnmarshalDateTimeConstError(v)
+	}
+
+	if v.Type() != reflectTypeTime {
+		return newRequestUnmarshalDateTimeTypeError(v)
+	}
+
+	v.Set(reflect.ValueOf(d))
+	return nil
+}
+
+func setDuration(v reflect.Value, d time.Duration) error {
+	if v == reflectValueNil {
+		return nil
+	}
+
+	if !v.CanSet() {
+		return newRequestUnmarshalDurationConstError(v)
+	}
+
+	if v.Kind() != reflect.Int64 {
+		return newRequestUnmarshalDurationTypeError(v)
+	}
+
+	v.SetInt(int64(d))
+	return nil
+}
@@ -491,6 +491,9 @@ func TestGetSetOfStrings(t *testing.T) {
 	ss := getSetOfStrings(reflect.ValueOf(eSs))
 	assertStrings(SortSetOfStrings(ss), SortSetOfStrings(eSs), "getSetOfStrings", t)
 
+	ss = getSetOfStrings(reflect.ValueOf([]string{"one", "two", "one", "three"}))
+	assertStrings(SortSetOfStrings(ss), SortSetOfStrings(eSs), "getSetOfStrings(slice)", t)
+
 	ss = getSetOfStrings(reflect.ValueOf(nil))
 	if ss != nil {
 		t.Errorf("expected nil but got %#v", SortSetOfStrings(ss))
@@ -510,6 +513,13 @@ func TestSetSetOfStrings(t *testing.T) {
 		assertStrings(SortSetOfStrings(ss), SortSetOfStrings(eSs), "setSetOfStrings", t)
 	}
 
+	var ls []string
+	if err := setSetOfStrings(reflect.Indirect(reflect.ValueOf(&ls)), eSs); err != nil {
+		t.Error(err)
+	} else {
+		assertStrings(ls, SortSetOfStrings(eSs), "setSetOfStrings(slice)", t)
+	}
+
 	if err := setSetOfStrings(reflect.ValueOf(nil), eSs); err != nil {
 		t.Error(err)
 	}
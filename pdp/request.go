@@ -6,6 +6,7 @@ import (
 	"math"
 	"net"
 	"reflect"
+	"time"
 
 	"github.com/infobloxopen/go-trees/domain"
 	"github.com/infobloxopen/go-trees/domaintree"
@@ -31,6 +32,8 @@ const (
 	requestWireTypeSetOfDomains
 	requestWireTypeListOfStrings
 	requestWireTypeSetOfFlags
+	requestWireTypeDateTime
+	requestWireTypeDuration
 
 	requestWireTypesTotal
 )
@@ -52,6 +55,8 @@ var (
 		"set of domains",
 		"list of strings",
 		"set of flags",
+		"datetime",
+		"duration",
 	}
 
 	builtinTypeByWire = []Type{
@@ -69,6 +74,9 @@ var (
 		TypeSetOfNetworks,
 		TypeSetOfDomains,
 		TypeListOfStrings,
+		nil,
+		TypeDateTime,
+		TypeDuration,
 	}
 )
 
@@ -83,6 +91,8 @@ const (
 	reqBooleanValueSize     = 0
 	reqIntegerValueSize     = 8
 	reqFloatValueSize       = 8
+	reqDateTimeValueSize    = 8
+	reqDurationValueSize    = 8
 	reqIPv4AddressValueSize = 4
 	reqIPv6AddressValueSize = 16
 	reqNetworkCIDRSize      = 1
@@ -448,6 +458,14 @@ func putRequestAttribute(b []byte, name string, value AttributeValue) (int, erro
 	case TypeListOfStrings:
 		v, _ := value.listOfStrings()
 		return putRequestAttributeListOfStrings(b, name, v)
+
+	case TypeDateTime:
+		v, _ := value.dateTime()
+		return putRequestAttributeDateTime(b, name, v)
+
+	case TypeDuration:
+		v, _ := value.duration()
+		return putRequestAttributeDuration(b, name, v)
 	}
 
 	return 0, newRequestAttributeMarshallingNotImplementedError(t)
@@ -518,6 +536,14 @@ func putRequestAttributeValue(b []byte, value AttributeValue) (int, error) {
 	case TypeListOfStrings:
 		v, _ := value.listOfStrings()
 		return putRequestListOfStringsValue(b, v)
+
+	case TypeDateTime:
+		v, _ := value.dateTime()
+		return putRequestDateTimeValue(b, v)
+
+	case TypeDuration:
+		v, _ := value.duration()
+		return putRequestDurationValue(b, v)
 	}
 
 	return 0, newRequestAttributeMarshallingNotImplementedError(t)
@@ -655,6 +681,22 @@ func getRequestAttributeValueWithType(t int, b []byte) (AttributeValue, int, err
 
 		return MakeListOfStringsValue(ls), n, nil
 
+	case requestWireTypeDateTime:
+		d, n, err := getRequestDateTimeValue(b)
+		if err != nil {
+			return UndefinedValue, 0, err
+		}
+
+		return MakeDateTimeValue(d), n, nil
+
+	case requestWireTypeDuration:
+		d, n, err := getRequestDurationValue(b)
+		if err != nil {
+			return UndefinedValue, 0, err
+		}
+
+		return MakeDurationValue(d), n, nil
+
 	case requestWireTypeSetOfFlags:
 		v, s, n, err := getRequestAbstractSetOfFlagsValue(b)
 		if err != nil {
@@ -1628,6 +1670,122 @@ func putRequestListOfStringsValue(b []byte, value []string) (int, error) {
 	return off, nil
 }
 
+func putRequestAttributeDateTime(b []byte, name string, value time.Time) (int, error) {
+	off, err := putRequestAttributeName(b, name)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := putRequestDateTimeValue(b[off:], value)
+	if err != nil {
+		return 0, err
+	}
+
+	return off + n, err
+}
+
+func putRequestDateTimeValue(b []byte, value time.Time) (int, error) {
+	off, err := putRequestAttributeType(b, requestWireTypeDateTime)
+	if err != nil {
+		return 0, err
+	}
+
+	b = b[off:]
+
+	if len(b) < reqDateTimeValueSize {
+		return 0, newRequestBufferOverflowError()
+	}
+
+	binary.LittleEndian.PutUint64(b, uint64(value.UnixNano()))
+	return off + reqDateTimeValueSize, nil
+}
+
+func getRequestDateTimeValue(b []byte) (time.Time, int, error) {
+	if len(b) < reqDateTimeValueSize {
+		return time.Time{}, 0, newRequestBufferUnderflowError()
+	}
+
+	return time.Unix(0, int64(binary.LittleEndian.Uint64(b))).UTC(), reqDateTimeValueSize, nil
+}
+
+// GetInfoRequestDateTimeValue extracts date and time value from request for
+// additional information.
+func GetInfoRequestDateTimeValue(b []byte) (time.Time, []byte, error) {
+	if len(b) < reqTypeSize {
+		return time.Time{}, nil, newRequestBufferUnderflowError()
+	}
+
+	if t := int(b[0]); t != requestWireTypeDateTime {
+		return time.Time{}, nil, newRequestAttributeUnmarshallingDateTimeTypeError(t)
+	}
+	b = b[reqTypeSize:]
+
+	v, n, err := getRequestDateTimeValue(b)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	return v, b[n:], nil
+}
+
+func putRequestAttributeDuration(b []byte, name string, value time.Duration) (int, error) {
+	off, err := putRequestAttributeName(b, name)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := putRequestDurationValue(b[off:], value)
+	if err != nil {
+		return 0, err
+	}
+
+	return off + n, err
+}
+
+func putRequestDurationValue(b []byte, value time.Duration) (int, error) {
+	off, err := putRequestAttributeType(b, requestWireTypeDuration)
+	if err != nil {
+		return 0, err
+	}
+
+	b = b[off:]
+
+	if len(b) < reqDurationValueSize {
+		return 0, newRequestBufferOverflowError()
+	}
+
+	binary.LittleEndian.PutUint64(b, uint64(value))
+	return off + reqDurationValueSize, nil
+}
+
+func getRequestDurationValue(b []byte) (time.Duration, int, error) {
+	if len(b) < reqDurationValueSize {
+		return 0, 0, newRequestBufferUnderflowError()
+	}
+
+	return time.Duration(binary.LittleEndian.Uint64(b)), reqDurationValueSize, nil
+}
+
+// GetInfoRequestDurationValue extracts duration value from request for
+// additional information.
+func GetInfoRequestDurationValue(b []byte) (time.Duration, []byte, error) {
+	if len(b) < reqTypeSize {
+		return 0, nil, newRequestBufferUnderflowError()
+	}
+
+	if t := int(b[0]); t != requestWireTypeDuration {
+		return 0, nil, newRequestAttributeUnmarshallingDurationTypeError(t)
+	}
+	b = b[reqTypeSize:]
+
+	v, n, err := getRequestDurationValue(b)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return v, b[n:], nil
+}
+
 func putRequestSetOfFlags8Value(b []byte, value uint8, t *FlagsType) (int, error) {
 	off, err := putRequestAttributeType(b, requestWireTypeSetOfFlags)
 	if err != nil {
@@ -1849,6 +2007,14 @@ func calcRequestAttributeSize(value AttributeValue) (int, error) {
 	case TypeListOfStrings:
 		v, _ := value.listOfStrings()
 		s, err = calcRequestAttributeListOfStringsSize(v)
+
+	case TypeDateTime:
+		v, _ := value.dateTime()
+		s, err = calcRequestAttributeDateTimeSize(v)
+
+	case TypeDuration:
+		v, _ := value.duration()
+		s, err = calcRequestAttributeDurationSize(v)
 	}
 
 	return reqTypeSize + s, err
@@ -1978,6 +2144,14 @@ func calcRequestAttributeListOfStringsSize(value []string) (int, error) {
 	return total, nil
 }
 
+func calcRequestAttributeDateTimeSize(value time.Time) (int, error) {
+	return reqDateTimeValueSize, nil
+}
+
+func calcRequestAttributeDurationSize(value time.Duration) (int, error) {
+	return reqDurationValueSize, nil
+}
+
 func getRequestWireTypeName(t int) string {
 	if t < 0 || t >= len(requestWireTypeNames) {
 		return fmt.Sprintf("unknown (%d)", t)
@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/infobloxopen/go-trees/domain"
 	"github.com/infobloxopen/go-trees/domaintree"
@@ -1019,6 +1020,48 @@ func TestGetRequestListOfStringsValue(t *testing.T) {
 	}
 }
 
+func TestGetRequestDateTimeValue(t *testing.T) {
+	testWireDateTimeValue := []byte{
+		0, 50, 131, 186, 2, 233, 117, 21,
+	}
+	v, n, err := getRequestDateTimeValue(testWireDateTimeValue)
+	if err != nil {
+		t.Error(err)
+	} else if n != len(testWireDateTimeValue) {
+		t.Errorf("expected whole buffer consumed (%d) but got (%d)", len(testWireDateTimeValue), n)
+	} else if e := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC); !v.Equal(e) {
+		t.Errorf("expected datetime %s as attribute value but got %s", e, v)
+	}
+
+	v, _, err = getRequestDateTimeValue([]byte{})
+	if err == nil {
+		t.Errorf("expected *requestBufferUnderflowError but got datetime %s", v)
+	} else if _, ok := err.(*requestBufferUnderflowError); !ok {
+		t.Errorf("expected *requestBufferUnderflowError but got %T (%s)", err, err)
+	}
+}
+
+func TestGetRequestDurationValue(t *testing.T) {
+	testWireDurationValue := []byte{
+		0, 176, 142, 240, 27, 0, 0, 0,
+	}
+	v, n, err := getRequestDurationValue(testWireDurationValue)
+	if err != nil {
+		t.Error(err)
+	} else if n != len(testWireDurationValue) {
+		t.Errorf("expected whole buffer consumed (%d) but got (%d)", len(testWireDurationValue), n)
+	} else if v != 2*time.Minute {
+		t.Errorf("expected duration %s as attribute value but got %s", 2*time.Minute, v)
+	}
+
+	v, _, err = getRequestDurationValue([]byte{})
+	if err == nil {
+		t.Errorf("expected *requestBufferUnderflowError but got duration %s", v)
+	} else if _, ok := err.(*requestBufferUnderflowError); !ok {
+		t.Errorf("expected *requestBufferUnderflowError but got %T (%s)", err, err)
+	}
+}
+
 func TestGetRequestAbstractSetOfFlagsValue(t *testing.T) {
 	testWireSetOfFlags8Value := []byte{
 		8, 0x55,
@@ -1603,6 +1646,51 @@ func TestGetInfoRequestListOfStringsValue(t *testing.T) {
 	}
 }
 
+func TestGetInfoRequestDateTimeValue(t *testing.T) {
+	v, out, err := GetInfoRequestDateTimeValue([]byte{
+		byte(requestWireTypeDateTime), 0, 50, 131, 186, 2, 233, 117, 21,
+	})
+	if err != nil {
+		t.Error(err)
+	} else if len(out) != 0 {
+		t.Errorf("expected whole buffer consumed but %d bytes remain", len(out))
+	} else if e := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC); !v.Equal(e) {
+		t.Errorf("expected %s but got %s", e, v)
+	}
+
+	v, out, err = GetInfoRequestDateTimeValue([]byte{
+		byte(requestWireTypeDuration), 0, 176, 142, 240, 27, 0, 0, 0,
+	})
+	if err == nil {
+		t.Errorf("expected *requestAttributeUnmarshallingDateTimeTypeError but got %s (and %d bytes remain)",
+			v, len(out))
+	} else if _, ok := err.(*requestAttributeUnmarshallingDateTimeTypeError); !ok {
+		t.Errorf("expected *requestAttributeUnmarshallingDateTimeTypeError but got %T (%s)", err, err)
+	}
+}
+
+func TestGetInfoRequestDurationValue(t *testing.T) {
+	v, out, err := GetInfoRequestDurationValue([]byte{
+		byte(requestWireTypeDuration), 0, 176, 142, 240, 27, 0, 0, 0,
+	})
+	if err != nil {
+		t.Error(err)
+	} else if len(out) != 0 {
+		t.Errorf("expected whole buffer consumed but %d bytes remain", len(out))
+	} else if v != 2*time.Minute {
+		t.Errorf("expected %s but got %s", 2*time.Minute, v)
+	}
+
+	v, out, err = GetInfoRequestDurationValue([]byte{
+		byte(requestWireTypeDuration), 0, 176,
+	})
+	if err == nil {
+		t.Errorf("expected *requestBufferUnderflowError but got %s (and %d bytes remain)", v, len(out))
+	} else if _, ok := err.(*requestBufferUnderflowError); !ok {
+		t.Errorf("expected *requestBufferUnderflowError but got %T (%s)", err, err)
+	}
+}
+
 func TestGetRequestAttribute(t *testing.T) {
 	testWireStringAttribute := []byte{
 		6, 's', 't', 'r', 'i', 'n', 'g', byte(requestWireTypeString), 4, 0, 't', 'e', 's', 't',
@@ -3021,6 +3109,91 @@ func TestPutRequestListOfStringsValue(t *testing.T) {
 	}
 }
 
+func TestPutRequestDateTimeValue(t *testing.T) {
+	var b [9]byte
+
+	n, err := putRequestDateTimeValue(b[:], time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC))
+	assertRequestBytesBuffer(t, "putRequestDateTimeValue", err, b[:], n,
+		byte(requestWireTypeDateTime), 0, 50, 131, 186, 2, 233, 117, 21,
+	)
+
+	n, err = putRequestDateTimeValue(nil, time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC))
+	assertRequestBufferOverflow(t, "putRequestDateTimeValue", err, n)
+
+	n, err = putRequestDateTimeValue(b[:5], time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC))
+	assertRequestBufferOverflow(t, "putRequestDateTimeValue(buffer)", err, n)
+}
+
+func TestPutRequestDurationValue(t *testing.T) {
+	var b [9]byte
+
+	n, err := putRequestDurationValue(b[:], 2*time.Minute)
+	assertRequestBytesBuffer(t, "putRequestDurationValue", err, b[:], n,
+		byte(requestWireTypeDuration), 0, 176, 142, 240, 27, 0, 0, 0,
+	)
+
+	n, err = putRequestDurationValue(b[:5], 2*time.Minute)
+	assertRequestBufferOverflow(t, "putRequestDurationValue(buffer)", err, n)
+}
+
+func TestRequestReflectionDateTimeAndDuration(t *testing.T) {
+	type request struct {
+		Time     time.Time
+		Duration time.Duration
+		hidden   time.Time
+	}
+
+	in := request{
+		Time:     time.Date(2019, 1, 2, 3, 4, 5, 6, time.UTC),
+		Duration: 90 * time.Second,
+		hidden:   time.Date(2020, 2, 3, 4, 5, 6, 7, time.UTC),
+	}
+
+	v := reflect.ValueOf(in)
+	fields := []struct {
+		name string
+		t    Type
+	}{
+		{"Time", TypeDateTime},
+		{"Duration", TypeDuration},
+		{"hidden", TypeDateTime},
+	}
+
+	b, err := MarshalRequestReflection(len(fields), func(i int) (string, Type, reflect.Value, error) {
+		return fields[i].name, fields[i].t, v.FieldByName(fields[i].name), nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err)
+	}
+
+	var out request
+	ov := reflect.ValueOf(&out).Elem()
+	err = UnmarshalRequestReflection(b, func(id string, t Type) (reflect.Value, error) {
+		if id == "hidden" {
+			return reflect.ValueOf(&out.hidden).Elem(), nil
+		}
+
+		return ov.FieldByName(id), nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err)
+	}
+
+	if !out.Time.Equal(in.Time) || out.Duration != in.Duration || !out.hidden.Equal(in.hidden) {
+		t.Errorf("expected %#v but got %#v", in, out)
+	}
+
+	var s string
+	err = UnmarshalRequestReflection(b, func(id string, t Type) (reflect.Value, error) {
+		return reflect.ValueOf(&s).Elem(), nil
+	})
+	if err == nil {
+		t.Error("expected *requestUnmarshalDateTimeTypeError but got nothing")
+	} else if _, ok := err.(*requestUnmarshalDateTimeTypeError); !ok {
+		t.Errorf("expected *requestUnmarshalDateTimeTypeError but got %T (%s)", err, err)
+	}
+}
+
 func TestPutRequestSetOfFlags8Value(t *testing.T) {
 	var b [3]byte
 
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/infobloxopen/go-trees/domain"
 	"github.com/infobloxopen/go-trees/domaintree"
@@ -774,6 +775,12 @@ func putAttributesFromReflection(b []byte, c int, f func(i int) (string, Type, r
 
 		case TypeListOfStrings:
 			n, err = putRequestAttributeListOfStrings(b[off:], id, getListOfStrings(v))
+
+		case TypeDateTime:
+			n, err = putRequestAttributeDateTime(b[off:], id, getDateTime(v))
+
+		case TypeDuration:
+			n, err = putRequestAttributeDuration(b[off:], id, time.Duration(v.Int()))
 		}
 
 		if err != nil {
@@ -890,7 +897,7 @@ func getAttributesToReflection(b []byte, f func(string, Type) (reflect.Value, er
 		}
 		b = b[n:]
 
-		if t < 0 || t >= len(builtinTypeByWire) {
+		if t < 0 || t >= len(builtinTypeByWire) || builtinTypeByWire[t] == nil {
 			return bindError(newRequestAttributeUnmarshallingTypeError(t), id)
 		}
 
@@ -1025,6 +1032,26 @@ func getAttributesToReflection(b []byte, f func(string, Type) (reflect.Value, er
 			b = b[n:]
 
 			err = setListOfStrings(v, ls)
+
+		case requestWireTypeDateTime:
+			var d time.Time
+			d, n, err = getRequestDateTimeValue(b)
+			if err != nil {
+				return bindError(err, id)
+			}
+			b = b[n:]
+
+			err = setDateTime(v, d)
+
+		case requestWireTypeDuration:
+			var d time.Duration
+			d, n, err = getRequestDurationValue(b)
+			if err != nil {
+				return bindError(err, id)
+			}
+			b = b[n:]
+
+			err = setDuration(v, d)
 		}
 
 		if err != nil {
@@ -1142,6 +1169,12 @@ func calcAttributesSizeFromReflection(c int, f func(i int) (string, Type, reflec
 
 		case TypeListOfStrings:
 			n, err = calcRequestAttributeListOfStringsSize(getListOfStrings(v))
+
+		case TypeDateTime:
+			n, err = calcRequestAttributeDateTimeSize(getDateTime(v))
+
+		case TypeDuration:
+			n, err = calcRequestAttributeDurationSize(time.Duration(v.Int()))
 		}
 
 		if err != nil {
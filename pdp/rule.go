@@ -50,8 +50,24 @@ func (r Rule) GetID() (string, bool) {
 	return r.id, !r.hidden
 }
 
+// Calculate evaluates rule for given request context. The method is intended
+// for custom rule combining algorithms.
+func (r Rule) Calculate(ctx *Context) Response {
+	return r.calculate(ctx)
+}
+
 func (r Rule) calculate(ctx *Context) Response {
+	n := ctx.traceEnter(TraceKindRule, r.id, r.hidden)
+	res := r.evaluate(ctx, n)
+	ctx.traceLeave(n, res)
+	ctx.coverRule(r, n)
+
+	return res
+}
+
+func (r Rule) evaluate(ctx *Context, n *TraceNode) Response {
 	match, boundErr := r.target.calculate(ctx)
+	n.setTarget(match, boundErr)
 	if boundErr != nil {
 		return makeMatchStatus(bindError(boundErr, r.describe()), r.effect)
 	}
@@ -65,6 +81,7 @@ func (r Rule) calculate(ctx *Context) Response {
 	}
 
 	c, err := ctx.calculateBooleanExpression(r.condition)
+	n.setCondition(c, err)
 	if err != nil {
 		return makeConditionStatus(bindError(bindError(err, "condition"), r.describe()), r.effect)
 	}
@@ -37,6 +37,15 @@ type Selector interface {
 	SelectorFunc(*url.URL, []Expression, Type, ...SelectorOption) (Expression, error)
 }
 
+// SelectorExpression is implemented by expressions made by selectors.
+// GetSelectorURI returns URI of data the expression reads. Value of such
+// expression comes from content or external service rather than from request
+// attributes so it can change between evaluations of the same request.
+type SelectorExpression interface {
+	Expression
+	GetSelectorURI() string
+}
+
 var selectorMap = make(map[string]Selector)
 
 // MakeSelector returns new selector for given uri with path as a set of
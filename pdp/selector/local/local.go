@@ -82,8 +82,38 @@ func (s LocalSelector) GetResultType() pdp.Type {
 	return s.t
 }
 
+// GetContentReference implements pdp.LocalContentReader interface and returns
+// ids of content and content item the selector reads.
+func (s LocalSelector) GetContentReference() (string, string) {
+	return s.content, s.item
+}
+
+// GetSelectorURI implements pdp.SelectorExpression interface and returns
+// URI of content item the selector reads.
+func (s LocalSelector) GetSelectorURI() string {
+	return localSelectorScheme + ":" + s.content + "/" + s.item
+}
+
+// WalkArguments implements pdp.ExpressionWalker interface and visits path,
+// default and error expressions of the selector.
+func (s LocalSelector) WalkArguments(visit func(e pdp.Expression)) {
+	for _, e := range s.path {
+		visit(e)
+	}
+
+	visit(s.def)
+	visit(s.err)
+}
+
 // Calculate implements Expression interface and returns calculated value
 func (s LocalSelector) Calculate(ctx *pdp.Context) (pdp.AttributeValue, error) {
+	v, err := s.calculate(ctx)
+	ctx.TraceSelector(s.GetSelectorURI(), s.path, v, err)
+
+	return v, err
+}
+
+func (s LocalSelector) calculate(ctx *pdp.Context) (pdp.AttributeValue, error) {
 	item, err := ctx.GetContentItem(s.content, s.item)
 	if err != nil {
 		return s.handleError(ctx, err)
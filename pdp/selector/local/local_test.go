@@ -35,13 +35,23 @@ func TestMakeLocalSelector(t *testing.T) {
 		e, err := pdp.MakeSelector(uri, path, pdp.TypeString)
 		if err != nil {
 			t.Errorf("Expected no error but got: %s", err)
-		} else if _, ok := e.(LocalSelector); !ok {
+		} else if ls, ok := e.(LocalSelector); !ok {
 			t.Errorf("Expected LocalSelector expression but got %T (%#v)", e, e)
 		} else {
 			st := e.GetResultType()
 			if st != pdp.TypeString {
 				t.Errorf("Expected %q as selector result type but got %q", pdp.TypeString, st)
 			}
+
+			if c, i := ls.GetContentReference(); c != "content" || i != "item" {
+				t.Errorf("Expected reference to \"content\"/\"item\" but got %q/%q", c, i)
+			}
+
+			if se, ok := e.(pdp.SelectorExpression); !ok {
+				t.Errorf("Expected selector expression but got %T (%#v)", e, e)
+			} else if s := se.GetSelectorURI(); s != "local:content/item" {
+				t.Errorf("Expected %q as selector URI but got %q", "local:content/item", s)
+			}
 		}
 	}
 
@@ -85,6 +85,7 @@ type PipSelector struct {
 	k8s  bool
 	addr string
 	id   string
+	uri  string
 
 	path []pdp.Expression
 	t    pdp.Type
@@ -101,6 +102,7 @@ func MakePipSelector(clients *clientsPool, uri *url.URL, path []pdp.Expression,
 		net:     "tcp",
 		addr:    uri.Host,
 		id:      uri.Path,
+		uri:     uri.String(),
 		path:    path,
 		t:       t,
 	}
@@ -146,9 +148,33 @@ func (s PipSelector) GetResultType() pdp.Type {
 	return s.t
 }
 
+// GetSelectorURI implements pdp.SelectorExpression interface and returns
+// URI of PIP the selector queries.
+func (s PipSelector) GetSelectorURI() string {
+	return s.uri
+}
+
+// WalkArguments implements pdp.ExpressionWalker interface and visits path,
+// default and error expressions of the selector.
+func (s PipSelector) WalkArguments(visit func(e pdp.Expression)) {
+	for _, e := range s.path {
+		visit(e)
+	}
+
+	visit(s.def)
+	visit(s.err)
+}
+
 // Calculate implements pdp.Expression interface and obtains result from
 // unified PIP for given context.
 func (s PipSelector) Calculate(ctx *pdp.Context) (pdp.AttributeValue, error) {
+	v, err := s.calculate(ctx)
+	ctx.TraceSelector(s.uri, s.path, v, err)
+
+	return v, err
+}
+
+func (s PipSelector) calculate(ctx *pdp.Context) (pdp.AttributeValue, error) {
 	vals := make([]pdp.AttributeValue, 0, len(s.path))
 	for i, item := range s.path {
 		v, err := item.Calculate(ctx)
@@ -32,6 +32,16 @@ func (s *PolicyStorage) Root() Evaluable {
 	return s.policies
 }
 
+// GetTag returns tag of the storage. It's nil for untagged storage.
+func (s *PolicyStorage) GetTag() *uuid.UUID {
+	return s.tag
+}
+
+// GetSymbols returns read-only copy of symbol table of the storage.
+func (s *PolicyStorage) GetSymbols() Symbols {
+	return s.symbols.makeROCopy()
+}
+
 // CheckTag checks if given tag matches to the storage tag. If the storage
 // doesn't have any tag, no tag matches the storage and vice versa nil tag
 // doesn't match any storage.
@@ -152,6 +162,33 @@ func (u *PolicyUpdate) Append(op int, path []string, entity interface{}) {
 	u.cmds = append(u.cmds, &command{op: op, path: path, entity: entity})
 }
 
+// AppendWithVariables works like Append but also keeps variables defined by
+// policy set or policy to add. Symbol tables should have the variables at
+// full paths from the root (parsers put them so to tables made by
+// MakeDocumentSymbols). The variables replace ones of previous entity with
+// the same path when the update is applied.
+func (u *PolicyUpdate) AppendWithVariables(op int, path []string, entity interface{}, s Symbols) {
+	u.cmds = append(u.cmds, &command{
+		op:     op,
+		path:   path,
+		entity: entity,
+		vars:   getEntityVariables(s.vars, path, entity),
+	})
+}
+
+// Iterate calls f for each command of the update in order with operation,
+// path and entity (nil for delete operation). It stops at first error
+// returned by f.
+func (u *PolicyUpdate) Iterate(f func(op int, path []string, entity interface{}) error) error {
+	for _, cmd := range u.cmds {
+		if err := f(cmd.op, cmd.path, cmd.entity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // String implements Stringer interface.
 func (u *PolicyUpdate) String() string {
 	if u == nil {
@@ -173,6 +210,28 @@ type command struct {
 	op     int
 	path   []string
 	entity interface{}
+	vars   *variableScope
+}
+
+// getEntityVariables returns scope of policy set or policy to add at given
+// path. It returns nil for rules, hidden entities and entities without
+// variables.
+func getEntityVariables(s *variableScope, path []string, entity interface{}) *variableScope {
+	e, ok := entity.(Evaluable)
+	if !ok {
+		return nil
+	}
+
+	ID, ok := e.GetID()
+	if !ok {
+		return nil
+	}
+
+	for _, pID := range path {
+		s = s.lookupChild(pID)
+	}
+
+	return s.lookupChild(ID)
 }
 
 func (c *command) describe() string {
@@ -225,10 +284,20 @@ func (t *PolicyStorageTransaction) Symbols() Symbols {
 func (t *PolicyStorageTransaction) applyCmd(cmd *command) error {
 	switch cmd.op {
 	case UOAdd:
-		return t.appendItem(cmd.path, cmd.entity)
+		if err := t.appendItem(cmd.path, cmd.entity); err != nil {
+			return err
+		}
+
+		t.putVariables(cmd.path, cmd.entity, cmd.vars)
+		return nil
 
 	case UODelete:
-		return t.del(cmd.path)
+		if err := t.del(cmd.path); err != nil {
+			return err
+		}
+
+		t.symbols.vars = t.symbols.vars.replace(cmd.path, nil)
+		return nil
 	}
 
 	return newUnknownPolicyUpdateOperationError(cmd.op)
@@ -302,6 +371,34 @@ func (t *PolicyStorageTransaction) appendItem(path []string, v interface{}) erro
 	return nil
 }
 
+// putVariables binds variables of added policy set or policy to its path
+// dropping variables of entity it replaces. Scopes are copied on change so
+// committed storage keeps its symbol tables intact.
+func (t *PolicyStorageTransaction) putVariables(path []string, entity interface{}, vars *variableScope) {
+	e, ok := entity.(Evaluable)
+	if !ok {
+		return
+	}
+
+	ID, ok := e.GetID()
+	if !ok {
+		return
+	}
+
+	if len(path) <= 0 {
+		// New root policy replaces whole hierarchy so only document level
+		// variables remain.
+		root := newVariableScope()
+		if t.symbols.vars != nil {
+			root.vars = t.symbols.vars.vars
+		}
+
+		t.symbols.vars = root
+	}
+
+	t.symbols.vars = t.symbols.vars.replace(appendDiffPath(path, ID), vars)
+}
+
 func (t *PolicyStorageTransaction) del(path []string) error {
 	if len(path) <= 0 {
 		return newEmptyPathModificationError()
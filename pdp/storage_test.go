@@ -23,6 +23,16 @@ func TestStorage(t *testing.T) {
 	if sr != root {
 		t.Errorf("Expected stored root policy to be exactly root policy but got different")
 	}
+
+	if tag := s.GetTag(); tag != nil {
+		t.Errorf("Expected no tag but got %s", tag)
+	}
+
+	tag := uuid.New()
+	s = NewPolicyStorage(root, MakeSymbols(), &tag)
+	if st := s.GetTag(); st != &tag {
+		t.Errorf("Expected tag %s but got %s", tag, st)
+	}
 }
 
 func TestStorageNewTransaction(t *testing.T) {
@@ -330,6 +340,95 @@ func TestStorageTransactionalUpdate(t *testing.T) {
 	}
 }
 
+func TestStorageTransactionalUpdateVariables(t *testing.T) {
+	symbols := MakeSymbols()
+	first := NewVariable("v", MakeStringValue("old"))
+	if err := symbols.PutVariable([]string{"test", "first"}, first); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	del := NewVariable("d", MakeStringValue("del"))
+	if err := symbols.PutVariable([]string{"test", "del"}, del); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	tag := uuid.New()
+	s := NewPolicyStorage(makeSimplePolicySet("test",
+		makeSimplePolicy("first", makeSimpleRule("permit", EffectPermit)),
+		makeSimplePolicy("del", makeSimpleRule("permit", EffectPermit)),
+	), symbols, &tag)
+
+	vars := MakeDocumentSymbols(symbols)
+	replaced := NewVariable("v", MakeStringValue("new"))
+	if err := vars.PutVariable([]string{"test", "first"}, replaced); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	added := NewVariable("a", MakeStringValue("added"))
+	if err := vars.PutVariable([]string{"test", "added"}, added); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	newTag := uuid.New()
+	u := NewPolicyUpdate(tag, newTag)
+	u.AppendWithVariables(UOAdd, []string{"test"}, makeSimplePolicy("first", makeSimpleRule("deny", EffectDeny)), vars)
+	u.AppendWithVariables(UOAdd, []string{"test"}, makeSimplePolicy("added", makeSimpleRule("deny", EffectDeny)), vars)
+	u.Append(UODelete, []string{"test", "del"}, nil)
+
+	tr, err := s.NewTransaction(&tag)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	if err := tr.Apply(u); err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	ns, err := tr.Commit()
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	nSymbols := ns.GetSymbols()
+	if v, ok := nSymbols.GetVariable([]string{"test", "first"}, "v"); !ok || v != replaced {
+		t.Errorf("Expected replaced variable %p but got %p", replaced, v)
+	}
+
+	if v, ok := nSymbols.GetVariable([]string{"test", "added"}, "a"); !ok || v != added {
+		t.Errorf("Expected added variable %p but got %p", added, v)
+	}
+
+	if v, ok := nSymbols.GetVariable([]string{"test", "del"}, "d"); ok {
+		t.Errorf("Expected no variable of deleted policy but got %p", v)
+	}
+
+	if v, ok := symbols.GetVariable([]string{"test", "first"}, "v"); !ok || v != first {
+		t.Errorf("Expected original variable %p to stay in old storage but got %p", first, v)
+	}
+
+	if v, ok := symbols.GetVariable([]string{"test", "del"}, "d"); !ok || v != del {
+		t.Errorf("Expected original variable %p to stay in old storage but got %p", del, v)
+	}
+
+	tag = newTag
+	newTag = uuid.New()
+	u = NewPolicyUpdate(tag, newTag)
+	u.Append(UOAdd, []string{"test"}, makeSimplePolicy("first", makeSimpleRule("permit", EffectPermit)))
+
+	tr, err = ns.NewTransaction(&tag)
+	if err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	if err := tr.Apply(u); err != nil {
+		t.Fatalf("Expected no error but got %T (%s)", err, err)
+	}
+
+	if v, ok := tr.Symbols().GetVariable([]string{"test", "first"}, "v"); ok {
+		t.Errorf("Expected no variable of policy replaced by one without variables but got %p", v)
+	}
+}
+
 func makeSymbols(t map[string]Type, a map[string]Attribute) Symbols {
 	return Symbols{
 		types: t,
@@ -2,18 +2,32 @@ package pdp
 
 import "strings"
 
-// Symbols wraps type and attribute symbol tables.
+// Symbols wraps type, attribute and variable symbol tables.
 type Symbols struct {
 	types map[string]Type
 	attrs map[string]Attribute
+	vars  *variableScope
 	ro    bool
 }
 
-// MakeSymbols create symbol tables without any types and attributes.
+// MakeSymbols create symbol tables without any types, attributes and
+// variables.
 func MakeSymbols() Symbols {
 	return Symbols{
 		types: make(map[string]Type),
 		attrs: make(map[string]Attribute),
+		vars:  newVariableScope(),
+	}
+}
+
+// MakeDocumentSymbols creates symbol tables which share types and attributes
+// with given ones but have own variables. Such tables are used to parse
+// policy documents which are linked by LinkPolicies.
+func MakeDocumentSymbols(s Symbols) Symbols {
+	return Symbols{
+		types: s.types,
+		attrs: s.attrs,
+		vars:  newVariableScope(),
 	}
 }
 
@@ -56,6 +70,16 @@ func (s Symbols) GetType(ID string) Type {
 	return nil
 }
 
+// rebindType returns type from the symbol table with the same key as given
+// custom type has (or given type if it's built-in or there is no such type).
+func (s Symbols) rebindType(t Type) Type {
+	if ot, ok := s.types[t.GetKey()]; ok {
+		return ot
+	}
+
+	return t
+}
+
 // PutAttribute stores given attribute in the symbol table.
 func (s Symbols) PutAttribute(a Attribute) error {
 	if s.ro {
@@ -92,10 +116,92 @@ func (s Symbols) GetAttribute(ID string) (Attribute, bool) {
 	return Attribute{}, false
 }
 
+// PutVariable stores given variable in the symbol table. Path is a list of
+// IDs of policy sets and policies from the root to one which defines
+// the variable (empty path stands for variables defined at the root of
+// policy file).
+func (s Symbols) PutVariable(path []string, v *Variable) error {
+	if s.ro {
+		return newReadOnlySymbolsChangeError()
+	}
+
+	if v == nil {
+		return newNilVariableError()
+	}
+
+	scope := s.vars
+	for _, ID := range path {
+		child, ok := scope.children[ID]
+		if !ok {
+			child = newVariableScope()
+			scope.children[ID] = child
+		}
+
+		scope = child
+	}
+
+	if _, ok := scope.vars[v.id]; ok {
+		return newDuplicateVariableError(v.id)
+	}
+
+	scope.vars[v.id] = v
+
+	return nil
+}
+
+// GetVariable returns variable by name visible at given path. It looks for
+// the variable from the deepest scope of the path to the root one.
+func (s Symbols) GetVariable(path []string, ID string) (*Variable, bool) {
+	if s.vars == nil {
+		return nil, false
+	}
+
+	scopes := []*variableScope{s.vars}
+	for _, pID := range path {
+		scope, ok := scopes[len(scopes)-1].children[pID]
+		if !ok {
+			break
+		}
+
+		scopes = append(scopes, scope)
+	}
+
+	for i := len(scopes) - 1; i >= 0; i-- {
+		if v, ok := scopes[i].vars[ID]; ok {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// SymbolsSummary holds number of symbols of each kind in symbol tables.
+type SymbolsSummary struct {
+	Types      int
+	Attributes int
+	Variables  int
+}
+
+// Summary counts custom types, attributes and variables of all scopes in
+// the symbol tables.
+func (s Symbols) Summary() SymbolsSummary {
+	out := SymbolsSummary{
+		Types:      len(s.types),
+		Attributes: len(s.attrs),
+	}
+
+	if s.vars != nil {
+		out.Variables = s.vars.count()
+	}
+
+	return out
+}
+
 func (s Symbols) makeROCopy() Symbols {
 	return Symbols{
 		types: s.types,
 		attrs: s.attrs,
+		vars:  s.vars,
 		ro:    true,
 	}
 }
@@ -147,3 +147,85 @@ func TestSymbolsAttribute(t *testing.T) {
 		t.Errorf("Expected *builtinCustomTypeError but got %T (%s)", err, err)
 	}
 }
+
+func TestSymbolsVariable(t *testing.T) {
+	s := MakeSymbols()
+
+	g := NewVariable("v", MakeStringValue("global"))
+	if err := s.PutVariable(nil, g); err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	}
+
+	p := NewVariable("v", MakeStringValue("policy"))
+	if err := s.PutVariable([]string{"root", "p"}, p); err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	}
+
+	for _, tc := range []struct {
+		path []string
+		v    *Variable
+	}{
+		{nil, g},
+		{[]string{"root"}, g},
+		{[]string{"root", "p"}, p},
+		{[]string{"root", "p", "r"}, p},
+		{[]string{"root", "q"}, g},
+	} {
+		if v, ok := s.GetVariable(tc.path, "v"); !ok || v != tc.v {
+			t.Errorf("Expected %p at %v but got %p (%v)", tc.v, tc.path, v, ok)
+		}
+	}
+
+	if v, ok := s.GetVariable([]string{"root", "p"}, "x"); ok {
+		t.Errorf("Expected no variable but got %p", v)
+	}
+
+	err := s.PutVariable([]string{"root", "p"}, NewVariable("v", MakeStringValue("duplicate")))
+	if err == nil {
+		t.Error("Expected *duplicateVariableError but got nothing")
+	} else if _, ok := err.(*duplicateVariableError); !ok {
+		t.Errorf("Expected *duplicateVariableError but got %T (%s)", err, err)
+	}
+
+	ros := s.makeROCopy()
+	err = ros.PutVariable(nil, NewVariable("x", MakeStringValue("x")))
+	if err == nil {
+		t.Error("Expected *ReadOnlySymbolsChangeError but got nothing")
+	} else if _, ok := err.(*ReadOnlySymbolsChangeError); !ok {
+		t.Errorf("Expected *ReadOnlySymbolsChangeError but got %T (%s)", err, err)
+	}
+}
+
+func TestSymbolsSummary(t *testing.T) {
+	s := MakeSymbols()
+
+	ft, err := NewFlagsType("flags", "first", "second")
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if err := s.PutType(ft); err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	}
+
+	if err := s.PutAttribute(MakeAttribute("a", TypeString)); err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	}
+
+	if err := s.PutVariable(nil, NewVariable("v", MakeStringValue("global"))); err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	}
+
+	if err := s.PutVariable([]string{"root", "p"}, NewVariable("v", MakeStringValue("policy"))); err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	}
+
+	e := SymbolsSummary{Types: 1, Attributes: 1, Variables: 2}
+	if sum := s.makeROCopy().Summary(); sum != e {
+		t.Errorf("Expected %+v but got %+v", e, sum)
+	}
+
+	if sum := (Symbols{}).Summary(); sum != (SymbolsSummary{}) {
+		t.Errorf("Expected empty summary but got %+v", sum)
+	}
+}
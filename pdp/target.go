@@ -220,4 +220,26 @@ var TargetCompatibleExpressions = map[string]map[Type]map[Type]twoArgumentsFunct
 		TypeSetOfNetworks: {
 			TypeAddress: makeFunctionSetOfNetworksContainsAddress},
 		TypeSetOfDomains: {
-			TypeDomain: makeFunctionSetOfDomainsContains}}}
+			TypeDomain: makeFunctionSetOfDomainsContains}},
+	"match": {
+		TypeString: {
+			TypeString: makeFunctionMatch},
+		TypeDomain: {
+			TypeString: makeFunctionMatch}},
+	"glob": {
+		TypeString: {
+			TypeString: makeFunctionGlob},
+		TypeDomain: {
+			TypeString: makeFunctionGlob}},
+	"has prefix": {
+		TypeString: {
+			TypeString: makeFunctionStringHasPrefix}},
+	"has suffix": {
+		TypeString: {
+			TypeString: makeFunctionStringHasSuffix}},
+	"before": {
+		TypeDateTime: {
+			TypeDateTime: makeFunctionDateTimeBefore}},
+	"after": {
+		TypeDateTime: {
+			TypeDateTime: makeFunctionDateTimeAfter}}}
@@ -0,0 +1,419 @@
+package pdp
+
+import (
+	"net"
+	"sort"
+
+	"github.com/infobloxopen/go-trees/domain"
+	"github.com/infobloxopen/go-trees/domaintree"
+	"github.com/infobloxopen/go-trees/iptree"
+)
+
+// minTargetIndexSize is minimal number of children with indexable targets
+// which makes index worth building.
+const minTargetIndexSize = 8
+
+const (
+	targetIndexKindString = iota
+	targetIndexKindNetwork
+	targetIndexKindDomain
+)
+
+type targetIndexKey struct {
+	a    Attribute
+	kind int
+}
+
+// targetIndex narrows list of children of policy set or policy to ones which
+// targets can match given request. The index covers children which targets
+// start with match on the same attribute and immediate value (or values)
+// like "equal" for string or "contains" for network or domain sets. Other
+// children are always candidates.
+type targetIndex struct {
+	key targetIndexKey
+	all []int
+
+	strings  map[string][]int
+	networks *iptree.Tree
+	domains  *domaintree.Node
+}
+
+// newTargetIndex creates index for given list of child targets. It returns
+// nil if there isn't enough indexable targets.
+func newTargetIndex(targets []Target) *targetIndex {
+	keys := make([]targetIndexKey, len(targets))
+	values := make([][]interface{}, len(targets))
+	ok := make([]bool, len(targets))
+
+	counts := make(map[targetIndexKey]int)
+	for i, t := range targets {
+		keys[i], values[i], ok[i] = getTargetIndexValues(t)
+		if ok[i] {
+			counts[keys[i]]++
+		}
+	}
+
+	var (
+		key targetIndexKey
+		max int
+	)
+	for k, n := range counts {
+		if n > max || n == max && k.a.id < key.a.id {
+			key = k
+			max = n
+		}
+	}
+
+	if max < minTargetIndexSize {
+		return nil
+	}
+
+	idx := &targetIndex{
+		key: key,
+		all: []int{},
+	}
+
+	strs := make(map[string][]int)
+	nets := make(map[string]*targetIndexNetwork)
+	doms := make(map[string]*targetIndexDomain)
+	for i := range targets {
+		if !ok[i] || keys[i] != key {
+			idx.all = append(idx.all, i)
+			continue
+		}
+
+		for _, v := range values[i] {
+			switch v := v.(type) {
+			case string:
+				strs[v] = appendTargetIndex(strs[v], i)
+
+			case *net.IPNet:
+				s := v.String()
+				n, ok := nets[s]
+				if !ok {
+					n = &targetIndexNetwork{n: v}
+					nets[s] = n
+				}
+
+				n.idx = appendTargetIndex(n.idx, i)
+
+			case domain.Name:
+				s := v.String()
+				d, ok := doms[s]
+				if !ok {
+					d = &targetIndexDomain{d: v}
+					doms[s] = d
+				}
+
+				d.idx = appendTargetIndex(d.idx, i)
+			}
+		}
+	}
+
+	switch key.kind {
+	case targetIndexKindString:
+		idx.strings = strs
+
+	case targetIndexKindNetwork:
+		idx.networks = makeTargetIndexNetworks(nets)
+
+	case targetIndexKindDomain:
+		idx.domains = makeTargetIndexDomains(doms)
+	}
+
+	return idx
+}
+
+// lookup returns sorted indices of children which targets can match
+// the request. It returns false if all children should be evaluated. Index
+// isn't used while tracing or collecting coverage as they should see all
+// children.
+func (idx *targetIndex) lookup(ctx *Context) ([]int, bool) {
+	if idx == nil || ctx.tr != nil || ctx.cov != nil {
+		return nil, false
+	}
+
+	v, err := ctx.getAttribute(idx.key.a)
+	if err != nil {
+		// Targets of indexed children fail with the same error so let them
+		// report it.
+		return nil, false
+	}
+
+	var matched []int
+	switch idx.key.kind {
+	case targetIndexKindString:
+		s, err := v.str()
+		if err != nil {
+			return nil, false
+		}
+
+		matched = idx.strings[s]
+
+	case targetIndexKindNetwork:
+		a, err := v.address()
+		if err != nil {
+			return nil, false
+		}
+
+		if m, ok := idx.networks.GetByIP(a); ok {
+			matched = m.([]int)
+		}
+
+	case targetIndexKindDomain:
+		d, err := v.domain()
+		if err != nil {
+			return nil, false
+		}
+
+		if m, ok := idx.domains.Get(d); ok {
+			matched = m.([]int)
+		}
+	}
+
+	return mergeTargetIndices(idx.all, matched), true
+}
+
+// getTargetIndexValues checks if target can be indexed and returns attribute
+// and kind of index along with values which make the target match. Target
+// evaluates its first AnyOf expression first and AnyOf evaluates first match
+// of each AllOf first. So if the attribute is present in request and none of
+// the first matches is true the target is false without any error.
+func getTargetIndexValues(t Target) (targetIndexKey, []interface{}, bool) {
+	if len(t.a) <= 0 || len(t.a[0].a) <= 0 {
+		return targetIndexKey{}, nil, false
+	}
+
+	var (
+		key    targetIndexKey
+		values []interface{}
+	)
+	for i, a := range t.a[0].a {
+		if len(a.m) <= 0 {
+			return targetIndexKey{}, nil, false
+		}
+
+		k, v, ok := getMatchIndexValues(a.m[0])
+		if !ok || i > 0 && k != key {
+			return targetIndexKey{}, nil, false
+		}
+
+		key = k
+		values = append(values, v...)
+	}
+
+	return key, values, true
+}
+
+func getMatchIndexValues(m Match) (targetIndexKey, []interface{}, bool) {
+	switch e := m.m.(type) {
+	case functionStringEqual:
+		d, v, ok := getTargetIndexArguments(e.first, e.second)
+		if !ok {
+			d, v, ok = getTargetIndexArguments(e.second, e.first)
+		}
+
+		if ok && d.a.t == TypeString {
+			if s, err := v.str(); err == nil {
+				return targetIndexKey{a: d.a, kind: targetIndexKindString}, []interface{}{s}, true
+			}
+		}
+
+	case functionSetOfStringsContains:
+		d, v, ok := getTargetIndexArguments(e.value, e.set)
+		if ok && d.a.t == TypeString {
+			if s, err := v.setOfStrings(); err == nil {
+				values := []interface{}{}
+				for p := range s.Enumerate() {
+					values = append(values, p.Key)
+				}
+
+				return targetIndexKey{a: d.a, kind: targetIndexKindString}, values, true
+			}
+		}
+
+	case functionNetworkContainsAddress:
+		d, v, ok := getTargetIndexArguments(e.address, e.network)
+		if ok && d.a.t == TypeAddress {
+			if n, err := v.network(); err == nil {
+				return targetIndexKey{a: d.a, kind: targetIndexKindNetwork}, []interface{}{n}, true
+			}
+		}
+
+	case functionSetOfNetworksContainsAddress:
+		d, v, ok := getTargetIndexArguments(e.value, e.set)
+		if ok && d.a.t == TypeAddress {
+			if s, err := v.setOfNetworks(); err == nil {
+				values := []interface{}{}
+				for p := range s.Enumerate() {
+					values = append(values, p.Key)
+				}
+
+				return targetIndexKey{a: d.a, kind: targetIndexKindNetwork}, values, true
+			}
+		}
+
+	case functionSetOfDomainsContains:
+		d, v, ok := getTargetIndexArguments(e.value, e.set)
+		if ok && d.a.t == TypeDomain {
+			if s, err := v.setOfDomains(); err == nil {
+				values := []interface{}{}
+				for p := range s.Enumerate() {
+					n, err := domain.MakeNameFromString(p.Key)
+					if err != nil {
+						// Let enumeration finish and give up on the match.
+						ok = false
+						continue
+					}
+
+					values = append(values, n)
+				}
+
+				if ok {
+					return targetIndexKey{a: d.a, kind: targetIndexKindDomain}, values, true
+				}
+			}
+		}
+	}
+
+	return targetIndexKey{}, nil, false
+}
+
+func getTargetIndexArguments(d, v Expression) (AttributeDesignator, AttributeValue, bool) {
+	ad, ok := d.(AttributeDesignator)
+	if !ok {
+		return AttributeDesignator{}, UndefinedValue, false
+	}
+
+	av, ok := v.(AttributeValue)
+	if !ok {
+		return AttributeDesignator{}, UndefinedValue, false
+	}
+
+	return ad, av, true
+}
+
+type targetIndexNetwork struct {
+	n   *net.IPNet
+	idx []int
+}
+
+// makeTargetIndexNetworks puts networks to the tree from wider to narrower
+// ones. Tree lookup gives the narrowest network containing an address so each
+// network keeps indices of all networks which contain it.
+func makeTargetIndexNetworks(nets map[string]*targetIndexNetwork) *iptree.Tree {
+	list := make([]*targetIndexNetwork, 0, len(nets))
+	for _, n := range nets {
+		list = append(list, n)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		a, _ := list[i].n.Mask.Size()
+		b, _ := list[j].n.Mask.Size()
+		if a != b {
+			return a < b
+		}
+
+		return list[i].n.String() < list[j].n.String()
+	})
+
+	t := iptree.NewTree()
+	for _, n := range list {
+		idx := n.idx
+		if v, ok := t.GetByNet(n.n); ok {
+			idx = mergeTargetIndices(v.([]int), idx)
+		}
+
+		t.InplaceInsertNet(n.n, idx)
+	}
+
+	return t
+}
+
+type targetIndexDomain struct {
+	d   domain.Name
+	idx []int
+}
+
+// makeTargetIndexDomains puts domains to the tree from top level to deeper
+// ones. Tree lookup gives the deepest domain for a subdomain so each domain
+// keeps indices of all its parent domains.
+func makeTargetIndexDomains(doms map[string]*targetIndexDomain) *domaintree.Node {
+	list := make([]*targetIndexDomain, 0, len(doms))
+	labels := make(map[*targetIndexDomain]int, len(doms))
+	for _, d := range doms {
+		list = append(list, d)
+
+		n := 0
+		d.d.GetLabels(func(string) error {
+			n++
+			return nil
+		})
+
+		labels[d] = n
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		a := labels[list[i]]
+		b := labels[list[j]]
+		if a != b {
+			return a < b
+		}
+
+		return list[i].d.String() < list[j].d.String()
+	})
+
+	t := &domaintree.Node{}
+	for _, d := range list {
+		idx := d.idx
+		if v, ok := t.Get(d.d); ok {
+			idx = mergeTargetIndices(v.([]int), idx)
+		}
+
+		t.InplaceInsert(d.d, idx)
+	}
+
+	return t
+}
+
+func appendTargetIndex(idx []int, i int) []int {
+	if n := len(idx); n > 0 && idx[n-1] == i {
+		return idx
+	}
+
+	return append(idx, i)
+}
+
+// mergeTargetIndices merges two sorted lists of indices into new sorted list
+// without duplicates. It returns one of given lists as is if other is empty.
+func mergeTargetIndices(a, b []int) []int {
+	if len(a) <= 0 {
+		return b
+	}
+
+	if len(b) <= 0 {
+		return a
+	}
+
+	out := make([]int, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			out = append(out, a[i])
+			i++
+
+		case a[i] > b[j]:
+			out = append(out, b[j])
+			j++
+
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+
+	out = append(out, a[i:]...)
+	return append(out, b[j:]...)
+}
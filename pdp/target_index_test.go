@@ -0,0 +1,192 @@
+package pdp
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/infobloxopen/go-trees/domaintree"
+	"github.com/infobloxopen/go-trees/iptree"
+	"github.com/infobloxopen/go-trees/strtree"
+)
+
+func TestTargetIndexStrings(t *testing.T) {
+	policies := []Evaluable{}
+	for i := 0; i < 12; i++ {
+		policies = append(policies, makeTargetIndexTestPolicy(fmt.Sprintf("p%d", i), EffectPermit,
+			makeSimpleStringTarget("s", fmt.Sprintf("v%d", i%6))))
+	}
+
+	set := strtree.NewTree()
+	set.InplaceInsert("v1", nil)
+	set.InplaceInsert("v7", nil)
+
+	policies = append(policies,
+		makeTargetIndexTestPolicy("always", EffectDeny, MakeTarget()),
+		makeTargetIndexTestPolicy("set", EffectDeny, Target{a: []AnyOf{{a: []AllOf{{m: []Match{{
+			m: functionSetOfStringsContains{
+				set:   MakeSetOfStringsValue(set),
+				value: MakeStringDesignator("s")}}}}}}}}),
+		makeTargetIndexTestPolicy("any", EffectPermit, Target{a: []AnyOf{{a: []AllOf{
+			{m: []Match{{m: functionStringEqual{first: MakeStringValue("v3"), second: MakeStringDesignator("s")}}}},
+			{m: []Match{{m: functionStringEqual{first: MakeStringDesignator("s"), second: MakeStringValue("v8")}}}},
+		}}}}),
+		makeTargetIndexTestPolicy("other", EffectDeny, makeSimpleStringTarget("o", "v1")),
+	)
+
+	ctxs := []*Context{
+		{a: map[string]interface{}{}},
+		{a: map[string]interface{}{"s": MakeBooleanValue(true)}},
+		{a: map[string]interface{}{"s": MakeStringValue("v1")}},
+		{a: map[string]interface{}{"s": MakeStringValue("v3")}},
+		{a: map[string]interface{}{"s": MakeStringValue("v7")}},
+		{a: map[string]interface{}{"s": MakeStringValue("v8")}},
+		{a: map[string]interface{}{"s": MakeStringValue("v9")}},
+		{a: map[string]interface{}{"s": MakeStringValue("v9"), "o": MakeStringValue("v1")}},
+	}
+
+	for _, name := range []string{"firstapplicableeffect", "denyoverrides"} {
+		p := NewPolicySet("test", false, MakeTarget(), policies, PolicyCombiningAlgs[name], nil, nil)
+		if p.index == nil {
+			t.Fatalf("Expected target index for %q policy set but got nothing", name)
+		}
+
+		assertTargetIndexDecisions(t, name, p, ctxs...)
+
+		idx, ok := p.index.lookup(ctxs[4])
+		e := []int{12, 13, 15}
+		if !ok || !reflect.DeepEqual(idx, e) {
+			t.Errorf("Expected %v candidates for %q policy set but got %v (%v)", e, name, idx, ok)
+		}
+	}
+
+	p := NewPolicySet("test", false, MakeTarget(), policies, PolicyCombiningAlgs["permitoverrides"], nil, nil)
+	if p.index != nil {
+		t.Errorf("Expected no target index for permitOverrides policy set but got %#v", p.index)
+	}
+}
+
+func TestTargetIndexNetworks(t *testing.T) {
+	nets := []string{
+		"10.0.0.0/8", "10.1.0.0/16", "10.1.1.0/24", "192.0.2.0/24",
+		"2001:db8::/32", "2001:db8:1::/48", "0.0.0.0/0", "10.1.0.0/16",
+	}
+
+	rules := []*Rule{}
+	for i, n := range nets {
+		rules = append(rules, NewRule(fmt.Sprintf("r%d", i), false, Target{a: []AnyOf{{a: []AllOf{{m: []Match{{
+			m: functionNetworkContainsAddress{
+				network: MakeNetworkValue(makeTestNetwork(n)),
+				address: MakeAddressDesignator("a")}}}}}}}},
+			nil, EffectPermit, makeSingleStringObligation("r", fmt.Sprintf("r%d", i))))
+	}
+
+	set := iptree.NewTree()
+	set.InplaceInsertNet(makeTestNetwork("10.1.1.0/24"), nil)
+	set.InplaceInsertNet(makeTestNetwork("2001:db8:1:1::/64"), nil)
+	rules = append(rules, NewRule("set", false, Target{a: []AnyOf{{a: []AllOf{{m: []Match{{
+		m: functionSetOfNetworksContainsAddress{
+			set:   MakeSetOfNetworksValue(set),
+			value: MakeAddressDesignator("a")}}}}}}}},
+		nil, EffectDeny, makeSingleStringObligation("r", "set")))
+
+	ctxs := []*Context{{a: map[string]interface{}{}}}
+	for _, a := range []string{"10.1.1.1", "10.1.2.1", "10.2.0.1", "192.0.2.1", "203.0.113.1",
+		"2001:db8:1:1::1", "2001:db8:2::1", "2001:db9::1"} {
+		ctxs = append(ctxs, &Context{a: map[string]interface{}{"a": MakeAddressValue(net.ParseIP(a))}})
+	}
+
+	for _, name := range []string{"firstapplicableeffect", "denyoverrides"} {
+		p := NewPolicy("test", false, MakeTarget(), rules, RuleCombiningAlgs[name], nil, nil)
+		if p.index == nil {
+			t.Fatalf("Expected target index for %q policy but got nothing", name)
+		}
+
+		assertTargetIndexDecisions(t, name, p, ctxs...)
+	}
+}
+
+func TestTargetIndexDomains(t *testing.T) {
+	doms := []string{
+		"com", "example.com", "www.example.com", "example.net",
+		"test.example.net", "example.org", "www.example.org", "example.com",
+	}
+
+	policies := []Evaluable{}
+	for i, d := range doms {
+		set := &domaintree.Node{}
+		set.InplaceInsert(makeTestDomain(d), nil)
+
+		policies = append(policies, makeTargetIndexTestPolicy(fmt.Sprintf("p%d", i), EffectPermit,
+			Target{a: []AnyOf{{a: []AllOf{{m: []Match{{
+				m: functionSetOfDomainsContains{
+					set:   MakeSetOfDomainsValue(set),
+					value: MakeDomainDesignator("d")}}}}}}}}))
+	}
+
+	ctxs := []*Context{{a: map[string]interface{}{}}}
+	for _, d := range []string{"www.example.com", "mail.example.com", "test.example.net",
+		"example.net", "www.example.org", "example.edu"} {
+		ctxs = append(ctxs, &Context{a: map[string]interface{}{"d": MakeDomainValue(makeTestDomain(d))}})
+	}
+
+	p := NewPolicySet("test", false, MakeTarget(), policies, PolicyCombiningAlgs["denyoverrides"], nil, nil)
+	if p.index == nil {
+		t.Fatalf("Expected target index for policy set but got nothing")
+	}
+
+	assertTargetIndexDecisions(t, "denyoverrides", p, ctxs...)
+
+	e, err := p.Delete([]string{"p1"})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	assertTargetIndexDecisions(t, "denyoverrides after delete", e, ctxs...)
+}
+
+func TestMergeTargetIndices(t *testing.T) {
+	idx := mergeTargetIndices([]int{0, 3, 5, 9}, []int{1, 3, 4, 10, 11})
+	e := []int{0, 1, 3, 4, 5, 9, 10, 11}
+	if !reflect.DeepEqual(idx, e) {
+		t.Errorf("Expected %v but got %v", e, idx)
+	}
+}
+
+func makeTargetIndexTestPolicy(ID string, effect int, target Target) *Policy {
+	return NewPolicy(ID, false, target,
+		[]*Rule{NewRule("rule", false, MakeTarget(), nil, effect, makeSingleStringObligation("p", ID))},
+		makeFirstApplicableEffectRCA, nil, nil,
+	)
+}
+
+func assertTargetIndexDecisions(t *testing.T, desc string, e Evaluable, ctxs ...*Context) {
+	var plain Evaluable
+	switch e := e.(type) {
+	case *PolicySet:
+		p := *e
+		p.index = nil
+		plain = &p
+
+	case *Policy:
+		p := *e
+		p.index = nil
+		plain = &p
+
+	default:
+		t.Fatalf("Expected policy set or policy but got %T", e)
+	}
+
+	for i, ctx := range ctxs {
+		r := e.Calculate(ctx)
+		er := plain.Calculate(ctx)
+		if r.Effect != er.Effect ||
+			fmt.Sprintf("%v", r.Status) != fmt.Sprintf("%v", er.Status) ||
+			!reflect.DeepEqual(r.Obligations, er.Obligations) {
+			t.Errorf("Expected for %s and request %d:\n\t%s, %v, %v\nbut got:\n\t%s, %v, %v", desc, i,
+				effectNames[er.Effect], er.Status, er.Obligations,
+				effectNames[r.Effect], r.Status, r.Obligations)
+		}
+	}
+}
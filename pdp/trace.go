@@ -0,0 +1,339 @@
+package pdp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// TraceAttribute is a name of boolean request attribute which asks PDP server
+// to record evaluation trace for the request. The server returns the trace
+// in JSON form as string obligation with the same name.
+const TraceAttribute = "themis.trace"
+
+// Trace* constants define kinds of entities which appear in evaluation trace.
+const (
+	// TraceKindPolicySet marks trace node of policy set.
+	TraceKindPolicySet = "policy set"
+	// TraceKindPolicy marks trace node of policy.
+	TraceKindPolicy = "policy"
+	// TraceKindRule marks trace node of rule.
+	TraceKindRule = "rule"
+)
+
+// Trace* constants define possible outcomes of target and condition
+// evaluation in trace node.
+const (
+	// TraceMatched indicates that target matched the request.
+	TraceMatched = "matched"
+	// TraceNotMatched indicates that target didn't match the request.
+	TraceNotMatched = "not matched"
+	// TraceTrue indicates that condition evaluated to true.
+	TraceTrue = "true"
+	// TraceFalse indicates that condition evaluated to false.
+	TraceFalse = "false"
+	// TraceError indicates that target or condition evaluation failed.
+	// Status field of trace node contains details.
+	TraceError = "error"
+)
+
+// Trace represents record of request evaluation. Root holds node of the first
+// evaluated entity (usually root policy set or policy). Selectors contains
+// lookups made outside of any policy set, policy or rule.
+type Trace struct {
+	Root      *TraceNode      `json:"root,omitempty"`
+	Selectors []TraceSelector `json:"selectors,omitempty"`
+}
+
+// TraceNode represents evaluation of particular policy set, policy or rule.
+// Target contains result of target matching and Condition result of rule's
+// condition. Algorithm is a type of combining algorithm used to combine
+// children (in order they have been evaluated). Effect and Status hold final
+// effect of the entity and its error if any. Selectors lists all selector
+// lookups made directly by the entity.
+type TraceNode struct {
+	Kind      string          `json:"kind"`
+	ID        string          `json:"id,omitempty"`
+	Hidden    bool            `json:"hidden,omitempty"`
+	Target    string          `json:"target,omitempty"`
+	Condition string          `json:"condition,omitempty"`
+	Algorithm string          `json:"algorithm,omitempty"`
+	Effect    string          `json:"effect"`
+	Status    string          `json:"status,omitempty"`
+	Selectors []TraceSelector `json:"selectors,omitempty"`
+	Children  []*TraceNode    `json:"children,omitempty"`
+}
+
+// TraceSelector represents selector lookup. Keys contains serialized values
+// of selector path and Value serialized result. Error is set instead of Value
+// if the lookup failed.
+type TraceSelector struct {
+	URI   string   `json:"uri"`
+	Keys  []string `json:"keys"`
+	Value string   `json:"value,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+type tracer struct {
+	trace Trace
+	stack []*TraceNode
+}
+
+// EnableTrace makes context to record evaluation trace. The trace can be
+// obtained with GetTrace after evaluation.
+func (c *Context) EnableTrace() {
+	c.tr = &tracer{}
+}
+
+// GetTrace returns evaluation trace recorded by the context or nil if
+// tracing hasn't been enabled.
+func (c *Context) GetTrace() *Trace {
+	if c == nil || c.tr == nil {
+		return nil
+	}
+
+	return &c.tr.trace
+}
+
+// IsTraceRequested returns true if request contains boolean attribute
+// TraceAttribute with true value.
+func (c *Context) IsTraceRequested() bool {
+	if c == nil {
+		return false
+	}
+
+	v, err := c.getAttribute(MakeAttribute(TraceAttribute, TypeBoolean))
+	if err != nil {
+		return false
+	}
+
+	b, err := v.boolean()
+	return err == nil && b
+}
+
+// TraceSelector records selector lookup to evaluation trace. Selector
+// implementations should call it after each evaluation with the selector's
+// URI, path and evaluation result. It does nothing if tracing isn't enabled.
+func (c *Context) TraceSelector(uri string, path []Expression, v AttributeValue, err error) {
+	if c == nil || c.tr == nil {
+		return
+	}
+
+	s := TraceSelector{
+		URI:  uri,
+		Keys: make([]string, len(path)),
+	}
+
+	for i, e := range path {
+		s.Keys[i] = describeTraceValue(e.Calculate(c))
+	}
+
+	if err != nil {
+		s.Error = err.Error()
+	} else {
+		s.Value = describeTraceValue(v, nil)
+	}
+
+	if n := len(c.tr.stack); n > 0 {
+		node := c.tr.stack[n-1]
+		node.Selectors = append(node.Selectors, s)
+	} else {
+		c.tr.trace.Selectors = append(c.tr.trace.Selectors, s)
+	}
+}
+
+func (c *Context) traceEnter(kind, ID string, hidden bool) *TraceNode {
+	if c == nil || c.tr == nil && c.cov == nil {
+		return nil
+	}
+
+	n := &TraceNode{
+		Kind:   kind,
+		Hidden: hidden,
+	}
+
+	if !hidden {
+		n.ID = ID
+	}
+
+	if c.tr == nil {
+		// Coverage collection needs evaluation results but not the trace.
+		return n
+	}
+
+	if k := len(c.tr.stack); k > 0 {
+		parent := c.tr.stack[k-1]
+		parent.Children = append(parent.Children, n)
+	} else if c.tr.trace.Root == nil {
+		c.tr.trace.Root = n
+	}
+
+	c.tr.stack = append(c.tr.stack, n)
+	return n
+}
+
+func (c *Context) traceLeave(n *TraceNode, r Response) {
+	if n == nil {
+		return
+	}
+
+	n.Effect = EffectNameFromEnum(r.Effect)
+	if r.Status != nil {
+		n.Status = r.Status.Error()
+	}
+
+	if c.tr == nil {
+		return
+	}
+
+	if k := len(c.tr.stack); k > 0 {
+		c.tr.stack = c.tr.stack[:k-1]
+	}
+}
+
+func (n *TraceNode) setTarget(match bool, err error) {
+	if n == nil {
+		return
+	}
+
+	switch {
+	case err != nil:
+		n.Target = TraceError
+
+	case match:
+		n.Target = TraceMatched
+
+	default:
+		n.Target = TraceNotMatched
+	}
+}
+
+func (n *TraceNode) setCondition(c bool, err error) {
+	if n == nil {
+		return
+	}
+
+	switch {
+	case err != nil:
+		n.Condition = TraceError
+
+	case c:
+		n.Condition = TraceTrue
+
+	default:
+		n.Condition = TraceFalse
+	}
+}
+
+func (n *TraceNode) setAlgorithm(a json.Marshaler) {
+	if n == nil {
+		return
+	}
+
+	b, err := a.MarshalJSON()
+	if err != nil {
+		n.Algorithm = fmt.Sprintf("%T", a)
+		return
+	}
+
+	var alg algFmt
+	if err := json.Unmarshal(b, &alg); err != nil || len(alg.Type) <= 0 {
+		n.Algorithm = fmt.Sprintf("%T", a)
+		return
+	}
+
+	n.Algorithm = alg.Type
+}
+
+// String implements Stringer interface and returns human readable indented
+// representation of the trace.
+func (t *Trace) String() string {
+	lines := []string{}
+	for _, s := range t.Selectors {
+		lines = append(lines, s.String())
+	}
+
+	if t.Root != nil {
+		lines = t.Root.appendLines(lines, "")
+	}
+
+	if len(lines) <= 0 {
+		return "no trace"
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (n *TraceNode) appendLines(lines []string, indent string) []string {
+	name := n.Kind
+	if n.Hidden {
+		name = "hidden " + name
+	} else {
+		name = fmt.Sprintf("%s %q", name, n.ID)
+	}
+
+	details := []string{}
+	if len(n.Target) > 0 {
+		details = append(details, "target: "+n.Target)
+	}
+
+	if len(n.Condition) > 0 {
+		details = append(details, "condition: "+n.Condition)
+	}
+
+	if len(n.Algorithm) > 0 {
+		details = append(details, "algorithm: "+n.Algorithm)
+	}
+
+	line := indent + name
+	if len(details) > 0 {
+		line += " (" + strings.Join(details, ", ") + ")"
+	}
+
+	line += ": " + n.Effect
+	if len(n.Status) > 0 {
+		line += " - " + n.Status
+	}
+
+	lines = append(lines, line)
+
+	indent += "  "
+	for _, s := range n.Selectors {
+		lines = append(lines, indent+s.String())
+	}
+
+	for _, child := range n.Children {
+		lines = child.appendLines(lines, indent)
+	}
+
+	return lines
+}
+
+// String implements Stringer interface and returns human readable
+// representation of selector lookup.
+func (s TraceSelector) String() string {
+	keys := make([]string, len(s.Keys))
+	for i, k := range s.Keys {
+		keys[i] = fmt.Sprintf("%q", k)
+	}
+
+	line := fmt.Sprintf("selector %s [%s]", s.URI, strings.Join(keys, ", "))
+	if len(s.Error) > 0 {
+		return line + " failed: " + s.Error
+	}
+
+	return line + fmt.Sprintf(" = %q", s.Value)
+}
+
+func describeTraceValue(v AttributeValue, err error) string {
+	if err != nil {
+		return fmt.Sprintf("<%s>", err)
+	}
+
+	s, err := v.Serialize()
+	if err != nil {
+		return fmt.Sprintf("<%s>", err)
+	}
+
+	return s
+}
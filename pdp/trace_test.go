@@ -0,0 +1,166 @@
+package pdp
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type testTraceSelector struct {
+	path []Expression
+}
+
+func (s testTraceSelector) GetResultType() Type {
+	return TypeString
+}
+
+func (s testTraceSelector) Calculate(ctx *Context) (AttributeValue, error) {
+	v := MakeStringValue("value")
+	ctx.TraceSelector("test:content/item", s.path, v, nil)
+	return v, nil
+}
+
+func TestTrace(t *testing.T) {
+	ctx, err := NewContext(nil, 2, func(i int) (string, AttributeValue, error) {
+		if i == 0 {
+			return "s", MakeStringValue("test"), nil
+		}
+
+		return TraceAttribute, MakeBooleanValue(true), nil
+	})
+	if err != nil {
+		t.Fatalf("Expected context but got error %s", err)
+	}
+
+	if !ctx.IsTraceRequested() {
+		t.Errorf("Expected trace to be requested")
+	}
+
+	p := makeSimplePolicySet("root",
+		NewPolicy("first", false, makeSimpleStringTarget("s", "other"),
+			[]*Rule{makeSimpleRule("unreachable", EffectDeny)}, makeFirstApplicableEffectRCA, nil, nil),
+		makeSimplePolicy("second",
+			NewRule("condition", false, Target{},
+				functionStringEqual{
+					first:  testTraceSelector{path: []Expression{MakeStringDesignator("s")}},
+					second: MakeStringValue("other")},
+				EffectDeny, nil),
+			makeSimpleHiddenRule(EffectPermit),
+		),
+	)
+
+	r := p.Calculate(ctx)
+	if r.Effect != EffectPermit {
+		t.Fatalf("Expected %q but got %q (%s)", effectNames[EffectPermit], effectNames[r.Effect], r.Status)
+	}
+
+	if trace := ctx.GetTrace(); trace != nil {
+		t.Errorf("Expected no trace when tracing isn't enabled but got %#v", trace)
+	}
+
+	ctx.EnableTrace()
+	p.Calculate(ctx)
+
+	e := &Trace{
+		Root: &TraceNode{
+			Kind:      TraceKindPolicySet,
+			ID:        "root",
+			Target:    TraceMatched,
+			Algorithm: "firstApplicableEffectPCA",
+			Effect:    "Permit",
+			Children: []*TraceNode{
+				{
+					Kind:   TraceKindPolicy,
+					ID:     "first",
+					Target: TraceNotMatched,
+					Effect: "NotApplicable",
+				},
+				{
+					Kind:      TraceKindPolicy,
+					ID:        "second",
+					Target:    TraceMatched,
+					Algorithm: "firstApplicableEffectRCA",
+					Effect:    "Permit",
+					Children: []*TraceNode{
+						{
+							Kind:      TraceKindRule,
+							ID:        "condition",
+							Target:    TraceMatched,
+							Condition: TraceFalse,
+							Effect:    "NotApplicable",
+							Selectors: []TraceSelector{
+								{
+									URI:   "test:content/item",
+									Keys:  []string{"test"},
+									Value: "value",
+								},
+							},
+						},
+						{
+							Kind:   TraceKindRule,
+							Hidden: true,
+							Target: TraceMatched,
+							Effect: "Permit",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	trace := ctx.GetTrace()
+	if !reflect.DeepEqual(trace, e) {
+		b, _ := json.Marshal(trace)
+		t.Errorf("Expected trace:\n%s\nbut got:\n%s", e, b)
+	}
+
+	es := "policy set \"root\" (target: matched, algorithm: firstApplicableEffectPCA): Permit\n" +
+		"  policy \"first\" (target: not matched): NotApplicable\n" +
+		"  policy \"second\" (target: matched, algorithm: firstApplicableEffectRCA): Permit\n" +
+		"    rule \"condition\" (target: matched, condition: false): NotApplicable\n" +
+		"      selector test:content/item [\"test\"] = \"value\"\n" +
+		"    hidden rule (target: matched): Permit"
+	if s := trace.String(); s != es {
+		t.Errorf("Expected trace:\n%s\nbut got:\n%s", es, s)
+	}
+
+	b, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	u := new(Trace)
+	if err := json.Unmarshal(b, u); err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	} else if !reflect.DeepEqual(u, e) {
+		t.Errorf("Expected the same trace after JSON round trip but got:\n%s", u)
+	}
+}
+
+func TestTraceErrors(t *testing.T) {
+	ctx := &Context{}
+	if ctx.IsTraceRequested() {
+		t.Errorf("Expected no trace request for context without attributes")
+	}
+
+	ctx.EnableTrace()
+
+	r := NewRule("missing", false, makeSimpleStringTarget("s", "test"), nil, EffectDeny, nil).Calculate(ctx)
+	if r.Effect != EffectIndeterminateD {
+		t.Errorf("Expected %q but got %q", effectNames[EffectIndeterminateD], effectNames[r.Effect])
+	}
+
+	n := ctx.GetTrace().Root
+	if n == nil {
+		t.Fatalf("Expected trace root but got nothing")
+	}
+
+	if n.Target != TraceError || n.Effect != "Indeterminate{D}" || len(n.Status) <= 0 {
+		t.Errorf("Expected failed target with status but got %#v", n)
+	}
+
+	ctx.TraceSelector("test:content/item", nil, UndefinedValue, newMissingValueError())
+	if s := ctx.GetTrace().Selectors; len(s) != 1 || len(s[0].Error) <= 0 {
+		t.Errorf("Expected single failed top-level selector lookup but got %#v", s)
+	}
+}
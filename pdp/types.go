@@ -34,6 +34,10 @@ var (
 	TypeSetOfDomains = newBuiltinType("Set of Domains")
 	// TypeListOfStrings is list of strings data type.
 	TypeListOfStrings = newBuiltinType("List of Strings")
+	// TypeDateTime is date and time (with time zone) data type.
+	TypeDateTime = newBuiltinType("DateTime")
+	// TypeDuration is time duration data type.
+	TypeDuration = newBuiltinType("Duration")
 
 	// BuiltinTypeIDs maps type keys to Type* constants.
 	BuiltinTypes = make(map[string]Type)
@@ -5,6 +5,7 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/infobloxopen/go-trees/domain"
 	"github.com/infobloxopen/go-trees/domaintree"
@@ -104,6 +105,20 @@ func MakeListOfStringsValue(v []string) AttributeValue {
 		v: v}
 }
 
+// MakeDateTimeValue creates instance of date and time attribute value.
+func MakeDateTimeValue(v time.Time) AttributeValue {
+	return AttributeValue{
+		t: TypeDateTime,
+		v: v}
+}
+
+// MakeDurationValue creates instance of duration attribute value.
+func MakeDurationValue(v time.Duration) AttributeValue {
+	return AttributeValue{
+		t: TypeDuration,
+		v: v}
+}
+
 // MakeFlagsValue8 creates instance of given flags value which fits 8 bits integer.
 func MakeFlagsValue8(v uint8, t Type) AttributeValue {
 	if t, ok := t.(*FlagsType); ok {
@@ -229,6 +244,22 @@ func MakeValueFromString(t Type, s string) (AttributeValue, error) {
 		}
 
 		return MakeDomainValue(d), nil
+
+	case TypeDateTime:
+		d, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return UndefinedValue, newInvalidDateTimeStringCastError(s, err)
+		}
+
+		return MakeDateTimeValue(d), nil
+
+	case TypeDuration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return UndefinedValue, newInvalidDurationStringCastError(s, err)
+		}
+
+		return MakeDurationValue(d), nil
 	}
 
 	return UndefinedValue, newUnknownTypeStringCastError(t)
@@ -343,6 +374,12 @@ func (v AttributeValue) describe() string {
 		}
 
 		return fmt.Sprintf("[%s]", strings.Join(s, ", "))
+
+	case TypeDateTime:
+		return fmt.Sprintf("datetime(%s)", v.v.(time.Time).Format(time.RFC3339Nano))
+
+	case TypeDuration:
+		return fmt.Sprintf("duration(%s)", v.v.(time.Duration))
 	}
 
 	return "val(unknown type)"
@@ -476,6 +513,24 @@ func (v AttributeValue) listOfStrings() ([]string, error) {
 	return v.v.([]string), nil
 }
 
+func (v AttributeValue) dateTime() (time.Time, error) {
+	err := v.typeCheck(TypeDateTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return v.v.(time.Time), nil
+}
+
+func (v AttributeValue) duration() (time.Duration, error) {
+	err := v.typeCheck(TypeDuration)
+	if err != nil {
+		return 0, err
+	}
+
+	return v.v.(time.Duration), nil
+}
+
 func (v AttributeValue) flags8() (uint8, error) {
 	err := v.flagsTypeCheckN(8)
 	if err != nil {
@@ -532,6 +587,82 @@ func (v AttributeValue) flags() (uint64, error) {
 	return v.v.(uint64), nil
 }
 
+// GetBoolean returns boolean value or error if the value has other type.
+func (v AttributeValue) GetBoolean() (bool, error) {
+	return v.boolean()
+}
+
+// GetString returns string value or error if the value has other type.
+func (v AttributeValue) GetString() (string, error) {
+	return v.str()
+}
+
+// GetInteger returns integer value or error if the value has other type.
+func (v AttributeValue) GetInteger() (int64, error) {
+	return v.integer()
+}
+
+// GetFloat returns float value or error if the value has other type.
+func (v AttributeValue) GetFloat() (float64, error) {
+	return v.float()
+}
+
+// GetAddress returns address value or error if the value has other type.
+func (v AttributeValue) GetAddress() (net.IP, error) {
+	return v.address()
+}
+
+// GetNetwork returns network value or error if the value has other type.
+func (v AttributeValue) GetNetwork() (*net.IPNet, error) {
+	return v.network()
+}
+
+// GetDomain returns domain value or error if the value has other type.
+func (v AttributeValue) GetDomain() (domain.Name, error) {
+	return v.domain()
+}
+
+// GetSetOfStrings returns set of strings value or error if the value has
+// other type.
+func (v AttributeValue) GetSetOfStrings() (*strtree.Tree, error) {
+	return v.setOfStrings()
+}
+
+// GetSetOfNetworks returns set of networks value or error if the value has
+// other type.
+func (v AttributeValue) GetSetOfNetworks() (*iptree.Tree, error) {
+	return v.setOfNetworks()
+}
+
+// GetSetOfDomains returns set of domains value or error if the value has
+// other type.
+func (v AttributeValue) GetSetOfDomains() (*domaintree.Node, error) {
+	return v.setOfDomains()
+}
+
+// GetListOfStrings returns list of strings value or error if the value has
+// other type.
+func (v AttributeValue) GetListOfStrings() ([]string, error) {
+	return v.listOfStrings()
+}
+
+// GetDateTime returns date and time value or error if the value has other
+// type.
+func (v AttributeValue) GetDateTime() (time.Time, error) {
+	return v.dateTime()
+}
+
+// GetDuration returns duration value or error if the value has other type.
+func (v AttributeValue) GetDuration() (time.Duration, error) {
+	return v.duration()
+}
+
+// GetFlags returns flags value of any capacity as 64-bit integer or error
+// if the value isn't flags.
+func (v AttributeValue) GetFlags() (uint64, error) {
+	return v.flags()
+}
+
 // Calculate implements Expression interface and returns calculated value
 func (v AttributeValue) Calculate(ctx *Context) (AttributeValue, error) {
 	return v, nil
@@ -602,6 +733,12 @@ func (v AttributeValue) Serialize() (string, error) {
 
 	case TypeListOfStrings:
 		return serializeListOfStrings(v.v.([]string)), nil
+
+	case TypeDateTime:
+		return v.v.(time.Time).Format(time.RFC3339Nano), nil
+
+	case TypeDuration:
+		return v.v.(time.Duration).String(), nil
 	}
 
 	return "", newUnknownTypeSerializationError(v.t)
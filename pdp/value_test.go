@@ -472,6 +472,42 @@ func TestMakeValueFromSting(t *testing.T) {
 		t.Errorf("Expected *invalidDomainNameStringCastError but got %T (%s)", err, err)
 	}
 
+	v, err = MakeValueFromString(TypeDateTime, "2019-01-04T17:30:00.5+03:00")
+	if err != nil {
+		t.Errorf("Expected datetime attribute value but got error: %s", err)
+	} else {
+		expDesc := "datetime(2019-01-04T17:30:00.5+03:00)"
+		d := v.describe()
+		if d != expDesc {
+			t.Errorf("Expected %q as value description but got %q", expDesc, d)
+		}
+	}
+
+	v, err = MakeValueFromString(TypeDateTime, "2019-01-04 17:30")
+	if err == nil {
+		t.Errorf("Expected error but got value: %s", v.describe())
+	} else if _, ok := err.(*invalidDateTimeStringCastError); !ok {
+		t.Errorf("Expected *invalidDateTimeStringCastError but got %T (%s)", err, err)
+	}
+
+	v, err = MakeValueFromString(TypeDuration, "1h30m")
+	if err != nil {
+		t.Errorf("Expected duration attribute value but got error: %s", err)
+	} else {
+		expDesc := "duration(1h30m0s)"
+		d := v.describe()
+		if d != expDesc {
+			t.Errorf("Expected %q as value description but got %q", expDesc, d)
+		}
+	}
+
+	v, err = MakeValueFromString(TypeDuration, "90 minutes")
+	if err == nil {
+		t.Errorf("Expected error but got value: %s", v.describe())
+	} else if _, ok := err.(*invalidDurationStringCastError); !ok {
+		t.Errorf("Expected *invalidDurationStringCastError but got %T (%s)", err, err)
+	}
+
 	ft, err := NewFlagsType("flags", "first", "second", "third")
 	if err != nil {
 		t.Errorf("Expected no error but got %s", err)
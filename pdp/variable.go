@@ -0,0 +1,191 @@
+package pdp
+
+// Variable represents named expression defined in variables section of
+// policy set or policy (or at the root of policy file). Variable is
+// an expression itself and rules refer it by pointer. Value of the variable
+// is calculated on first use and is kept in request context so the nested
+// expression is calculated at most once per request.
+type Variable struct {
+	id string
+	e  Expression
+}
+
+type variableValue struct {
+	v   AttributeValue
+	err error
+}
+
+// NewVariable creates variable with given name and expression.
+func NewVariable(ID string, e Expression) *Variable {
+	return &Variable{
+		id: ID,
+		e:  e,
+	}
+}
+
+// GetID returns name of the variable.
+func (v *Variable) GetID() string {
+	return v.id
+}
+
+// GetResultType implements Expression interface and returns type of
+// the variable's expression.
+func (v *Variable) GetResultType() Type {
+	return v.e.GetResultType()
+}
+
+// WalkArguments implements ExpressionWalker interface and visits
+// the variable's expression.
+func (v *Variable) WalkArguments(visit func(e Expression)) {
+	visit(v.e)
+}
+
+// Calculate implements Expression interface and returns value of
+// the variable's expression. The value (or error) is memoized in the context.
+func (v *Variable) Calculate(ctx *Context) (AttributeValue, error) {
+	if r, ok := ctx.vars[v]; ok {
+		return r.v, r.err
+	}
+
+	r, err := v.e.Calculate(ctx)
+	if err != nil {
+		err = bindErrorf(err, "variable %q", v.id)
+	}
+
+	if ctx.vars == nil {
+		ctx.vars = make(map[*Variable]variableValue)
+	}
+
+	ctx.vars[v] = variableValue{v: r, err: err}
+	return r, err
+}
+
+// variableScope keeps variables defined at some level of policy hierarchy.
+// Scopes of nested policy sets and policies are identified by their IDs.
+type variableScope struct {
+	vars     map[string]*Variable
+	children map[string]*variableScope
+}
+
+func newVariableScope() *variableScope {
+	return &variableScope{
+		vars:     make(map[string]*Variable),
+		children: make(map[string]*variableScope),
+	}
+}
+
+// getChild returns scope of nested policy set or policy with given ID. It
+// creates the scope if it doesn't exist.
+func (s *variableScope) getChild(ID string) *variableScope {
+	child, ok := s.children[ID]
+	if !ok {
+		child = newVariableScope()
+		s.children[ID] = child
+	}
+
+	return child
+}
+
+// lookupChild returns scope of nested policy set or policy with given ID or
+// nil if there is no such scope. It's safe to call the method for nil scope.
+func (s *variableScope) lookupChild(ID string) *variableScope {
+	if s == nil {
+		return nil
+	}
+
+	return s.children[ID]
+}
+
+// replace returns copy of the scope where scope at given path is replaced
+// by child (or removed if child is nil). Only scopes along the path are
+// copied so the original scope and its children stay unchanged.
+func (s *variableScope) replace(path []string, child *variableScope) *variableScope {
+	if len(path) <= 0 {
+		return s
+	}
+
+	out := newVariableScope()
+	if s != nil {
+		out.vars = s.vars
+		for k, v := range s.children {
+			out.children[k] = v
+		}
+	}
+
+	ID := path[0]
+	if len(path) > 1 {
+		next := s.lookupChild(ID)
+		if next == nil && child == nil {
+			return out
+		}
+
+		out.children[ID] = next.replace(path[1:], child)
+		return out
+	}
+
+	if child != nil {
+		out.children[ID] = child
+	} else {
+		delete(out.children, ID)
+	}
+
+	return out
+}
+
+// equalVariables checks if both scopes define the same variables. It
+// doesn't compare nested scopes. Nil scope stands for scope without
+// variables.
+func equalVariables(a, b *variableScope) bool {
+	var av, bv map[string]*Variable
+	if a != nil {
+		av = a.vars
+	}
+
+	if b != nil {
+		bv = b.vars
+	}
+
+	if len(av) != len(bv) {
+		return false
+	}
+
+	for k, v := range av {
+		if w, ok := bv[k]; !ok || !equalExpressions(v, w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// count returns number of variables in the scope and all its children.
+func (s *variableScope) count() int {
+	n := len(s.vars)
+	for _, child := range s.children {
+		n += child.count()
+	}
+
+	return n
+}
+
+// graft puts variables of linked policy document to scope of its root
+// policy with given ID. Document level variables are put to the same scope
+// unless the policy defines variables with the same names.
+func (s *variableScope) graft(ID string, doc *variableScope) {
+	child := s.getChild(ID)
+	if p, ok := doc.children[ID]; ok {
+		for k, v := range p.vars {
+			child.vars[k] = v
+		}
+
+		for k, v := range p.children {
+			child.children[k] = v
+		}
+	}
+
+	for k, v := range doc.vars {
+		if _, ok := child.vars[k]; !ok {
+			child.vars[k] = v
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package pdp
+
+import "testing"
+
+type countingExpression struct {
+	n *int
+}
+
+func (e countingExpression) GetResultType() Type {
+	return TypeString
+}
+
+func (e countingExpression) Calculate(ctx *Context) (AttributeValue, error) {
+	*e.n++
+	return MakeStringDesignator("s").Calculate(ctx)
+}
+
+func TestVariable(t *testing.T) {
+	n := 0
+	v := NewVariable("v", countingExpression{n: &n})
+	if v.GetResultType() != TypeString {
+		t.Errorf("Expected %q type but got %q", TypeString, v.GetResultType())
+	}
+
+	ctx := &Context{a: map[string]interface{}{"s": MakeStringValue("test")}}
+	for i := 0; i < 3; i++ {
+		r, err := v.Calculate(ctx)
+		if err != nil {
+			t.Errorf("Expected no error but got %s", err)
+		} else if s, err := r.str(); err != nil || s != "test" {
+			t.Errorf("Expected %q but got %s (%v)", "test", r.describe(), err)
+		}
+	}
+
+	if n != 1 {
+		t.Errorf("Expected expression to be calculated once but got %d", n)
+	}
+
+	ctx = &Context{a: map[string]interface{}{}}
+	for i := 0; i < 2; i++ {
+		if r, err := v.Calculate(ctx); err == nil {
+			t.Errorf("Expected error but got %s", r.describe())
+		}
+	}
+
+	if n != 2 {
+		t.Errorf("Expected expression to be calculated once per request but got %d", n)
+	}
+}
@@ -0,0 +1,199 @@
+package pdp
+
+import "encoding/json"
+
+var (
+	permitOverridesPCAInstance   = permitOverridesPCA{}
+	denyUnlessPermitPCAInstance  = denyUnlessPermitPCA{}
+	permitUnlessDenyPCAInstance  = permitUnlessDenyPCA{}
+	onlyOneApplicablePCAInstance = onlyOneApplicablePCA{}
+)
+
+type permitOverridesPCA struct {
+}
+
+func makePermitOverridesPCA(policies []Evaluable, params interface{}) PolicyCombiningAlg {
+	return permitOverridesPCAInstance
+}
+
+func (permitOverridesPCA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(algFmt{
+		Type: "permitOverridesPCA",
+	})
+}
+
+func (a permitOverridesPCA) describe() string {
+	return "permit overrides"
+}
+
+func (a permitOverridesPCA) execute(policies []Evaluable, ctx *Context) Response {
+	errs := []error{}
+	obligations := make([]AttributeAssignment, 0)
+
+	indetD := 0
+	indetP := 0
+	indetDP := 0
+
+	denies := 0
+
+	for _, p := range policies {
+		r := p.Calculate(ctx)
+		if r.Effect == EffectPermit {
+			return r
+		}
+
+		if r.Effect == EffectDeny {
+			denies++
+			obligations = append(obligations, r.Obligations...)
+			continue
+		}
+
+		if r.Effect == EffectNotApplicable {
+			continue
+		}
+
+		if r.Effect == EffectIndeterminateD {
+			indetD++
+		} else {
+			if r.Effect == EffectIndeterminateP {
+				indetP++
+			} else {
+				indetDP++
+			}
+
+		}
+
+		errs = append(errs, r.Status)
+	}
+
+	var err boundError
+	if len(errs) > 1 {
+		err = bindError(newMultiError(errs), a.describe())
+	} else if len(errs) > 0 {
+		err = bindError(errs[0], a.describe())
+	}
+
+	if indetDP > 0 || (indetP > 0 && (indetD > 0 || denies > 0)) {
+		return Response{EffectIndeterminateDP, err, nil}
+	}
+
+	if indetP > 0 {
+		return Response{EffectIndeterminateP, err, nil}
+	}
+
+	if denies > 0 {
+		return Response{EffectDeny, nil, obligations}
+	}
+
+	if indetD > 0 {
+		return Response{EffectIndeterminateD, err, nil}
+	}
+
+	return Response{EffectNotApplicable, nil, nil}
+}
+
+type denyUnlessPermitPCA struct {
+}
+
+func makeDenyUnlessPermitPCA(policies []Evaluable, params interface{}) PolicyCombiningAlg {
+	return denyUnlessPermitPCAInstance
+}
+
+func (denyUnlessPermitPCA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(algFmt{
+		Type: "denyUnlessPermitPCA",
+	})
+}
+
+func (a denyUnlessPermitPCA) execute(policies []Evaluable, ctx *Context) Response {
+	obligations := make([]AttributeAssignment, 0)
+
+	for _, p := range policies {
+		r := p.Calculate(ctx)
+		if r.Effect == EffectPermit {
+			return r
+		}
+
+		if r.Effect == EffectDeny {
+			obligations = append(obligations, r.Obligations...)
+		}
+	}
+
+	return Response{EffectDeny, nil, obligations}
+}
+
+type permitUnlessDenyPCA struct {
+}
+
+func makePermitUnlessDenyPCA(policies []Evaluable, params interface{}) PolicyCombiningAlg {
+	return permitUnlessDenyPCAInstance
+}
+
+func (permitUnlessDenyPCA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(algFmt{
+		Type: "permitUnlessDenyPCA",
+	})
+}
+
+func (a permitUnlessDenyPCA) execute(policies []Evaluable, ctx *Context) Response {
+	obligations := make([]AttributeAssignment, 0)
+
+	for _, p := range policies {
+		r := p.Calculate(ctx)
+		if r.Effect == EffectDeny {
+			return r
+		}
+
+		if r.Effect == EffectPermit {
+			obligations = append(obligations, r.Obligations...)
+		}
+	}
+
+	return Response{EffectPermit, nil, obligations}
+}
+
+type onlyOneApplicablePCA struct {
+}
+
+func makeOnlyOneApplicablePCA(policies []Evaluable, params interface{}) PolicyCombiningAlg {
+	return onlyOneApplicablePCAInstance
+}
+
+func (onlyOneApplicablePCA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(algFmt{
+		Type: "onlyOneApplicablePCA",
+	})
+}
+
+func (a onlyOneApplicablePCA) describe() string {
+	return "only one applicable"
+}
+
+func (a onlyOneApplicablePCA) execute(policies []Evaluable, ctx *Context) Response {
+	var (
+		selected Evaluable
+		count    int
+	)
+
+	for _, p := range policies {
+		match, err := p.isApplicable(ctx)
+		if err != nil {
+			return Response{EffectIndeterminate, bindError(bindError(err, p.describe()), a.describe()), nil}
+		}
+
+		if match {
+			count++
+			selected = p
+		}
+	}
+
+	if count > 1 {
+		return Response{EffectIndeterminate, bindError(newTooManyApplicableError(count), a.describe()), nil}
+	}
+
+	if selected != nil {
+		return selected.Calculate(ctx)
+	}
+
+	return Response{EffectNotApplicable, nil, nil}
+}
@@ -0,0 +1,133 @@
+package pdp
+
+import "testing"
+
+func TestPermitOverridesPCA(t *testing.T) {
+	c := &Context{
+		a: map[string]interface{}{
+			"test-string": MakeStringValue("test")}}
+
+	permit := makeSimplePermitPolicyWithObligations("Permit", makeSingleStringObligation("p", "permit"))
+	deny := NewPolicy("Deny", false, Target{}, []*Rule{makeSimpleHiddenRule(EffectDeny)},
+		makeFirstApplicableEffectRCA, nil, makeSingleStringObligation("d", "deny"))
+	indetP := NewPolicy("IndetP", false, Target{}, []*Rule{
+		NewRule("", true, makeSimpleStringTarget("missing", "test"), nil, EffectPermit, nil),
+	}, makeFirstApplicableEffectRCA, nil, nil)
+	indetD := NewPolicy("IndetD", false, Target{}, []*Rule{
+		NewRule("", true, makeSimpleStringTarget("missing", "test"), nil, EffectDeny, nil),
+	}, makeFirstApplicableEffectRCA, nil, nil)
+
+	assertPCAEffect(t, "permit overrides with permit", makePermitOverridesPCA, c, EffectPermit, 1,
+		deny, indetD, indetP, permit)
+	assertPCAEffect(t, "permit overrides with deny", makePermitOverridesPCA, c, EffectDeny, 2,
+		deny, makeSimplePolicy("NotApplicable"), deny)
+	assertPCAEffect(t, "permit overrides with deny and indeterminate{p}", makePermitOverridesPCA, c,
+		EffectIndeterminateDP, 0, deny, indetP)
+	assertPCAEffect(t, "permit overrides with indeterminate{p}", makePermitOverridesPCA, c,
+		EffectIndeterminateP, 0, indetP)
+	assertPCAEffect(t, "permit overrides with indeterminate{d}", makePermitOverridesPCA, c,
+		EffectIndeterminateD, 0, indetD)
+	assertPCAEffect(t, "permit overrides with no policies", makePermitOverridesPCA, c,
+		EffectNotApplicable, 0)
+}
+
+func TestDenyUnlessPermitPCA(t *testing.T) {
+	c := &Context{}
+
+	permit := makeSimplePermitPolicyWithObligations("Permit", makeSingleStringObligation("p", "permit"))
+	deny := NewPolicy("Deny", false, Target{}, []*Rule{makeSimpleHiddenRule(EffectDeny)},
+		makeFirstApplicableEffectRCA, nil, makeSingleStringObligation("d", "deny"))
+
+	assertPCAEffect(t, "deny unless permit with permit", makeDenyUnlessPermitPCA, c, EffectPermit, 1,
+		deny, permit)
+	assertPCAEffect(t, "deny unless permit with deny", makeDenyUnlessPermitPCA, c, EffectDeny, 2,
+		deny, makeSimplePolicy("NotApplicable"), deny)
+	assertPCAEffect(t, "deny unless permit with no policies", makeDenyUnlessPermitPCA, c, EffectDeny, 0)
+}
+
+func TestPermitUnlessDenyPCA(t *testing.T) {
+	c := &Context{}
+
+	permit := makeSimplePermitPolicyWithObligations("Permit", makeSingleStringObligation("p", "permit"))
+	deny := NewPolicy("Deny", false, Target{}, []*Rule{makeSimpleHiddenRule(EffectDeny)},
+		makeFirstApplicableEffectRCA, nil, makeSingleStringObligation("d", "deny"))
+
+	assertPCAEffect(t, "permit unless deny with deny", makePermitUnlessDenyPCA, c, EffectDeny, 1,
+		permit, deny)
+	assertPCAEffect(t, "permit unless deny with permit", makePermitUnlessDenyPCA, c, EffectPermit, 2,
+		permit, makeSimplePolicy("NotApplicable"), permit)
+	assertPCAEffect(t, "permit unless deny with no policies", makePermitUnlessDenyPCA, c, EffectPermit, 0)
+}
+
+func TestOnlyOneApplicablePCA(t *testing.T) {
+	c := &Context{
+		a: map[string]interface{}{
+			"test-string": MakeStringValue("test")}}
+
+	permit := NewPolicy("Permit", false, makeSimpleStringTarget("test-string", "test"),
+		[]*Rule{makeSimpleHiddenRule(EffectPermit)}, makeFirstApplicableEffectRCA, nil, nil)
+	deny := NewPolicy("Deny", false, makeSimpleStringTarget("test-string", "example"),
+		[]*Rule{makeSimpleHiddenRule(EffectDeny)}, makeFirstApplicableEffectRCA, nil, nil)
+	empty := NewPolicy("Empty", false, makeSimpleStringTarget("test-string", "test"),
+		nil, makeFirstApplicableEffectRCA, nil, nil)
+	missing := NewPolicy("Missing", false, makeSimpleStringTarget("missing", "test"),
+		[]*Rule{makeSimpleHiddenRule(EffectDeny)}, makeFirstApplicableEffectRCA, nil, nil)
+
+	assertPCAEffect(t, "only one applicable with single permit", makeOnlyOneApplicablePCA, c, EffectPermit, 0,
+		deny, permit)
+	assertPCAEffect(t, "only one applicable with matching target and no rules", makeOnlyOneApplicablePCA, c,
+		EffectIndeterminate, 0, permit, empty)
+	assertPCAEffect(t, "only one applicable with target error", makeOnlyOneApplicablePCA, c,
+		EffectIndeterminate, 0, permit, missing)
+	assertPCAEffect(t, "only one applicable with no applicable policies", makeOnlyOneApplicablePCA, c,
+		EffectNotApplicable, 0, deny)
+
+	r := NewPolicySet("test", false, Target{}, []Evaluable{permit, empty}, makeOnlyOneApplicablePCA, nil,
+		nil).Calculate(c)
+	if _, ok := r.Status.(*tooManyApplicableError); !ok {
+		t.Errorf("Expected *tooManyApplicableError for only one applicable with two policies but got %T (%s)",
+			r.Status, r.Status)
+	}
+}
+
+func TestXACMLPCAInMapper(t *testing.T) {
+	c := &Context{
+		a: map[string]interface{}{
+			"x": MakeListOfStringsValue([]string{"Deny", "Permit"})}}
+
+	deny := makeSimplePolicy("Deny", makeSimpleHiddenRule(EffectDeny))
+	permit := makeSimplePolicy("Permit", makeSimpleHiddenRule(EffectPermit))
+
+	p := NewPolicySet("test", false, Target{}, []Evaluable{deny, permit}, makeMapperPCA, MapperPCAParams{
+		Argument:  MakeListOfStringsDesignator("x"),
+		Algorithm: makePermitUnlessDenyPCA(nil, nil),
+	}, nil)
+
+	r := p.Calculate(c)
+	if r.Effect != EffectDeny {
+		t.Errorf("Expected %q for mapper with permit unless deny but got %q (%s)",
+			effectNames[EffectDeny], effectNames[r.Effect], r.Status)
+	}
+
+	b, err := p.algorithm.(mapperPCA).MarshalJSON()
+	if err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	} else {
+		e := `{"type":"mapperPCA","def":"\"\"","err":"\"\"","alg":{"type":"permitUnlessDenyPCA"}}`
+		if string(b) != e {
+			t.Errorf("Expected %s but got %s", e, b)
+		}
+	}
+}
+
+func assertPCAEffect(t *testing.T, desc string, maker PolicyCombiningAlgMaker, ctx *Context,
+	effect, obligations int, policies ...Evaluable) {
+	r := NewPolicySet("test", false, Target{}, policies, maker, nil, nil).Calculate(ctx)
+	if r.Effect != effect {
+		t.Errorf("Expected %q for %s but got %q (%s)", effectNames[effect], desc, effectNames[r.Effect], r.Status)
+	}
+
+	if len(r.Obligations) != obligations {
+		t.Errorf("Expected %d obligations for %s but got %d", obligations, desc, len(r.Obligations))
+	}
+}
@@ -0,0 +1,201 @@
+package pdp
+
+import "encoding/json"
+
+var (
+	permitOverridesRCAInstance   = permitOverridesRCA{}
+	denyUnlessPermitRCAInstance  = denyUnlessPermitRCA{}
+	permitUnlessDenyRCAInstance  = permitUnlessDenyRCA{}
+	onlyOneApplicableRCAInstance = onlyOneApplicableRCA{}
+)
+
+type permitOverridesRCA struct {
+}
+
+func makePermitOverridesRCA(rules []*Rule, params interface{}) RuleCombiningAlg {
+	return permitOverridesRCAInstance
+}
+
+func (permitOverridesRCA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(algFmt{
+		Type: "permitOverridesRCA",
+	})
+}
+
+func (a permitOverridesRCA) describe() string {
+	return "permit overrides"
+}
+
+func (a permitOverridesRCA) execute(rules []*Rule, ctx *Context) Response {
+	errs := []error{}
+	obligations := make([]AttributeAssignment, 0)
+
+	indetD := 0
+	indetP := 0
+	indetDP := 0
+
+	denies := 0
+
+	for _, rule := range rules {
+		r := rule.calculate(ctx)
+		if r.Effect == EffectPermit {
+			return r
+		}
+
+		if r.Effect == EffectDeny {
+			denies++
+			obligations = append(obligations, r.Obligations...)
+			continue
+		}
+
+		if r.Effect == EffectNotApplicable {
+			continue
+		}
+
+		if r.Effect == EffectIndeterminateD {
+			indetD++
+		} else {
+			if r.Effect == EffectIndeterminateP {
+				indetP++
+			} else {
+				indetDP++
+			}
+
+		}
+
+		errs = append(errs, r.Status)
+	}
+
+	var err boundError
+	if len(errs) > 1 {
+		err = bindError(newMultiError(errs), a.describe())
+	} else if len(errs) > 0 {
+		err = bindError(errs[0], a.describe())
+	}
+
+	if indetDP > 0 || (indetP > 0 && (indetD > 0 || denies > 0)) {
+		return Response{EffectIndeterminateDP, err, nil}
+	}
+
+	if indetP > 0 {
+		return Response{EffectIndeterminateP, err, nil}
+	}
+
+	if denies > 0 {
+		return Response{EffectDeny, nil, obligations}
+	}
+
+	if indetD > 0 {
+		return Response{EffectIndeterminateD, err, nil}
+	}
+
+	return Response{EffectNotApplicable, nil, nil}
+}
+
+type denyUnlessPermitRCA struct {
+}
+
+func makeDenyUnlessPermitRCA(rules []*Rule, params interface{}) RuleCombiningAlg {
+	return denyUnlessPermitRCAInstance
+}
+
+func (denyUnlessPermitRCA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(algFmt{
+		Type: "denyUnlessPermitRCA",
+	})
+}
+
+func (a denyUnlessPermitRCA) execute(rules []*Rule, ctx *Context) Response {
+	obligations := make([]AttributeAssignment, 0)
+
+	for _, rule := range rules {
+		r := rule.calculate(ctx)
+		if r.Effect == EffectPermit {
+			return r
+		}
+
+		if r.Effect == EffectDeny {
+			obligations = append(obligations, r.Obligations...)
+		}
+	}
+
+	return Response{EffectDeny, nil, obligations}
+}
+
+type permitUnlessDenyRCA struct {
+}
+
+func makePermitUnlessDenyRCA(rules []*Rule, params interface{}) RuleCombiningAlg {
+	return permitUnlessDenyRCAInstance
+}
+
+func (permitUnlessDenyRCA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(algFmt{
+		Type: "permitUnlessDenyRCA",
+	})
+}
+
+func (a permitUnlessDenyRCA) execute(rules []*Rule, ctx *Context) Response {
+	obligations := make([]AttributeAssignment, 0)
+
+	for _, rule := range rules {
+		r := rule.calculate(ctx)
+		if r.Effect == EffectDeny {
+			return r
+		}
+
+		if r.Effect == EffectPermit {
+			obligations = append(obligations, r.Obligations...)
+		}
+	}
+
+	return Response{EffectPermit, nil, obligations}
+}
+
+type onlyOneApplicableRCA struct {
+}
+
+func makeOnlyOneApplicableRCA(rules []*Rule, params interface{}) RuleCombiningAlg {
+	return onlyOneApplicableRCAInstance
+}
+
+func (onlyOneApplicableRCA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(algFmt{
+		Type: "onlyOneApplicableRCA",
+	})
+}
+
+func (a onlyOneApplicableRCA) describe() string {
+	return "only one applicable"
+}
+
+func (a onlyOneApplicableRCA) execute(rules []*Rule, ctx *Context) Response {
+	var (
+		res   Response
+		count int
+	)
+
+	for _, rule := range rules {
+		r := rule.calculate(ctx)
+		if r.Effect == EffectNotApplicable {
+			continue
+		}
+
+		if r.Effect != EffectDeny && r.Effect != EffectPermit {
+			return Response{EffectIndeterminate, bindError(r.Status, a.describe()), nil}
+		}
+
+		count++
+		res = r
+	}
+
+	if count > 1 {
+		return Response{EffectIndeterminate, bindError(newTooManyApplicableError(count), a.describe()), nil}
+	}
+
+	if count > 0 {
+		return res
+	}
+
+	return Response{EffectNotApplicable, nil, nil}
+}
@@ -0,0 +1,126 @@
+package pdp
+
+import "testing"
+
+func TestPermitOverridesRCA(t *testing.T) {
+	c := &Context{
+		a: map[string]interface{}{
+			"test-string": MakeStringValue("test")}}
+
+	permit := makeSimplePermitRuleWithObligations("Permit", makeSingleStringObligation("p", "permit"))
+	deny := NewRule("Deny", false, Target{}, nil, EffectDeny, makeSingleStringObligation("d", "deny"))
+	indetP := NewRule("IndetP", false, makeSimpleStringTarget("missing", "test"), nil, EffectPermit, nil)
+	indetD := NewRule("IndetD", false, makeSimpleStringTarget("missing", "test"), nil, EffectDeny, nil)
+	notApplicable := NewRule("NotApplicable", false, makeSimpleStringTarget("test-string", "example"), nil,
+		EffectPermit, nil)
+
+	assertRCAEffect(t, "permit overrides with permit", makePermitOverridesRCA, c, EffectPermit, 1,
+		deny, indetD, indetP, permit)
+	assertRCAEffect(t, "permit overrides with deny", makePermitOverridesRCA, c, EffectDeny, 2,
+		deny, notApplicable, deny)
+	assertRCAEffect(t, "permit overrides with deny and indeterminate{d}", makePermitOverridesRCA, c, EffectDeny, 1,
+		indetD, deny)
+	assertRCAEffect(t, "permit overrides with deny and indeterminate{p}", makePermitOverridesRCA, c,
+		EffectIndeterminateDP, 0, deny, indetP)
+	assertRCAEffect(t, "permit overrides with indeterminate{p}", makePermitOverridesRCA, c,
+		EffectIndeterminateP, 0, indetP)
+	assertRCAEffect(t, "permit overrides with indeterminate{d}", makePermitOverridesRCA, c,
+		EffectIndeterminateD, 0, indetD)
+	assertRCAEffect(t, "permit overrides with no rules", makePermitOverridesRCA, c,
+		EffectNotApplicable, 0)
+}
+
+func TestDenyUnlessPermitRCA(t *testing.T) {
+	c := &Context{}
+
+	permit := makeSimplePermitRuleWithObligations("Permit", makeSingleStringObligation("p", "permit"))
+	deny := NewRule("Deny", false, Target{}, nil, EffectDeny, makeSingleStringObligation("d", "deny"))
+	indetP := NewRule("IndetP", false, makeSimpleStringTarget("missing", "test"), nil, EffectPermit, nil)
+
+	assertRCAEffect(t, "deny unless permit with permit", makeDenyUnlessPermitRCA, c, EffectPermit, 1,
+		deny, indetP, permit)
+	assertRCAEffect(t, "deny unless permit with deny and indeterminate", makeDenyUnlessPermitRCA, c,
+		EffectDeny, 2, deny, indetP, deny)
+	assertRCAEffect(t, "deny unless permit with no rules", makeDenyUnlessPermitRCA, c, EffectDeny, 0)
+}
+
+func TestPermitUnlessDenyRCA(t *testing.T) {
+	c := &Context{}
+
+	permit := makeSimplePermitRuleWithObligations("Permit", makeSingleStringObligation("p", "permit"))
+	deny := NewRule("Deny", false, Target{}, nil, EffectDeny, makeSingleStringObligation("d", "deny"))
+	indetD := NewRule("IndetD", false, makeSimpleStringTarget("missing", "test"), nil, EffectDeny, nil)
+
+	assertRCAEffect(t, "permit unless deny with deny", makePermitUnlessDenyRCA, c, EffectDeny, 1,
+		permit, indetD, deny)
+	assertRCAEffect(t, "permit unless deny with permit and indeterminate", makePermitUnlessDenyRCA, c,
+		EffectPermit, 2, permit, indetD, permit)
+	assertRCAEffect(t, "permit unless deny with no rules", makePermitUnlessDenyRCA, c, EffectPermit, 0)
+}
+
+func TestOnlyOneApplicableRCA(t *testing.T) {
+	c := &Context{
+		a: map[string]interface{}{
+			"test-string": MakeStringValue("test")}}
+
+	permit := makeSimplePermitRuleWithObligations("Permit", makeSingleStringObligation("p", "permit"))
+	deny := NewRule("Deny", false, makeSimpleStringTarget("test-string", "example"), nil, EffectDeny, nil)
+	indetD := NewRule("IndetD", false, makeSimpleStringTarget("missing", "test"), nil, EffectDeny, nil)
+
+	assertRCAEffect(t, "only one applicable with single permit", makeOnlyOneApplicableRCA, c, EffectPermit, 1,
+		deny, permit)
+	assertRCAEffect(t, "only one applicable with two permits", makeOnlyOneApplicableRCA, c, EffectIndeterminate, 0,
+		permit, deny, permit)
+	assertRCAEffect(t, "only one applicable with indeterminate", makeOnlyOneApplicableRCA, c, EffectIndeterminate, 0,
+		permit, indetD)
+	assertRCAEffect(t, "only one applicable with no applicable rules", makeOnlyOneApplicableRCA, c,
+		EffectNotApplicable, 0, deny)
+
+	r := NewPolicy("test", false, Target{}, []*Rule{permit, permit}, makeOnlyOneApplicableRCA, nil, nil).Calculate(c)
+	if _, ok := r.Status.(*tooManyApplicableError); !ok {
+		t.Errorf("Expected *tooManyApplicableError for only one applicable with two permits but got %T (%s)",
+			r.Status, r.Status)
+	}
+}
+
+func TestXACMLRCAInMapper(t *testing.T) {
+	c := &Context{
+		a: map[string]interface{}{
+			"x": MakeListOfStringsValue([]string{"Deny", "Permit"})}}
+
+	deny := makeSimpleRule("Deny", EffectDeny)
+	permit := makeSimpleRule("Permit", EffectPermit)
+
+	p := NewPolicy("test", false, Target{}, []*Rule{deny, permit}, makeMapperRCA, MapperRCAParams{
+		Argument:  MakeListOfStringsDesignator("x"),
+		Algorithm: makePermitOverridesRCA(nil, nil),
+	}, nil)
+
+	r := p.Calculate(c)
+	if r.Effect != EffectPermit {
+		t.Errorf("Expected %q for mapper with permit overrides but got %q (%s)",
+			effectNames[EffectPermit], effectNames[r.Effect], r.Status)
+	}
+
+	b, err := p.algorithm.(mapperRCA).MarshalJSON()
+	if err != nil {
+		t.Errorf("Expected no error but got %s", err)
+	} else {
+		e := `{"type":"mapperRCA","def":"\"\"","err":"\"\"","alg":{"type":"permitOverridesRCA"}}`
+		if string(b) != e {
+			t.Errorf("Expected %s but got %s", e, b)
+		}
+	}
+}
+
+func assertRCAEffect(t *testing.T, desc string, maker RuleCombiningAlgMaker, ctx *Context, effect, obligations int,
+	rules ...*Rule) {
+	r := NewPolicy("test", false, Target{}, rules, maker, nil, nil).Calculate(ctx)
+	if r.Effect != effect {
+		t.Errorf("Expected %q for %s but got %q (%s)", effectNames[effect], desc, effectNames[r.Effect], r.Status)
+	}
+
+	if len(r.Obligations) != obligations {
+		t.Errorf("Expected %d obligations for %s but got %d", obligations, desc, len(r.Obligations))
+	}
+}
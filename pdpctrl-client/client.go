@@ -30,6 +30,51 @@ func (e *TagError) Error() string {
 	return e.tag
 }
 
+// Version describes policy or content version which PDP server keeps for
+// rollback. Timestamp is time when the version has been applied. Current flag
+// is set for version which server currently uses.
+type Version struct {
+	Tag       string
+	Timestamp time.Time
+	Current   bool
+}
+
+// Status describes state of PDP server. Ready flag is set if server serves
+// decision requests. PolicyTag is empty for untagged policies.
+type Status struct {
+	Ready     bool
+	PolicyTag string
+	Symbols   SymbolsSummary
+	Contents  []ContentStatus
+	Queue     []QueueEntry
+}
+
+// SymbolsSummary holds number of custom types, attributes and variables
+// defined by policies.
+type SymbolsSummary struct {
+	Types      int
+	Attributes int
+	Variables  int
+}
+
+// ContentStatus describes content loaded to PDP server.
+type ContentStatus struct {
+	ID    string
+	Tag   string
+	Items int
+}
+
+// QueueEntry describes upload request pending on PDP server. ContentID is
+// empty for policy uploads. Uploaded flag is set if data has been uploaded
+// and waits for apply.
+type QueueEntry struct {
+	ID        int32
+	ContentID string
+	FromTag   string
+	ToTag     string
+	Uploaded  bool
+}
+
 // Client structure represents client side of PDP control protocol. It's
 // responsible for establishing connection and uploading data to PDP server.
 type Client struct {
@@ -167,6 +212,146 @@ func (c *Client) NotifyReady() error {
 	return nil
 }
 
+// ListPoliciesVersions returns policy versions kept by server with the most
+// recent one first.
+func (c *Client) ListPoliciesVersions() ([]Version, error) {
+	return c.listVersions(&pb.VersionRequest{Type: pb.Item_POLICIES})
+}
+
+// ListContentVersions returns versions of content with given id kept by
+// server with the most recent one first.
+func (c *Client) ListContentVersions(id string) ([]Version, error) {
+	return c.listVersions(&pb.VersionRequest{
+		Type: pb.Item_CONTENT,
+		Id:   id})
+}
+
+// RollbackPolicies makes server to switch to its policy version with given
+// tag. If server doesn't keep such version, the method returns TagError.
+func (c *Client) RollbackPolicies(tag string) error {
+	return c.rollback(&pb.RollbackRequest{
+		Type: pb.Item_POLICIES,
+		Tag:  tag})
+}
+
+// RollbackContent makes server to switch to version with given tag of
+// content with given id. If server doesn't keep such version, the method
+// returns TagError.
+func (c *Client) RollbackContent(id, tag string) error {
+	return c.rollback(&pb.RollbackRequest{
+		Type: pb.Item_CONTENT,
+		Id:   id,
+		Tag:  tag})
+}
+
+// DeleteContent removes content with given id from server. If tag isn't
+// empty the content is removed only if it has the tag otherwise the method
+// returns TagError.
+func (c *Client) DeleteContent(id, tag string) error {
+	r, err := c.client.DeleteContent(context.Background(), &pb.DeleteRequest{
+		Id:  id,
+		Tag: tag})
+	if err != nil {
+		return err
+	}
+
+	switch r.Status {
+	case pb.Response_ACK:
+		return nil
+
+	case pb.Response_TAG_ERROR:
+		return &TagError{tag: r.Details}
+	}
+
+	return errors.New(r.Details)
+}
+
+// Status returns current state of PDP server.
+func (c *Client) Status() (*Status, error) {
+	r, err := c.client.Status(context.Background(), &pb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Status != pb.Response_ACK {
+		return nil, errors.New(r.Details)
+	}
+
+	out := &Status{
+		Ready:     r.Ready,
+		PolicyTag: r.PolicyTag,
+		Contents:  make([]ContentStatus, len(r.Contents)),
+		Queue:     make([]QueueEntry, len(r.Queue)),
+	}
+
+	if r.Symbols != nil {
+		out.Symbols = SymbolsSummary{
+			Types:      int(r.Symbols.Types),
+			Attributes: int(r.Symbols.Attributes),
+			Variables:  int(r.Symbols.Variables),
+		}
+	}
+
+	for i, cs := range r.Contents {
+		out.Contents[i] = ContentStatus{
+			ID:    cs.Id,
+			Tag:   cs.Tag,
+			Items: int(cs.Items),
+		}
+	}
+
+	for i, e := range r.Queue {
+		out.Queue[i] = QueueEntry{
+			ID:        e.Id,
+			ContentID: e.ContentId,
+			FromTag:   e.FromTag,
+			ToTag:     e.ToTag,
+			Uploaded:  e.Uploaded,
+		}
+	}
+
+	return out, nil
+}
+
+func (c *Client) listVersions(req *pb.VersionRequest) ([]Version, error) {
+	r, err := c.client.ListVersions(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Status != pb.Response_ACK {
+		return nil, errors.New(r.Details)
+	}
+
+	out := make([]Version, len(r.Versions))
+	for i, v := range r.Versions {
+		out[i] = Version{
+			Tag:       v.Tag,
+			Timestamp: time.Unix(0, v.Timestamp),
+			Current:   v.Current,
+		}
+	}
+
+	return out, nil
+}
+
+func (c *Client) rollback(req *pb.RollbackRequest) error {
+	r, err := c.client.Rollback(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	switch r.Status {
+	case pb.Response_ACK:
+		return nil
+
+	case pb.Response_TAG_ERROR:
+		return &TagError{tag: r.Details}
+	}
+
+	return errors.New(r.Details)
+}
+
 func (c *Client) request(item *pb.Item) (int32, error) {
 	r, err := c.client.Request(context.Background(), item)
 	if err != nil {
@@ -25,7 +25,7 @@ var policyParsers = map[string]ast.Parser{
 }
 
 type config struct {
-	policy              string
+	policy              stringSet
 	policyParser        ast.Parser
 	content             stringSet
 	serviceEP           string
@@ -38,6 +38,7 @@ type config struct {
 	maxStreams          uint
 	autoResponseSize    bool
 	maxResponseSize     uint
+	decisionTrace       bool
 	memStatsLogPath     string
 	memStatsLogInterval time.Duration
 	memProfDumpPath     string
@@ -46,6 +47,8 @@ type config struct {
 	pipNoCache          bool
 	pipCacheTTL         time.Duration
 	pipCacheMaxSize     int
+	stateDir            string
+	historySize         int
 }
 
 type stringSet []string
@@ -65,7 +68,7 @@ func parseCommandLine() {
 	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 
 	verbose := flag.Int("v", 1, "log verbosity (0 - error, 1 - warn (default), 2 - info, 3 - debug)")
-	flag.StringVar(&conf.policy, "p", "", "policy file to start with")
+	flag.Var(&conf.policy, "p", "policy file or directory to start with (can be repeated)")
 	policyFmt := flag.String("pfmt", policyFormatNameYAML, "policy data format \"yaml\" or \"json\"")
 	flag.Var(&conf.content, "j", "JSON content files to start with")
 	flag.StringVar(&conf.serviceEP, "l", ":5555", "listen for decision requests on this address:port")
@@ -78,6 +81,7 @@ func parseCommandLine() {
 	flag.UintVar(&conf.maxStreams, "max-streams", 0, "maximum number of parallel gRPC streams (0 - use gRPC default)")
 	flag.BoolVar(&conf.autoResponseSize, "auto-response", false, "automatic respose buffer allocation")
 	flag.UintVar(&conf.maxResponseSize, "max-response", 10240, "maximal response size")
+	flag.BoolVar(&conf.decisionTrace, "decision-trace", false, "allow clients to request evaluation trace")
 
 	flag.StringVar(&conf.memStatsLogPath, "mem-stats-log", "mem-stats.log", "file to log memory allocator statistics")
 	flag.DurationVar(&conf.memStatsLogInterval, "mem-stats-interval", -1,
@@ -95,6 +99,12 @@ func parseCommandLine() {
 	flag.IntVar(&conf.pipCacheMaxSize, "pip-cache-size", 10*1024*1024,
 		"enables pip selector cache and sets its size limit")
 
+	flag.StringVar(&conf.stateDir, "state", "",
+		"directory to keep policies and content applied via control interface across restarts")
+
+	flag.IntVar(&conf.historySize, "history", 0,
+		"number of tagged policy and content versions to keep for rollback (zero - no history)")
+
 	flag.Parse()
 
 	initLogging(*verbose)
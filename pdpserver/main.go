@@ -40,6 +40,7 @@ func main() {
 		server.WithMaxGRPCStreams(uint32(conf.maxStreams)),
 		server.WithAutoResponseSize(conf.autoResponseSize),
 		server.WithMaxResponseSize(uint32(conf.maxResponseSize)),
+		server.WithDecisionTrace(conf.decisionTrace),
 		server.WithMemStatsLogging(
 			conf.memStatsLogPath,
 			conf.memStatsLogInterval,
@@ -49,15 +50,17 @@ func main() {
 			uint32(conf.memProfNumGC),
 			conf.memProfDelay,
 		),
+		server.WithStateDir(conf.stateDir),
+		server.WithHistorySize(conf.historySize),
 	)
 
 	pdp.InitializeSelectors()
 
-	err := pdp.LoadPolicies(conf.policy)
+	err := pdp.LoadPolicies(conf.policy...)
 	if err != nil {
 		logger.WithFields(
 			log.Fields{
-				"policy": conf.policy,
+				"policy": conf.policy.String(),
 				"err":    err,
 			},
 		).Error("Failed to load policy. Continue with no policy...")
@@ -68,6 +71,16 @@ func main() {
 		logger.WithField("err", err).Error("Failed to load content. Continue with no content...")
 	}
 
+	err = pdp.LoadState()
+	if err != nil {
+		logger.WithFields(
+			log.Fields{
+				"state": conf.stateDir,
+				"err":   err,
+			},
+		).Fatal("Failed to load state")
+	}
+
 	runtime.GC()
 
 	err = pdp.Serve()
@@ -29,6 +29,15 @@ func controlFail(err error) *pb.Response {
 		case *pdp.UntaggedContentModificationError, *pdp.MissingContentTagError, *pdp.ContentTagsNotMatchError:
 			status = pb.Response_TAG_ERROR
 		}
+
+	case *contentDeleteError:
+		switch e.err.(type) {
+		case *pdp.UntaggedContentModificationError, *pdp.ContentTagsNotMatchError:
+			status = pb.Response_TAG_ERROR
+		}
+
+	case *unknownVersionError:
+		status = pb.Response_TAG_ERROR
 	}
 
 	return &pb.Response{
@@ -122,6 +131,10 @@ func (s *Server) Upload(stream pb.PDPControl_UploadServer) error {
 		return stream.SendAndClose(controlFail(newUnknownUploadError(id)))
 	}
 
+	if s.st != nil {
+		r.record()
+	}
+
 	if req.fromTag == nil {
 		if req.policy {
 			err = s.uploadPolicy(id, r, req, stream)
@@ -150,6 +163,9 @@ func (s *Server) Apply(ctx context.Context, in *pb.Update) (*pb.Response, error)
 		return controlFail(newUnknownUploadedRequestError(in.Id)), nil
 	}
 
+	s.applying.Lock()
+	defer s.applying.Unlock()
+
 	var (
 		res *pb.Response
 		err error
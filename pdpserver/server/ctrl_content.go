@@ -1,6 +1,9 @@
 package server
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 
@@ -31,6 +34,7 @@ func (s *Server) uploadContent(id int32, r *streamReader, req *item, stream pb.P
 	}
 
 	req.c = c
+	req.raw = r.bytes()
 	nid, err := s.q.push(req)
 	if err != nil {
 		return stream.SendAndClose(controlFail(newContentUploadStoreError(id, err)))
@@ -63,6 +67,7 @@ func (s *Server) uploadContentUpdate(id int32, r *streamReader, req *item, strea
 	}
 
 	req.ct = t
+	req.raw = r.bytes()
 	nid, err := s.q.push(req)
 	if err != nil {
 		return stream.SendAndClose(controlFail(newContentUpdateUploadStoreError(id, req, err)))
@@ -73,9 +78,15 @@ func (s *Server) uploadContentUpdate(id int32, r *streamReader, req *item, strea
 
 func (s *Server) applyContent(id int32, req *item) (*pb.Response, error) {
 	if req.c != nil {
+		d := makeStateData(req)
+		if err := s.saveState(d); err != nil {
+			return controlFail(newStateSaveError(id, err)), nil
+		}
+
 		s.Lock()
 		s.c = s.c.Add(req.c)
 		s.Unlock()
+		s.pushVersion(d, req.toTag, nil, req.c)
 
 		if req.toTag == nil {
 			s.opts.logger.WithField("id", id).Info("New content has been applied")
@@ -89,17 +100,33 @@ func (s *Server) applyContent(id int32, req *item) (*pb.Response, error) {
 	}
 
 	if req.ct != nil {
-		s.Lock()
-		c, err := req.ct.Commit(s.c)
-		if err != nil {
-			s.Unlock()
+		s.RLock()
+		c := s.c
+		s.RUnlock()
 
+		// The content could be deleted after the update was uploaded.
+		if _, err := c.GetLocalContent(req.id, req.fromTag); err != nil {
+			return controlFail(newTagCheckError(err)), nil
+		}
+
+		c, err := req.ct.Commit(c)
+		if err != nil {
 			return controlFail(newContentTransactionCommitError(id, req, err)), nil
 		}
 
+		d := makeStateData(req)
+		if err := s.saveState(d); err != nil {
+			return controlFail(newStateSaveError(id, err)), nil
+		}
+
+		s.Lock()
 		s.c = c
 		s.Unlock()
 
+		if lc, err := c.GetLocalContent(req.id, req.toTag); err == nil {
+			s.pushVersion(d, req.toTag, nil, lc)
+		}
+
 		s.opts.logger.WithFields(log.Fields{
 			"id":       id,
 			"cid":      req.id,
@@ -111,3 +138,46 @@ func (s *Server) applyContent(id int32, req *item) (*pb.Response, error) {
 
 	return controlFail(newMissingContentDataApplyError(id, req.id)), nil
 }
+
+// DeleteContent is a server handler for gRPC call
+// It removes content with given id. If tag is set content must have the tag
+func (s *Server) DeleteContent(ctx context.Context, in *pb.DeleteRequest) (*pb.Response, error) {
+	s.opts.logger.WithFields(log.Fields{
+		"cid": in.Id,
+		"tag": in.Tag}).Info("Got content delete command")
+
+	tag, err := newTag(in.Tag)
+	if err != nil {
+		return controlFail(newInvalidFromTagError(in.Tag, err)), nil
+	}
+
+	s.applying.Lock()
+	defer s.applying.Unlock()
+
+	s.RLock()
+	c, err := s.c.Delete(in.Id, tag)
+	s.RUnlock()
+	if err != nil {
+		return controlFail(newContentDeleteError(in.Id, err)), nil
+	}
+
+	d := stateData{
+		h: stateRecord{
+			Op:      stateOpContentDelete,
+			ID:      in.Id,
+			FromTag: in.Tag,
+			Time:    time.Now().UnixNano(),
+		},
+	}
+	if err := s.saveState(d); err != nil {
+		return controlFail(newContentDeleteSaveError(in.Id, err)), nil
+	}
+
+	s.Lock()
+	s.c = c
+	s.Unlock()
+
+	s.opts.logger.WithField("cid", in.Id).Info("Content has been deleted")
+
+	return &pb.Response{Status: pb.Response_ACK}, nil
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	pb "github.com/infobloxopen/themis/pdp-control"
+	"github.com/infobloxopen/themis/pdp/jcon"
+)
+
+func TestDeleteContent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "themis-state")
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pTag1 := uuid.New()
+	pTag2 := uuid.New()
+	cTag1 := uuid.New()
+	cTag2 := uuid.New()
+
+	s := newStateTestServer(t, dir)
+	applyStateTestUploads(t, s, &pTag1, &pTag2, &cTag1)
+
+	// Update uploaded before delete can't bring the content back.
+	ct, err := s.c.NewTransaction("test", &cTag1)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	u, err := jcon.UnmarshalUpdate(strings.NewReader(stateContentUpdate), "test", cTag1, cTag2, ct.Symbols())
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if err := ct.Apply(u); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	req := newContentItem("test", &cTag1, &cTag2)
+	req.ct = ct
+	req.raw = []byte(stateContentUpdate)
+
+	before := s.c
+	assertDeleteContentResponse(t, s, "test", uuid.New().String(), pb.Response_TAG_ERROR)
+	assertDeleteContentResponse(t, s, "missing", "", pb.Response_ERROR)
+	assertDeleteContentResponse(t, s, "test", cTag1.String(), pb.Response_ACK)
+
+	if _, err := before.GetLocalContent("test", &cTag1); err != nil {
+		t.Errorf("Expected content to stay in previous storage but got %s", err)
+	}
+
+	r, err := s.applyContent(4, req)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if r.Status == pb.Response_ACK {
+		t.Errorf("Expected error for update of deleted content but got %s", r.Status)
+	}
+
+	assertDeletedContent(t, "deleted", s)
+	s.st.close()
+
+	s = newStateTestServer(t, dir)
+	assertDeletedContent(t, "restored", s)
+
+	if err := s.st.compact(); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	s.st.close()
+
+	// Content loaded from file stays deleted after restart.
+	logger := log.New()
+	logger.SetOutput(ioutil.Discard)
+
+	s = NewServer(WithLogger(logger), WithStateDir(dir))
+	if err := s.ReadContent(strings.NewReader(stateContent)); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if err := s.LoadState(); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	assertDeletedContent(t, "compacted", s)
+	s.st.close()
+}
+
+func assertDeleteContentResponse(t *testing.T, s *Server, id, tag string, e pb.Response_Status) {
+	r, err := s.DeleteContent(context.Background(), &pb.DeleteRequest{Id: id, Tag: tag})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if r.Status != e {
+		t.Errorf("Expected %s for delete of %q but got %s (%s)", e, id, r.Status, r.Details)
+	}
+}
+
+func assertDeletedContent(t *testing.T, desc string, s *Server) {
+	if _, err := s.c.Get("test", "m"); err == nil {
+		t.Errorf("Expected no content for %s state but got it", desc)
+	}
+}
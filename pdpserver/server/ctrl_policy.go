@@ -29,6 +29,7 @@ func (s *Server) uploadPolicy(id int32, r *streamReader, req *item, stream pb.PD
 	}
 
 	req.p = p
+	req.raw = r.bytes()
 	nid, err := s.q.push(req)
 	if err != nil {
 		return stream.SendAndClose(controlFail(newPolicyUploadStoreError(id, err)))
@@ -66,6 +67,7 @@ func (s *Server) uploadPolicyUpdate(id int32, r *streamReader, req *item, stream
 	}
 
 	req.pt = t
+	req.raw = r.bytes()
 	nid, err := s.q.push(req)
 	if err != nil {
 		return stream.SendAndClose(controlFail(newPolicyUpdateUploadStoreError(id, req, err)))
@@ -76,9 +78,15 @@ func (s *Server) uploadPolicyUpdate(id int32, r *streamReader, req *item, stream
 
 func (s *Server) applyPolicy(id int32, req *item) (*pb.Response, error) {
 	if req.p != nil {
+		d := makeStateData(req)
+		if err := s.saveState(d); err != nil {
+			return controlFail(newStateSaveError(id, err)), nil
+		}
+
 		s.Lock()
 		s.p = req.p
 		s.Unlock()
+		s.pushVersion(d, req.toTag, req.p, nil)
 
 		if req.toTag == nil {
 			s.opts.logger.WithField("id", id).Info("New policy has been applied")
@@ -92,14 +100,30 @@ func (s *Server) applyPolicy(id int32, req *item) (*pb.Response, error) {
 	}
 
 	if req.pt != nil {
+		s.RLock()
+		cur := s.p
+		s.RUnlock()
+
+		// Policy could be changed by other update or rollback after
+		// the update was uploaded.
+		if err := cur.CheckTag(req.fromTag); err != nil {
+			return controlFail(newTagCheckError(err)), nil
+		}
+
 		p, err := req.pt.Commit()
 		if err != nil {
 			return controlFail(newPolicyTransactionCommitError(id, req, err)), nil
 		}
 
+		d := makeStateData(req)
+		if err := s.saveState(d); err != nil {
+			return controlFail(newStateSaveError(id, err)), nil
+		}
+
 		s.Lock()
 		s.p = p
 		s.Unlock()
+		s.pushVersion(d, req.toTag, p, nil)
 
 		s.opts.logger.WithFields(log.Fields{
 			"id":       id,
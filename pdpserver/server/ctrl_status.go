@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+
+	pb "github.com/infobloxopen/themis/pdp-control"
+)
+
+func tagString(tag *uuid.UUID) string {
+	if tag == nil {
+		return ""
+	}
+
+	return tag.String()
+}
+
+// Status is a server handler for gRPC call
+// It returns current policy and content tags, pending uploads and readiness
+func (s *Server) Status(ctx context.Context, in *pb.Empty) (*pb.ServerStatus, error) {
+	s.opts.logger.Info("Got status request")
+
+	s.RLock()
+	p := s.p
+	c := s.c
+	s.RUnlock()
+
+	out := &pb.ServerStatus{
+		Status:  pb.Response_ACK,
+		Ready:   atomic.LoadInt32(&s.ready) != 0,
+		Symbols: &pb.SymbolsSummary{},
+	}
+
+	if p != nil {
+		out.PolicyTag = tagString(p.GetTag())
+
+		sum := p.GetSymbols().Summary()
+		out.Symbols = &pb.SymbolsSummary{
+			Types:      int32(sum.Types),
+			Attributes: int32(sum.Attributes),
+			Variables:  int32(sum.Variables),
+		}
+	}
+
+	for _, lc := range c.GetLocalContents() {
+		out.Contents = append(out.Contents, &pb.ContentStatus{
+			Id:    lc.GetID(),
+			Tag:   tagString(lc.GetTag()),
+			Items: int32(lc.GetItemsCount()),
+		})
+	}
+
+	ids, items := s.q.list()
+	for i, v := range items {
+		e := &pb.QueueEntry{
+			Id:       ids[i],
+			Type:     pb.Item_POLICIES,
+			FromTag:  tagString(v.fromTag),
+			ToTag:    tagString(v.toTag),
+			Uploaded: v.uploaded(),
+		}
+
+		if !v.policy {
+			e.Type = pb.Item_CONTENT
+			e.ContentId = v.id
+		}
+
+		out.Queue = append(out.Queue, e)
+	}
+
+	return out, nil
+}
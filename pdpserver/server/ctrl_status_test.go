@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+
+	pb "github.com/infobloxopen/themis/pdp-control"
+)
+
+func TestStatus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "themis-state")
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pTag1 := uuid.New()
+	pTag2 := uuid.New()
+	cTag1 := uuid.New()
+	cTag2 := uuid.New()
+
+	s := newStateTestServer(t, dir)
+	defer s.st.close()
+
+	r := assertStatusResponse(t, s)
+	if r.Ready || len(r.PolicyTag) > 0 || len(r.Contents) > 0 || len(r.Queue) > 0 {
+		t.Errorf("Expected empty status but got %s", r)
+	}
+
+	applyStateTestUploads(t, s, &pTag1, &pTag2, &cTag1)
+	if _, err := s.contentRequest("test", &cTag1, &cTag2); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	r = assertStatusResponse(t, s)
+	if r.PolicyTag != pTag2.String() {
+		t.Errorf("Expected policy tag %s but got %q", pTag2, r.PolicyTag)
+	}
+
+	if r.Symbols == nil || r.Symbols.Types != 0 || r.Symbols.Attributes != 0 || r.Symbols.Variables != 0 {
+		t.Errorf("Expected empty symbols summary but got %s", r.Symbols)
+	}
+
+	if len(r.Contents) != 1 {
+		t.Errorf("Expected single content but got %d", len(r.Contents))
+	} else if c := r.Contents[0]; c.Id != "test" || c.Tag != cTag1.String() || c.Items != 1 {
+		t.Errorf("Expected content %q with tag %s and 1 item but got %s", "test", cTag1, c)
+	}
+
+	if len(r.Queue) != 1 {
+		t.Errorf("Expected single queue entry but got %d", len(r.Queue))
+	} else if e := r.Queue[0]; e.Type != pb.Item_CONTENT || e.ContentId != "test" ||
+		e.FromTag != cTag1.String() || e.ToTag != cTag2.String() || e.Uploaded {
+		t.Errorf("Expected requested content %q update from %s to %s but got %s", "test", cTag1, cTag2, e)
+	}
+}
+
+func assertStatusResponse(t *testing.T, s *Server) *pb.ServerStatus {
+	r, err := s.Status(context.Background(), &pb.Empty{})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if r.Status != pb.Response_ACK {
+		t.Fatalf("Expected ACK but got %s (%s)", r.Status, r.Details)
+	}
+
+	return r
+}
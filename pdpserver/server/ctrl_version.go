@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	pb "github.com/infobloxopen/themis/pdp-control"
+)
+
+func versionStreamKey(t pb.Item_DataType, id string) (string, error) {
+	switch t {
+	case pb.Item_POLICIES:
+		return streamKey(true, ""), nil
+
+	case pb.Item_CONTENT:
+		return streamKey(false, id), nil
+	}
+
+	return "", newUnknownUploadRequestError(t)
+}
+
+// ListVersions is a server handler for gRPC call
+// It returns policy or content versions kept for rollback
+func (s *Server) ListVersions(ctx context.Context, in *pb.VersionRequest) (*pb.VersionList, error) {
+	s.opts.logger.Info("Got versions request")
+
+	key, err := versionStreamKey(in.Type, in.Id)
+	if err != nil {
+		r := controlFail(err)
+		return &pb.VersionList{Status: r.Status, Details: r.Details}, nil
+	}
+
+	s.RLock()
+	p := s.p
+	c := s.c
+	s.RUnlock()
+
+	vs := s.h.list(key)
+	out := make([]*pb.Version, len(vs))
+	for i, v := range vs {
+		out[i] = &pb.Version{
+			Tag:       v.tag.String(),
+			Timestamp: v.time.UnixNano(),
+			Current:   v.current(p, c),
+		}
+	}
+
+	return &pb.VersionList{Status: pb.Response_ACK, Versions: out}, nil
+}
+
+// Rollback is a server handler for gRPC call
+// It makes policy or content version kept in history current
+func (s *Server) Rollback(ctx context.Context, in *pb.RollbackRequest) (*pb.Response, error) {
+	s.opts.logger.WithField("tag", in.Tag).Info("Got rollback command")
+
+	tag, err := newTag(in.Tag)
+	if err != nil {
+		return controlFail(newInvalidToTagError(in.Tag, err)), nil
+	}
+
+	if tag == nil {
+		return controlFail(newMissingVersionTagError()), nil
+	}
+
+	key, err := versionStreamKey(in.Type, in.Id)
+	if err != nil {
+		return controlFail(err), nil
+	}
+
+	s.applying.Lock()
+	defer s.applying.Unlock()
+
+	v := s.h.get(key, *tag)
+	if v == nil {
+		return controlFail(newUnknownVersionError(in.Tag)), nil
+	}
+
+	if s.st != nil {
+		if len(v.records) <= 0 {
+			return controlFail(newVersionRecordsError(in.Tag)), nil
+		}
+
+		h := stateRecord{
+			Op:    stateOpPolicyRollback,
+			ToTag: tag.String(),
+		}
+		if v.c != nil {
+			h.Op = stateOpContentRollback
+			h.ID = v.c.GetID()
+		}
+
+		b, err := encodeStateRecords(v.records)
+		if err == nil {
+			err = s.st.append(h, b)
+		}
+
+		if err != nil {
+			return controlFail(newRollbackSaveError(in.Tag, err)), nil
+		}
+	}
+
+	s.Lock()
+	if v.p != nil {
+		s.p = v.p
+	} else {
+		s.c = s.c.Add(v.c)
+	}
+	s.Unlock()
+
+	s.h.push(key, v)
+
+	if v.p != nil {
+		s.opts.logger.WithField("tag", in.Tag).Info("Policy has been rolled back")
+	} else {
+		s.opts.logger.WithFields(log.Fields{
+			"cid": in.Id,
+			"tag": in.Tag}).Info("Content has been rolled back")
+	}
+
+	return &pb.Response{Status: pb.Response_ACK}, nil
+}
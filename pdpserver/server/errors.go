@@ -47,6 +47,20 @@ const (
 	contentTransactionCommitErrorID   = 35
 	unknownUploadedRequestErrorID     = 36
 	unsupportedPolicyFromatErrorID    = 37
+	stateSaveErrorID                  = 38
+	stateRecordSizeErrorID            = 39
+	stateRecordChecksumErrorID        = 40
+	stateRecordHeaderErrorID          = 41
+	stateSnapshotEndErrorID           = 42
+	stateFileCorruptionErrorID        = 43
+	unknownStateRecordErrorID         = 44
+	missingVersionTagErrorID          = 45
+	unknownVersionErrorID             = 46
+	versionRecordsErrorID             = 47
+	rollbackSaveErrorID               = 48
+	contentDeleteErrorID              = 49
+	contentDeleteSaveErrorID          = 50
+	traceMarshalErrorID               = 51
 )
 
 type externalError struct {
@@ -672,3 +686,221 @@ func newUnsupportedPolicyFromatError(format string) *unsupportedPolicyFromatErro
 func (e *unsupportedPolicyFromatError) Error() string {
 	return e.errorf("The %s policy format is unsupported. Must be YAML or JSON", e.format)
 }
+
+type stateSaveError struct {
+	errorLink
+	id  int32
+	err error
+}
+
+func newStateSaveError(id int32, err error) *stateSaveError {
+	return &stateSaveError{
+		errorLink: errorLink{id: stateSaveErrorID},
+		id:        id,
+		err:       err}
+}
+
+func (e *stateSaveError) Error() string {
+	return e.errorf("Failed to save request %d to state directory: %s", e.id, e.err)
+}
+
+type stateRecordSizeError struct {
+	errorLink
+	head uint32
+	data uint32
+}
+
+func newStateRecordSizeError(head, data uint32) *stateRecordSizeError {
+	return &stateRecordSizeError{
+		errorLink: errorLink{id: stateRecordSizeErrorID},
+		head:      head,
+		data:      data}
+}
+
+func (e *stateRecordSizeError) Error() string {
+	return e.errorf("Invalid record size (header %d, data %d)", e.head, e.data)
+}
+
+type stateRecordChecksumError struct {
+	errorLink
+}
+
+func newStateRecordChecksumError() *stateRecordChecksumError {
+	return &stateRecordChecksumError{
+		errorLink: errorLink{id: stateRecordChecksumErrorID}}
+}
+
+func (e *stateRecordChecksumError) Error() string {
+	return e.errorf("Record checksum mismatch")
+}
+
+type stateRecordHeaderError struct {
+	errorLink
+	err error
+}
+
+func newStateRecordHeaderError(err error) *stateRecordHeaderError {
+	return &stateRecordHeaderError{
+		errorLink: errorLink{id: stateRecordHeaderErrorID},
+		err:       err}
+}
+
+func (e *stateRecordHeaderError) Error() string {
+	return e.errorf("Can't decode record header: %s", e.err)
+}
+
+type stateSnapshotEndError struct {
+	errorLink
+}
+
+func newStateSnapshotEndError() *stateSnapshotEndError {
+	return &stateSnapshotEndError{
+		errorLink: errorLink{id: stateSnapshotEndErrorID}}
+}
+
+func (e *stateSnapshotEndError) Error() string {
+	return e.errorf("Snapshot has no end record")
+}
+
+type stateFileCorruptionError struct {
+	errorLink
+	path   string
+	offset int64
+	err    error
+}
+
+func newStateFileCorruptionError(path string, offset int64, err error) *stateFileCorruptionError {
+	return &stateFileCorruptionError{
+		errorLink: errorLink{id: stateFileCorruptionErrorID},
+		path:      path,
+		offset:    offset,
+		err:       err}
+}
+
+func (e *stateFileCorruptionError) Error() string {
+	return e.errorf("File %q is damaged at %d: %s", e.path, e.offset, e.err)
+}
+
+type unknownStateRecordError struct {
+	errorLink
+	op string
+}
+
+func newUnknownStateRecordError(op string) *unknownStateRecordError {
+	return &unknownStateRecordError{
+		errorLink: errorLink{id: unknownStateRecordErrorID},
+		op:        op}
+}
+
+func (e *unknownStateRecordError) Error() string {
+	return e.errorf("Unknown state record %q", e.op)
+}
+
+type missingVersionTagError struct {
+	errorLink
+}
+
+func newMissingVersionTagError() *missingVersionTagError {
+	return &missingVersionTagError{
+		errorLink: errorLink{id: missingVersionTagErrorID}}
+}
+
+func (e *missingVersionTagError) Error() string {
+	return e.errorf("Missing tag of version to roll back to")
+}
+
+type unknownVersionError struct {
+	errorLink
+	tag string
+}
+
+func newUnknownVersionError(tag string) *unknownVersionError {
+	return &unknownVersionError{
+		errorLink: errorLink{id: unknownVersionErrorID},
+		tag:       tag}
+}
+
+func (e *unknownVersionError) Error() string {
+	return e.errorf("There is no version with tag %q", e.tag)
+}
+
+type versionRecordsError struct {
+	errorLink
+	tag string
+}
+
+func newVersionRecordsError(tag string) *versionRecordsError {
+	return &versionRecordsError{
+		errorLink: errorLink{id: versionRecordsErrorID},
+		tag:       tag}
+}
+
+func (e *versionRecordsError) Error() string {
+	return e.errorf("Version %q can't be saved to state directory", e.tag)
+}
+
+type rollbackSaveError struct {
+	errorLink
+	tag string
+	err error
+}
+
+func newRollbackSaveError(tag string, err error) *rollbackSaveError {
+	return &rollbackSaveError{
+		errorLink: errorLink{id: rollbackSaveErrorID},
+		tag:       tag,
+		err:       err}
+}
+
+func (e *rollbackSaveError) Error() string {
+	return e.errorf("Failed to save rollback to %q to state directory: %s", e.tag, e.err)
+}
+
+type contentDeleteError struct {
+	errorLink
+	cid string
+	err error
+}
+
+func newContentDeleteError(cid string, err error) *contentDeleteError {
+	return &contentDeleteError{
+		errorLink: errorLink{id: contentDeleteErrorID},
+		cid:       cid,
+		err:       err}
+}
+
+func (e *contentDeleteError) Error() string {
+	return e.errorf("Can't delete content %q: %s", e.cid, e.err)
+}
+
+type contentDeleteSaveError struct {
+	errorLink
+	cid string
+	err error
+}
+
+func newContentDeleteSaveError(cid string, err error) *contentDeleteSaveError {
+	return &contentDeleteSaveError{
+		errorLink: errorLink{id: contentDeleteSaveErrorID},
+		cid:       cid,
+		err:       err}
+}
+
+func (e *contentDeleteSaveError) Error() string {
+	return e.errorf("Failed to save delete of content %q to state directory: %s", e.cid, e.err)
+}
+
+type traceMarshalError struct {
+	errorLink
+	err error
+}
+
+func newTraceMarshalError(err error) *traceMarshalError {
+	return &traceMarshalError{
+		errorLink: errorLink{id: traceMarshalErrorID},
+		err:       err}
+}
+
+func (e *traceMarshalError) Error() string {
+	return e.errorf("Failed to marshal decision trace: %s", e.err)
+}
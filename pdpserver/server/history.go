@@ -0,0 +1,140 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/infobloxopen/themis/pdp"
+)
+
+// version is a committed policy storage or content which server keeps for
+// rollback.
+type version struct {
+	tag  uuid.UUID
+	time time.Time
+
+	p *pdp.PolicyStorage
+	c *pdp.LocalContent
+
+	// records holds uploads which make the version starting from full one.
+	// Server saves them to state directory on rollback. The field is empty
+	// if server has no state directory.
+	records []stateData
+}
+
+// current returns true if the version is used by server with given policy
+// and content storages.
+func (v *version) current(p *pdp.PolicyStorage, c *pdp.LocalContentStorage) bool {
+	if v.p != nil {
+		return v.p == p
+	}
+
+	lc, err := c.GetLocalContent(v.c.GetID(), &v.tag)
+	return err == nil && lc == v.c
+}
+
+// history keeps recent versions of policy and each content (see streamKey)
+// with the most recent version first.
+type history struct {
+	sync.Mutex
+
+	size    int
+	streams map[string][]*version
+}
+
+func newHistory(size int) *history {
+	return &history{
+		size:    size,
+		streams: make(map[string][]*version),
+	}
+}
+
+// push puts version to the front of given stream. It drops a version with
+// the same tag and the oldest versions over history size.
+func (h *history) push(key string, v *version) {
+	if h.size <= 0 {
+		return
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	vs := []*version{v}
+	for _, old := range h.streams[key] {
+		if len(vs) >= h.size {
+			break
+		}
+
+		if old.tag != v.tag {
+			vs = append(vs, old)
+		}
+	}
+
+	h.streams[key] = vs
+}
+
+// head returns the most recent version of given stream.
+func (h *history) head(key string) *version {
+	h.Lock()
+	defer h.Unlock()
+
+	if vs := h.streams[key]; len(vs) > 0 {
+		return vs[0]
+	}
+
+	return nil
+}
+
+// get returns version of given stream with given tag.
+func (h *history) get(key string, tag uuid.UUID) *version {
+	h.Lock()
+	defer h.Unlock()
+
+	for _, v := range h.streams[key] {
+		if v.tag == tag {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// list returns all versions of given stream.
+func (h *history) list(key string) []*version {
+	h.Lock()
+	defer h.Unlock()
+
+	return append([]*version{}, h.streams[key]...)
+}
+
+// pushVersion puts applied policy or content to history. Untagged versions
+// aren't kept as they can't be found for rollback.
+func (s *Server) pushVersion(d stateData, tag *uuid.UUID, p *pdp.PolicyStorage, c *pdp.LocalContent) {
+	if tag == nil || s.h.size <= 0 {
+		return
+	}
+
+	key := d.h.stream()
+	v := &version{
+		tag:  *tag,
+		time: time.Now(),
+		p:    p,
+		c:    c,
+	}
+
+	if d.h.Time != 0 {
+		v.time = time.Unix(0, d.h.Time)
+	}
+
+	if len(s.opts.stateDir) > 0 {
+		if d.h.full() {
+			v.records = []stateData{d}
+		} else if prev := s.h.head(key); prev != nil && len(prev.records) > 0 && prev.tag.String() == d.h.FromTag {
+			v.records = append(prev.records[:len(prev.records):len(prev.records)], d)
+		}
+	}
+
+	s.h.push(key, v)
+}
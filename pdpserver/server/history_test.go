@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/infobloxopen/themis/pdp"
+	pb "github.com/infobloxopen/themis/pdp-control"
+)
+
+func TestHistory(t *testing.T) {
+	h := newHistory(2)
+
+	v1 := &version{tag: uuid.New()}
+	v2 := &version{tag: uuid.New()}
+	v3 := &version{tag: uuid.New()}
+
+	h.push("test", v1)
+	h.push("test", v2)
+	h.push("test", v3)
+	assertHistoryVersions(t, "push", h.list("test"), v3, v2)
+
+	h.push("test", v2)
+	assertHistoryVersions(t, "repeated push", h.list("test"), v2, v3)
+
+	if v := h.get("test", v1.tag); v != nil {
+		t.Errorf("Expected no version %s but got %p", v1.tag, v)
+	}
+
+	if v := h.get("test", v3.tag); v != v3 {
+		t.Errorf("Expected version %s but got %p", v3.tag, v)
+	}
+
+	if v := h.head("other"); v != nil {
+		t.Errorf("Expected no version for other stream but got %p", v)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "themis-state")
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pTag1 := uuid.New()
+	pTag2 := uuid.New()
+	cTag1 := uuid.New()
+	cTag2 := uuid.New()
+
+	s := newStateTestServer(t, dir, WithHistorySize(3))
+	applyStateTestUploads(t, s, &pTag1, &pTag2, &cTag1)
+	applyStateTestUpdate(t, s, &cTag1, &cTag2)
+	assertRollbackVersions(t, "applied", s, pb.Item_POLICIES, "", pTag2, pTag1)
+	assertRollbackVersions(t, "applied", s, pb.Item_CONTENT, "test", cTag2, cTag1)
+
+	assertRollbackResponse(t, s, pb.Item_POLICIES, "", pTag1, pb.Response_ACK)
+	assertRollbackResponse(t, s, pb.Item_CONTENT, "test", cTag1, pb.Response_ACK)
+	assertRollbackResponse(t, s, pb.Item_CONTENT, "test", uuid.New(), pb.Response_TAG_ERROR)
+	assertRollbackResponse(t, s, pb.Item_CONTENT, "missing", cTag1, pb.Response_TAG_ERROR)
+
+	assertRollbackVersions(t, "rolled back", s, pb.Item_POLICIES, "", pTag1, pTag2)
+	assertRollbackVersions(t, "rolled back", s, pb.Item_CONTENT, "test", cTag1, cTag2)
+	assertRollbackServer(t, "rolled back", s, pTag1, cTag1)
+	s.st.close()
+
+	s = newStateTestServer(t, dir, WithHistorySize(3))
+	assertRollbackVersions(t, "restored", s, pb.Item_POLICIES, "", pTag1, pTag2)
+	assertRollbackVersions(t, "restored", s, pb.Item_CONTENT, "test", cTag1, cTag2)
+	assertRollbackServer(t, "restored", s, pTag1, cTag1)
+
+	// Update on top of rolled back content makes new version which can be
+	// restored after compaction.
+	cTag3 := uuid.New()
+	applyStateTestUpdate(t, s, &cTag1, &cTag3)
+	if err := s.st.compact(); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	s.st.close()
+
+	s = newStateTestServer(t, dir, WithHistorySize(3))
+	assertRollbackVersions(t, "compacted", s, pb.Item_CONTENT, "test", cTag3, cTag1)
+	if err := s.p.CheckTag(&pTag1); err != nil {
+		t.Errorf("Expected policy with tag %s for compacted state but got %s", pTag1, err)
+	}
+
+	if _, err := s.c.GetLocalContent("test", &cTag3); err != nil {
+		t.Errorf("Expected content with tag %s for compacted state but got %s", cTag3, err)
+	}
+	s.st.close()
+}
+
+func assertHistoryVersions(t *testing.T, desc string, vs []*version, e ...*version) {
+	if len(vs) != len(e) {
+		t.Errorf("Expected %d versions on %s but got %d", len(e), desc, len(vs))
+		return
+	}
+
+	for i, v := range vs {
+		if v != e[i] {
+			t.Errorf("Expected version %s at %d on %s but got %s", e[i].tag, i, desc, v.tag)
+		}
+	}
+}
+
+func assertRollbackVersions(t *testing.T, desc string, s *Server, typ pb.Item_DataType, id string, e ...uuid.UUID) {
+	r, err := s.ListVersions(context.Background(), &pb.VersionRequest{Type: typ, Id: id})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if r.Status != pb.Response_ACK {
+		t.Fatalf("Expected ACK for %s versions but got %s (%s)", desc, r.Status, r.Details)
+	}
+
+	if len(r.Versions) != len(e) {
+		t.Errorf("Expected %d %s versions but got %d", len(e), desc, len(r.Versions))
+		return
+	}
+
+	for i, v := range r.Versions {
+		if v.Tag != e[i].String() {
+			t.Errorf("Expected %s version %s at %d but got %s", desc, e[i], i, v.Tag)
+		}
+
+		if v.Current != (i == 0) {
+			t.Errorf("Expected %s version %s at %d to be current %v", desc, v.Tag, i, i == 0)
+		}
+	}
+}
+
+func assertRollbackResponse(t *testing.T, s *Server, typ pb.Item_DataType, id string, tag uuid.UUID, e pb.Response_Status) {
+	r, err := s.Rollback(context.Background(), &pb.RollbackRequest{Type: typ, Id: id, Tag: tag.String()})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if r.Status != e {
+		t.Errorf("Expected %s for rollback to %s but got %s (%s)", e, tag, r.Status, r.Details)
+	}
+}
+
+func assertRollbackServer(t *testing.T, desc string, s *Server, pTag, cTag uuid.UUID) {
+	if err := s.p.CheckTag(&pTag); err != nil {
+		t.Errorf("Expected policy with tag %s for %s state but got %s", pTag, desc, err)
+	}
+
+	ctx, err := pdp.NewContext(nil, 0, nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if r := s.p.Root().Calculate(ctx); r.Effect != pdp.EffectPermit {
+		t.Errorf("Expected %s for %s state but got %s", pdp.EffectNameFromEnum(pdp.EffectPermit), desc,
+			pdp.EffectNameFromEnum(r.Effect))
+	}
+
+	if _, err := s.c.GetLocalContent("test", &cTag); err != nil {
+		t.Errorf("Expected content with tag %s for %s state but got %s", cTag, desc, err)
+	}
+
+	item, err := s.c.Get("test", "m")
+	if err != nil {
+		t.Fatalf("Expected no error for %s state but got %s", desc, err)
+	}
+
+	if _, err := item.GetByValues([]pdp.AttributeValue{pdp.MakeStringValue("a")}, pdp.AggTypeDisable); err != nil {
+		t.Errorf("Expected value for %s state but got %s", desc, err)
+	}
+}
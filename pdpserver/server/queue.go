@@ -2,6 +2,7 @@ package server
 
 import (
 	"math"
+	"sort"
 	"sync"
 
 	"github.com/google/uuid"
@@ -21,6 +22,8 @@ type item struct {
 
 	c  *pdp.LocalContent
 	ct *pdp.LocalContentStorageTransaction
+
+	raw []byte
 }
 
 type queue struct {
@@ -76,3 +79,28 @@ func (q *queue) pop(idx int32) (*item, bool) {
 
 	return v, ok
 }
+
+// uploaded returns true if the item holds uploaded data waiting for apply.
+func (v *item) uploaded() bool {
+	return v.p != nil || v.pt != nil || v.c != nil || v.ct != nil
+}
+
+// list returns ids of queued items in ascending order along with the items.
+func (q *queue) list() ([]int32, []*item) {
+	q.Lock()
+	defer q.Unlock()
+
+	ids := make([]int32, 0, len(q.items))
+	for id := range q.items {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	items := make([]*item, len(ids))
+	for i, id := range ids {
+		items[i] = q.items[id]
+	}
+
+	return ids, items
+}
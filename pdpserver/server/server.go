@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/infobloxopen/themis/pdp"
@@ -146,6 +147,13 @@ func WithMaxResponseSize(size uint32) Option {
 	}
 }
 
+// WithDecisionTrace creates an option which allows clients to request evaluation trace. If the option is set and request contains boolean attribute pdp.TraceAttribute with true value, PDP records which policy sets, policies and rules it has visited and returns the trace in JSON form as string obligation with the same name. Response with trace is always allocated automatically regardless of maximal response size.
+func WithDecisionTrace(b bool) Option {
+	return func(o *options) {
+		o.decisionTrace = b
+	}
+}
+
 // WithMemStatsLogging returns a Option which enables regular runtime.MemStats logging. Path points to file where stats are logged as sequence of JSON objects splitted by new line. Each JSON object contains timestamp and output of runtime.ReadMemStats taken with given interval. Zero interval logs MemStats with minimum and maximum Alloc value between NumGC changes but not more than once a 100 ms. Negative interval disables logging.
 func WithMemStatsLogging(path string, interval time.Duration) Option {
 	return func(o *options) {
@@ -163,6 +171,24 @@ func WithMemProfDumping(path string, numGC uint32, delay time.Duration) Option {
 	}
 }
 
+// WithStateDir returns a Option which sets directory where server keeps
+// policies and content applied via control interface to restore them after
+// restart (see LoadState). Empty path disables state saving.
+func WithStateDir(path string) Option {
+	return func(o *options) {
+		o.stateDir = path
+	}
+}
+
+// WithHistorySize returns a Option which sets number of tagged policy and
+// content versions server keeps for rollback. Each content id has its own
+// history. Zero size disables history.
+func WithHistorySize(size int) Option {
+	return func(o *options) {
+		o.historySize = size
+	}
+}
+
 const memStatsCheckInterval = 100 * time.Millisecond
 
 type options struct {
@@ -181,6 +207,7 @@ type options struct {
 
 	autoResponseSize bool
 	maxResponseSize  uint32
+	decisionTrace    bool
 
 	memStatsLogPath     string
 	memStatsLogInterval time.Duration
@@ -188,6 +215,9 @@ type options struct {
 	memProfNumGC        uint32
 	memProfDelay        time.Duration
 
+	stateDir    string
+	historySize int
+
 	validatePreHook  ValidatePreHookFn
 	validatePostHook ValidatePostHookFn
 }
@@ -199,6 +229,7 @@ type Server struct {
 	opts options
 
 	startOnce sync.Once
+	ready     int32
 	errCh     chan error
 
 	requests    transport
@@ -209,6 +240,12 @@ type Server struct {
 
 	q *queue
 
+	// applying serializes apply requests so state journal keeps records
+	// in the order they change policies and content.
+	applying sync.Mutex
+	st       *stateDir
+	h        *history
+
 	p *pdp.PolicyStorage
 	c *pdp.LocalContentStorage
 
@@ -252,6 +289,7 @@ func NewServer(opts ...Option) *Server {
 		opts:                o,
 		errCh:               make(chan error, 100),
 		q:                   newQueue(),
+		h:                   newHistory(o.historySize),
 		c:                   pdp.NewLocalContentStorage(nil),
 		memProfBaseDumpDone: memProfBaseDumpDone,
 		pool:                pool,
@@ -271,23 +309,17 @@ func (s *Server) GRPCServer() *grpc.Server {
 	return s.requests.proto
 }
 
-// LoadPolicies loads policies from file
-func (s *Server) LoadPolicies(path string) error {
-	if len(path) <= 0 {
+// LoadPolicies loads policies from given files and directories. Files
+// can include other files and refer their root policies.
+func (s *Server) LoadPolicies(paths ...string) error {
+	if len(paths) <= 0 {
 		return nil
 	}
 
-	s.opts.logger.WithField("policy", path).Info("Loading policy")
-	pf, err := os.Open(path)
+	s.opts.logger.WithField("policy", paths).Info("Loading policy")
+	p, err := ast.LoadPolicies(s.opts.parser, paths, nil)
 	if err != nil {
-		s.opts.logger.WithFields(log.Fields{"policy": path, "error": err}).Error("Failed load policy")
-		return err
-	}
-
-	s.opts.logger.WithField("policy", path).Info("Parsing policy")
-	p, err := s.opts.parser.Unmarshal(pf, nil)
-	if err != nil {
-		s.opts.logger.WithFields(log.Fields{"policy": path, "error": err}).Error("Failed parse policy")
+		s.opts.logger.WithFields(log.Fields{"policy": paths, "error": err}).Error("Failed load policy")
 		return err
 	}
 
@@ -330,6 +362,7 @@ func (s *Server) LoadContent(paths []string) error {
 			s.opts.logger.WithField("content", path).Info("Parsing content")
 			item, err := jcon.Unmarshal(f, nil)
 			if err != nil {
+				pdp.SetErrorSourceFile(err, path)
 				return err
 			}
 
@@ -473,6 +506,9 @@ func (s *Server) serveRequests() error {
 	}
 
 	s.opts.logger.Info("Serving decision requests")
+	atomic.StoreInt32(&s.ready, 1)
+	defer atomic.StoreInt32(&s.ready, 0)
+
 	if err := s.requests.proto.Serve(s.requests.iface); err != nil {
 		return err
 	}
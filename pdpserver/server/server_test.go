@@ -2,9 +2,11 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 	"testing"
 
+	"github.com/infobloxopen/themis/pdp"
 	pb "github.com/infobloxopen/themis/pdp-service"
 )
 
@@ -104,3 +106,59 @@ func TestValidate_hook_context(t *testing.T) {
 	}
 
 }
+
+func TestValidateWithDecisionTrace(t *testing.T) {
+	root := pdp.NewPolicy("test", false, pdp.Target{},
+		[]*pdp.Rule{pdp.NewRule("permit", false, pdp.Target{}, nil, pdp.EffectPermit, nil)},
+		pdp.RuleCombiningAlgs["firstapplicableeffect"], nil, nil)
+	p := pdp.NewPolicyStorage(root, pdp.Symbols{}, nil)
+
+	in, err := pdp.MarshalRequestAssignments([]pdp.AttributeAssignment{
+		pdp.MakeBooleanAssignment(pdp.TraceAttribute, true),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf [1024]byte
+
+	s := NewServer()
+	for _, out := range [][]byte{s.rawValidate(p, nil, in), s.rawValidateToBuffer(p, nil, in, buf[:])} {
+		_, o, err := pdp.UnmarshalResponseAssignments(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o) > 0 {
+			t.Errorf("expected no obligations without decision trace option but got %d", len(o))
+		}
+	}
+
+	s = NewServer(WithDecisionTrace(true))
+	for _, out := range [][]byte{s.rawValidate(p, nil, in), s.rawValidateToBuffer(p, nil, in, buf[:])} {
+		_, o, err := pdp.UnmarshalResponseAssignments(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o) != 1 {
+			t.Fatalf("expected trace obligation but got %d obligations", len(o))
+		}
+
+		id, _, v, err := o[0].Serialize(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id != pdp.TraceAttribute {
+			t.Errorf("expected %q obligation but got %q", pdp.TraceAttribute, id)
+		}
+
+		trace := new(pdp.Trace)
+		if err := json.Unmarshal([]byte(v), trace); err != nil {
+			t.Errorf("expected JSON trace but got %q (%s)", v, err)
+		} else if trace.Root == nil || trace.Root.ID != "test" || len(trace.Root.Children) != 1 {
+			t.Errorf("expected trace of policy \"test\" with single rule but got %s", trace)
+		}
+	}
+}
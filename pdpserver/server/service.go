@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -20,6 +21,46 @@ func (s *Server) newContext(c *pdp.LocalContentStorage, in []byte) (*pdp.Context
 	return ctx, nil
 }
 
+func (s *Server) calculate(p *pdp.PolicyStorage, ctx *pdp.Context) (pdp.Response, bool) {
+	if s.opts.logger.Level >= log.DebugLevel {
+		s.opts.logger.WithField("context", ctx).Debug("Request context")
+	}
+
+	traced := s.opts.decisionTrace && ctx.IsTraceRequested()
+	if traced {
+		ctx.EnableTrace()
+	}
+
+	r := p.Root().Calculate(ctx)
+
+	if s.opts.logger.Level >= log.DebugLevel {
+		s.opts.logger.WithFields(log.Fields{
+			"effect": pdp.EffectNameFromEnum(r.Effect),
+			"reason": r.Status,
+			"obligations": obligations{
+				ctx: ctx,
+				o:   r.Obligations,
+			},
+		}).Debug("Response")
+	}
+
+	if traced {
+		b, err := json.Marshal(ctx.GetTrace())
+		if err != nil {
+			return pdp.Response{
+				Effect: pdp.EffectIndeterminate,
+				Status: newTraceMarshalError(err),
+			}, false
+		}
+
+		o := make([]pdp.AttributeAssignment, len(r.Obligations), len(r.Obligations)+1)
+		copy(o, r.Obligations)
+		r.Obligations = append(o, pdp.MakeStringAssignment(pdp.TraceAttribute, string(b)))
+	}
+
+	return r, traced
+}
+
 func makeFailureResponse(err error) []byte {
 	b, err := pdp.MakeIndeterminateResponse(err)
 	if err != nil {
@@ -57,22 +98,7 @@ func (s *Server) rawValidate(p *pdp.PolicyStorage, c *pdp.LocalContentStorage, i
 		return makeFailureResponse(err)
 	}
 
-	if s.opts.logger.Level >= log.DebugLevel {
-		s.opts.logger.WithField("context", ctx).Debug("Request context")
-	}
-
-	r := p.Root().Calculate(ctx)
-
-	if s.opts.logger.Level >= log.DebugLevel {
-		s.opts.logger.WithFields(log.Fields{
-			"effect": pdp.EffectNameFromEnum(r.Effect),
-			"reason": r.Status,
-			"obligations": obligations{
-				ctx: ctx,
-				o:   r.Obligations,
-			},
-		}).Debug("Response")
-	}
+	r, _ := s.calculate(p, ctx)
 
 	out, err := r.Marshal(ctx)
 	if err != nil {
@@ -92,22 +118,7 @@ func (s *Server) rawValidateWithAllocator(p *pdp.PolicyStorage, c *pdp.LocalCont
 		return makeFailureResponseWithAllocator(f, err)
 	}
 
-	if s.opts.logger.Level >= log.DebugLevel {
-		s.opts.logger.WithField("context", ctx).Debug("Request context")
-	}
-
-	r := p.Root().Calculate(ctx)
-
-	if s.opts.logger.Level >= log.DebugLevel {
-		s.opts.logger.WithFields(log.Fields{
-			"effect": pdp.EffectNameFromEnum(r.Effect),
-			"reason": r.Status,
-			"obligations": obligations{
-				ctx: ctx,
-				o:   r.Obligations,
-			},
-		}).Debug("Response")
-	}
+	r, _ := s.calculate(p, ctx)
 
 	out, err := r.MarshalWithAllocator(f, ctx)
 	if err != nil {
@@ -127,21 +138,15 @@ func (s *Server) rawValidateToBuffer(p *pdp.PolicyStorage, c *pdp.LocalContentSt
 		return makeFailureResponseWithBuffer(out, err)
 	}
 
-	if s.opts.logger.Level >= log.DebugLevel {
-		s.opts.logger.WithField("context", ctx).Debug("Request context")
-	}
+	r, traced := s.calculate(p, ctx)
 
-	r := p.Root().Calculate(ctx)
+	if traced {
+		b, err := r.Marshal(ctx)
+		if err != nil {
+			panic(err)
+		}
 
-	if s.opts.logger.Level >= log.DebugLevel {
-		s.opts.logger.WithFields(log.Fields{
-			"effect": pdp.EffectNameFromEnum(r.Effect),
-			"reason": r.Status,
-			"obligations": obligations{
-				ctx: ctx,
-				o:   r.Obligations,
-			},
-		}).Debug("Response")
+		return b
 	}
 
 	n, err := r.MarshalToBuffer(out, ctx)
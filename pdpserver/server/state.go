@@ -0,0 +1,754 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infobloxopen/themis/pdp"
+	"github.com/infobloxopen/themis/pdp/jcon"
+)
+
+// State directory keeps policies and content applied via control interface
+// so server restores them after restart. The directory contains snapshot
+// and journal files. Both are sequences of records. Each record holds
+// uploaded data of a full policy or content or of an update together with
+// its tags. Rollback record holds all records which make the version to roll
+// back to. Every successful apply or rollback appends a record to the
+// journal. When the journal grows bigger than the snapshot, records still
+// required to restore current state are copied to a new snapshot and the
+// journal is cleared. The new snapshot replaces the old one with rename and
+// the old one is kept as a fallback for the case of damaged snapshot.
+const (
+	stateSnapshotFile        = "snapshot"
+	stateSnapshotPrevFile    = "snapshot.prev"
+	stateSnapshotTempFile    = "snapshot.tmp"
+	stateSnapshotDamagedFile = "snapshot.damaged"
+	stateJournalFile         = "journal"
+
+	stateCompactMinSize = 1024 * 1024
+	stateMaxHeaderSize  = 64 * 1024
+)
+
+const (
+	stateOpPolicy        = "policy"
+	stateOpPolicyUpdate  = "policy-update"
+	stateOpContent       = "content"
+	stateOpContentUpdate = "content-update"
+	stateOpContentDelete = "content-delete"
+
+	stateOpPolicyRollback  = "policy-rollback"
+	stateOpContentRollback = "content-rollback"
+
+	stateOpEnd = "end"
+)
+
+// On disk record starts with a prefix of sizes of JSON encoded header and
+// data followed by CRC32 (Castagnoli) of both. The header and the data go
+// after the prefix.
+const stateRecordPrefixSize = 12
+
+var stateCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+type stateRecord struct {
+	Seq     uint64 `json:"seq"`
+	Op      string `json:"op"`
+	ID      string `json:"id,omitempty"`
+	FromTag string `json:"from-tag,omitempty"`
+	ToTag   string `json:"to-tag,omitempty"`
+	Time    int64  `json:"time,omitempty"`
+}
+
+// stateData is a record together with its data.
+type stateData struct {
+	h    stateRecord
+	data []byte
+}
+
+// streamKey returns key of sequence of records which modify policy or
+// content with given id.
+func streamKey(policy bool, id string) string {
+	if policy {
+		return stateOpPolicy
+	}
+
+	return stateOpContent + ":" + id
+}
+
+// stream returns key of sequence of records which modify the same policy
+// or content.
+func (h *stateRecord) stream() string {
+	switch h.Op {
+	case stateOpPolicy, stateOpPolicyUpdate, stateOpPolicyRollback:
+		return streamKey(true, "")
+	}
+
+	return streamKey(false, h.ID)
+}
+
+// full returns true if record replaces whole policy or content. Content
+// delete record is full as well so snapshot keeps it to remove content
+// loaded from files.
+func (h *stateRecord) full() bool {
+	switch h.Op {
+	case stateOpPolicy, stateOpContent, stateOpContentDelete, stateOpPolicyRollback, stateOpContentRollback:
+		return true
+	}
+
+	return false
+}
+
+// stateRef points to a record in one of state files.
+type stateRef struct {
+	h      stateRecord
+	file   *os.File
+	offset int64
+	size   int64
+	data   int64
+}
+
+func (r stateRef) read() ([]byte, error) {
+	b := make([]byte, r.offset+r.size-r.data)
+	if _, err := r.file.ReadAt(b, r.data); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+type stateDir struct {
+	path   string
+	logger *log.Logger
+
+	snapshot *os.File
+	journal  *os.File
+
+	seq          uint64
+	snapshotSize int64
+	journalSize  int64
+	compactSize  int64
+
+	// streams holds records required to restore current state of policy
+	// and each content.
+	streams map[string][]stateRef
+}
+
+func openStateDir(path string, logger *log.Logger) (*stateDir, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(filepath.Join(path, stateJournalFile), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stateDir{
+		path:        path,
+		logger:      logger,
+		journal:     f,
+		compactSize: stateCompactMinSize,
+		streams:     make(map[string][]stateRef),
+	}, nil
+}
+
+func (st *stateDir) close() {
+	if st.snapshot != nil {
+		st.snapshot.Close()
+	}
+
+	st.journal.Close()
+}
+
+// load reads snapshot and journal and calls given function for each record
+// in order of their sequence numbers. Records which the function fails to
+// apply are dropped from state. Damaged snapshot is replaced with previous
+// one together with whole journal. Damaged journal is truncated to its last
+// good record.
+func (st *stateDir) load(f func(h *stateRecord, b []byte) error) error {
+	useJournal := true
+	rewrite := false
+
+	refs, end, err := st.openSnapshot(stateSnapshotFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			st.logger.WithError(err).Error("State snapshot is damaged. Falling back to previous snapshot...")
+			if err := os.Rename(
+				filepath.Join(st.path, stateSnapshotFile),
+				filepath.Join(st.path, stateSnapshotDamagedFile),
+			); err != nil {
+				return err
+			}
+
+			useJournal = false
+		}
+
+		// Previous snapshot without current one means compaction has
+		// been interrupted and journal still follows previous snapshot.
+		rewrite = true
+		refs, end, err = st.openSnapshot(stateSnapshotPrevFile)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				st.logger.WithError(err).Error("Previous state snapshot is damaged. Ignoring it...")
+				useJournal = false
+			}
+
+			refs = nil
+			end = 0
+		}
+	}
+
+	st.seq = end
+
+	jRefs, good, err := scanStateFile(st.journal)
+	if err != nil {
+		st.logger.WithError(err).Warn("State journal is damaged. Dropping records after the damage...")
+	}
+
+	if !useJournal {
+		if good > 0 {
+			st.logger.Warn("Dropping state journal...")
+		}
+
+		jRefs = nil
+		good = 0
+	}
+
+	if err := st.journal.Truncate(good); err != nil {
+		return err
+	}
+
+	if err := st.journal.Sync(); err != nil {
+		return err
+	}
+
+	st.journalSize = good
+
+	for _, r := range jRefs {
+		if r.h.Seq > st.seq {
+			refs = append(refs, r)
+			st.seq = r.h.Seq
+		}
+	}
+
+	for _, r := range refs {
+		b, err := r.read()
+		if err == nil {
+			err = f(&r.h, b)
+		}
+
+		if err != nil {
+			st.logger.WithFields(log.Fields{
+				"seq":   r.h.Seq,
+				"op":    r.h.Op,
+				"id":    r.h.ID,
+				"error": err,
+			}).Error("Failed to restore state record. Dropping it...")
+			continue
+		}
+
+		st.add(r)
+	}
+
+	if rewrite || st.needsCompaction() {
+		return st.compact()
+	}
+
+	return nil
+}
+
+// openSnapshot opens and checks snapshot file with given name. It returns
+// references to snapshot records and sequence number of the last record
+// the snapshot covers.
+func (st *stateDir) openSnapshot(name string) ([]stateRef, uint64, error) {
+	path := filepath.Join(st.path, name)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	refs, size, err := scanStateFile(f)
+	if err == nil && (len(refs) <= 0 || refs[len(refs)-1].h.Op != stateOpEnd) {
+		err = newStateFileCorruptionError(path, size, newStateSnapshotEndError())
+	}
+
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+
+	if st.snapshot != nil {
+		st.snapshot.Close()
+	}
+
+	st.snapshot = f
+	st.snapshotSize = size
+
+	end := refs[len(refs)-1]
+	return refs[:len(refs)-1], end.h.Seq, nil
+}
+
+// scanStateFile reads all records of given file and checks their sizes and
+// checksums. It returns references to good records and size of the file
+// part they take. The error is returned for damaged record.
+func scanStateFile(f *os.File) ([]stateRef, int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+
+	r := bufio.NewReader(f)
+	refs := []stateRef{}
+	offset := int64(0)
+	for offset < info.Size() {
+		ref, err := readStateRecord(r, info.Size()-offset)
+		if err != nil {
+			return refs, offset, newStateFileCorruptionError(f.Name(), offset, err)
+		}
+
+		ref.file = f
+		ref.offset = offset
+		ref.data += offset
+		refs = append(refs, ref)
+
+		offset += ref.size
+	}
+
+	return refs, offset, nil
+}
+
+// readStateRecord reads record which can't be longer than given limit. It
+// returns reference with size of the record and data offset relative to
+// the record start.
+func readStateRecord(r io.Reader, limit int64) (stateRef, error) {
+	var prefix [stateRecordPrefixSize]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return stateRef{}, err
+	}
+
+	hSize := binary.BigEndian.Uint32(prefix[0:])
+	dSize := binary.BigEndian.Uint32(prefix[4:])
+	size := stateRecordPrefixSize + int64(hSize) + int64(dSize)
+	if hSize > stateMaxHeaderSize || size > limit {
+		return stateRef{}, newStateRecordSizeError(hSize, dSize)
+	}
+
+	hb := make([]byte, hSize)
+	if _, err := io.ReadFull(r, hb); err != nil {
+		return stateRef{}, err
+	}
+
+	crc := crc32.New(stateCRCTable)
+	crc.Write(hb)
+	if _, err := io.CopyN(crc, r, int64(dSize)); err != nil {
+		return stateRef{}, err
+	}
+
+	if crc.Sum32() != binary.BigEndian.Uint32(prefix[8:]) {
+		return stateRef{}, newStateRecordChecksumError()
+	}
+
+	ref := stateRef{
+		size: size,
+		data: stateRecordPrefixSize + int64(hSize),
+	}
+	if err := json.Unmarshal(hb, &ref.h); err != nil {
+		return stateRef{}, newStateRecordHeaderError(err)
+	}
+
+	return ref, nil
+}
+
+// encodeStateRecords makes data of rollback record from given records.
+func encodeStateRecords(records []stateData) ([]byte, error) {
+	out := []byte{}
+	for _, r := range records {
+		b, err := encodeStateRecord(&r.h, r.data)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, b...)
+	}
+
+	return out, nil
+}
+
+// decodeStateRecords gets records from data of rollback record.
+func decodeStateRecords(b []byte) ([]stateData, error) {
+	records := []stateData{}
+	offset := int64(0)
+	for offset < int64(len(b)) {
+		ref, err := readStateRecord(bytes.NewReader(b[offset:]), int64(len(b))-offset)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, stateData{
+			h:    ref.h,
+			data: b[offset+ref.data : offset+ref.size],
+		})
+		offset += ref.size
+	}
+
+	return records, nil
+}
+
+func encodeStateRecord(h *stateRecord, data []byte) ([]byte, error) {
+	hb, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, stateRecordPrefixSize, stateRecordPrefixSize+len(hb)+len(data))
+	binary.BigEndian.PutUint32(b[0:], uint32(len(hb)))
+	binary.BigEndian.PutUint32(b[4:], uint32(len(data)))
+
+	crc := crc32.New(stateCRCTable)
+	crc.Write(hb)
+	crc.Write(data)
+	binary.BigEndian.PutUint32(b[8:], crc.Sum32())
+
+	return append(append(b, hb...), data...), nil
+}
+
+func (st *stateDir) add(r stateRef) {
+	k := r.h.stream()
+	if r.h.full() {
+		st.streams[k] = []stateRef{r}
+		return
+	}
+
+	st.streams[k] = append(st.streams[k], r)
+}
+
+// append writes record to the journal and flushes it to disk. Journal
+// keeps no part of the record if writing fails.
+func (st *stateDir) append(h stateRecord, data []byte) error {
+	h.Seq = st.seq + 1
+	b, err := encodeStateRecord(&h, data)
+	if err != nil {
+		return err
+	}
+
+	offset := st.journalSize
+	if _, err := st.journal.WriteAt(b, offset); err != nil {
+		st.journal.Truncate(offset)
+		return err
+	}
+
+	if err := st.journal.Sync(); err != nil {
+		st.journal.Truncate(offset)
+		return err
+	}
+
+	st.seq = h.Seq
+	st.journalSize += int64(len(b))
+	st.add(stateRef{
+		h:      h,
+		file:   st.journal,
+		offset: offset,
+		size:   int64(len(b)),
+		data:   offset + int64(len(b)-len(data)),
+	})
+
+	if st.needsCompaction() {
+		if err := st.compact(); err != nil {
+			st.logger.WithError(err).Error("Failed to compact state journal")
+		}
+	}
+
+	return nil
+}
+
+func (st *stateDir) needsCompaction() bool {
+	return st.journalSize >= st.compactSize && st.journalSize >= st.snapshotSize
+}
+
+// compact writes records required to restore current state to new snapshot
+// and clears the journal.
+func (st *stateDir) compact() error {
+	refs := []stateRef{}
+	for _, s := range st.streams {
+		refs = append(refs, s...)
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].h.Seq < refs[j].h.Seq })
+
+	tmp := filepath.Join(st.path, stateSnapshotTempFile)
+	f, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	offset := int64(0)
+	for i, r := range refs {
+		if _, err := io.Copy(f, io.NewSectionReader(r.file, r.offset, r.size)); err != nil {
+			f.Close()
+			return err
+		}
+
+		refs[i].file = f
+		refs[i].data += offset - r.offset
+		refs[i].offset = offset
+		offset += r.size
+	}
+
+	b, err := encodeStateRecord(&stateRecord{Seq: st.seq, Op: stateOpEnd}, nil)
+	if err == nil {
+		_, err = f.Write(b)
+	}
+
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	path := filepath.Join(st.path, stateSnapshotFile)
+	if err := os.Rename(path, filepath.Join(st.path, stateSnapshotPrevFile)); err != nil && !os.IsNotExist(err) {
+		f.Close()
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := syncDir(st.path); err != nil {
+		f.Close()
+		return err
+	}
+
+	if st.snapshot != nil {
+		st.snapshot.Close()
+	}
+
+	st.snapshot = f
+	st.snapshotSize = offset + int64(len(b))
+
+	st.streams = make(map[string][]stateRef)
+	for _, r := range refs {
+		st.add(r)
+	}
+
+	if err := st.journal.Truncate(0); err != nil {
+		return err
+	}
+
+	st.journalSize = 0
+	return st.journal.Sync()
+}
+
+func syncDir(path string) error {
+	d, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
+
+// LoadState restores policies and content saved to state directory (see
+// WithStateDir) on top of ones loaded from files. After that server saves
+// each applied upload to the directory. The method does nothing if state
+// directory isn't set.
+func (s *Server) LoadState() error {
+	if len(s.opts.stateDir) <= 0 {
+		return nil
+	}
+
+	s.opts.logger.WithField("path", s.opts.stateDir).Info("Loading state")
+	st, err := openStateDir(s.opts.stateDir, s.opts.logger)
+	if err != nil {
+		return err
+	}
+
+	if err := st.load(s.restoreStateRecord); err != nil {
+		st.close()
+		return err
+	}
+
+	s.st = st
+	return nil
+}
+
+func (s *Server) restoreStateRecord(h *stateRecord, b []byte) error {
+	fromTag, err := newTag(h.FromTag)
+	if err != nil {
+		return newInvalidFromTagError(h.FromTag, err)
+	}
+
+	toTag, err := newTag(h.ToTag)
+	if err != nil {
+		return newInvalidToTagError(h.ToTag, err)
+	}
+
+	if !h.full() && toTag == nil {
+		return newInvalidTagsError(h.FromTag)
+	}
+
+	switch h.Op {
+	case stateOpPolicy:
+		p, err := s.opts.parser.Unmarshal(bytes.NewReader(b), toTag)
+		if err != nil {
+			return err
+		}
+
+		s.p = p
+		s.pushVersion(stateData{h: *h, data: b}, toTag, p, nil)
+
+	case stateOpPolicyUpdate:
+		if s.p == nil {
+			return newMissingPolicyStorageError()
+		}
+
+		t, err := s.p.NewTransaction(fromTag)
+		if err != nil {
+			return err
+		}
+
+		u, err := s.opts.parser.UnmarshalUpdate(bytes.NewReader(b), t.Symbols(), *fromTag, *toTag)
+		if err != nil {
+			return err
+		}
+
+		if err := t.Apply(u); err != nil {
+			return err
+		}
+
+		p, err := t.Commit()
+		if err != nil {
+			return err
+		}
+
+		s.p = p
+		s.pushVersion(stateData{h: *h, data: b}, toTag, p, nil)
+
+	case stateOpContent:
+		c, err := jcon.Unmarshal(bytes.NewReader(b), toTag)
+		if err != nil {
+			return err
+		}
+
+		s.c = s.c.Add(c)
+		s.pushVersion(stateData{h: *h, data: b}, toTag, nil, c)
+
+	case stateOpContentUpdate:
+		t, err := s.c.NewTransaction(h.ID, fromTag)
+		if err != nil {
+			return err
+		}
+
+		u, err := jcon.UnmarshalUpdate(bytes.NewReader(b), h.ID, *fromTag, *toTag, t.Symbols())
+		if err != nil {
+			return err
+		}
+
+		if err := t.Apply(u); err != nil {
+			return err
+		}
+
+		c, err := t.Commit(s.c)
+		if err != nil {
+			return err
+		}
+
+		s.c = c
+		if lc, err := c.GetLocalContent(h.ID, toTag); err == nil {
+			s.pushVersion(stateData{h: *h, data: b}, toTag, nil, lc)
+		}
+
+	case stateOpContentDelete:
+		c, err := s.c.Delete(h.ID, nil)
+		if err != nil {
+			if _, ok := err.(*pdp.MissingContentError); ok {
+				return nil
+			}
+
+			return err
+		}
+
+		s.c = c
+
+	case stateOpPolicyRollback, stateOpContentRollback:
+		records, err := decodeStateRecords(b)
+		if err != nil {
+			return err
+		}
+
+		p, c := s.p, s.c
+		for _, r := range records {
+			if err := s.restoreStateRecord(&r.h, r.data); err != nil {
+				s.p, s.c = p, c
+				return err
+			}
+		}
+
+	default:
+		return newUnknownStateRecordError(h.Op)
+	}
+
+	return nil
+}
+
+// saveState appends applied request to state journal. It does nothing if
+// server has no state directory.
+func (s *Server) saveState(d stateData) error {
+	if s.st == nil {
+		return nil
+	}
+
+	return s.st.append(d.h, d.data)
+}
+
+// makeStateData makes state record for given request.
+func makeStateData(req *item) stateData {
+	h := stateRecord{
+		ID:   req.id,
+		Time: time.Now().UnixNano(),
+	}
+	if req.fromTag != nil {
+		h.FromTag = req.fromTag.String()
+	}
+
+	if req.toTag != nil {
+		h.ToTag = req.toTag.String()
+	}
+
+	switch {
+	case req.p != nil:
+		h.Op = stateOpPolicy
+		h.ID = ""
+
+	case req.pt != nil:
+		h.Op = stateOpPolicyUpdate
+		h.ID = ""
+
+	case req.c != nil:
+		h.Op = stateOpContent
+		h.ID = req.c.GetID()
+
+	case req.ct != nil:
+		h.Op = stateOpContentUpdate
+	}
+
+	return stateData{h: h, data: req.raw}
+}
@@ -0,0 +1,343 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infobloxopen/themis/pdp"
+	pb "github.com/infobloxopen/themis/pdp-control"
+	"github.com/infobloxopen/themis/pdp/jcon"
+)
+
+const (
+	statePolicy = `# Permit with single rule
+policies:
+  id: Root
+  alg: FirstApplicableEffect
+  rules:
+  - id: Permit
+    effect: Permit
+`
+
+	statePolicyUpdate = `# Delete the rule
+- op: delete
+  path:
+  - Root
+  - Permit
+`
+
+	statePolicyAddUpdate = `# Add deny rule
+- op: add
+  path:
+  - Root
+  entity:
+    id: Deny
+    effect: Deny
+`
+
+	stateContent = `{
+  "id": "test",
+  "items": {
+    "m": {
+      "type": "string",
+      "keys": ["string"],
+      "data": {
+        "a": "x",
+        "b": "y"
+      }
+    }
+  }
+}`
+
+	stateContentUpdate = `[
+  {
+    "op": "Delete",
+    "path": ["m", "a"]
+  }
+]`
+)
+
+func TestStateRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "themis-state")
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pTag1 := uuid.New()
+	pTag2 := uuid.New()
+	cTag1 := uuid.New()
+	cTag2 := uuid.New()
+
+	s := newStateTestServer(t, dir)
+	applyStateTestUploads(t, s, &pTag1, &pTag2, &cTag1)
+	applyStateTestUpdate(t, s, &cTag1, &cTag2)
+	s.st.close()
+
+	s = newStateTestServer(t, dir)
+	assertStateTestServer(t, "restored", s, &pTag2, &cTag2, true)
+
+	applyStateTestUploads(t, s, &pTag1, &pTag2, &cTag1)
+	if err := s.st.compact(); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	applyStateTestUpdate(t, s, &cTag1, &cTag2)
+	if err := s.st.compact(); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	s.st.close()
+
+	if info, err := os.Stat(filepath.Join(dir, stateJournalFile)); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	} else if info.Size() != 0 {
+		t.Errorf("Expected empty journal after compaction but got %d bytes", info.Size())
+	}
+
+	s = newStateTestServer(t, dir)
+	assertStateTestServer(t, "compacted", s, &pTag2, &cTag2, true)
+	s.st.close()
+
+	// Damaged snapshot is replaced with previous one which doesn't have
+	// the last content update.
+	damageStateTestFile(t, filepath.Join(dir, stateSnapshotFile), -20)
+
+	s = newStateTestServer(t, dir)
+	assertStateTestServer(t, "fallback", s, &pTag2, &cTag1, false)
+
+	// Broken journal record is dropped together with all records after it.
+	applyStateTestUpdate(t, s, &cTag1, &cTag2)
+	s.st.close()
+
+	damageStateTestFile(t, filepath.Join(dir, stateJournalFile), -1)
+
+	s = newStateTestServer(t, dir)
+	assertStateTestServer(t, "truncated", s, &pTag2, &cTag1, false)
+	s.st.close()
+
+	if info, err := os.Stat(filepath.Join(dir, stateJournalFile)); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	} else if info.Size() != 0 {
+		t.Errorf("Expected truncated journal but got %d bytes", info.Size())
+	}
+}
+
+func TestStatePolicyUpdateTagCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "themis-state")
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pTag1 := uuid.New()
+	pTag2 := uuid.New()
+	pTag3 := uuid.New()
+	pTag4 := uuid.New()
+	cTag := uuid.New()
+
+	s := newStateTestServer(t, dir, WithHistorySize(3))
+	applyStateTestUploads(t, s, &pTag1, &pTag2, &cTag)
+
+	// Both updates start from the same tag but only the first one can be
+	// applied.
+	u1 := newStateTestPolicyUpdate(t, s, &pTag2, &pTag3)
+	u2 := newStateTestPolicyUpdate(t, s, &pTag2, &pTag4)
+
+	r, err := s.applyPolicy(4, u1)
+	assertStateTestResponse(t, r, err)
+
+	r, err = s.applyPolicy(5, u2)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if r.Status != pb.Response_TAG_ERROR {
+		t.Errorf("Expected TAG_ERROR for outdated update but got %s (%s)", r.Status, r.Details)
+	}
+
+	// Update made before rollback can't be applied after it.
+	u3 := newStateTestPolicyUpdate(t, s, &pTag3, &pTag4)
+	assertRollbackResponse(t, s, pb.Item_POLICIES, "", pTag2, pb.Response_ACK)
+
+	r, err = s.applyPolicy(6, u3)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if r.Status != pb.Response_TAG_ERROR {
+		t.Errorf("Expected TAG_ERROR for update after rollback but got %s (%s)", r.Status, r.Details)
+	}
+
+	if err := s.p.CheckTag(&pTag2); err != nil {
+		t.Errorf("Expected policy with tag %s but got %s", pTag2, err)
+	}
+	s.st.close()
+
+	s = newStateTestServer(t, dir, WithHistorySize(3))
+	defer s.st.close()
+
+	if err := s.p.CheckTag(&pTag2); err != nil {
+		t.Errorf("Expected policy with tag %s for restored state but got %s", pTag2, err)
+	}
+}
+
+func newStateTestServer(t *testing.T, dir string, opts ...Option) *Server {
+	logger := log.New()
+	logger.SetOutput(ioutil.Discard)
+
+	s := NewServer(append([]Option{WithLogger(logger), WithStateDir(dir)}, opts...)...)
+	if err := s.LoadState(); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	return s
+}
+
+func applyStateTestUploads(t *testing.T, s *Server, pTag1, pTag2, cTag *uuid.UUID) {
+	p, err := s.opts.parser.Unmarshal(strings.NewReader(statePolicy), pTag1)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	req := newPolicyItem(nil, pTag1)
+	req.p = p
+	req.raw = []byte(statePolicy)
+	r, err := s.applyPolicy(0, req)
+	assertStateTestResponse(t, r, err)
+
+	pt, err := s.p.NewTransaction(pTag1)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	u, err := s.opts.parser.UnmarshalUpdate(strings.NewReader(statePolicyUpdate), pt.Symbols(), *pTag1, *pTag2)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if err := pt.Apply(u); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	req = newPolicyItem(pTag1, pTag2)
+	req.pt = pt
+	req.raw = []byte(statePolicyUpdate)
+	r, err = s.applyPolicy(1, req)
+	assertStateTestResponse(t, r, err)
+
+	c, err := jcon.Unmarshal(strings.NewReader(stateContent), cTag)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	req = newContentItem("test", nil, cTag)
+	req.c = c
+	req.raw = []byte(stateContent)
+	r, err = s.applyContent(2, req)
+	assertStateTestResponse(t, r, err)
+}
+
+func newStateTestPolicyUpdate(t *testing.T, s *Server, fromTag, toTag *uuid.UUID) *item {
+	pt, err := s.p.NewTransaction(fromTag)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	u, err := s.opts.parser.UnmarshalUpdate(strings.NewReader(statePolicyAddUpdate), pt.Symbols(), *fromTag, *toTag)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if err := pt.Apply(u); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	req := newPolicyItem(fromTag, toTag)
+	req.pt = pt
+	req.raw = []byte(statePolicyAddUpdate)
+
+	return req
+}
+
+func applyStateTestUpdate(t *testing.T, s *Server, cTag1, cTag2 *uuid.UUID) {
+	ct, err := s.c.NewTransaction("test", cTag1)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	u, err := jcon.UnmarshalUpdate(strings.NewReader(stateContentUpdate), "test", *cTag1, *cTag2, ct.Symbols())
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if err := ct.Apply(u); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	req := newContentItem("test", cTag1, cTag2)
+	req.ct = ct
+	req.raw = []byte(stateContentUpdate)
+	r, err := s.applyContent(3, req)
+	assertStateTestResponse(t, r, err)
+}
+
+func assertStateTestResponse(t *testing.T, r *pb.Response, err error) {
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if r.Status != pb.Response_ACK {
+		t.Fatalf("Expected ACK but got %s (%s)", r.Status, r.Details)
+	}
+}
+
+func assertStateTestServer(t *testing.T, desc string, s *Server, pTag, cTag *uuid.UUID, updated bool) {
+	if err := s.p.CheckTag(pTag); err != nil {
+		t.Errorf("Expected policy with tag %s for %s state but got %s", pTag, desc, err)
+	}
+
+	ctx, err := pdp.NewContext(nil, 0, nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	if r := s.p.Root().Calculate(ctx); r.Effect != pdp.EffectNotApplicable {
+		t.Errorf("Expected %s for %s state but got %s", pdp.EffectNameFromEnum(pdp.EffectNotApplicable), desc,
+			pdp.EffectNameFromEnum(r.Effect))
+	}
+
+	if _, err := s.c.GetLocalContent("test", cTag); err != nil {
+		t.Errorf("Expected content with tag %s for %s state but got %s", cTag, desc, err)
+	}
+
+	item, err := s.c.Get("test", "m")
+	if err != nil {
+		t.Fatalf("Expected no error for %s state but got %s", desc, err)
+	}
+
+	_, err = item.GetByValues([]pdp.AttributeValue{pdp.MakeStringValue("a")}, pdp.AggTypeDisable)
+	if updated && err == nil {
+		t.Errorf("Expected deleted value for %s state but got nothing", desc)
+	} else if !updated && err != nil {
+		t.Errorf("Expected value for %s state but got %s", desc, err)
+	}
+}
+
+// damageStateTestFile flips byte at given offset from the end of the file.
+func damageStateTestFile(t *testing.T, path string, offset int) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	b[len(b)+offset] ^= 0xff
+	if err := ioutil.WriteFile(path, b, 0644); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"bytes"
 	"io"
 
 	log "github.com/sirupsen/logrus"
@@ -15,6 +16,7 @@ type streamReader struct {
 	offset int
 	eof    bool
 	logger *log.Logger
+	raw    *bytes.Buffer
 }
 
 func newStreamReader(id int32, head []byte, stream pb.PDPControl_UploadServer, logger *log.Logger) *streamReader {
@@ -48,7 +50,30 @@ func (r *streamReader) skip() error {
 	return nil
 }
 
+// record makes the reader keep a copy of all data it returns. The copy is
+// available with bytes method.
+func (r *streamReader) record() {
+	r.raw = new(bytes.Buffer)
+}
+
+func (r *streamReader) bytes() []byte {
+	if r.raw == nil {
+		return nil
+	}
+
+	return r.raw.Bytes()
+}
+
 func (r *streamReader) Read(p []byte) (n int, err error) {
+	n, err = r.read(p)
+	if r.raw != nil && n > 0 {
+		r.raw.Write(p[:n])
+	}
+
+	return n, err
+}
+
+func (r *streamReader) read(p []byte) (n int, err error) {
 	if r.eof {
 		return 0, io.EOF
 	}
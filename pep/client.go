@@ -42,16 +42,25 @@ var (
 // attributes. If no fields contains format string, Validate tries to convert
 // all exported fields to attributes. Any bool field is converted to boolean
 // attribute, string - to string attribute, net.IP - to address, net.IPNet or
-// *net.IPNet to network. Fields of other types are silently ingnored.
+// *net.IPNet to network, *strtree.Tree to set of strings, *iptree.Tree to set
+// of networks, *domaintree.Node to set of domains, []string to list of
+// strings, time.Time to datetime and time.Duration to duration. Fields of
+// other types are silently ingnored.
 //
 // Marshalling can be ajusted more precisely with help of `pdp` key in format
 // string. When some fields of "in" structure have format string, only fields
 // with "pdp" key are converted to attributes. The key supports two option
 // separated by comma. First is desired attribute name. Second - attribute type.
-// Allowed types are: boolean, string, address, network and domain. Validate can
-// convert only bool structure field to boolean attribute, string to string
-// attribute, net.IP to address attribute, net.IPNet or *net.IPNet to network
-// attribute and string to domain attribute.
+// Allowed types are: boolean, string, integer, float, address, network, domain,
+// set of strings, set of networks, set of domains, list of strings, datetime
+// and duration. Validate can convert only bool structure field to boolean
+// attribute, string to string attribute, integer types to integer attribute,
+// float32 or float64 to float attribute, net.IP to address attribute,
+// net.IPNet or *net.IPNet to network attribute, string or domain.Name to domain
+// attribute, *strtree.Tree or []string to set of strings attribute,
+// *iptree.Tree to set of networks attribute, *domaintree.Node to set of domains
+// attribute, []string to list of strings attribute, time.Time to datetime
+// attribute and time.Duration or int64 to duration attribute.
 //
 // Validate is also able to unmarshal server's response to structure.
 // It accepts pointer to the structure as "out" argument. If no fields
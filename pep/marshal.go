@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/infobloxopen/go-trees/domain"
 	"github.com/infobloxopen/go-trees/domaintree"
@@ -55,6 +56,8 @@ var (
 	iptreeType      = reflect.TypeOf((*iptree.Tree)(nil))
 	domaintreeType  = reflect.TypeOf((*domaintree.Node)(nil))
 	stringsType     = reflect.TypeOf([]string(nil))
+	timeType        = reflect.TypeOf(time.Time{})
+	durationType    = reflect.TypeOf(time.Duration(0))
 
 	attrTypeByType = map[reflect.Type]pdp.Type{
 		boolType:        pdp.TypeBoolean,
@@ -79,6 +82,8 @@ var (
 		iptreeType:      pdp.TypeSetOfNetworks,
 		domaintreeType:  pdp.TypeSetOfDomains,
 		stringsType:     pdp.TypeListOfStrings,
+		timeType:        pdp.TypeDateTime,
+		durationType:    pdp.TypeDuration,
 	}
 
 	attrTypeByTag = map[string]pdp.Type{
@@ -93,6 +98,8 @@ var (
 		pdp.TypeSetOfNetworks.GetKey(): pdp.TypeSetOfNetworks,
 		pdp.TypeSetOfDomains.GetKey():  pdp.TypeSetOfDomains,
 		pdp.TypeListOfStrings.GetKey(): pdp.TypeListOfStrings,
+		pdp.TypeDateTime.GetKey():      pdp.TypeDateTime,
+		pdp.TypeDuration.GetKey():      pdp.TypeDuration,
 	}
 
 	typeByAttrType = map[pdp.Type]map[reflect.Type]struct{}{
@@ -131,6 +138,7 @@ var (
 		},
 		pdp.TypeSetOfStrings: {
 			strtreeType: {},
+			stringsType: {},
 		},
 		pdp.TypeSetOfNetworks: {
 			iptreeType: {},
@@ -141,6 +149,13 @@ var (
 		pdp.TypeListOfStrings: {
 			stringsType: {},
 		},
+		pdp.TypeDateTime: {
+			timeType: {},
+		},
+		pdp.TypeDuration: {
+			durationType: {},
+			int64Type:    {},
+		},
 	}
 
 	typeByTag = map[string]map[reflect.Type]struct{}{}
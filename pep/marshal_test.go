@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/infobloxopen/go-trees/domain"
 	"github.com/infobloxopen/go-trees/domaintree"
@@ -54,6 +55,13 @@ type TestTaggedStruct struct {
 	strlist  []string         `pdp:"ls,list of strings"`
 }
 
+type TestTimeStruct struct {
+	Time     time.Time     `pdp:""`
+	Duration time.Duration `pdp:""`
+	expires  time.Time     `pdp:"e,datetime"`
+	ttl      int64         `pdp:"ttl,duration"`
+}
+
 type TestInvalidStruct1 struct {
 	String string `pdp:",address"`
 }
@@ -178,6 +186,44 @@ func TestMarshalTaggedStruct(t *testing.T) {
 	)
 }
 
+func TestMarshalTimeStruct(t *testing.T) {
+	var b [60]byte
+
+	v := TestTimeStruct{
+		Time:     time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC),
+		Duration: 2 * time.Minute,
+		expires:  time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC),
+		ttl:      int64(2 * time.Minute),
+	}
+
+	n, err := marshalValueToBuffer(reflect.ValueOf(v), b[:])
+	assertBytesBuffer(t, "marshalValueToBuffer(TestTimeStruct)", err, b[:], n,
+		1, 0,
+		4, 0,
+		4, 'T', 'i', 'm', 'e', 15, 0, 50, 131, 186, 2, 233, 117, 21,
+		8, 'D', 'u', 'r', 'a', 't', 'i', 'o', 'n', 16, 0, 176, 142, 240, 27, 0, 0, 0,
+		1, 'e', 15, 0, 50, 131, 186, 2, 233, 117, 21,
+		3, 't', 't', 'l', 16, 0, 176, 142, 240, 27, 0, 0, 0,
+	)
+}
+
+func TestMarshalSetOfStringsFromSlice(t *testing.T) {
+	var b [19]byte
+
+	v := struct {
+		groups []string `pdp:"g,set of strings"`
+	}{
+		groups: []string{"one", "two", "one"},
+	}
+
+	n, err := marshalValueToBuffer(reflect.ValueOf(v), b[:])
+	assertBytesBuffer(t, "marshalValueToBuffer(set of strings from slice)", err, b[:], n,
+		1, 0,
+		1, 0,
+		1, 'g', 10, 2, 0, 3, 0, 'o', 'n', 'e', 3, 0, 't', 'w', 'o',
+	)
+}
+
 func TestMarshalInvalidStructs(t *testing.T) {
 	b, err := marshalValue(reflect.ValueOf(TestInvalidStruct1{}))
 	if err == nil {
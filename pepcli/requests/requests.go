@@ -13,8 +13,12 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/infobloxopen/go-trees/domain"
+	"github.com/infobloxopen/go-trees/domaintree"
+	"github.com/infobloxopen/go-trees/iptree"
+	"github.com/infobloxopen/go-trees/strtree"
 	"github.com/infobloxopen/themis/pdp"
 	pb "github.com/infobloxopen/themis/pdp-service"
 )
@@ -64,28 +68,16 @@ func Load(data string, size uint32) ([]pb.Msg, error) {
 		}
 	}
 
-	symbols := make(map[string]pdp.Type)
-	for k, v := range in.Attributes {
-		t, ok := pdp.BuiltinTypes[strings.ToLower(v)]
-		if !ok {
-			return nil, fmt.Errorf("unknown type %q of %q attribute", v, k)
-		}
-
-		symbols[k] = t
+	symbols, err := MakeSymbols(in.Attributes)
+	if err != nil {
+		return nil, err
 	}
 
 	out := make([]pb.Msg, len(in.Requests))
 	for i, r := range in.Requests {
-		attrs := make([]pdp.AttributeAssignment, len(r))
-		j := 0
-		for k, v := range r {
-			a, err := makeAttribute(k, v, symbols)
-			if err != nil {
-				return nil, fmt.Errorf("invalid attribute in request %d: %s", i+1, err)
-			}
-
-			attrs[j] = a
-			j++
+		attrs, err := MakeAssignments(r, symbols)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute in request %d: %s", i+1, err)
 		}
 
 		b := make([]byte, 10240)
@@ -100,6 +92,41 @@ func Load(data string, size uint32) ([]pb.Msg, error) {
 	return out, nil
 }
 
+// MakeSymbols converts attribute declarations (map of attribute names to
+// type names) to map of attribute names to types.
+func MakeSymbols(attrs map[string]string) (map[string]pdp.Type, error) {
+	symbols := make(map[string]pdp.Type, len(attrs))
+	for k, v := range attrs {
+		t, ok := pdp.BuiltinTypes[strings.ToLower(v)]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %q of %q attribute", v, k)
+		}
+
+		symbols[k] = t
+	}
+
+	return symbols, nil
+}
+
+// MakeAssignments converts request (map of attribute names to values) to list
+// of attribute assignments. Attribute types are taken from symbols or guessed
+// from values for undeclared attributes.
+func MakeAssignments(r map[string]interface{}, symbols map[string]pdp.Type) ([]pdp.AttributeAssignment, error) {
+	attrs := make([]pdp.AttributeAssignment, len(r))
+	i := 0
+	for k, v := range r {
+		a, err := makeAttribute(k, v, symbols)
+		if err != nil {
+			return nil, err
+		}
+
+		attrs[i] = a
+		i++
+	}
+
+	return attrs, nil
+}
+
 type attributeMarshaller func(value interface{}) (pdp.AttributeValue, error)
 
 var marshallers = map[pdp.Type]attributeMarshaller{
@@ -110,7 +137,12 @@ var marshallers = map[pdp.Type]attributeMarshaller{
 	pdp.TypeAddress:       addressMarshaller,
 	pdp.TypeNetwork:       networkMarshaller,
 	pdp.TypeDomain:        domainMarshaller,
-	pdp.TypeListOfStrings: listOfStringsMarshaller}
+	pdp.TypeSetOfStrings:  setOfStringsMarshaller,
+	pdp.TypeSetOfNetworks: setOfNetworksMarshaller,
+	pdp.TypeSetOfDomains:  setOfDomainsMarshaller,
+	pdp.TypeListOfStrings: listOfStringsMarshaller,
+	pdp.TypeDateTime:      dateTimeMarshaller,
+	pdp.TypeDuration:      durationMarshaller}
 
 func makeAttribute(name string, value interface{}, symbols map[string]pdp.Type) (pdp.AttributeAssignment, error) {
 	t, ok := symbols[name]
@@ -150,6 +182,8 @@ func guessType(value interface{}) (pdp.Type, error) {
 		return pdp.TypeNetwork, nil
 	case *net.IPNet:
 		return pdp.TypeNetwork, nil
+	case time.Time:
+		return pdp.TypeDateTime, nil
 	case []interface{}:
 		if len(value) == 0 {
 			return pdp.TypeUndefined, fmt.Errorf("unable to unmarshal empty array of unknown type %T", value)
@@ -325,3 +359,109 @@ func listOfStringsMarshaller(value interface{}) (pdp.AttributeValue, error) {
 
 	return pdp.MakeListOfStringsValue(los), nil
 }
+
+func setOfStringsMarshaller(value interface{}) (pdp.AttributeValue, error) {
+	v, ok := value.([]interface{})
+	if !ok {
+		return pdp.UndefinedValue, fmt.Errorf("can't marshal %T as set of strings", value)
+	}
+
+	ss := strtree.NewTree()
+	n := 0
+	for i, s := range v {
+		str, ok := s.(string)
+		if !ok {
+			return pdp.UndefinedValue, fmt.Errorf("can't marshal %T at %d as string in set of strings", s, i)
+		}
+
+		if _, ok := ss.Get(str); !ok {
+			ss.InplaceInsert(str, n)
+			n++
+		}
+	}
+
+	return pdp.MakeSetOfStringsValue(ss), nil
+}
+
+func setOfNetworksMarshaller(value interface{}) (pdp.AttributeValue, error) {
+	v, ok := value.([]interface{})
+	if !ok {
+		return pdp.UndefinedValue, fmt.Errorf("can't marshal %T as set of networks", value)
+	}
+
+	sn := iptree.NewTree()
+	for i, s := range v {
+		str, ok := s.(string)
+		if !ok {
+			return pdp.UndefinedValue, fmt.Errorf("can't marshal %T at %d as network in set of networks", s, i)
+		}
+
+		if a := net.ParseIP(str); a != nil {
+			sn.InplaceInsertIP(a, i)
+			continue
+		}
+
+		_, n, err := net.ParseCIDR(str)
+		if err != nil {
+			return pdp.UndefinedValue, fmt.Errorf("can't marshal \"%s\" at %d as network in set of networks", str, i)
+		}
+
+		sn.InplaceInsertNet(n, i)
+	}
+
+	return pdp.MakeSetOfNetworksValue(sn), nil
+}
+
+func setOfDomainsMarshaller(value interface{}) (pdp.AttributeValue, error) {
+	v, ok := value.([]interface{})
+	if !ok {
+		return pdp.UndefinedValue, fmt.Errorf("can't marshal %T as set of domains", value)
+	}
+
+	sd := &domaintree.Node{}
+	for i, s := range v {
+		str, ok := s.(string)
+		if !ok {
+			return pdp.UndefinedValue, fmt.Errorf("can't marshal %T at %d as domain in set of domains", s, i)
+		}
+
+		d, err := domain.MakeNameFromString(str)
+		if err != nil {
+			return pdp.UndefinedValue, fmt.Errorf("can't marshal %q at %d as domain in set of domains: %s", str, i, err)
+		}
+
+		sd.InplaceInsert(d, i)
+	}
+
+	return pdp.MakeSetOfDomainsValue(sd), nil
+}
+
+func dateTimeMarshaller(value interface{}) (pdp.AttributeValue, error) {
+	switch value := value.(type) {
+	case time.Time:
+		return pdp.MakeDateTimeValue(value), nil
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return pdp.UndefinedValue, fmt.Errorf("can't marshal \"%s\" as datetime: %s", value, err)
+		}
+
+		return pdp.MakeDateTimeValue(t), nil
+	}
+
+	return pdp.UndefinedValue, fmt.Errorf("can't marshal %T as datetime", value)
+}
+
+func durationMarshaller(value interface{}) (pdp.AttributeValue, error) {
+	s, ok := value.(string)
+	if !ok {
+		return pdp.UndefinedValue, fmt.Errorf("can't marshal %T as duration", value)
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return pdp.UndefinedValue, fmt.Errorf("can't marshal %q as duration: %s", s, err)
+	}
+
+	return pdp.MakeDurationValue(d), nil
+}
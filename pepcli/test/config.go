@@ -9,7 +9,8 @@ import (
 )
 
 type config struct {
-	sort bool
+	sort  bool
+	trace bool
 }
 
 var testFlagSet = flag.NewFlagSet(Name, flag.ExitOnError)
@@ -20,6 +21,8 @@ func FlagsParser(args []string) interface{} {
 
 	testFlagSet.Usage = usage
 	testFlagSet.BoolVar(&conf.sort, "sort", false, "sort lists of strings in returned obligations")
+	testFlagSet.BoolVar(&conf.trace, "trace", false,
+		"request evaluation trace (PDP server should run with -decision-trace option)")
 	testFlagSet.Parse(args)
 
 	count := testFlagSet.NArg()
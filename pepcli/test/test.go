@@ -34,6 +34,18 @@ func Exec(addr string, opts []pep.Option, maxRequestSize, maxResponseObligations
 		n = len(reqs)
 	}
 
+	conf := v.(config)
+	if conf.trace {
+		for i := range reqs {
+			reqs[i].Body, err = addTraceAttribute(reqs[i].Body)
+			if err != nil {
+				return fmt.Errorf("can't request trace for request %d: %s", i, err)
+			}
+		}
+
+		maxResponseObligations++
+	}
+
 	f := os.Stdout
 	if len(out) > 0 {
 		f, err = os.Create(out)
@@ -62,7 +74,7 @@ func Exec(addr string, opts []pep.Option, maxRequestSize, maxResponseObligations
 			return fmt.Errorf("can't send request %d (%d): %s", idx, i, err)
 		}
 
-		err = dump(res, f, v.(config).sort)
+		err = dump(res, f, conf.sort)
 		if err != nil {
 			return fmt.Errorf("can't dump response for reqiest %d (%d): %s", idx, i, err)
 		}
@@ -79,27 +91,46 @@ func dump(r pdp.Response, f io.Writer, s bool) error {
 		lines = append(lines, fmt.Sprintf("  reason: %q", r.Status))
 	}
 
-	if len(r.Obligations) > 0 {
-		lines = append(lines, "  obligation:")
-		for i, o := range r.Obligations {
-			id, t, v, err := o.Serialize(nil)
+	trace := ""
+	obligations := make([]string, 0, 4*len(r.Obligations))
+	for i, o := range r.Obligations {
+		id, t, v, err := o.Serialize(nil)
+		if err != nil {
+			return fmt.Errorf("can't get %d obligation: %s", i+1, err)
+		}
+
+		if id == pdp.TraceAttribute && t == pdp.TypeString.GetKey() {
+			trace, err = formatTrace(v)
 			if err != nil {
-				return fmt.Errorf("can't get %d obligation: %s", i+1, err)
+				return fmt.Errorf("can't get trace: %s", err)
 			}
 
-			if s && t == "list of strings" {
-				if list, err := sortListOfStrings(v, ","); err == nil {
-					v = list
-				} else {
-					return fmt.Errorf("can't sort list of strings: %s", err)
-				}
-			}
+			continue
+		}
 
-			lines = append(lines, fmt.Sprintf("    - id: %q", id))
-			lines = append(lines, fmt.Sprintf("      type: %q", t))
-			lines = append(lines, fmt.Sprintf("      value: %q", v))
-			lines = append(lines, "")
+		if s && t == "list of strings" {
+			if list, err := sortListOfStrings(v, ","); err == nil {
+				v = list
+			} else {
+				return fmt.Errorf("can't sort list of strings: %s", err)
+			}
 		}
+
+		obligations = append(obligations,
+			fmt.Sprintf("    - id: %q", id),
+			fmt.Sprintf("      type: %q", t),
+			fmt.Sprintf("      value: %q", v),
+			"",
+		)
+	}
+
+	if len(trace) > 0 {
+		lines = append(lines, "  trace: |", trace)
+	}
+
+	if len(obligations) > 0 {
+		lines = append(lines, "  obligation:")
+		lines = append(lines, obligations...)
 	} else {
 		lines = append(lines, "")
 	}
@@ -108,6 +139,29 @@ func dump(r pdp.Response, f io.Writer, s bool) error {
 	return err
 }
 
+func addTraceAttribute(b []byte) ([]byte, error) {
+	in, err := pdp.UnmarshalRequestAssignments(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return pdp.MarshalRequestAssignments(append(in, pdp.MakeBooleanAssignment(pdp.TraceAttribute, true)))
+}
+
+func formatTrace(s string) (string, error) {
+	t := new(pdp.Trace)
+	if err := json.Unmarshal([]byte(s), t); err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(t.String(), "\n")
+	for i, line := range lines {
+		lines[i] = "    " + line
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
 func sortListOfStrings(unsortedList, delimiter string) (string, error) {
 	var list []string
 	if err := json.Unmarshal([]byte("["+unsortedList+"]"), &list); err != nil {
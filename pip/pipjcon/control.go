@@ -82,6 +82,10 @@ func (s *srv) Upload(stream pb.PDPControl_UploadServer) error {
 		return stream.SendAndClose(ctrlError(err.Error()))
 	}
 
+	s.Lock()
+	u.uploaded = true
+	s.Unlock()
+
 	return stream.SendAndClose(ctrlAckID(id))
 }
 
@@ -119,6 +123,12 @@ func (s *srv) Apply(ctx context.Context, in *pb.Update) (*pb.Response, error) {
 
 	if u.t != nil {
 		s.Lock()
+		if _, err := s.c.GetLocalContent(u.id, u.fromTag); err != nil {
+			s.Unlock()
+
+			return ctrlTagErrorf("can't apply content %q transaction %d: %s", u.id, in.Id, err), nil
+		}
+
 		c, err := u.t.Commit(s.c)
 		if err != nil {
 			s.Unlock()
@@ -149,6 +159,79 @@ func (s *srv) NotifyReady(context.Context, *pb.Empty) (*pb.Response, error) {
 	return ctrlAck(), nil
 }
 
+func (s *srv) ListVersions(context.Context, *pb.VersionRequest) (*pb.VersionList, error) {
+	return &pb.VersionList{
+		Status:  pb.Response_ERROR,
+		Details: "content versions aren't kept",
+	}, nil
+}
+
+func (s *srv) Rollback(context.Context, *pb.RollbackRequest) (*pb.Response, error) {
+	return ctrlError("content versions aren't kept"), nil
+}
+
+func (s *srv) Status(context.Context, *pb.Empty) (*pb.ServerStatus, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	out := &pb.ServerStatus{
+		Status:  pb.Response_ACK,
+		Ready:   s.ss != nil,
+		Symbols: &pb.SymbolsSummary{},
+	}
+
+	for _, c := range s.c.GetLocalContents() {
+		out.Contents = append(out.Contents, &pb.ContentStatus{
+			Id:    c.GetID(),
+			Tag:   tagString(c.GetTag()),
+			Items: int32(c.GetItemsCount()),
+		})
+	}
+
+	if u := s.u; u != nil {
+		out.Queue = []*pb.QueueEntry{{
+			Id:        s.uIdx,
+			Type:      pb.Item_CONTENT,
+			ContentId: u.id,
+			FromTag:   tagString(u.fromTag),
+			ToTag:     tagString(u.toTag),
+			Uploaded:  u.uploaded,
+		}}
+	}
+
+	return out, nil
+}
+
+func (s *srv) DeleteContent(ctx context.Context, in *pb.DeleteRequest) (*pb.Response, error) {
+	log.WithFields(log.Fields{
+		"ctn-id": in.Id,
+		"tag":    in.Tag,
+	}).Info("content delete command")
+
+	tag, err := newTag(in.Tag)
+	if err != nil {
+		return ctrlTagErrorf("can't treat %q as current tag: %s", in.Tag, err), nil
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	c, err := s.c.Delete(in.Id, tag)
+	if err != nil {
+		switch err.(type) {
+		case *pdp.UntaggedContentModificationError, *pdp.ContentTagsNotMatchError:
+			return ctrlTagError(err.Error()), nil
+		}
+
+		return ctrlError(err.Error()), nil
+	}
+
+	s.c = c
+	log.WithField("ctn-id", in.Id).Info("content has been deleted")
+
+	return ctrlAck(), nil
+}
+
 func (s *srv) contentRequest(id string, fromTag, toTag *uuid.UUID) (int32, error) {
 	s.Lock()
 	defer s.Unlock()
@@ -189,6 +272,14 @@ func newTag(s string) (*uuid.UUID, error) {
 	return nil, nil
 }
 
+func tagString(tag *uuid.UUID) string {
+	if tag != nil {
+		return tag.String()
+	}
+
+	return ""
+}
+
 func ctrlAck() *pb.Response {
 	return ctrlAckID(0)
 }
@@ -17,8 +17,9 @@ var (
 )
 
 type update struct {
-	id    string
-	inUse bool
+	id       string
+	inUse    bool
+	uploaded bool
 
 	fromTag *uuid.UUID
 	toTag   *uuid.UUID
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/infobloxopen/themis/pdp/ast"
+)
+
+const (
+	policyFormatNameYAML = "yaml"
+	policyFormatNameJSON = "json"
+)
+
+var policyParsers = map[string]ast.Parser{
+	policyFormatNameYAML: ast.NewYAMLParser(),
+	policyFormatNameJSON: ast.NewJSONParser(),
+}
+
+type config struct {
+	oldPath      string
+	newPath      string
+	inputFormat  string
+	outputFormat string
+	output       string
+	content      bool
+	fromTag      uuid.UUID
+	toTag        uuid.UUID
+}
+
+var conf config
+
+func init() {
+	flag.Usage = usage
+
+	flag.StringVar(&conf.inputFormat, "pfmt", "",
+		"policy data format \"yaml\" or \"json\" (default is guessed from file extension)")
+	flag.StringVar(&conf.outputFormat, "ofmt", "",
+		"update format \"yaml\" or \"json\" (default is the same as format of new policy)")
+	flag.StringVar(&conf.output, "o", "", "file to write update (default stdout)")
+	flag.BoolVar(&conf.content, "c", false,
+		"compare JCON content instead of policies (update is always in JCON format)")
+	fromTag := flag.String("vf", "", "tag of old data (the update can be applied only to data with the tag)")
+	toTag := flag.String("vt", "", "new tag to set by the update (default random)")
+
+	flag.Parse()
+
+	for _, f := range []string{conf.inputFormat, conf.outputFormat} {
+		if len(f) > 0 {
+			if _, ok := policyParsers[strings.ToLower(f)]; !ok {
+				fmt.Fprintf(os.Stderr, "unknown format %q\n", f)
+				os.Exit(2)
+			}
+		}
+	}
+
+	if flag.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "expected old and new data files")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	conf.oldPath = flag.Arg(0)
+	conf.newPath = flag.Arg(1)
+
+	t, err := uuid.Parse(*fromTag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't treat %q as tag of old data: %s\n", *fromTag, err)
+		os.Exit(2)
+	}
+	conf.fromTag = t
+
+	if len(*toTag) > 0 {
+		t, err = uuid.Parse(*toTag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "can't treat %q as new tag: %s\n", *toTag, err)
+			os.Exit(2)
+		}
+		conf.toTag = t
+	} else {
+		conf.toTag = uuid.New()
+	}
+}
+
+func usage() {
+	base := path.Base(os.Args[0])
+	fmt.Fprintf(os.Stderr,
+		"Usage of %s:\n\n"+
+			"  %s [OPTIONS] -vf tag old new\n"+
+			"  %s -c [OPTIONS] -vf tag old.json new.json\n\n"+
+			"OPTIONS:\n", base, base, base)
+	flag.PrintDefaults()
+}
+
+func guessFormat(f, path string) string {
+	if len(f) > 0 {
+		return strings.ToLower(f)
+	}
+
+	if strings.HasSuffix(strings.ToLower(path), ".json") {
+		return policyFormatNameJSON
+	}
+
+	return policyFormatNameYAML
+}
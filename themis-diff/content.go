@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/infobloxopen/themis/pdp"
+	"github.com/infobloxopen/themis/pdp/jcon"
+)
+
+func loadContent(path string) (*pdp.LocalContent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return jcon.Unmarshal(f, nil)
+}
+
+func diffContent() ([]byte, int, error) {
+	o, err := loadContent(conf.oldPath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("can't load old content %q: %s", conf.oldPath, err)
+	}
+
+	n, err := loadContent(conf.newPath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("can't load new content %q: %s", conf.newPath, err)
+	}
+
+	u, err := pdp.DiffContent(o, n, conf.fromTag, conf.toTag)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	count := 0
+	u.Iterate(func(op int, path []string, entity *pdp.ContentItem) error {
+		count++
+		return nil
+	})
+
+	b, err := json.MarshalIndent(u, "", "  ")
+	return b, count, err
+}
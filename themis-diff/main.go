@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	_ "github.com/infobloxopen/themis/pdp/selector"
+)
+
+func main() {
+	diff := diffPolicies
+	if conf.content {
+		diff = diffContent
+	}
+
+	b, n, err := diff()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to make update: %s\n", err)
+		os.Exit(1)
+	}
+
+	if len(b) > 0 && b[len(b)-1] != '\n' {
+		b = append(b, '\n')
+	}
+
+	if len(conf.output) > 0 {
+		err = ioutil.WriteFile(conf.output, b, 0644)
+	} else {
+		_, err = os.Stdout.Write(b)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write update: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stderr, "update with %d commands from %s to %s\n", n, conf.fromTag, conf.toTag)
+}
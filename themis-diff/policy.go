@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/infobloxopen/themis/pdp"
+)
+
+// policyDocument holds both parsed policies and their raw representation.
+// The latter is used to get source of entities for update commands.
+type policyDocument struct {
+	s   *pdp.PolicyStorage
+	raw interface{}
+}
+
+func loadPolicyDocument(path, format string) (*policyDocument, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := policyParsers[format].Unmarshal(bytes.NewReader(b), nil)
+	if err != nil {
+		pdp.SetErrorSourceFile(err, path)
+		return nil, err
+	}
+
+	// JSON is (almost) a subset of YAML so YAML parser reads both formats.
+	// Parsing to MapSlice keeps order of keys which matters for JAST.
+	var raw yaml.MapSlice
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	return &policyDocument{s: s, raw: raw}, nil
+}
+
+func diffPolicies() ([]byte, int, error) {
+	oldDoc, err := loadPolicyDocument(conf.oldPath, guessFormat(conf.inputFormat, conf.oldPath))
+	if err != nil {
+		return nil, 0, fmt.Errorf("can't load old policies %q: %s", conf.oldPath, err)
+	}
+
+	newFormat := guessFormat(conf.inputFormat, conf.newPath)
+	newDoc, err := loadPolicyDocument(conf.newPath, newFormat)
+	if err != nil {
+		return nil, 0, fmt.Errorf("can't load new policies %q: %s", conf.newPath, err)
+	}
+
+	u, err := pdp.DiffPolicies(oldDoc.s, newDoc.s, conf.fromTag, conf.toTag)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	root, err := getRawChild(newDoc.raw, "policies")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cmds := []yaml.MapSlice{}
+	err = u.Iterate(func(op int, path []string, entity interface{}) error {
+		cmd := yaml.MapSlice{
+			{Key: "op", Value: strings.ToLower(pdp.UpdateOpNames[op])},
+			{Key: "path", Value: path},
+		}
+
+		if op == pdp.UOAdd {
+			e, err := getRawEntity(root, path, entity)
+			if err != nil {
+				return err
+			}
+
+			cmd = append(cmd, yaml.MapItem{Key: "entity", Value: e})
+		}
+
+		cmds = append(cmds, cmd)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	b, err := marshalRaw(cmds, guessFormat(conf.outputFormat, conf.newPath))
+	return b, len(cmds), err
+}
+
+// getRawEntity returns source of entity to add at given path. Source of
+// policy set or policy includes its variables section so an update which
+// replaces the entity because its variables differ brings new ones.
+func getRawEntity(root interface{}, path []string, entity interface{}) (interface{}, error) {
+	var ID string
+	switch e := entity.(type) {
+	case *pdp.Rule:
+		ID, _ = e.GetID()
+
+	case pdp.Evaluable:
+		ID, _ = e.GetID()
+	}
+
+	if len(path) <= 0 {
+		return root, nil
+	}
+
+	if rootID, _ := getRawID(root); rootID != path[0] {
+		return nil, fmt.Errorf("expected root %q but got %q", path[0], rootID)
+	}
+
+	ids := make([]string, len(path))
+	copy(ids, path[1:])
+	ids[len(ids)-1] = ID
+
+	node := root
+	for _, childID := range ids {
+		child, err := getRawItem(node, childID)
+		if err != nil {
+			return nil, err
+		}
+
+		node = child
+	}
+
+	return node, nil
+}
+
+func getRawItem(node interface{}, ID string) (interface{}, error) {
+	for _, key := range []string{"policies", "rules"} {
+		list, err := getRawChild(node, key)
+		if err != nil {
+			continue
+		}
+
+		items, ok := list.([]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, item := range items {
+			if itemID, ok := getRawID(item); ok && itemID == ID {
+				return item, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("can't find source of %q", ID)
+}
+
+func getRawID(node interface{}) (string, bool) {
+	v, err := getRawChild(node, "id")
+	if err != nil {
+		return "", false
+	}
+
+	s, ok := v.(string)
+	return s, ok
+}
+
+func getRawChild(node interface{}, key string) (interface{}, error) {
+	switch m := node.(type) {
+	case yaml.MapSlice:
+		for _, item := range m {
+			if k, ok := item.Key.(string); ok && strings.ToLower(k) == key {
+				return item.Value, nil
+			}
+		}
+
+	case map[interface{}]interface{}:
+		if v, ok := m[key]; ok {
+			return v, nil
+		}
+
+	case map[string]interface{}:
+		for k, v := range m {
+			if strings.ToLower(k) == key {
+				return v, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no %q found", key)
+}
+
+// marshalRaw converts raw data to given format.
+func marshalRaw(v interface{}, format string) ([]byte, error) {
+	if format == policyFormatNameJSON {
+		return json.MarshalIndent(normalizeRaw(v, true), "", "  ")
+	}
+
+	return yaml.Marshal(normalizeRaw(v, false))
+}
+
+// normalizeRaw makes raw data marshallable to JSON (if toJSON is true) or YAML.
+// JSON requires maps with string keys and JAST requires certain order of keys.
+func normalizeRaw(v interface{}, toJSON bool) interface{} {
+	switch v := v.(type) {
+	case yaml.MapSlice:
+		if !toJSON {
+			out := make(yaml.MapSlice, len(v))
+			for i, item := range v {
+				out[i] = yaml.MapItem{Key: item.Key, Value: normalizeRaw(item.Value, toJSON)}
+			}
+
+			return out
+		}
+
+		out := make(jsonMapSlice, len(v))
+		for i, item := range v {
+			out[i] = yaml.MapItem{Key: fmt.Sprintf("%v", item.Key), Value: normalizeRaw(item.Value, toJSON)}
+		}
+
+		return out
+
+	case map[interface{}]interface{}:
+		if !toJSON {
+			out := make(map[interface{}]interface{}, len(v))
+			for k, item := range v {
+				out[k] = normalizeRaw(item, toJSON)
+			}
+
+			return out
+		}
+
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[fmt.Sprintf("%v", k)] = normalizeRaw(item, toJSON)
+		}
+
+		return out
+
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = normalizeRaw(item, toJSON)
+		}
+
+		return out
+
+	case []yaml.MapSlice:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalizeRaw(item, toJSON)
+		}
+
+		return out
+
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalizeRaw(item, toJSON)
+		}
+
+		return out
+
+	}
+
+	return v
+}
+
+// jsonMapSlice marshals to JSON object keeping order of keys.
+type jsonMapSlice yaml.MapSlice
+
+func (m jsonMapSlice) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteByte('{')
+	for i, item := range m {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		k, err := json.Marshal(item.Key)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+
+		v, err := json.Marshal(item.Value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(v)
+	}
+	b.WriteByte('}')
+
+	return b.Bytes(), nil
+}
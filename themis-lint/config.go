@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/infobloxopen/themis/pdp/ast"
+)
+
+const (
+	policyFormatNameYAML = "yaml"
+	policyFormatNameJSON = "json"
+)
+
+var policyParsers = map[string]ast.Parser{
+	policyFormatNameYAML: ast.NewYAMLParser(),
+	policyFormatNameJSON: ast.NewJSONParser(),
+}
+
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+type config struct {
+	policy       string
+	policyParser ast.Parser
+	content      stringSet
+	output       string
+}
+
+type stringSet []string
+
+func (s *stringSet) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringSet) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+var conf config
+
+func init() {
+	flag.StringVar(&conf.policy, "p", "", "policy file to check")
+	policyFmt := flag.String("pfmt", policyFormatNameYAML, "policy data format \"yaml\" or \"json\"")
+	flag.Var(&conf.content, "j", "JSON content files to check selectors against")
+	flag.StringVar(&conf.output, "o", outputFormatText, "output format \"text\" or \"json\"")
+
+	flag.Parse()
+
+	if len(conf.policy) <= 0 {
+		fmt.Fprintln(os.Stderr, "no policy file given")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	p, ok := policyParsers[strings.ToLower(*policyFmt)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown policy format %q\n", *policyFmt)
+		os.Exit(2)
+	}
+	conf.policyParser = p
+
+	conf.output = strings.ToLower(conf.output)
+	if conf.output != outputFormatText && conf.output != outputFormatJSON {
+		fmt.Fprintf(os.Stderr, "unknown output format %q\n", conf.output)
+		os.Exit(2)
+	}
+}
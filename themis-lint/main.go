@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/infobloxopen/themis/pdp"
+	"github.com/infobloxopen/themis/pdp/jcon"
+	_ "github.com/infobloxopen/themis/pdp/selector"
+)
+
+func main() {
+	p, err := loadPolicy(conf.policy)
+	if err != nil {
+		if pos, ok := pdp.GetErrorPosition(err); ok {
+			if err := dump([]pdp.LintIssue{{
+				Kind:     pdp.LintParseError,
+				Position: &pos,
+				Message:  err.Error(),
+			}}); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to dump issues: %s\n", err)
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "failed to load policy %q: %s\n", conf.policy, err)
+		}
+
+		os.Exit(2)
+	}
+
+	var c *pdp.LocalContentStorage
+	if len(conf.content) > 0 {
+		c, err = loadContent(conf.content)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load content: %s\n", err)
+			os.Exit(2)
+		}
+	}
+
+	issues := p.Lint(c)
+	if err := dump(issues); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to dump issues: %s\n", err)
+		os.Exit(2)
+	}
+
+	if len(issues) > 0 {
+		os.Exit(1)
+	}
+}
+
+func loadPolicy(path string) (*pdp.PolicyStorage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p, err := conf.policyParser.Unmarshal(f, nil)
+	if err != nil {
+		pdp.SetErrorSourceFile(err, path)
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func loadContent(paths []string) (*pdp.LocalContentStorage, error) {
+	items := []*pdp.LocalContent{}
+	for _, path := range paths {
+		item, err := func() (*pdp.LocalContent, error) {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+
+			return jcon.Unmarshal(f, nil)
+		}()
+		if err != nil {
+			return nil, fmt.Errorf("%q: %s", path, err)
+		}
+
+		items = append(items, item)
+	}
+
+	return pdp.NewLocalContentStorage(items), nil
+}
+
+func dump(issues []pdp.LintIssue) error {
+	if conf.output == outputFormatJSON {
+		if issues == nil {
+			issues = []pdp.LintIssue{}
+		}
+
+		b, err := json.MarshalIndent(issues, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(os.Stdout, "%s\n", b)
+		return err
+	}
+
+	for _, i := range issues {
+		if _, err := fmt.Fprintln(os.Stdout, i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/infobloxopen/themis/pdp/ast"
+)
+
+const (
+	policyFormatNameYAML = "yaml"
+	policyFormatNameJSON = "json"
+)
+
+var policyParsers = map[string]ast.Parser{
+	policyFormatNameYAML: ast.NewYAMLParser(),
+	policyFormatNameJSON: ast.NewJSONParser(),
+}
+
+const (
+	coverageFormatText = "text"
+	coverageFormatJSON = "json"
+)
+
+type config struct {
+	policy       string
+	policyFormat string
+	content      stringSet
+	junit        string
+	verbose      bool
+	coverage     string
+	coverageFmt  string
+	tests        []string
+}
+
+type stringSet []string
+
+func (s *stringSet) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringSet) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+var conf config
+
+// parseFlags fills conf from command line. It's called from main rather
+// than from init so the package can be tested.
+func parseFlags() {
+	flag.Usage = usage
+
+	flag.StringVar(&conf.policy, "p", "", "policy file to test (overrides \"policy\" field of test files)")
+	flag.StringVar(&conf.policyFormat, "pfmt", "",
+		"policy data format \"yaml\" or \"json\" (default is guessed from file extension)")
+	flag.Var(&conf.content, "j", "JSON content files (override \"content\" field of test files)")
+	flag.StringVar(&conf.junit, "junit", "", "file to write results in JUnit XML format")
+	flag.BoolVar(&conf.verbose, "v", false, "print passed tests as well")
+	flag.StringVar(&conf.coverage, "coverage", "", "file to write policy coverage report (\"-\" for standard output)")
+	flag.StringVar(&conf.coverageFmt, "coverage-fmt", coverageFormatText, "coverage report format \"text\" or \"json\"")
+
+	flag.Parse()
+
+	if len(conf.policyFormat) > 0 {
+		if _, ok := policyParsers[strings.ToLower(conf.policyFormat)]; !ok {
+			fmt.Fprintf(os.Stderr, "unknown policy format %q\n", conf.policyFormat)
+			os.Exit(2)
+		}
+	}
+
+	conf.coverageFmt = strings.ToLower(conf.coverageFmt)
+	if conf.coverageFmt != coverageFormatText && conf.coverageFmt != coverageFormatJSON {
+		fmt.Fprintf(os.Stderr, "unknown coverage report format %q\n", conf.coverageFmt)
+		os.Exit(2)
+	}
+
+	conf.tests = flag.Args()
+	if len(conf.tests) <= 0 {
+		fmt.Fprintln(os.Stderr, "no test files given")
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	base := path.Base(os.Args[0])
+	fmt.Fprintf(os.Stderr,
+		"Usage of %s:\n\n"+
+			"  %s [OPTIONS] test-file [test-file ...]\n\n"+
+			"OPTIONS:\n", base, base)
+	flag.PrintDefaults()
+}
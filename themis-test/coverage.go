@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/infobloxopen/themis/pdp"
+)
+
+// cov collects coverage of all loaded policies if coverage report has been
+// requested.
+var cov *pdp.Coverage
+
+type coverageReport struct {
+	Policy string `json:"policy"`
+	*pdp.CoverageReport
+}
+
+func writeCoverage(path string) error {
+	if path == "-" {
+		return dumpCoverage(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return dumpCoverage(f)
+}
+
+func dumpCoverage(w io.Writer) error {
+	reports := make([]coverageReport, len(policies))
+	for i, p := range policies {
+		reports[i] = coverageReport{
+			Policy:         p.path,
+			CoverageReport: cov.Report(p.p),
+		}
+	}
+
+	if conf.coverageFmt == coverageFormatJSON {
+		b, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+
+	for _, r := range reports {
+		if _, err := fmt.Fprintf(w, "policy %s\n%s\n", r.Policy, r.CoverageReport); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
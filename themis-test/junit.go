@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+func dumpJUnit(w io.Writer, results []suiteResult) error {
+	out := junitTestSuites{
+		Suites: make([]junitTestSuite, len(results)),
+	}
+
+	var total time.Duration
+	for i, r := range results {
+		s := junitTestSuite{
+			Name:     r.name,
+			Tests:    len(r.cases),
+			Failures: r.failures(),
+			Errors:   r.errors(),
+			Skipped:  r.skipped(),
+			Time:     junitTime(r.elapsed),
+			Cases:    make([]junitTestCase, 0, len(r.cases)+1),
+		}
+
+		if r.err != nil {
+			s.Tests++
+			s.Cases = append(s.Cases, junitTestCase{
+				Name:      "load",
+				ClassName: r.name,
+				Time:      junitTime(r.elapsed),
+				Error:     &junitMessage{Message: r.err.Error()},
+			})
+		}
+
+		for _, c := range r.cases {
+			tc := junitTestCase{
+				Name:      c.name,
+				ClassName: r.name,
+				Time:      junitTime(c.elapsed),
+			}
+
+			if c.err != nil {
+				tc.Error = &junitMessage{Message: c.err.Error()}
+			} else if c.unchecked {
+				tc.Skipped = &junitMessage{Message: "no expectations"}
+			} else if len(c.diffs) > 0 {
+				tc.Failure = &junitMessage{
+					Message: c.diffs[0],
+					Text:    strings.Join(c.diffs, "\n"),
+				}
+			}
+
+			s.Cases = append(s.Cases, tc)
+		}
+
+		out.Suites[i] = s
+		out.Tests += s.Tests
+		out.Failures += s.Failures
+		out.Errors += s.Errors
+		total += r.elapsed
+	}
+	out.Time = junitTime(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/infobloxopen/themis/pdp"
+	_ "github.com/infobloxopen/themis/pdp/selector"
+)
+
+func main() {
+	parseFlags()
+
+	if len(conf.coverage) > 0 {
+		cov = pdp.NewCoverage()
+	}
+
+	results := make([]suiteResult, len(conf.tests))
+	for i, path := range conf.tests {
+		results[i] = runSuite(path)
+	}
+
+	passed := dump(os.Stdout, results)
+
+	if len(conf.junit) > 0 {
+		if err := writeJUnit(conf.junit, results); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write JUnit report: %s\n", err)
+			os.Exit(2)
+		}
+	}
+
+	if cov != nil {
+		if err := writeCoverage(conf.coverage); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write coverage report: %s\n", err)
+			os.Exit(2)
+		}
+	}
+
+	if !passed {
+		os.Exit(1)
+	}
+}
+
+// dump writes results of all suites and returns false if any test has
+// failed. Cases without expectations (like requests) don't count as tests.
+func dump(w io.Writer, results []suiteResult) bool {
+	passed := true
+	tests, failed, unchecked := 0, 0, 0
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(w, "ERROR %s: %s\n", r.name, r.err)
+			passed = false
+			continue
+		}
+
+		for _, c := range r.cases {
+			if c.unchecked && c.err == nil {
+				unchecked++
+				if conf.verbose {
+					fmt.Fprintf(w, "SKIP %s: %s: no expectations\n", r.name, c.name)
+				}
+
+				continue
+			}
+
+			tests++
+			switch {
+			case c.err != nil:
+				fmt.Fprintf(w, "ERROR %s: %s: %s\n", r.name, c.name, c.err)
+
+			case len(c.diffs) > 0:
+				fmt.Fprintf(w, "FAIL %s: %s\n", r.name, c.name)
+				for _, d := range c.diffs {
+					fmt.Fprintf(w, "    %s\n", d)
+				}
+
+			default:
+				if conf.verbose {
+					fmt.Fprintf(w, "PASS %s: %s\n", r.name, c.name)
+				}
+
+				continue
+			}
+
+			failed++
+			passed = false
+		}
+	}
+
+	fmt.Fprintf(w, "%d tests, %d passed, %d failed", tests, tests-failed, failed)
+	if unchecked > 0 {
+		fmt.Fprintf(w, ", %d requests without expectations", unchecked)
+	}
+	fmt.Fprintln(w)
+
+	return passed
+}
+
+func writeJUnit(path string, results []suiteResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return dumpJUnit(f, results)
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/infobloxopen/themis/pdp"
+	"github.com/infobloxopen/themis/pdp/jcon"
+	"github.com/infobloxopen/themis/pepcli/requests"
+)
+
+type obligation struct {
+	ID    string `yaml:"id"`
+	Type  string `yaml:"type"`
+	Value string `yaml:"value"`
+}
+
+func (o obligation) String() string {
+	return fmt.Sprintf("%q (%s): %q", o.ID, o.Type, o.Value)
+}
+
+type testCase struct {
+	Name        string                 `yaml:"name"`
+	Request     map[string]interface{} `yaml:"request"`
+	Effect      string                 `yaml:"effect"`
+	Reason      string                 `yaml:"reason"`
+	Obligations *[]obligation          `yaml:"obligations"`
+}
+
+// hasExpectations checks if test case expects anything from response.
+func (c testCase) hasExpectations() bool {
+	return len(c.Effect) > 0 || len(c.Reason) > 0 || c.Obligations != nil
+}
+
+type suite struct {
+	Policy     string                   `yaml:"policy"`
+	Format     string                   `yaml:"format"`
+	Content    []string                 `yaml:"content"`
+	Attributes map[string]string        `yaml:"attributes"`
+	Tests      []testCase               `yaml:"tests"`
+	Requests   []map[string]interface{} `yaml:"requests"`
+
+	path string
+	p    *pdp.PolicyStorage
+	c    *pdp.LocalContentStorage
+}
+
+type caseResult struct {
+	name      string
+	diffs     []string
+	err       error
+	unchecked bool
+	elapsed   time.Duration
+}
+
+type suiteResult struct {
+	name    string
+	cases   []caseResult
+	err     error
+	elapsed time.Duration
+}
+
+func (r suiteResult) failures() int {
+	n := 0
+	for _, c := range r.cases {
+		if c.err == nil && len(c.diffs) > 0 {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (r suiteResult) skipped() int {
+	n := 0
+	for _, c := range r.cases {
+		if c.err == nil && c.unchecked {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (r suiteResult) errors() int {
+	n := 0
+	for _, c := range r.cases {
+		if c.err != nil {
+			n++
+		}
+	}
+
+	if r.err != nil {
+		n++
+	}
+
+	return n
+}
+
+func loadSuite(path string) (*suite, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &suite{path: path}
+	if err := yaml.Unmarshal(b, s); err != nil {
+		return nil, err
+	}
+
+	policy := conf.policy
+	if len(policy) <= 0 {
+		if len(s.Policy) <= 0 {
+			return nil, fmt.Errorf("no policy given")
+		}
+
+		policy = s.relPath(s.Policy)
+	}
+
+	p, err := loadPolicy(policy, s.Format)
+	if err != nil {
+		return nil, fmt.Errorf("can't load policy %q: %s", policy, err)
+	}
+	s.p = p
+
+	for i, r := range s.Requests {
+		s.Tests = append(s.Tests, testCase{
+			Name:    fmt.Sprintf("request #%d", i+1),
+			Request: r,
+		})
+	}
+
+	content := []string(conf.content)
+	if len(content) <= 0 {
+		content = make([]string, len(s.Content))
+		for i, c := range s.Content {
+			content[i] = s.relPath(c)
+		}
+	}
+
+	c, err := loadContent(content)
+	if err != nil {
+		return nil, err
+	}
+	s.c = c
+
+	return s, nil
+}
+
+func (s *suite) relPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(filepath.Dir(s.path), path)
+}
+
+type loadedPolicy struct {
+	path string
+	p    *pdp.PolicyStorage
+}
+
+// policies holds all loaded policies in order of loading. Test files which
+// point to the same policy share it so their requests count to the same
+// coverage report.
+var policies []loadedPolicy
+
+func loadPolicy(path, format string) (*pdp.PolicyStorage, error) {
+	if len(conf.policyFormat) > 0 {
+		format = conf.policyFormat
+	}
+
+	if len(format) <= 0 {
+		format = policyFormatNameYAML
+		if strings.ToLower(filepath.Ext(path)) == ".json" {
+			format = policyFormatNameJSON
+		}
+	}
+
+	parser, ok := policyParsers[strings.ToLower(format)]
+	if !ok {
+		return nil, fmt.Errorf("unknown policy format %q", format)
+	}
+
+	path = filepath.Clean(path)
+	for _, p := range policies {
+		if p.path == path {
+			return p.p, nil
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p, err := parser.Unmarshal(f, nil)
+	if err != nil {
+		pdp.SetErrorSourceFile(err, path)
+		return nil, err
+	}
+
+	policies = append(policies, loadedPolicy{path: path, p: p})
+	return p, nil
+}
+
+func loadContent(paths []string) (*pdp.LocalContentStorage, error) {
+	items := []*pdp.LocalContent{}
+	for _, path := range paths {
+		item, err := func() (*pdp.LocalContent, error) {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+
+			return jcon.Unmarshal(f, nil)
+		}()
+		if err != nil {
+			return nil, fmt.Errorf("can't load content %q: %s", path, err)
+		}
+
+		items = append(items, item)
+	}
+
+	return pdp.NewLocalContentStorage(items), nil
+}
+
+func runSuite(path string) suiteResult {
+	start := time.Now()
+	res := suiteResult{name: path}
+
+	s, err := loadSuite(path)
+	if err != nil {
+		res.err = err
+		res.elapsed = time.Since(start)
+		return res
+	}
+
+	symbols, err := requests.MakeSymbols(s.Attributes)
+	if err != nil {
+		res.err = err
+		res.elapsed = time.Since(start)
+		return res
+	}
+
+	res.cases = make([]caseResult, len(s.Tests))
+	for i, c := range s.Tests {
+		res.cases[i] = s.run(i, c, symbols)
+	}
+
+	res.elapsed = time.Since(start)
+	return res
+}
+
+func (s *suite) run(i int, c testCase, symbols map[string]pdp.Type) caseResult {
+	start := time.Now()
+	res := caseResult{name: c.Name}
+	if len(res.name) <= 0 {
+		res.name = fmt.Sprintf("#%d", i+1)
+	}
+
+	defer func() {
+		res.elapsed = time.Since(start)
+	}()
+
+	attrs, err := requests.MakeAssignments(c.Request, symbols)
+	if err != nil {
+		res.err = fmt.Errorf("invalid request: %s", err)
+		return res
+	}
+
+	ctx, err := pdp.NewContext(s.c, len(attrs), func(i int) (string, pdp.AttributeValue, error) {
+		v, err := attrs[i].GetValue()
+		return attrs[i].GetID(), v, err
+	})
+	if err != nil {
+		res.err = fmt.Errorf("can't create context: %s", err)
+		return res
+	}
+
+	if cov != nil {
+		ctx.EnableCoverage(cov)
+	}
+
+	r := s.p.Root().Calculate(ctx)
+	res.unchecked = !c.hasExpectations()
+	res.diffs, res.err = check(c, r, ctx)
+	return res
+}
+
+func check(c testCase, r pdp.Response, ctx *pdp.Context) ([]string, error) {
+	diffs := []string{}
+
+	effect := pdp.EffectNameFromEnum(r.Effect)
+	if len(c.Effect) > 0 && !strings.EqualFold(c.Effect, effect) {
+		diffs = append(diffs, fmt.Sprintf("effect: expected %q but got %q", c.Effect, effect))
+	}
+
+	reason := ""
+	if r.Status != nil {
+		reason = r.Status.Error()
+	}
+
+	if len(c.Reason) > 0 {
+		re, err := regexp.Compile(c.Reason)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reason pattern %q: %s", c.Reason, err)
+		}
+
+		if !re.MatchString(reason) {
+			diffs = append(diffs, fmt.Sprintf("reason: expected to match %q but got %q", c.Reason, reason))
+		}
+	}
+
+	if c.Obligations != nil {
+		obligations := make([]obligation, len(r.Obligations))
+		for i, o := range r.Obligations {
+			ID, t, v, err := o.Serialize(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("can't get %d obligation: %s", i+1, err)
+			}
+
+			obligations[i] = obligation{ID: ID, Type: t, Value: v}
+		}
+
+		diffs = append(diffs, diffObligations(*c.Obligations, obligations)...)
+	}
+
+	return diffs, nil
+}
+
+func diffObligations(e, a []obligation) []string {
+	same := len(e) == len(a)
+	for i := 0; same && i < len(e); i++ {
+		same = e[i].ID == a[i].ID && strings.EqualFold(e[i].Type, a[i].Type) && e[i].Value == a[i].Value
+	}
+
+	if same {
+		return nil
+	}
+
+	lines := []string{"obligations: expected (-) but got (+)"}
+	for _, o := range e {
+		lines = append(lines, "  - "+o.String())
+	}
+
+	for _, o := range a {
+		lines = append(lines, "  + "+o.String())
+	}
+
+	return lines
+}
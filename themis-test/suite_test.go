@@ -0,0 +1,273 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	testPolicy = `# Policy for test suites
+attributes:
+  s: string
+  r: string
+
+policies:
+  alg: FirstApplicableEffect
+  rules:
+  - id: Permit
+    condition:
+      equal:
+      - attr: s
+      - val:
+          type: string
+          content: test
+    effect: Permit
+    obligations:
+    - r:
+        val:
+          type: string
+          content: permit
+  - id: Deny
+    effect: Deny
+`
+
+	testSuite = `# Suite with passing, failing and broken cases
+policy: policy.yaml
+
+attributes:
+  s: string
+
+tests:
+- name: Pass
+  request:
+    s: test
+  effect: Permit
+  obligations:
+  - id: r
+    type: string
+    value: permit
+
+- name: Wrong effect
+  request:
+    s: other
+  effect: Permit
+
+- name: Wrong obligations
+  request:
+    s: test
+  obligations: []
+
+- name: Invalid request
+  request:
+    s:
+    - test
+  effect: Deny
+
+requests:
+- s: test
+- s: other
+`
+
+	testPassingSuite = `# Suite where all cases pass
+policy: policy.yaml
+
+attributes:
+  s: string
+
+tests:
+- name: Permit
+  request:
+    s: test
+  effect: permit
+  reason: "^$"
+
+- name: Deny
+  request:
+    s: other
+  effect: Deny
+  obligations: []
+`
+
+	testRequestsSuite = `# Suite with requests only
+policy: policy.yaml
+
+attributes:
+  s: string
+
+requests:
+- s: test
+- s: other
+`
+
+	testMissingPolicySuite = `# Suite which points to missing policy
+policy: missing.yaml
+`
+)
+
+func TestRunSuite(t *testing.T) {
+	dir := makeTestSuiteDir(t)
+	defer os.RemoveAll(dir)
+
+	r := runSuite(filepath.Join(dir, "suite.yaml"))
+	if r.err != nil {
+		t.Fatalf("Expected no error but got %s", r.err)
+	}
+
+	names := make([]string, len(r.cases))
+	for i, c := range r.cases {
+		names[i] = c.name
+	}
+
+	eNames := []string{"Pass", "Wrong effect", "Wrong obligations", "Invalid request", "request #1", "request #2"}
+	if !reflect.DeepEqual(names, eNames) {
+		t.Fatalf("Expected cases %q but got %q", eNames, names)
+	}
+
+	if c := r.cases[0]; c.err != nil || c.unchecked || len(c.diffs) > 0 {
+		t.Errorf("Expected %q to pass but got error %v, unchecked %v and diffs %q", c.name, c.err, c.unchecked, c.diffs)
+	}
+
+	e := []string{"effect: expected \"Permit\" but got \"Deny\""}
+	if c := r.cases[1]; c.err != nil || !reflect.DeepEqual(c.diffs, e) {
+		t.Errorf("Expected %q to fail with %q but got error %v and diffs %q", c.name, e, c.err, c.diffs)
+	}
+
+	e = []string{
+		"obligations: expected (-) but got (+)",
+		"  + \"r\" (string): \"permit\"",
+	}
+	if c := r.cases[2]; c.err != nil || !reflect.DeepEqual(c.diffs, e) {
+		t.Errorf("Expected %q to fail with %q but got error %v and diffs %q", c.name, e, c.err, c.diffs)
+	}
+
+	if c := r.cases[3]; c.err == nil {
+		t.Errorf("Expected error for %q but got nothing", c.name)
+	}
+
+	for _, c := range r.cases[4:] {
+		if c.err != nil || !c.unchecked || len(c.diffs) > 0 {
+			t.Errorf("Expected %q to be unchecked but got error %v, unchecked %v and diffs %q",
+				c.name, c.err, c.unchecked, c.diffs)
+		}
+	}
+
+	if n := r.failures(); n != 2 {
+		t.Errorf("Expected %d failures but got %d", 2, n)
+	}
+
+	if n := r.errors(); n != 1 {
+		t.Errorf("Expected %d errors but got %d", 1, n)
+	}
+
+	if n := r.skipped(); n != 2 {
+		t.Errorf("Expected %d skipped cases but got %d", 2, n)
+	}
+}
+
+func TestRunSuiteWithMissingPolicy(t *testing.T) {
+	dir := makeTestSuiteDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "missing.yaml")
+	r := runSuite(path)
+	if r.err == nil {
+		t.Errorf("Expected error for missing policy but got %d cases", len(r.cases))
+	}
+
+	var b strings.Builder
+	if dump(&b, []suiteResult{r}) {
+		t.Errorf("Expected suite with missing policy to fail")
+	}
+}
+
+func TestDump(t *testing.T) {
+	dir := makeTestSuiteDir(t)
+	defer os.RemoveAll(dir)
+
+	var b strings.Builder
+	if dump(&b, []suiteResult{runSuite(filepath.Join(dir, "suite.yaml"))}) {
+		t.Errorf("Expected failed suite")
+	}
+
+	s := b.String()
+	e := "4 tests, 1 passed, 3 failed, 2 requests without expectations\n"
+	if !strings.HasSuffix(s, e) {
+		t.Errorf("Expected summary %q but got:\n%s", e, s)
+	}
+
+	for _, line := range []string{
+		"FAIL " + filepath.Join(dir, "suite.yaml") + ": Wrong effect\n",
+		"FAIL " + filepath.Join(dir, "suite.yaml") + ": Wrong obligations\n",
+		"ERROR " + filepath.Join(dir, "suite.yaml") + ": Invalid request: invalid request: ",
+	} {
+		if !strings.Contains(s, line) {
+			t.Errorf("Expected %q in output but got:\n%s", line, s)
+		}
+	}
+
+	b.Reset()
+	if !dump(&b, []suiteResult{runSuite(filepath.Join(dir, "passing.yaml"))}) {
+		t.Errorf("Expected passed suite but got:\n%s", b.String())
+	}
+
+	if s, e := b.String(), "2 tests, 2 passed, 0 failed\n"; s != e {
+		t.Errorf("Expected %q but got %q", e, s)
+	}
+
+	b.Reset()
+	if !dump(&b, []suiteResult{runSuite(filepath.Join(dir, "requests.yaml"))}) {
+		t.Errorf("Expected passed suite but got:\n%s", b.String())
+	}
+
+	if s, e := b.String(), "0 tests, 0 passed, 0 failed, 2 requests without expectations\n"; s != e {
+		t.Errorf("Expected %q but got %q", e, s)
+	}
+}
+
+func TestDumpJUnit(t *testing.T) {
+	dir := makeTestSuiteDir(t)
+	defer os.RemoveAll(dir)
+
+	var b strings.Builder
+	if err := dumpJUnit(&b, []suiteResult{runSuite(filepath.Join(dir, "suite.yaml"))}); err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	s := b.String()
+	for _, part := range []string{
+		"<testsuites tests=\"6\" failures=\"2\" errors=\"1\"",
+		"tests=\"6\" failures=\"2\" errors=\"1\" skipped=\"2\"",
+		"<failure message=\"effect: expected &#34;Permit&#34; but got &#34;Deny&#34;\">",
+		"<skipped message=\"no expectations\"></skipped>",
+	} {
+		if !strings.Contains(s, part) {
+			t.Errorf("Expected %q in report but got:\n%s", part, s)
+		}
+	}
+}
+
+func makeTestSuiteDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "themis-test")
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	for name, data := range map[string]string{
+		"policy.yaml":   testPolicy,
+		"suite.yaml":    testSuite,
+		"passing.yaml":  testPassingSuite,
+		"requests.yaml": testRequestsSuite,
+		"missing.yaml":  testMissingPolicySuite,
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			os.RemoveAll(dir)
+			t.Fatalf("Expected no error but got %s", err)
+		}
+	}
+
+	return dir
+}